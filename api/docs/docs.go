@@ -24,19 +24,16 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/conversations": {
-            "get": {
-                "description": "get all conversation by user id",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/auth/logout": {
+            "post": {
+                "description": "revoke the access token of the request and end its session, the token and refresh_token cookies are cleared. When the access token expired the session of the refresh_token cookie is ended instead, logging out without either succeeds and only clears the cookies",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Conversation"
+                    "User"
                 ],
-                "summary": "Get All Conversation By User Id",
+                "summary": "Logout",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -49,10 +46,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/payload.GetAllByUserIdConv"
-                                            }
+                                            "$ref": "#/definitions/payload.LogoutResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -65,9 +59,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/conversations/{convo_id}": {
-            "get": {
-                "description": "get conversation by id",
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "exchange a refresh token for a new access token and refresh token, the refresh token can only be used once",
                 "consumes": [
                     "application/json"
                 ],
@@ -75,16 +69,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Conversation"
+                    "User"
                 ],
-                "summary": "Get Conversation By Id",
+                "summary": "Refresh Token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Conversation ID",
-                        "name": "convo_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token, read from the refresh_token cookie when left out",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/payload.RefreshRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -99,7 +94,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.GetByIdConversationResponse"
+                                            "$ref": "#/definitions/payload.RefreshResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -112,9 +107,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/conversations/{convo_id}/messages": {
+        "/api/v1/bots": {
             "get": {
-                "description": "get message by conversation id",
+                "description": "get the caller's bots",
                 "consumes": [
                     "application/json"
                 ],
@@ -122,18 +117,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Message"
-                ],
-                "summary": "Get Message By Conversation Id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Conversation ID",
-                        "name": "convo_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Bot"
                 ],
+                "summary": "Get All Bots",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -146,10 +132,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/model.Message"
-                                            }
+                                            "$ref": "#/definitions/payload.GetAllBotsResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -160,11 +143,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/messages": {
+            },
             "post": {
-                "description": "create message from request body",
+                "description": "create a bot account owned by the caller, the returned API key and callback secret are not shown again",
                 "consumes": [
                     "application/json"
                 ],
@@ -172,17 +153,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Message"
+                    "Bot"
                 ],
-                "summary": "Create New Message",
+                "summary": "Create Bot",
                 "parameters": [
                     {
-                        "description": "Create User",
+                        "description": "Bot profile and callback URL",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payload.CreateMessageRequest"
+                            "$ref": "#/definitions/payload.CreateBotRequest"
                         }
                     }
                 ],
@@ -198,7 +179,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.CreateMessageResponse"
+                                            "$ref": "#/definitions/payload.CreateBotResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -211,9 +192,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user": {
-            "get": {
-                "description": "get user by token",
+        "/api/v1/bots/{id}": {
+            "put": {
+                "description": "change the callback URL of one of the caller's bots or whether it can search the user directory",
                 "consumes": [
                     "application/json"
                 ],
@@ -221,9 +202,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Bot"
+                ],
+                "summary": "Update Bot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.UpdateBotRequest"
+                        }
+                    }
                 ],
-                "summary": "Get By Token",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -236,7 +235,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.GetByTokenResponse"
+                                            "$ref": "#/definitions/payload.UpdateBotResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -247,11 +246,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user/create": {
-            "post": {
-                "description": "create user from request body",
+            },
+            "delete": {
+                "description": "remove one of the caller's bots, its API keys stop working",
                 "consumes": [
                     "application/json"
                 ],
@@ -259,18 +256,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Bot"
                 ],
-                "summary": "Create New User",
+                "summary": "Remove Bot",
                 "parameters": [
                     {
-                        "description": "Create User",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/payload.CreateRequest"
-                        }
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -285,7 +280,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.CreateResponse"
+                                            "$ref": "#/definitions/payload.RemoveBotResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -298,9 +293,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/delete/{id}": {
-            "delete": {
-                "description": "delete user from request body",
+        "/api/v1/bots/{id}/keys": {
+            "get": {
+                "description": "get a bot's API keys, only their prefixes are shown",
                 "consumes": [
                     "application/json"
                 ],
@@ -308,20 +303,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Bot"
                 ],
-                "summary": "Delete User",
+                "summary": "Get Bot API Keys",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID",
+                        "description": "Bot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -339,7 +327,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.DeleteResponse"
+                                            "$ref": "#/definitions/payload.GetKeysResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -350,11 +338,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/user/login": {
+            },
             "post": {
-                "description": "user login",
+                "description": "create another API key for a bot, it is not shown again",
                 "consumes": [
                     "application/json"
                 ],
@@ -362,23 +348,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Bot"
                 ],
-                "summary": "Login",
+                "summary": "Create Bot API Key",
                 "parameters": [
                     {
-                        "description": "Login",
+                        "type": "string",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Key name",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/payload.LoginRequest"
+                            "$ref": "#/definitions/payload.CreateKeyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {
@@ -388,7 +381,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.LoginResponse"
+                                            "$ref": "#/definitions/payload.CreateKeyResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -401,9 +394,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/update/{id}": {
-            "patch": {
-                "description": "update user from request body",
+        "/api/v1/bots/{id}/keys/{key_id}": {
+            "delete": {
+                "description": "revoke one of a bot's API keys",
                 "consumes": [
                     "application/json"
                 ],
@@ -411,32 +404,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Bot"
                 ],
-                "summary": "Update User",
+                "summary": "Revoke Bot API Key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Token",
-                        "name": "Authorization",
-                        "in": "header",
+                        "description": "Bot ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "API key ID",
+                        "name": "key_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update User",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/payload.UpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -451,7 +435,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.UpdateResponse"
+                                            "$ref": "#/definitions/payload.RevokeKeyResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -464,9 +448,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/user/{id}": {
+        "/api/v1/contacts": {
             "get": {
-                "description": "get user by id",
+                "description": "get the caller's contacts",
                 "consumes": [
                     "application/json"
                 ],
@@ -474,21 +458,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Contact"
                 ],
-                "summary": "Get User By Id",
+                "summary": "Get All Contact",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllContactResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "add a user to the caller's contacts with an optional private nickname",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Add Contact",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Add Contact",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.AddContactRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {
@@ -498,7 +520,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.GetByIdResponse"
+                                            "$ref": "#/definitions/payload.AddContactResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -511,9 +533,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users": {
-            "get": {
-                "description": "get all users",
+        "/api/v1/contacts/import": {
+            "post": {
+                "description": "add every existing user matching the given emails to the caller's contacts",
                 "consumes": [
                     "application/json"
                 ],
@@ -521,15 +543,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Contact"
                 ],
-                "summary": "Get All User",
+                "summary": "Import Contact",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search by user name",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Import Contact",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.ImportContactRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -544,7 +569,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/payload.GetAllResponse"
+                                            "$ref": "#/definitions/payload.ImportContactResponse"
                                         },
                                         "status": {
                                             "type": "string"
@@ -557,126 +582,4422 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "get the status of server.",
+        "/api/v1/contacts/{id}": {
+            "delete": {
+                "description": "remove a user from the caller's contacts",
                 "consumes": [
-                    "*/*"
+                    "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "Contact"
+                ],
+                "summary": "Remove Contact",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Show the status of server.",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RemoveContactResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "model.Message": {
-            "type": "object",
-            "properties": {
-                "conversationId": {
-                    "type": "string"
-                },
-                "message": {
+            },
+            "patch": {
+                "description": "set the private nickname of a contact",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Update Contact Nickname",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Nickname",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.UpdateNicknameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.UpdateNicknameResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations": {
+            "get": {
+                "description": "get all conversation by user id, pinned conversations first then by last activity",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Get All Conversation By User Id",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "List archived conversations instead of the inbox",
+                        "name": "archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starts at 1",
+                        "name": "currentPage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Conversations per page, at most 100",
+                        "name": "itemsPerPage",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllByUserIdConvResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/requests": {
+            "get": {
+                "description": "get conversations started by users the caller has not accepted yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Get Message Requests",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starts at 1",
+                        "name": "currentPage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Requests per page, at most 100",
+                        "name": "itemsPerPage",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetRequestsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/requests/{convo_id}/accept": {
+            "post": {
+                "description": "accept a message request, the conversation moves to the inbox",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Accept Message Request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RespondRequestResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/requests/{convo_id}/block": {
+            "post": {
+                "description": "decline a message request and block its sender",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Decline And Block Message Request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RespondRequestResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/requests/{convo_id}/decline": {
+            "post": {
+                "description": "decline a message request, the sender can no longer message the caller in it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Decline Message Request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RespondRequestResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}": {
+            "get": {
+                "description": "get conversation by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Get Conversation By Id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetByIdConversationResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/archive": {
+            "put": {
+                "description": "archive or unarchive a conversation for the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Archive Conversation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Archive Conversation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.ArchiveConversationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.ConversationSettingsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/commands": {
+            "get": {
+                "description": "get the slash commands available in the conversation, built-in ones first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Command"
+                ],
+                "summary": "Get All Commands",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllCommandsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "add a slash command to the conversation that calls a URL, invocations are signed with the returned secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Command"
+                ],
+                "summary": "Create Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Command name and callback URL",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.CreateCommandRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.CreateCommandResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/commands/{id}": {
+            "delete": {
+                "description": "remove a slash command the conversation added",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Command"
+                ],
+                "summary": "Remove Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Command ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RemoveCommandResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/draft": {
+            "get": {
+                "description": "get the caller's draft for a conversation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Draft"
+                ],
+                "summary": "Get Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetDraftResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "save the caller's draft for a conversation, an empty text clears it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Draft"
+                ],
+                "summary": "Save Draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Save Draft",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.SaveDraftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.SaveDraftResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/export": {
+            "get": {
+                "description": "stream the full history of a conversation as a JSON, CSV or HTML transcript",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "text/html"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Export Conversation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Transcript format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA timezone for timestamps, defaults to UTC",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/history": {
+            "delete": {
+                "description": "delete the conversation history for the caller only, the other participant is not affected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Clear Conversation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.ConversationSettingsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/incoming-webhooks": {
+            "get": {
+                "description": "get the incoming webhooks of the conversation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incoming Webhook"
+                ],
+                "summary": "Get All Incoming Webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllIncomingWebhooksResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create a secret URL that posts into the conversation, the URL is not shown again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incoming Webhook"
+                ],
+                "summary": "Create Incoming Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Integration name and rate limit",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.CreateIncomingWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.CreateIncomingWebhookResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/incoming-webhooks/{id}": {
+            "delete": {
+                "description": "revoke an incoming webhook of the conversation, its URL stops working",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incoming Webhook"
+                ],
+                "summary": "Revoke Incoming Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Incoming webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RevokeIncomingWebhookResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/messages": {
+            "get": {
+                "description": "get message by conversation id, the messages the caller received are marked as read",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Message By Conversation Id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Message"
+                                            }
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/messages/search": {
+            "get": {
+                "description": "search the messages of a conversation, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Search Messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to look for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results, defaults to 20",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Message"
+                                            }
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/mute": {
+            "put": {
+                "description": "mute a conversation for the caller until the given time, null unmutes it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Mute Conversation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Mute Conversation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.MuteConversationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.ConversationSettingsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/conversations/{convo_id}/pin": {
+            "put": {
+                "description": "pin a conversation to the top of the caller's list or unpin it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Conversation"
+                ],
+                "summary": "Pin Conversation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID",
+                        "name": "convo_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pin Conversation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.PinConversationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.ConversationSettingsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/devices": {
+            "get": {
+                "description": "get the caller's devices with the number of one-time prekeys they have left",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Get All Devices",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllDevicesResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "register a device with its public identity key, signed prekey and a first batch of one-time prekeys for end-to-end encryption",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Register Device",
+                "parameters": [
+                    {
+                        "description": "Public keys of the device",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.RegisterDeviceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RegisterDeviceResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/devices/{id}": {
+            "delete": {
+                "description": "remove one of the caller's devices along with its prekeys",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Remove Device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RemoveDeviceResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/devices/{id}/prekeys": {
+            "post": {
+                "description": "add one-time prekeys to one of the caller's devices, key ids already uploaded are skipped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Upload Prekeys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "One-time prekeys",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.UploadPrekeysRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.UploadPrekeysResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/devices/{id}/signed-prekey": {
+            "put": {
+                "description": "replace the signed prekey of one of the caller's devices",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Rotate Signed Prekey",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New signed prekey",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.RotateSignedPrekeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RotateSignedPrekeyResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/hooks/{token}": {
+            "post": {
+                "description": "post a message into the hook's conversation, authorized by the token in the URL and rate limited per hook",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incoming Webhook"
+                ],
+                "summary": "Post Incoming Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token from the incoming webhook URL",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message text",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.PostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.PostResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/data-exports": {
+            "post": {
+                "description": "start building an archive of all the caller's data, poll the returned export for its status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data Export"
+                ],
+                "summary": "Request Data Export",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.DataExportResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/data-exports/{id}": {
+            "get": {
+                "description": "get the status of a data export, includes a download url once it is ready. Every call issues a new url and the previous one stops working",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data Export"
+                ],
+                "summary": "Get Data Export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.DataExportResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/data-exports/{id}/download": {
+            "get": {
+                "description": "download a ready data export as a zip archive, the token comes from the export's download url",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Data Export"
+                ],
+                "summary": "Download Data Export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Download token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/email-digest": {
+            "get": {
+                "description": "get whether the caller is emailed a digest of messages left unread",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Digest"
+                ],
+                "summary": "Get Email Digest Settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.SettingsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "turn the email digest of unread messages on or off for the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Digest"
+                ],
+                "summary": "Update Email Digest Settings",
+                "parameters": [
+                    {
+                        "description": "Whether to send the digest",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.UpdateSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.SettingsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/push-tokens": {
+            "post": {
+                "description": "register a push notification token of the caller's app install, a token registered by another user moves to the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification"
+                ],
+                "summary": "Register Push Token",
+                "parameters": [
+                    {
+                        "description": "Platform and token",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.RegisterTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RegisterTokenResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "stop sending push notifications to one of the caller's tokens, e.g. on logout",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notification"
+                ],
+                "summary": "Unregister Push Token",
+                "parameters": [
+                    {
+                        "description": "Token",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.UnregisterTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.UnregisterTokenResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/sessions": {
+            "get": {
+                "description": "list the caller's active sessions, the one the request was made with is marked current",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Get Sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetSessionsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "sign the caller out of every session but the current one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Revoke Other Sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RevokeOtherSessionsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/sessions/{id}": {
+            "delete": {
+                "description": "sign one of the caller's sessions out, its token is rejected from then on",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Revoke Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RevokeSessionResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/unread": {
+            "get": {
+                "description": "get the total of unread messages and unread conversations for the app badge, muted and archived conversations are not counted. Messages are read once the receiver opens their conversation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Unread Summary",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.UnreadSummaryResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/messages": {
+            "post": {
+                "description": "create message from request body. With mode e2e the message is opaque ciphertext per recipient device, a device list that doesn't match the receiver's devices is answered with 409",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Create New Message",
+                "parameters": [
+                    {
+                        "description": "Create User",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.CreateMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.CreateMessageResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/messages/{id}": {
+            "delete": {
+                "description": "delete a message the caller sent, it is removed for both participants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Delete Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.DeleteMessageResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user": {
+            "get": {
+                "description": "get user by token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get By Token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetByTokenResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/blocked": {
+            "get": {
+                "description": "get all users blocked by the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Block"
+                ],
+                "summary": "Get Blocked Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllBlockedResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/create": {
+            "post": {
+                "description": "create user from request body",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Create New User",
+                "parameters": [
+                    {
+                        "description": "Create User",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.CreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.CreateResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/delete/{id}": {
+            "delete": {
+                "description": "delete a user account, the profile is anonymized and conversations show it as a deleted account. Users can only delete themselves unless they are an admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also erase the user's messages after the grace period",
+                        "name": "purge_messages",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.DeleteResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/deletions/{id}/cancel": {
+            "post": {
+                "description": "keep the messages of a deleted account, using the cancel token returned when it was deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Cancel Message Purge",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Deletion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancel Token",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.CancelPurgeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.CancelPurgeResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/login": {
+            "post": {
+                "description": "user login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.LoginResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/update/{id}": {
+            "patch": {
+                "description": "update user from request body, users can only update themselves unless they are an admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update User",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.UpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.UpdateResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}": {
+            "get": {
+                "description": "get user by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get User By Id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetByIdResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}/block": {
+            "post": {
+                "description": "block a user, they can no longer send messages to the caller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Block"
+                ],
+                "summary": "Block User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.BlockResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "unblock a previously blocked user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Block"
+                ],
+                "summary": "Unblock User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.UnblockResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}/prekey-bundles": {
+            "post": {
+                "description": "get a prekey bundle for every device of a user to start end-to-end encrypted sessions, each bundle uses up one of the device's one-time prekeys",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Device"
+                ],
+                "summary": "Claim Prekey Bundles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.ClaimBundlesResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}/role": {
+            "put": {
+                "description": "change the role of another user, requires the admin role. Admins can't change their own role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.UpdateRoleResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/user/{id}/sessions": {
+            "delete": {
+                "description": "sign a user out on every device, requires the moderator role. Only admins can sign out an admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke User Sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RevokeSessionsResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "description": "get all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get All User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by user name",
+                        "name": "search",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "get the caller's webhooks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get All Webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetAllWebhooksResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "subscribe a URL to events in the caller's conversations, requests are signed with the returned secret",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create Webhook",
+                "parameters": [
+                    {
+                        "description": "URL and event types",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/payload.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.CreateWebhookResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "delete": {
+                "description": "remove one of the caller's webhooks, queued deliveries are dropped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Remove Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RemoveWebhookResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "get the delivery log of one of the caller's webhooks, newest first, with the response code and error of the last attempt",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get Webhook Deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivering, succeeded or dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starts at 1",
+                        "name": "currentPage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Deliveries per page, at most 100",
+                        "name": "itemsPerPage",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.GetDeliveriesResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries/{delivery_id}/redeliver": {
+            "post": {
+                "description": "send the payload of a past delivery again as a new delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Redeliver Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "type": "object"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/payload.RedeliverResponse"
+                                        },
+                                        "status": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "get the status of server.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Show the status of server.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.APIKey": {
+            "type": "object",
+            "properties": {
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Start of the key, to tell keys apart",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Bot": {
+            "type": "object",
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "can_search_directory": {
+                    "description": "Bots can't search the user directory unless the owner grants it",
+                    "type": "boolean"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.Contact": {
+            "type": "object",
+            "properties": {
+                "nickname": {
+                    "description": "Private name UserID gave to ContactID",
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "model.Device": {
+            "type": "object",
+            "properties": {
+                "identity_key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prekeys_left": {
+                    "description": "One-time prekeys not claimed yet, clients upload more when it runs low",
+                    "type": "integer"
+                },
+                "signed_prekey": {
+                    "type": "string"
+                },
+                "signed_prekey_id": {
+                    "type": "integer"
+                },
+                "signed_prekey_signature": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.IncomingWebhook": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "string"
+                },
+                "integration": {
+                    "$ref": "#/definitions/model.User"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "Posts allowed per minute",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Message": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Ciphertext of an e2e message, one per recipient device. Reads only carry the\ncaller's devices.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MessageDeviceContent"
+                    }
+                },
+                "conversationId": {
+                    "type": "string"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "read": {
+                    "description": "Read receipt, only shown to the sender and not when the receiver blocked them",
+                    "type": "boolean"
+                },
+                "sender": {
+                    "$ref": "#/definitions/model.User"
+                },
+                "sent_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MessageDeviceContent": {
+            "type": "object",
+            "properties": {
+                "ciphertext": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PushToken": {
+            "type": "object",
+            "properties": {
+                "platform": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SlashCommand": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "creator_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Without the slash, lowercase",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "usage": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.User": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "is_bot": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "One of RoleUser, RoleModerator or RoleAdmin",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "Body as it's sent, redeliveries send it again unchanged",
+                    "type": "string"
+                },
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.AddContactRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "nickname": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.AddContactResponse": {
+            "type": "object",
+            "properties": {
+                "contact": {
+                    "$ref": "#/definitions/model.Contact"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.ArchiveConversationRequest": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "payload.BlockResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CancelPurgeRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CancelPurgeResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.ClaimBundlesResponse": {
+            "type": "object",
+            "properties": {
+                "bundles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/payload.PrekeyBundle"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CommandInfo": {
+            "type": "object",
+            "properties": {
+                "builtin": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "usage": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.ConversationSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "cleared_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "muted_until": {
+                    "type": "string"
+                },
+                "pinned_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payload.CreateBotRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "callback_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "can_search_directory": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "photo_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "payload.CreateBotResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "bot": {
+                    "$ref": "#/definitions/model.Bot"
+                },
+                "callback_secret": {
+                    "type": "string"
+                },
+                "key": {
+                    "$ref": "#/definitions/model.APIKey"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CreateCommandRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "url"
+            ],
+            "properties": {
+                "conversationID": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "usage": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "payload.CreateCommandResponse": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "$ref": "#/definitions/model.SlashCommand"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CreateIncomingWebhookRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "conversationID": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "photo_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "rate_limit": {
+                    "description": "Posts allowed per minute, defaults to 60",
+                    "type": "integer",
+                    "maximum": 600,
+                    "minimum": 0
+                }
+            }
+        },
+        "payload.CreateIncomingWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "hook": {
+                    "$ref": "#/definitions/model.IncomingWebhook"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CreateKeyRequest": {
+            "type": "object",
+            "properties": {
+                "botID": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "payload.CreateKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "key": {
+                    "$ref": "#/definitions/model.APIKey"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CreateMessageRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Ciphertext for every device of the receiver, optionally also for the sender's other devices",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/payload.DeviceContent"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mode": {
+                    "description": "Defaults to plain, e2e messages leave message empty and send content instead",
+                    "type": "string",
+                    "enum": [
+                        "plain",
+                        "e2e"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CreateMessageResponse": {
+            "type": "object",
+            "properties": {
+                "conversation": {
+                    "$ref": "#/definitions/payload.GetConversationResponse"
+                },
+                "ephemeral": {
+                    "description": "Reply of a slash command only the sender sees, no message was stored",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Message ID",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "sender": {
+                    "$ref": "#/definitions/model.User"
+                },
+                "sent_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.CreateRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 8
+                }
+            }
+        },
+        "payload.CreateResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "payload.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "payload.CreateWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/model.Webhook"
+                }
+            }
+        },
+        "payload.DataExportResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "Set by getting a ready export that hasn't expired, a new link replaces the previous one",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.DeleteMessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.DeleteResponse": {
+            "type": "object",
+            "properties": {
+                "cancel_token": {
+                    "type": "string"
+                },
+                "deletion_id": {
+                    "description": "Set when a message purge was scheduled, the token cancels it until purge_at",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "purge_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.DeviceContent": {
+            "type": "object",
+            "required": [
+                "ciphertext",
+                "device_id"
+            ],
+            "properties": {
+                "ciphertext": {
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "device_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetAllBlockedResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                }
+            }
+        },
+        "payload.GetAllBotsResponse": {
+            "type": "object",
+            "properties": {
+                "bots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Bot"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetAllByUserIdConv": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "cleared_at": {
+                    "type": "string"
+                },
+                "draft": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_message": {
+                    "$ref": "#/definitions/model.Message"
+                },
+                "last_message_preview": {
+                    "description": "Start of the last message, cut to a fixed length",
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "description": "Presence of the other user, left out when they blocked the caller",
+                    "type": "string"
+                },
+                "muted_until": {
+                    "type": "string"
+                },
+                "pinned_order": {
+                    "type": "integer"
+                },
+                "unread_count": {
+                    "type": "integer"
+                },
+                "with_user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "payload.GetAllByUserIdConvResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "paginatedData": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/payload.GetAllByUserIdConv"
+                    }
+                },
+                "perPage": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payload.GetAllCommandsResponse": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/payload.CommandInfo"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetAllContactResponse": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Contact"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetAllDevicesResponse": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Device"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetAllIncomingWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "hooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.IncomingWebhook"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetAllResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "paginatedData": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.User"
+                    }
+                },
+                "perPage": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payload.GetAllWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Webhook"
+                    }
+                }
+            }
+        },
+        "payload.GetByIdConversationResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "description": "Presence of the other user, left out when they blocked the caller",
+                    "type": "string"
+                },
+                "with_user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "payload.GetByIdResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "payload.GetByTokenResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "payload.GetConversationResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "with_user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "payload.GetDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "paginatedData": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDelivery"
+                    }
+                },
+                "perPage": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payload.GetDraftResponse": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetKeysResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.APIKey"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.GetRequestsResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "paginatedData": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/payload.GetAllByUserIdConv"
+                    }
+                },
+                "perPage": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payload.GetSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/payload.SessionResponse"
+                    }
+                }
+            }
+        },
+        "payload.ImportContactRequest": {
+            "type": "object",
+            "required": [
+                "emails"
+            ],
+            "properties": {
+                "emails": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "payload.ImportContactResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Contact"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "not_found": {
+                    "description": "Emails that don't belong to any user",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "payload.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "device_name": {
+                    "description": "Shown in the session list, e.g. \"Alice's phone\"",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "exp": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "refresh_exp": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.MuteConversationRequest": {
+            "type": "object",
+            "properties": {
+                "muted_until": {
+                    "description": "Leave empty or null to unmute",
+                    "type": "string"
+                }
+            }
+        },
+        "payload.PinConversationRequest": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "description": "Lower order is shown first among pinned conversations",
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "payload.PostRequest": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "text": {
+                    "type": "string",
+                    "maxLength": 4000
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.PostResponse": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Message ID",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.Prekey": {
+            "type": "object",
+            "required": [
+                "public_key"
+            ],
+            "properties": {
+                "key_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "public_key": {
+                    "type": "string",
+                    "maxLength": 256
+                }
+            }
+        },
+        "payload.PrekeyBundle": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "identity_key": {
+                    "type": "string"
+                },
+                "one_time_prekey": {
+                    "$ref": "#/definitions/payload.Prekey"
+                },
+                "signed_prekey": {
+                    "type": "string"
+                },
+                "signed_prekey_id": {
+                    "type": "integer"
+                },
+                "signed_prekey_signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.RedeliverResponse": {
+            "type": "object",
+            "properties": {
+                "delivery": {
+                    "$ref": "#/definitions/model.WebhookDelivery"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "Falls back to the refresh_token cookie",
+                    "type": "string"
+                }
+            }
+        },
+        "payload.RefreshResponse": {
+            "type": "object",
+            "properties": {
+                "exp": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "refresh_exp": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "token": {
                     "type": "string"
+                }
+            }
+        },
+        "payload.RegisterDeviceRequest": {
+            "type": "object",
+            "required": [
+                "identity_key",
+                "signed_prekey",
+                "signed_prekey_signature"
+            ],
+            "properties": {
+                "identity_key": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "one_time_prekeys": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/payload.Prekey"
+                    }
                 },
-                "sender": {
-                    "$ref": "#/definitions/model.User"
+                "signed_prekey": {
+                    "type": "string",
+                    "maxLength": 256
                 },
-                "sent_at": {
-                    "type": "string"
+                "signed_prekey_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "signed_prekey_signature": {
+                    "type": "string",
+                    "maxLength": 256
                 }
             }
         },
-        "model.User": {
+        "payload.RegisterDeviceResponse": {
             "type": "object",
             "properties": {
-                "email": {
+                "device": {
+                    "$ref": "#/definitions/model.Device"
+                },
+                "message": {
                     "type": "string"
+                }
+            }
+        },
+        "payload.RegisterTokenRequest": {
+            "type": "object",
+            "required": [
+                "platform",
+                "token"
+            ],
+            "properties": {
+                "platform": {
+                    "type": "string",
+                    "enum": [
+                        "android",
+                        "ios"
+                    ]
                 },
-                "name": {
+                "token": {
+                    "type": "string",
+                    "maxLength": 4096
+                }
+            }
+        },
+        "payload.RegisterTokenResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
                 },
-                "photo_url": {
+                "push_token": {
+                    "$ref": "#/definitions/model.PushToken"
+                }
+            }
+        },
+        "payload.RemoveBotResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "payload.CreateMessageRequest": {
+        "payload.RemoveCommandResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
-                },
-                "user_id": {
+                }
+            }
+        },
+        "payload.RemoveContactResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "payload.CreateMessageResponse": {
+        "payload.RemoveDeviceResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.RemoveWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.RespondRequestResponse": {
             "type": "object",
             "properties": {
-                "conversation": {
-                    "$ref": "#/definitions/payload.GetConversationResponse"
-                },
                 "id": {
-                    "description": "Message ID",
                     "type": "string"
                 },
                 "message": {
                     "type": "string"
                 },
-                "sender": {
-                    "$ref": "#/definitions/model.User"
-                },
-                "sent_at": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "payload.CreateRequest": {
+        "payload.RevokeIncomingWebhookResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "message": {
                     "type": "string"
-                },
-                "name": {
+                }
+            }
+        },
+        "payload.RevokeKeyResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
-                },
-                "password": {
-                    "type": "string",
-                    "minLength": 8
                 }
             }
         },
-        "payload.CreateResponse": {
+        "payload.RevokeOtherSessionsResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/model.User"
+                "revoked": {
+                    "type": "integer"
                 }
             }
         },
-        "payload.DeleteResponse": {
+        "payload.RevokeSessionResponse": {
             "type": "object",
             "properties": {
                 "message": {
@@ -684,122 +5005,192 @@ const docTemplate = `{
                 }
             }
         },
-        "payload.GetAllByUserIdConv": {
+        "payload.RevokeSessionsResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "message": {
                     "type": "string"
                 },
-                "last_message": {
-                    "$ref": "#/definitions/model.Message"
-                },
-                "unread_count": {
+                "revoked": {
+                    "description": "Number of sessions that were ended",
                     "type": "integer"
+                }
+            }
+        },
+        "payload.RotateSignedPrekeyRequest": {
+            "type": "object",
+            "required": [
+                "signed_prekey",
+                "signed_prekey_signature"
+            ],
+            "properties": {
+                "signed_prekey": {
+                    "type": "string",
+                    "maxLength": 256
                 },
-                "with_user": {
-                    "$ref": "#/definitions/model.User"
+                "signed_prekey_id": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "signed_prekey_signature": {
+                    "type": "string",
+                    "maxLength": 256
                 }
             }
         },
-        "payload.GetAllResponse": {
+        "payload.RotateSignedPrekeyResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
+                }
+            }
+        },
+        "payload.SaveDraftRequest": {
+            "type": "object",
+            "properties": {
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.SaveDraftResponse": {
+            "type": "object",
+            "properties": {
+                "conversation_id": {
+                    "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "text": {
+                    "type": "string"
                 },
-                "paginatedData": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.User"
-                    }
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "payload.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "perPage": {
-                    "type": "integer"
+                "current": {
+                    "description": "The session the request was made with",
+                    "type": "boolean"
                 },
-                "sort": {
+                "device_name": {
                     "type": "string"
                 },
-                "totalItems": {
-                    "type": "integer"
+                "expires_at": {
+                    "type": "string"
                 },
-                "totalPages": {
-                    "type": "integer"
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
                 }
             }
         },
-        "payload.GetByIdConversationResponse": {
+        "payload.SettingsResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "with_user": {
-                    "$ref": "#/definitions/model.User"
+                "message": {
+                    "type": "string"
                 }
             }
         },
-        "payload.GetByIdResponse": {
+        "payload.UnblockResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
+                }
+            }
+        },
+        "payload.UnreadSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "unread_conversations": {
+                    "type": "integer"
                 },
-                "user": {
-                    "$ref": "#/definitions/model.User"
+                "unread_messages": {
+                    "type": "integer"
                 }
             }
         },
-        "payload.GetByTokenResponse": {
+        "payload.UnregisterTokenRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "maxLength": 4096
+                }
+            }
+        },
+        "payload.UnregisterTokenResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/model.User"
                 }
             }
         },
-        "payload.GetConversationResponse": {
+        "payload.UpdateBotRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "botID": {
                     "type": "string"
                 },
-                "with_user": {
-                    "$ref": "#/definitions/model.User"
+                "callback_url": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "can_search_directory": {
+                    "type": "boolean"
                 }
             }
         },
-        "payload.LoginRequest": {
+        "payload.UpdateBotResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "bot": {
+                    "$ref": "#/definitions/model.Bot"
+                },
+                "callback_secret": {
                     "type": "string"
                 },
-                "password": {
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "payload.LoginResponse": {
+        "payload.UpdateNicknameRequest": {
             "type": "object",
             "properties": {
-                "exp": {
+                "nickname": {
+                    "description": "Empty nickname falls back to the user's own name",
                     "type": "string"
-                },
+                }
+            }
+        },
+        "payload.UpdateNicknameResponse": {
+            "type": "object",
+            "properties": {
                 "message": {
                     "type": "string"
-                },
-                "token": {
-                    "type": "string"
                 }
             }
         },
@@ -827,6 +5218,71 @@ const docTemplate = `{
                     "$ref": "#/definitions/model.User"
                 }
             }
+        },
+        "payload.UpdateRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "moderator",
+                        "admin"
+                    ]
+                }
+            }
+        },
+        "payload.UpdateRoleResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/model.User"
+                }
+            }
+        },
+        "payload.UpdateSettingsRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "payload.UploadPrekeysRequest": {
+            "type": "object",
+            "required": [
+                "one_time_prekeys"
+            ],
+            "properties": {
+                "one_time_prekeys": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/payload.Prekey"
+                    }
+                }
+            }
+        },
+        "payload.UploadPrekeysResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "prekeys_left": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
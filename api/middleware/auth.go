@@ -2,39 +2,121 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	httpError "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	botPayload "gitlab.com/raihanlh/messenger-api/internal/domain/bot/payload"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
 )
 
-func (m *middlewares) AuthToken(next echo.HandlerFunc) echo.HandlerFunc {
+// Authenticate loads the user from the token cookie, or the bot from an API key sent as
+// "Authorization: Bearer <key>"
+func (m *middlewares) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		token := c.Request().Header.Get("Authorization")
-		if token == "token" {
+		if key, ok := apiKey(c); ok {
+			res, err := m.usecases.Bot.Authenticate(c.Request().Context(), &botPayload.AuthenticateRequest{
+				Key: key,
+			})
+			if err != nil {
+				httpErr, ok := err.(*httpError.Error)
+				if !ok {
+					httpErr = httpError.InternalServerError(err.Error())
+				}
+				return c.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+			}
+			c.Set("user", res.User)
+			m.markSeen(c, res.User)
 			return next(c)
 		}
-		err := httpError.Forbidden("Invalid token")
-		return c.JSON(http.StatusInternalServerError, err.HttpResponseError())
-	}
-}
 
-func (m *middlewares) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
 		token, err := c.Request().Cookie("token")
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, err.Error())
+			httpErr := httpError.Unauthorized("Authentication required")
+			return c.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
 		}
 
 		res, err := m.usecases.User.GetByToken(c.Request().Context(), &payload.GetByTokenRequest{
 			Token: token.Value,
 		})
 		if err != nil {
+			// A revoked session is an expected outcome, anything else keeps the old response
+			if httpErr, ok := err.(*httpError.Error); ok {
+				return c.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+			}
 			return c.JSON(http.StatusBadRequest, err.Error())
 		}
 		c.Set("token", token.Value)
+		c.Set("session_id", res.SessionID)
 		c.Set("user", res.User)
+		m.markSeen(c, res.User)
+
+		return next(c)
+	}
+}
+
+// AuthenticateOptional sets the user when a valid token cookie or API key is present but lets anonymous requests through
+func (m *middlewares) AuthenticateOptional(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if key, ok := apiKey(c); ok {
+			res, err := m.usecases.Bot.Authenticate(c.Request().Context(), &botPayload.AuthenticateRequest{
+				Key: key,
+			})
+			if err == nil {
+				c.Set("user", res.User)
+			}
+			return next(c)
+		}
+
+		token, err := c.Request().Cookie("token")
+		if err != nil {
+			return next(c)
+		}
+
+		res, err := m.usecases.User.GetByToken(c.Request().Context(), &payload.GetByTokenRequest{
+			Token: token.Value,
+		})
+		if err == nil {
+			c.Set("token", token.Value)
+			c.Set("session_id", res.SessionID)
+			c.Set("user", res.User)
+		}
 
 		return next(c)
 	}
 }
+
+// RequireRole lets through users with the role or a more privileged one. It reads the user
+// set by Authenticate, so it has to come after it.
+func (m *middlewares) RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, ok := c.Get("user").(*model.User)
+			if !ok {
+				httpErr := httpError.Unauthorized("Authentication required")
+				return c.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+			}
+			if !user.HasRole(role) {
+				httpErr := httpError.Forbidden("Requires the " + role + " role")
+				return c.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+			}
+			return next(c)
+		}
+	}
+}
+
+// Presence only decides whether to send push notifications, it mustn't fail the request
+func (m *middlewares) markSeen(c echo.Context, user *model.User) {
+	if err := m.usecases.User.MarkSeen(c.Request().Context(), user); err != nil {
+		logger.GetLogger(c.Request().Context()).Error("Failed to mark user as seen: ", zap.Error(err))
+	}
+}
+
+// API key of a bot from the Authorization header
+func apiKey(c echo.Context) (string, bool) {
+	key := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	return key, model.IsAPIKey(key)
+}
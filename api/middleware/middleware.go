@@ -11,8 +11,9 @@ type middlewares struct {
 }
 
 type Middlewares interface {
-	AuthToken(next echo.HandlerFunc) echo.HandlerFunc
 	Authenticate(next echo.HandlerFunc) echo.HandlerFunc
+	AuthenticateOptional(next echo.HandlerFunc) echo.HandlerFunc
+	RequireRole(role string) echo.MiddlewareFunc
 }
 
 func New(e *echo.Echo, u *dependency.Usecases) Middlewares {
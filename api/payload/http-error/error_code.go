@@ -11,4 +11,8 @@ const (
 	UnauthorizedCode        = "UNAUTHORIZED"
 	ForbiddenCode           = "FORBIDDEN"
 	NotFoundCode            = "PATH_NOT_FOUND"
+	BlockedCode             = "USER_BLOCKED"
+	ExpiredCode             = "LINK_EXPIRED"
+	StaleDevicesCode        = "STALE_DEVICES"
+	RateLimitedCode         = "RATE_LIMITED"
 )
@@ -53,6 +53,42 @@ func Forbidden(msg string) *Error {
 	return CustomError(httpCode, errorCode, message)
 }
 
+func Blocked(msg string) *Error {
+	httpCode := http.StatusForbidden
+	errorCode := BlockedCode
+	message := msg
+	return CustomError(httpCode, errorCode, message)
+}
+
+func Expired(msg string) *Error {
+	httpCode := http.StatusGone
+	errorCode := ExpiredCode
+	message := msg
+	return CustomError(httpCode, errorCode, message)
+}
+
+// The client encrypted for a device list that no longer matches the server's
+func StaleDevices(msg string) *Error {
+	httpCode := http.StatusConflict
+	errorCode := StaleDevicesCode
+	message := msg
+	return CustomError(httpCode, errorCode, message)
+}
+
+func DataExisted(msg string) *Error {
+	httpCode := http.StatusConflict
+	errorCode := DataExistedCode
+	message := msg
+	return CustomError(httpCode, errorCode, message)
+}
+
+func TooManyRequests(msg string) *Error {
+	httpCode := http.StatusTooManyRequests
+	errorCode := RateLimitedCode
+	message := msg
+	return CustomError(httpCode, errorCode, message)
+}
+
 func InternalServerError(msg string) *Error {
 	httpCode := http.StatusInternalServerError
 	errorCode := InternalServerErrorCode
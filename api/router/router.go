@@ -7,6 +7,7 @@ import (
 	_ "gitlab.com/raihanlh/messenger-api/api/docs"
 	"gitlab.com/raihanlh/messenger-api/api/middleware"
 	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
 )
 
 func MapRoutes(e *echo.Echo, h *dependency.Handlers, mw middleware.Middlewares) {
@@ -18,18 +19,96 @@ func MapRoutes(e *echo.Echo, h *dependency.Handlers, mw middleware.Middlewares)
 
 	user := v1.Group("/user")
 	user.POST("/create", h.User.Create)
-	user.PATCH("/update/:id", h.User.Update, mw.AuthToken)
-	user.DELETE("/delete/:id", h.User.Delete, mw.AuthToken)
+	user.PATCH("/update/:id", h.User.Update, mw.Authenticate)
+	user.DELETE("/delete/:id", h.User.Delete, mw.Authenticate)
+	user.PUT("/:id/role", h.User.UpdateRole, mw.Authenticate, mw.RequireRole(model.RoleAdmin))
+	user.DELETE("/:id/sessions", h.User.RevokeSessions, mw.Authenticate, mw.RequireRole(model.RoleModerator))
+	user.POST("/deletions/:id/cancel", h.User.CancelPurge)
 	user.GET("/:id", h.User.GetById)
-	user.GET("s", h.User.GetAll)
+	user.GET("s", h.User.GetAll, mw.AuthenticateOptional)
 	user.POST("/login", h.User.Login)
 	user.GET("", h.User.GetByToken, mw.Authenticate)
+	user.GET("/blocked", h.Block.GetAll, mw.Authenticate)
+	user.POST("/:id/block", h.Block.Block, mw.Authenticate)
+	user.DELETE("/:id/block", h.Block.Unblock, mw.Authenticate)
+	user.POST("/:id/prekey-bundles", h.Device.ClaimBundles, mw.Authenticate)
+
+	auth := v1.Group("/auth")
+	auth.POST("/refresh", h.User.Refresh)
+	auth.POST("/logout", h.User.Logout, mw.AuthenticateOptional)
+
+	contacts := v1.Group("/contacts")
+	contacts.GET("", h.Contact.GetAll, mw.Authenticate)
+	contacts.POST("", h.Contact.Add, mw.Authenticate)
+	contacts.POST("/import", h.Contact.Import, mw.Authenticate)
+	contacts.PATCH("/:id", h.Contact.UpdateNickname, mw.Authenticate)
+	contacts.DELETE("/:id", h.Contact.Remove, mw.Authenticate)
+
+	me := v1.Group("/me")
+	me.GET("/unread", h.Message.GetUnreadSummary, mw.Authenticate)
+	me.POST("/data-exports", h.DataExport.Request, mw.Authenticate)
+	me.GET("/data-exports/:id", h.DataExport.GetById, mw.Authenticate)
+	// Authorized by the token in the download url, so the link works outside the app
+	me.GET("/data-exports/:id/download", h.DataExport.Download)
+	me.POST("/push-tokens", h.Notification.RegisterToken, mw.Authenticate)
+	me.DELETE("/push-tokens", h.Notification.UnregisterToken, mw.Authenticate)
+	me.GET("/email-digest", h.Digest.GetSettings, mw.Authenticate)
+	me.PUT("/email-digest", h.Digest.UpdateSettings, mw.Authenticate)
+	me.GET("/sessions", h.Session.GetAll, mw.Authenticate)
+	me.DELETE("/sessions", h.Session.RevokeOthers, mw.Authenticate)
+	me.DELETE("/sessions/:id", h.Session.Revoke, mw.Authenticate)
+
+	devices := v1.Group("/devices")
+	devices.POST("", h.Device.Register, mw.Authenticate)
+	devices.GET("", h.Device.GetAll, mw.Authenticate)
+	devices.DELETE("/:id", h.Device.Remove, mw.Authenticate)
+	devices.PUT("/:id/signed-prekey", h.Device.RotateSignedPrekey, mw.Authenticate)
+	devices.POST("/:id/prekeys", h.Device.UploadPrekeys, mw.Authenticate)
+
+	webhooks := v1.Group("/webhooks")
+	webhooks.POST("", h.Webhook.Create, mw.Authenticate)
+	webhooks.GET("", h.Webhook.GetAll, mw.Authenticate)
+	webhooks.DELETE("/:id", h.Webhook.Remove, mw.Authenticate)
+	webhooks.GET("/:id/deliveries", h.Webhook.GetDeliveries, mw.Authenticate)
+	webhooks.POST("/:id/deliveries/:delivery_id/redeliver", h.Webhook.Redeliver, mw.Authenticate)
+
+	bots := v1.Group("/bots")
+	bots.POST("", h.Bot.Create, mw.Authenticate)
+	bots.GET("", h.Bot.GetAll, mw.Authenticate)
+	bots.PUT("/:id", h.Bot.Update, mw.Authenticate)
+	bots.DELETE("/:id", h.Bot.Remove, mw.Authenticate)
+	bots.POST("/:id/keys", h.Bot.CreateKey, mw.Authenticate)
+	bots.GET("/:id/keys", h.Bot.GetKeys, mw.Authenticate)
+	bots.DELETE("/:id/keys/:key_id", h.Bot.RevokeKey, mw.Authenticate)
+
+	// Authorized by the token in the url, it's the secret an integration posts with
+	hooks := v1.Group("/hooks")
+	hooks.POST("/:token", h.IncomingHook.Post)
 
 	messages := v1.Group("/messages")
 	messages.POST("", h.Message.Create, mw.Authenticate)
+	messages.DELETE("/:id", h.Message.Delete, mw.Authenticate)
 
 	conversations := v1.Group("/conversations")
 	conversations.GET("/:convo_id/messages", h.Message.GetByConversationId, mw.Authenticate)
+	conversations.GET("/:convo_id/messages/search", h.Message.Search, mw.Authenticate)
+	conversations.GET("/:convo_id/export", h.Message.Export, mw.Authenticate)
+	conversations.PUT("/:convo_id/draft", h.Draft.Save, mw.Authenticate)
+	conversations.GET("/:convo_id/draft", h.Draft.Get, mw.Authenticate)
+	conversations.PUT("/:convo_id/archive", h.Conversation.Archive, mw.Authenticate)
+	conversations.PUT("/:convo_id/mute", h.Conversation.Mute, mw.Authenticate)
+	conversations.PUT("/:convo_id/pin", h.Conversation.Pin, mw.Authenticate)
+	conversations.DELETE("/:convo_id/history", h.Conversation.Clear, mw.Authenticate)
+	conversations.GET("/requests", h.Conversation.GetRequests, mw.Authenticate)
+	conversations.POST("/requests/:convo_id/accept", h.Conversation.AcceptRequest, mw.Authenticate)
+	conversations.POST("/requests/:convo_id/decline", h.Conversation.DeclineRequest, mw.Authenticate)
+	conversations.POST("/requests/:convo_id/block", h.Conversation.DeclineAndBlockRequest, mw.Authenticate)
+	conversations.POST("/:convo_id/incoming-webhooks", h.IncomingHook.Create, mw.Authenticate)
+	conversations.GET("/:convo_id/incoming-webhooks", h.IncomingHook.GetAll, mw.Authenticate)
+	conversations.DELETE("/:convo_id/incoming-webhooks/:id", h.IncomingHook.Revoke, mw.Authenticate)
+	conversations.POST("/:convo_id/commands", h.Command.Create, mw.Authenticate)
+	conversations.GET("/:convo_id/commands", h.Command.GetAll, mw.Authenticate)
+	conversations.DELETE("/:convo_id/commands/:id", h.Command.Remove, mw.Authenticate)
 	conversations.GET("/:convo_id", h.Conversation.GetById, mw.Authenticate)
 	conversations.GET("", h.Conversation.GetAllByUserId, mw.Authenticate)
 }
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"gitlab.com/raihanlh/messenger-api/config"
+	"gitlab.com/raihanlh/messenger-api/internal/app"
+	inboxRepository "gitlab.com/raihanlh/messenger-api/internal/domain/inbox/repository"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+)
+
+// Rebuilds every inbox row from conversations and messages, e.g. after the initial migration
+// or when the inbox has drifted from the source tables
+func main() {
+	conf := config.New()
+
+	db := postgres.New(conf.DBHost, conf.DBPort, conf.DBUser, conf.DBPassword, conf.DBName, conf.DBTimezone)
+	encryption := app.NewEncryption(conf)
+	rows, err := inboxRepository.New(db, !encryption.MessagePlaintextAllowed()).Rebuild(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("Inbox rebuilt, %d rows written\n", rows)
+}
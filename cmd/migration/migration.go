@@ -24,5 +24,13 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+
+	// Download tokens of data exports are stored hashed, drop the plaintext ones
+	migrator := db.Migrator()
+	if migrator.HasColumn(&model.DataExport{}, "download_token") {
+		if err := migrator.DropColumn(&model.DataExport{}, "download_token"); err != nil {
+			panic(err)
+		}
+	}
 	log.Println("Migration success")
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"gitlab.com/raihanlh/messenger-api/config"
+	"gitlab.com/raihanlh/messenger-api/internal/app"
+	inboxRepository "gitlab.com/raihanlh/messenger-api/internal/domain/inbox/repository"
+	messageRepository "gitlab.com/raihanlh/messenger-api/internal/domain/message/repository"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+)
+
+// Moves every message to MESSAGE_ACTIVE_KEY_ID, plaintext messages included, one batch
+// per transaction so it can be stopped and resumed. The search index and the inbox
+// previews are brought in line with MESSAGE_SEARCH_INDEX afterwards.
+func main() {
+	batchSize := flag.Int("batch-size", 500, "messages re-encrypted per transaction")
+	flag.Parse()
+
+	conf := config.New()
+	encryption := app.NewEncryption(conf)
+	if !encryption.MessageKeys.Enabled() {
+		log.Fatal("MESSAGE_ACTIVE_KEY_ID is not set, there is no key to re-encrypt with")
+	}
+
+	ctx := context.Background()
+	db := postgres.New(conf.DBHost, conf.DBPort, conf.DBUser, conf.DBPassword, conf.DBName, conf.DBTimezone)
+	messages := messageRepository.New(db, encryption.MessageKeys, encryption.MessageSearchIndex)
+
+	var total int
+	var lastId string
+	for {
+		next, count, err := messages.ReencryptBatch(ctx, lastId, *batchSize)
+		if err != nil {
+			panic(err)
+		}
+		if count == 0 {
+			break
+		}
+		total += count
+		lastId = next
+		log.Printf("Re-encrypted %d messages, up to %s\n", total, lastId)
+	}
+
+	if !encryption.MessageSearchIndex {
+		dropped, err := messages.DropSearchIndex(ctx)
+		if err != nil {
+			panic(err)
+		}
+		log.Printf("Search index is off, dropped %d entries\n", dropped)
+	}
+
+	rows, err := inboxRepository.New(db, !encryption.MessagePlaintextAllowed()).Rebuild(ctx)
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("Re-encryption done, %d messages under key %s, %d inbox rows rebuilt\n", total, encryption.MessageKeys.ActiveKeyID(), rows)
+}
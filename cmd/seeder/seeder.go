@@ -14,6 +14,7 @@ import (
 	"gitlab.com/raihanlh/messenger-api/config"
 	"gitlab.com/raihanlh/messenger-api/internal/constant"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/internal/utils"
 	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
 	"gorm.io/gorm"
 )
@@ -34,13 +35,24 @@ func (s Seed) UserSeed() {
 		log.Printf("cannot read file, err: %s", err)
 	}
 
-	var users []*model.User
+	var users []*userSeed
 	err = json.Unmarshal(data, &users)
 	if err != nil {
 		log.Printf("cannot unmarshal json, err: %s", err)
 	}
 
-	for _, user := range users {
+	for _, entry := range users {
+		hashedPassword, err := utils.HashPassword(entry.Password)
+		if err != nil {
+			log.Printf("cannot hash password of %s, err: %s", entry.Email, err)
+			continue
+		}
+		user := &model.User{
+			Name:     entry.Name,
+			Email:    entry.Email,
+			Password: string(hashedPassword),
+			Role:     entry.Role,
+		}
 		result := s.db.Debug().Table(constant.UserTable).Create(&user)
 		if err := result.Error; err != nil {
 			log.Printf("cannot seed apps table: %v", err)
@@ -48,6 +60,14 @@ func (s Seed) UserSeed() {
 	}
 }
 
+// model.User doesn't read the password from json, the seed file has it in plain text
+type userSeed struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
 func GetSourcePath() string {
 	_, filename, _, _ := runtime.Caller(1)
 	return path.Dir(filename)
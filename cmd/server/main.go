@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
@@ -33,9 +34,13 @@ func main() {
 	logger.Setup(conf)
 
 	databases := app.NewDatabases(conf)
-	repositories := app.NewRepositories(databases)
-	usecases := app.NewUsecases(repositories)
+	storages := app.NewStorages(conf)
+	encryption := app.NewEncryption(conf)
+	gateways := app.NewGateways(conf)
+	repositories := app.NewRepositories(conf, databases, encryption)
+	usecases := app.NewUsecases(repositories, storages, gateways)
 	handlers := app.NewHandlers(usecases)
+	app.StartJobs(context.Background(), usecases)
 
 	e := echo.New()
 
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -17,6 +19,54 @@ type Config struct {
 	DBTimezone string `mapstructure:"DB_TIMEZONE"`
 	Env        string `mapstructure:"ENV"`
 	Debug      bool   `mapstructure:"DEBUG"`
+
+	StoragePath       string        `mapstructure:"STORAGE_PATH"`
+	DataExportLinkTTL time.Duration `mapstructure:"DATA_EXPORT_LINK_TTL"`
+
+	AccountPurgeGracePeriod time.Duration `mapstructure:"ACCOUNT_PURGE_GRACE_PERIOD"`
+
+	// Comma separated id:base64 AES-256 keys, new messages are encrypted with the active one
+	MessageKeys        string `mapstructure:"MESSAGE_KEYS"`
+	MessageActiveKeyID string `mapstructure:"MESSAGE_ACTIVE_KEY_ID"`
+	MessageSearchIndex bool   `mapstructure:"MESSAGE_SEARCH_INDEX"`
+
+	// Push providers, a platform is disabled when its endpoint is empty
+	FCMEndpoint    string `mapstructure:"FCM_ENDPOINT"`
+	FCMProjectID   string `mapstructure:"FCM_PROJECT_ID"`
+	FCMAccessToken string `mapstructure:"FCM_ACCESS_TOKEN"`
+	APNSEndpoint   string `mapstructure:"APNS_ENDPOINT"`
+	APNSTopic      string `mapstructure:"APNS_TOPIC"`
+	APNSAuthToken  string `mapstructure:"APNS_AUTH_TOKEN"`
+	// Include message text in notifications instead of a generic body
+	PushShowPreview bool `mapstructure:"PUSH_SHOW_PREVIEW"`
+	// Users seen within this window are online and don't get notified
+	PushOnlineWindow time.Duration `mapstructure:"PUSH_ONLINE_WINDOW"`
+
+	// Email is disabled when the host is empty
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     string `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom     string `mapstructure:"SMTP_FROM"`
+	// Messages unread for this long are included in the email digest
+	DigestDelay time.Duration `mapstructure:"DIGEST_DELAY"`
+
+	// How long a webhook receiver has to respond
+	WebhookTimeout time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	// How long a custom slash command has to reply, the sender is waiting
+	SlashCommandTimeout time.Duration `mapstructure:"SLASH_COMMAND_TIMEOUT"`
+	// Let webhooks, bot callbacks and slash commands reach private and loopback addresses,
+	// only meant for local development
+	OutboundAllowPrivate bool `mapstructure:"OUTBOUND_ALLOW_PRIVATE"`
+
+	// Access tokens are short-lived and renewed with the refresh token
+	AccessTokenTTL time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	// How long a login lasts at most, refreshing doesn't extend it
+	RefreshTokenTTL time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+	// A session unused for this long can't be refreshed anymore
+	SessionIdleTimeout time.Duration `mapstructure:"SESSION_IDLE_TIMEOUT"`
+	// Where logged out tokens are kept, "postgres" or "memory"
+	TokenRevocationStore string `mapstructure:"TOKEN_REVOCATION_STORE"`
 }
 
 func Setup() {
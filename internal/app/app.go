@@ -3,17 +3,76 @@ package app
 import (
 	"gitlab.com/raihanlh/messenger-api/config"
 	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	blockHandler "gitlab.com/raihanlh/messenger-api/internal/domain/block/delivery/handler"
+	blockRepository "gitlab.com/raihanlh/messenger-api/internal/domain/block/repository"
+	blockUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/block/usecase"
+	botHandler "gitlab.com/raihanlh/messenger-api/internal/domain/bot/delivery/handler"
+	botRepository "gitlab.com/raihanlh/messenger-api/internal/domain/bot/repository"
+	botUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/bot/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/builtin"
+	commandHandler "gitlab.com/raihanlh/messenger-api/internal/domain/command/delivery/handler"
+	commandRepository "gitlab.com/raihanlh/messenger-api/internal/domain/command/repository"
+	commandUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/command/usecase"
+	contactHandler "gitlab.com/raihanlh/messenger-api/internal/domain/contact/delivery/handler"
+	contactRepository "gitlab.com/raihanlh/messenger-api/internal/domain/contact/repository"
+	contactUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/contact/usecase"
 	conversationHandler "gitlab.com/raihanlh/messenger-api/internal/domain/conversation/delivery/handler"
 	conversationRepository "gitlab.com/raihanlh/messenger-api/internal/domain/conversation/repository"
 	conversationUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/conversation/usecase"
+	dataexportHandler "gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/delivery/handler"
+	dataexportRepository "gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/repository"
+	dataexportUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/usecase"
+	deviceHandler "gitlab.com/raihanlh/messenger-api/internal/domain/device/delivery/handler"
+	deviceRepository "gitlab.com/raihanlh/messenger-api/internal/domain/device/repository"
+	deviceUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/device/usecase"
+	digestHandler "gitlab.com/raihanlh/messenger-api/internal/domain/digest/delivery/handler"
+	digestRepository "gitlab.com/raihanlh/messenger-api/internal/domain/digest/repository"
+	digestUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/digest/usecase"
+	draftHandler "gitlab.com/raihanlh/messenger-api/internal/domain/draft/delivery/handler"
+	draftRepository "gitlab.com/raihanlh/messenger-api/internal/domain/draft/repository"
+	draftUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/draft/usecase"
+	inboxRepository "gitlab.com/raihanlh/messenger-api/internal/domain/inbox/repository"
+	incominghookHandler "gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/delivery/handler"
+	incominghookRepository "gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/repository"
+	incominghookUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/usecase"
 	messageHandler "gitlab.com/raihanlh/messenger-api/internal/domain/message/delivery/handler"
 	messageRepository "gitlab.com/raihanlh/messenger-api/internal/domain/message/repository"
 	messageUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/message/usecase"
+	notificationHandler "gitlab.com/raihanlh/messenger-api/internal/domain/notification/delivery/handler"
+	notificationRepository "gitlab.com/raihanlh/messenger-api/internal/domain/notification/repository"
+	notificationUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/notification/usecase"
+	outboxRepository "gitlab.com/raihanlh/messenger-api/internal/domain/outbox/repository"
+	outboxUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/outbox/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/revocation"
+	revocationRepository "gitlab.com/raihanlh/messenger-api/internal/domain/revocation/repository"
+	revocationUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/revocation/usecase"
+	sessionHandler "gitlab.com/raihanlh/messenger-api/internal/domain/session/delivery/handler"
+	sessionRepository "gitlab.com/raihanlh/messenger-api/internal/domain/session/repository"
+	sessionUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/session/usecase"
 	userHandler "gitlab.com/raihanlh/messenger-api/internal/domain/user/delivery/handler"
 	userRepository "gitlab.com/raihanlh/messenger-api/internal/domain/user/repository"
 	userUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/user/usecase"
+	webhookHandler "gitlab.com/raihanlh/messenger-api/internal/domain/webhook/delivery/handler"
+	webhookRepository "gitlab.com/raihanlh/messenger-api/internal/domain/webhook/repository"
+	webhookUsecase "gitlab.com/raihanlh/messenger-api/internal/domain/webhook/usecase"
 	healthHandler "gitlab.com/raihanlh/messenger-api/internal/health/handler"
+	"gitlab.com/raihanlh/messenger-api/pkg/cipher"
+	"gitlab.com/raihanlh/messenger-api/pkg/mail"
+	"gitlab.com/raihanlh/messenger-api/pkg/mail/smtp"
+	"gitlab.com/raihanlh/messenger-api/pkg/netguard"
 	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+	"gitlab.com/raihanlh/messenger-api/pkg/push/apns"
+	"gitlab.com/raihanlh/messenger-api/pkg/push/fcm"
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
+	"gitlab.com/raihanlh/messenger-api/pkg/storage/local"
+	"gitlab.com/raihanlh/messenger-api/pkg/webhook"
+)
+
+// Values of TOKEN_REVOCATION_STORE
+const (
+	TokenRevocationStorePostgres = "postgres"
+	TokenRevocationStoreMemory   = "memory"
 )
 
 // Initiate databases
@@ -23,22 +82,113 @@ func NewDatabases(config *config.Config) *dependency.Databases {
 	}
 }
 
+// Initiate storages
+func NewStorages(config *config.Config) *dependency.Storages {
+	return &dependency.Storages{
+		File: local.New(config.StoragePath),
+	}
+}
+
+// Initiate encryption keys and policies
+func NewEncryption(config *config.Config) *dependency.Encryption {
+	keys, err := cipher.ParseKeys(config.MessageKeys)
+	if err != nil {
+		panic(err)
+	}
+	keyring, err := cipher.NewKeyring(config.MessageActiveKeyID, keys)
+	if err != nil {
+		panic(err)
+	}
+	return &dependency.Encryption{
+		MessageKeys:        keyring,
+		MessageSearchIndex: config.MessageSearchIndex,
+	}
+}
+
+// Initiate gateways, push platforms without a configured provider and email without an SMTP host are skipped
+func NewGateways(config *config.Config) *dependency.Gateways {
+	providers := map[string]push.Provider{}
+	if config.FCMEndpoint != "" {
+		providers[push.PlatformAndroid] = fcm.New(config.FCMEndpoint, config.FCMProjectID, config.FCMAccessToken)
+	}
+	if config.APNSEndpoint != "" {
+		providers[push.PlatformIOS] = apns.New(config.APNSEndpoint, config.APNSTopic, config.APNSAuthToken)
+	}
+	var mailer mail.Mailer
+	if config.SMTPHost != "" {
+		mailer = smtp.New(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.SMTPFrom)
+	}
+	guard := netguard.New(config.OutboundAllowPrivate)
+	return &dependency.Gateways{
+		Push:             push.NewGateway(providers),
+		PushShowPreview:  config.PushShowPreview,
+		PushOnlineWindow: config.PushOnlineWindow,
+		Mail:             mailer,
+		DigestDelay:      config.DigestDelay,
+		Webhook:          webhook.NewClient(config.WebhookTimeout, guard),
+		Commands:         builtin.Registry(),
+		SlashCommands:    slash.NewClient(config.SlashCommandTimeout, guard),
+		URLGuard:         guard,
+	}
+}
+
 // Initiate repositories
-func NewRepositories(db *dependency.Databases) *dependency.Repositories {
+func NewRepositories(config *config.Config, db *dependency.Databases, e *dependency.Encryption) *dependency.Repositories {
 	return &dependency.Repositories{
+		Transactor:   postgres.NewTransactor(db.Main),
 		User:         userRepository.New(db.Main),
-		Message:      messageRepository.New(db.Main),
-		Conversation: conversationRepository.New(db.Main),
+		Message:      messageRepository.New(db.Main, e.MessageKeys, e.MessageSearchIndex),
+		Conversation: conversationRepository.New(db.Main, e.MessageKeys),
+		Draft:        draftRepository.New(db.Main),
+		Block:        blockRepository.New(db.Main),
+		Contact:      contactRepository.New(db.Main),
+		DataExport:   dataexportRepository.New(db.Main),
+		Inbox:        inboxRepository.New(db.Main, !e.MessagePlaintextAllowed()),
+		Device:       deviceRepository.New(db.Main),
+		Notification: notificationRepository.New(db.Main),
+		Digest:       digestRepository.New(db.Main),
+		Webhook:      webhookRepository.New(db.Main),
+		Bot:          botRepository.New(db.Main),
+		IncomingHook: incominghookRepository.New(db.Main),
+		Command:      commandRepository.New(db.Main),
+		Outbox:       outboxRepository.New(db.Main),
+		Session:      sessionRepository.New(db.Main),
+		Revocation:   newRevocationList(config, db),
+	}
+}
+
+// Revoked tokens are kept in Postgres unless TOKEN_REVOCATION_STORE is "memory", which
+// only works with a single instance
+func newRevocationList(config *config.Config, db *dependency.Databases) revocation.Repository {
+	if config.TokenRevocationStore == TokenRevocationStoreMemory {
+		return revocationRepository.NewMemory()
 	}
+	return revocationRepository.New(db.Main)
 }
 
 // Initiate Usecases
-func NewUsecases(r *dependency.Repositories) *dependency.Usecases {
-	return &dependency.Usecases{
+func NewUsecases(r *dependency.Repositories, s *dependency.Storages, g *dependency.Gateways) *dependency.Usecases {
+	u := &dependency.Usecases{
 		User:         userUsecase.New(r),
-		Message:      messageUsecase.New(r),
+		Message:      messageUsecase.New(r, g),
 		Conversation: conversationUsecase.New(r),
+		Draft:        draftUsecase.New(r),
+		Block:        blockUsecase.New(r),
+		Contact:      contactUsecase.New(r),
+		DataExport:   dataexportUsecase.New(r, s),
+		Device:       deviceUsecase.New(r),
+		Notification: notificationUsecase.New(r, g),
+		Digest:       digestUsecase.New(r, g),
+		Webhook:      webhookUsecase.New(r, g),
+		Bot:          botUsecase.New(r, g),
+		IncomingHook: incominghookUsecase.New(r),
+		Command:      commandUsecase.New(r, g),
+		Session:      sessionUsecase.New(r),
+		Revocation:   revocationUsecase.New(r),
 	}
+	// Subscribers are usecases themselves, so the bus is built once they exist
+	u.Outbox = outboxUsecase.New(r, NewEventBus(u))
+	return u
 }
 
 // Initiate repositories
@@ -48,5 +198,17 @@ func NewHandlers(u *dependency.Usecases) *dependency.Handlers {
 		Health:       healthHandler.New(),
 		Message:      messageHandler.New(u),
 		Conversation: conversationHandler.New(u),
+		Draft:        draftHandler.New(u),
+		Block:        blockHandler.New(u),
+		Contact:      contactHandler.New(u),
+		DataExport:   dataexportHandler.New(u),
+		Device:       deviceHandler.New(u),
+		Notification: notificationHandler.New(u),
+		Digest:       digestHandler.New(u),
+		Webhook:      webhookHandler.New(u),
+		Bot:          botHandler.New(u),
+		IncomingHook: incominghookHandler.New(u),
+		Command:      commandHandler.New(u),
+		Session:      sessionHandler.New(u),
 	}
 }
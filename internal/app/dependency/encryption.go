@@ -0,0 +1,16 @@
+package dependency
+
+import "gitlab.com/raihanlh/messenger-api/pkg/cipher"
+
+// Keys and policies for data encrypted at rest
+type Encryption struct {
+	MessageKeys *cipher.Keyring
+	// Allows keeping a plaintext copy of message text to search it
+	MessageSearchIndex bool
+}
+
+// Message text may be kept in plaintext elsewhere, e.g. inbox previews, when it isn't
+// encrypted in the first place or the search index policy allows it
+func (e *Encryption) MessagePlaintextAllowed() bool {
+	return !e.MessageKeys.Enabled() || e.MessageSearchIndex
+}
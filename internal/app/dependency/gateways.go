@@ -1,4 +1,29 @@
 package dependency
 
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/pkg/mail"
+	"gitlab.com/raihanlh/messenger-api/pkg/netguard"
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
+	"gitlab.com/raihanlh/messenger-api/pkg/webhook"
+)
+
 type Gateways struct {
+	Push push.Gateway
+	// Include message text in notifications, e2e messages never are
+	PushShowPreview bool
+	// Users seen within this window are online and aren't notified
+	PushOnlineWindow time.Duration
+	// Nil when email isn't configured
+	Mail mail.Mailer
+	// Messages unread for this long are emailed in a digest
+	DigestDelay time.Duration
+	Webhook     webhook.Sender
+	// Built-in slash commands, custom ones are called through SlashCommands
+	Commands      *slash.Registry
+	SlashCommands slash.Caller
+	// Checks the URLs users register for webhooks, bot callbacks and slash commands
+	URLGuard *netguard.Guard
 }
@@ -1,9 +1,21 @@
 package dependency
 
 import (
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/message"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook"
 	"gitlab.com/raihanlh/messenger-api/internal/health"
 )
 
@@ -12,4 +24,16 @@ type Handlers struct {
 	Health       health.Handler
 	Message      message.Handler
 	Conversation conversation.Handler
+	Draft        draft.Handler
+	Block        block.Handler
+	Contact      contact.Handler
+	DataExport   dataexport.Handler
+	Device       device.Handler
+	Notification notification.Handler
+	Digest       digest.Handler
+	Webhook      webhook.Handler
+	Bot          bot.Handler
+	IncomingHook incominghook.Handler
+	Command      command.Handler
+	Session      session.Handler
 }
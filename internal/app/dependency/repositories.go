@@ -1,14 +1,47 @@
 package dependency
 
 import (
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/inbox"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/message"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/outbox"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/revocation"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
 )
 
 // Add repositories here
 type Repositories struct {
+	// Runs several repository calls in one database transaction
+	Transactor   postgres.Transactor
 	User         user.Repository
 	Message      message.Repository
 	Conversation conversation.Repository
+	Draft        draft.Repository
+	Block        block.Repository
+	Contact      contact.Repository
+	DataExport   dataexport.Repository
+	Inbox        inbox.Repository
+	Device       device.Repository
+	Notification notification.Repository
+	Digest       digest.Repository
+	Webhook      webhook.Repository
+	Bot          bot.Repository
+	IncomingHook incominghook.Repository
+	Command      command.Repository
+	Outbox       outbox.Repository
+	Session      session.Repository
+	Revocation   revocation.Repository
 }
@@ -1,4 +1,7 @@
 package dependency
 
+import "gitlab.com/raihanlh/messenger-api/pkg/storage"
+
 type Storages struct {
+	File storage.Storage
 }
@@ -1,9 +1,23 @@
 package dependency
 
 import (
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/message"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/outbox"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/revocation"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook"
 )
 
 // Add usecases here
@@ -11,4 +25,18 @@ type Usecases struct {
 	User         user.Usecase
 	Message      message.Usecase
 	Conversation conversation.Usecase
+	Draft        draft.Usecase
+	Block        block.Usecase
+	Contact      contact.Usecase
+	DataExport   dataexport.Usecase
+	Device       device.Usecase
+	Notification notification.Usecase
+	Digest       digest.Usecase
+	Webhook      webhook.Usecase
+	Bot          bot.Usecase
+	IncomingHook incominghook.Usecase
+	Command      command.Usecase
+	Outbox       outbox.Usecase
+	Session      session.Usecase
+	Revocation   revocation.Usecase
 }
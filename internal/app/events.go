@@ -0,0 +1,19 @@
+package app
+
+import (
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+// NewEventBus subscribes the usecases to the domain events read from the outbox.
+// Subscriber names are stored on events that failed, renaming one drops its retries.
+func NewEventBus(u *dependency.Usecases) *eventbus.Bus {
+	bus := eventbus.New()
+	bus.Subscribe(model.EventMessageCreated, "push", u.Notification.QueuePush)
+	bus.Subscribe(model.EventMessageCreated, "webhooks", u.Webhook.QueueDeliveries)
+	bus.Subscribe(model.EventConversationCreated, "webhooks", u.Webhook.QueueDeliveries)
+	bus.Subscribe(model.EventUserRegistered, "webhooks", u.Webhook.QueueDeliveries)
+	bus.Subscribe(model.EventUserDeleted, "data-exports", u.DataExport.DeleteUserArchives)
+	return bus
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// How often deleted accounts are checked for messages due to be purged
+const AccountPurgeInterval = time.Hour
+
+// How often queued push notifications are sent
+const PushInterval = 5 * time.Second
+
+// How often users are checked for unread messages to email
+const DigestInterval = 15 * time.Minute
+
+// How often due webhook deliveries are sent
+const WebhookInterval = 5 * time.Second
+
+// How often domain events are read from the outbox
+const OutboxInterval = time.Second
+
+// How often expired tokens are dropped from the revocation list
+const RevocationPurgeInterval = time.Hour
+
+// How often interrupted data export builds are failed and expired archives deleted
+const DataExportCleanupInterval = 10 * time.Minute
+
+// Start background jobs, they run until ctx is cancelled
+func StartJobs(ctx context.Context, u *dependency.Usecases) {
+	go runEvery(ctx, AccountPurgeInterval, "purge deleted accounts", u.User.PurgeDeletedAccounts)
+	go runEvery(ctx, PushInterval, "send push notifications", u.Notification.SendPending)
+	go runEvery(ctx, DigestInterval, "send email digests", u.Digest.SendDigests)
+	go runEvery(ctx, WebhookInterval, "deliver webhooks", u.Webhook.DeliverPending)
+	go runEvery(ctx, OutboxInterval, "dispatch events", u.Outbox.DispatchPending)
+	go runEvery(ctx, RevocationPurgeInterval, "purge revoked tokens", u.Revocation.PurgeExpired)
+	go runEvery(ctx, DataExportCleanupInterval, "clean up data exports", u.DataExport.CleanUp)
+}
+
+func runEvery(ctx context.Context, interval time.Duration, name string, job func(ctx context.Context) error) {
+	log := logger.GetLogger(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil {
+			log.Error("Failed to "+name+": ", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -5,4 +5,27 @@ const (
 	MessageTable string = "messages"
 	ConversationTable string = "conversations"
 	UserParticipantTable string = "user_participants"
+	DraftTable string = "drafts"
+	BlockTable string = "blocks"
+	ContactTable string = "contacts"
+	DataExportTable string = "data_exports"
+	AccountDeletionTable string = "account_deletions"
+	InboxTable string = "inboxes"
+	MessageSearchEntryTable string = "message_search_entries"
+	DeviceTable string = "devices"
+	OneTimePrekeyTable string = "one_time_prekeys"
+	MessageDeviceContentTable string = "message_device_contents"
+	PushTokenTable string = "push_tokens"
+	PushJobTable string = "push_jobs"
+	DigestedMessageTable string = "digested_messages"
+	WebhookTable string = "webhooks"
+	WebhookDeliveryTable string = "webhook_deliveries"
+	BotTable string = "bots"
+	APIKeyTable string = "api_keys"
+	IncomingWebhookTable string = "incoming_webhooks"
+	SlashCommandTable string = "slash_commands"
+	OutboxEventTable string = "outbox_events"
+	SessionTable string = "sessions"
+	RefreshTokenTable string = "refresh_tokens"
+	RevokedTokenTable string = "revoked_tokens"
 )
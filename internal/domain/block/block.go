@@ -0,0 +1,28 @@
+package block
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	Create(ctx context.Context, block *model.Block) (*model.Block, error)
+	Delete(ctx context.Context, blockerId string, blockedId string) error
+	GetAllByBlockerId(ctx context.Context, blockerId string) ([]*model.Block, error)
+	IsBlocked(ctx context.Context, blockerId string, blockedId string) (bool, error)
+}
+
+type Usecase interface {
+	Block(ctx context.Context, req *payload.BlockRequest) (*payload.BlockResponse, error)
+	Unblock(ctx context.Context, req *payload.UnblockRequest) (*payload.UnblockResponse, error)
+	GetAll(ctx context.Context, req *payload.GetAllBlockedRequest) (*payload.GetAllBlockedResponse, error)
+}
+
+type Handler interface {
+	Block(ctx echo.Context) error
+	Unblock(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+}
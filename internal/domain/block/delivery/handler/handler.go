@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type BlockHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) block.Handler {
+	return &BlockHandler{
+		usecases: u,
+	}
+}
+
+// BlockUser godoc
+// @Summary Block User
+// @Description block a user, they can no longer send messages to the caller
+// @Tags Block
+// @Accept application/json
+// @Param id path string true "User ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.BlockResponse}
+// @Router /api/v1/user/{id}/block [post]
+func (h BlockHandler) Block(ctx echo.Context) error {
+	var body payload.BlockRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Block.Block(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to block user: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// UnblockUser godoc
+// @Summary Unblock User
+// @Description unblock a previously blocked user
+// @Tags Block
+// @Accept application/json
+// @Param id path string true "User ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.UnblockResponse}
+// @Router /api/v1/user/{id}/block [delete]
+func (h BlockHandler) Unblock(ctx echo.Context) error {
+	var body payload.UnblockRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Block.Unblock(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to unblock user: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetBlockedUsers godoc
+// @Summary Get Blocked Users
+// @Description get all users blocked by the caller
+// @Tags Block
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetAllBlockedResponse}
+// @Router /api/v1/user/blocked [get]
+func (h BlockHandler) GetAll(ctx echo.Context) error {
+	var body payload.GetAllBlockedRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Block.GetAll(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get blocked users: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
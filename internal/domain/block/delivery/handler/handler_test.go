@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gitlab.com/raihanlh/messenger-api/pkg/validator"
+	mock_block "gitlab.com/raihanlh/messenger-api/testing/mocks/block"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block/delivery/handler"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+func Test_BlockHandler_Block(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &model.User{Model: model.Model{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2"}}
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name            string
+		wantUsecaseResp *payload.BlockResponse
+		wantUsecaseErr  error
+		wantCode        int
+		want            string
+	}{
+		{
+			name:            "Block User Handler Success",
+			wantUsecaseResp: &payload.BlockResponse{Message: "Block user success"},
+			wantCode:        http.StatusOK,
+			want:            `{"status":"OK","data":{"message":"Block user success"}}`,
+		},
+		{
+			name:           "Block Unknown User",
+			wantUsecaseErr: http_error.RecordNotFound("user"),
+			wantCode:       http.StatusNotFound,
+		},
+		{
+			name:           "Block User Handler Failed",
+			wantUsecaseErr: errors.New("test error"),
+			wantCode:       http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validator.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/user/"+blockedId+"/block", nil)
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("id")
+			ctx.SetParamValues(blockedId)
+			ctx.Set("user", user)
+
+			blockUsecaseMock := mock_block.NewMockUsecase(ctrl)
+			blockUsecaseMock.EXPECT().Block(gomock.Any(), &payload.BlockRequest{UserID: user.ID, BlockedID: blockedId}).
+				Return(tt.wantUsecaseResp, tt.wantUsecaseErr)
+
+			blockHandler := handler.New(&dependency.Usecases{
+				Block: blockUsecaseMock,
+			})
+			err := blockHandler.Block(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantCode, rec.Code)
+				if tt.want != "" {
+					assert.Equal(t, tt.want, strings.Replace(rec.Body.String(), "\n", "", -1))
+				}
+			}
+		})
+	}
+}
+
+func Test_BlockHandler_Unblock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &model.User{Model: model.Model{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2"}}
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	e := echo.New()
+	e.Validator = validator.New()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/user/"+blockedId+"/block", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues(blockedId)
+	ctx.Set("user", user)
+
+	blockUsecaseMock := mock_block.NewMockUsecase(ctrl)
+	blockUsecaseMock.EXPECT().Unblock(gomock.Any(), &payload.UnblockRequest{UserID: user.ID, BlockedID: blockedId}).
+		Return(&payload.UnblockResponse{Message: "Unblock user success"}, nil)
+
+	blockHandler := handler.New(&dependency.Usecases{
+		Block: blockUsecaseMock,
+	})
+	err := blockHandler.Unblock(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"status":"OK","data":{"message":"Unblock user success"}}`, strings.Replace(rec.Body.String(), "\n", "", -1))
+	}
+}
@@ -0,0 +1,10 @@
+package payload
+
+type BlockRequest struct {
+	UserID    string `json:"-"`
+	BlockedID string `param:"id"`
+}
+
+type BlockResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,12 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type GetAllBlockedRequest struct {
+	UserID string `json:"-"`
+}
+
+type GetAllBlockedResponse struct {
+	Users   []*model.User `json:"users"`
+	Message string        `json:"message"`
+}
@@ -0,0 +1,10 @@
+package payload
+
+type UnblockRequest struct {
+	UserID    string `json:"-"`
+	BlockedID string `param:"id"`
+}
+
+type UnblockResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BlockRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) block.Repository {
+	return &BlockRepository{
+		DB: gormDB,
+	}
+}
+
+// Blocking someone twice is a no-op
+func (r BlockRepository) Create(ctx context.Context, block *model.Block) (*model.Block, error) {
+	result := postgres.Conn(ctx, r.DB).Model(block).Clauses(clause.OnConflict{DoNothing: true}).Create(block)
+	return block, result.Error
+}
+
+// Blocks are hard deleted so the pair can be blocked again later
+func (r BlockRepository) Delete(ctx context.Context, blockerId string, blockedId string) error {
+	result := r.DB.WithContext(ctx).Unscoped().Where("blocker_id = ? AND blocked_id = ?", blockerId, blockedId).Delete(&model.Block{})
+	return result.Error
+}
+
+func (r BlockRepository) GetAllByBlockerId(ctx context.Context, blockerId string) ([]*model.Block, error) {
+	var blocks []*model.Block
+	result := r.DB.WithContext(ctx).Preload("Blocked", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "photo_url")
+	}).Where("blocker_id = ?", blockerId).Order("created_at DESC").Find(&blocks)
+	return blocks, result.Error
+}
+
+func (r BlockRepository) IsBlocked(ctx context.Context, blockerId string, blockedId string) (bool, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).Table(constant.BlockTable).
+		Where("blocker_id = ? AND blocked_id = ? AND deleted_at IS NULL", blockerId, blockedId).Count(&count)
+	return count > 0, result.Error
+}
@@ -0,0 +1,173 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/block/repository"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type AnyTime struct{}
+
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_BlockRepository_Create(t *testing.T) {
+	db, mock := Setup()
+
+	query := `INSERT INTO "blocks" ("created_at","updated_at","deleted_at","blocker_id","blocked_id","id") VALUES ($1,$2,$3,$4,$5,$6) ON CONFLICT DO NOTHING RETURNING "id"`
+
+	blockerId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name      string
+		wantErrDB error
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "Create Block Success",
+			wantErr: assert.NoError,
+		},
+		{
+			name:      "Create Block Failed",
+			wantErrDB: errors.New("test error"),
+			wantErr:   assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			expect := mock.ExpectQuery(regexp.QuoteMeta(query)).
+				WithArgs(AnyTime{}, AnyTime{}, nil, blockerId, blockedId, sqlmock.AnyArg())
+			if tt.wantErrDB != nil {
+				expect.WillReturnError(tt.wantErrDB)
+				mock.ExpectRollback()
+			} else {
+				expect.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("b1"))
+				mock.ExpectCommit()
+			}
+
+			r := &repo.BlockRepository{
+				DB: db,
+			}
+			_, err := r.Create(context.TODO(), &model.Block{BlockerID: blockerId, BlockedID: blockedId})
+			tt.wantErr(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_BlockRepository_Delete(t *testing.T) {
+	db, mock := Setup()
+
+	blockerId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	// Unblocking removes the row instead of soft deleting it
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "blocks" WHERE blocker_id = $1 AND blocked_id = $2`)).
+		WithArgs(blockerId, blockedId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := &repo.BlockRepository{
+		DB: db,
+	}
+	err := r.Delete(context.TODO(), blockerId, blockedId)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_BlockRepository_GetAllByBlockerId(t *testing.T) {
+	db, mock := Setup()
+
+	blockerId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "blocks" WHERE blocker_id = $1 AND "blocks"."deleted_at" IS NULL ORDER BY created_at DESC`)).
+		WithArgs(blockerId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "blocker_id", "blocked_id"}).AddRow("b1", blockerId, blockedId))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","name","photo_url" FROM "users" WHERE "users"."id" = $1 AND "users"."deleted_at" IS NULL`)).
+		WithArgs(blockedId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "photo_url"}).AddRow(blockedId, "Bob", ""))
+
+	r := &repo.BlockRepository{
+		DB: db,
+	}
+	blocks, err := r.GetAllByBlockerId(context.TODO(), blockerId)
+	if assert.NoError(t, err) && assert.Len(t, blocks, 1) && assert.NotNil(t, blocks[0].Blocked) {
+		assert.Equal(t, "Bob", blocks[0].Blocked.Name)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_BlockRepository_IsBlocked(t *testing.T) {
+	db, mock := Setup()
+
+	query := `SELECT count(*) FROM "blocks" WHERE blocker_id = $1 AND blocked_id = $2 AND deleted_at IS NULL`
+
+	blockerId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name  string
+		count int
+		want  bool
+	}{
+		{
+			name:  "Blocked",
+			count: 1,
+			want:  true,
+		},
+		{
+			name:  "Not Blocked",
+			count: 0,
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(regexp.QuoteMeta(query)).
+				WithArgs(blockerId, blockedId).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.count))
+
+			r := &repo.BlockRepository{
+				DB: db,
+			}
+			got, err := r.IsBlocked(context.TODO(), blockerId, blockedId)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type BlockUsecase struct {
+	repositories *dependency.Repositories
+}
+
+func New(r *dependency.Repositories) block.Usecase {
+	return &BlockUsecase{
+		repositories: r,
+	}
+}
+
+func (u BlockUsecase) Block(ctx context.Context, req *payload.BlockRequest) (*payload.BlockResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if req.UserID == req.BlockedID {
+		return nil, http_error.BadRequest(errors.New("cannot block yourself"))
+	}
+	if _, err := u.repositories.User.GetById(ctx, req.BlockedID); err != nil {
+		log.Error("Failed to get user to block: ", zap.Error(err))
+		return nil, http_error.RecordNotFound("user")
+	}
+
+	_, err := u.repositories.Block.Create(ctx, &model.Block{
+		BlockerID: req.UserID,
+		BlockedID: req.BlockedID,
+	})
+	if err != nil {
+		log.Error("Failed to block user: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.BlockResponse{
+		Message: "Block user success",
+	}, nil
+}
+
+func (u BlockUsecase) Unblock(ctx context.Context, req *payload.UnblockRequest) (*payload.UnblockResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	err := u.repositories.Block.Delete(ctx, req.UserID, req.BlockedID)
+	if err != nil {
+		log.Error("Failed to unblock user: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.UnblockResponse{
+		Message: "Unblock user success",
+	}, nil
+}
+
+func (u BlockUsecase) GetAll(ctx context.Context, req *payload.GetAllBlockedRequest) (*payload.GetAllBlockedResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	blocks, err := u.repositories.Block.GetAllByBlockerId(ctx, req.UserID)
+	if err != nil {
+		log.Error("Failed to get blocked users: ", zap.Error(err))
+		return nil, err
+	}
+
+	users := make([]*model.User, 0, len(blocks))
+	for _, b := range blocks {
+		if b.Blocked != nil {
+			users = append(users, b.Blocked)
+		}
+	}
+
+	return &payload.GetAllBlockedResponse{
+		Users:   users,
+		Message: "Successfully get blocked users",
+	}, nil
+}
@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/block/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	mock_block "gitlab.com/raihanlh/messenger-api/testing/mocks/block"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+)
+
+func Test_BlockUsecase_Block(t *testing.T) {
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name         string
+		blockedId    string
+		userNotFound bool
+		wantCode     int
+	}{
+		{
+			name:      "Block User Success",
+			blockedId: blockedId,
+		},
+		{
+			name:      "Block Yourself",
+			blockedId: userId,
+			wantCode:  http.StatusBadRequest,
+		},
+		{
+			name:         "Block Unknown User",
+			blockedId:    blockedId,
+			userNotFound: true,
+			wantCode:     http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			if tt.blockedId != userId {
+				if tt.userNotFound {
+					userRepoMock.EXPECT().GetById(ctx, tt.blockedId).Return(nil, errors.New("record not found"))
+				} else {
+					userRepoMock.EXPECT().GetById(ctx, tt.blockedId).Return(&model.User{Model: model.Model{ID: tt.blockedId}}, nil)
+					blockRepoMock.EXPECT().Create(ctx, &model.Block{BlockerID: userId, BlockedID: tt.blockedId}).
+						Return(&model.Block{BlockerID: userId, BlockedID: tt.blockedId}, nil)
+				}
+			}
+
+			blockUsecase := usecase.New(&dependency.Repositories{
+				User:  userRepoMock,
+				Block: blockRepoMock,
+			})
+			_, err := blockUsecase.Block(ctx, &payload.BlockRequest{
+				UserID:    userId,
+				BlockedID: tt.blockedId,
+			})
+			if tt.wantCode == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			httpErr, ok := err.(*http_error.Error)
+			if assert.True(t, ok) {
+				assert.Equal(t, tt.wantCode, httpErr.HTTPCode)
+			}
+		})
+	}
+}
+
+func Test_BlockUsecase_Unblock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	blockedId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	blockRepoMock := mock_block.NewMockRepository(ctrl)
+	blockRepoMock.EXPECT().Delete(ctx, userId, blockedId).Return(nil)
+
+	blockUsecase := usecase.New(&dependency.Repositories{
+		Block: blockRepoMock,
+	})
+	res, err := blockUsecase.Unblock(ctx, &payload.UnblockRequest{
+		UserID:    userId,
+		BlockedID: blockedId,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Unblock user success", res.Message)
+	}
+}
+
+func Test_BlockUsecase_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	blocked := &model.User{Model: model.Model{ID: "47dsga9t-d76e-401a-a3ba-7a03352812c2"}, Name: "Bob"}
+
+	blockRepoMock := mock_block.NewMockRepository(ctrl)
+	blockRepoMock.EXPECT().GetAllByBlockerId(ctx, userId).Return([]*model.Block{
+		{BlockerID: userId, BlockedID: blocked.ID, Blocked: blocked},
+		// The blocked account was deleted since
+		{BlockerID: userId, BlockedID: "34251esd-d76e-401a-a3ba-7a03352812c2"},
+	}, nil)
+
+	blockUsecase := usecase.New(&dependency.Repositories{
+		Block: blockRepoMock,
+	})
+	res, err := blockUsecase.GetAll(ctx, &payload.GetAllBlockedRequest{
+		UserID: userId,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []*model.User{blocked}, res.Users)
+	}
+}
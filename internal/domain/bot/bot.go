@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	Create(ctx context.Context, bot *model.Bot) (*model.Bot, error)
+	GetById(ctx context.Context, id string) (*model.Bot, error)
+	GetByUserId(ctx context.Context, userId string) (*model.Bot, error)
+	GetAllByOwnerId(ctx context.Context, ownerId string) ([]*model.Bot, error)
+	Update(ctx context.Context, bot *model.Bot) error
+	Delete(ctx context.Context, bot *model.Bot) error
+	CreateKey(ctx context.Context, key *model.APIKey) (*model.APIKey, error)
+	GetKeyById(ctx context.Context, id string) (*model.APIKey, error)
+	GetKeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	GetKeysByUserId(ctx context.Context, userId string) ([]*model.APIKey, error)
+	DeleteKey(ctx context.Context, id string) error
+	TouchKey(ctx context.Context, id string, at time.Time) error
+}
+
+type Usecase interface {
+	Create(ctx context.Context, req *payload.CreateBotRequest) (*payload.CreateBotResponse, error)
+	GetAll(ctx context.Context, req *payload.GetAllBotsRequest) (*payload.GetAllBotsResponse, error)
+	Update(ctx context.Context, req *payload.UpdateBotRequest) (*payload.UpdateBotResponse, error)
+	Remove(ctx context.Context, req *payload.RemoveBotRequest) (*payload.RemoveBotResponse, error)
+	CreateKey(ctx context.Context, req *payload.CreateKeyRequest) (*payload.CreateKeyResponse, error)
+	GetKeys(ctx context.Context, req *payload.GetKeysRequest) (*payload.GetKeysResponse, error)
+	RevokeKey(ctx context.Context, req *payload.RevokeKeyRequest) (*payload.RevokeKeyResponse, error)
+	Authenticate(ctx context.Context, req *payload.AuthenticateRequest) (*payload.AuthenticateResponse, error)
+}
+
+type Handler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Update(ctx echo.Context) error
+	Remove(ctx echo.Context) error
+	CreateKey(ctx echo.Context) error
+	GetKeys(ctx echo.Context) error
+	RevokeKey(ctx echo.Context) error
+}
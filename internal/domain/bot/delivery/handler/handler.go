@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type BotHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) bot.Handler {
+	return &BotHandler{
+		usecases: u,
+	}
+}
+
+// CreateBot godoc
+// @Summary Create Bot
+// @Description create a bot account owned by the caller, the returned API key and callback secret are not shown again
+// @Tags Bot
+// @Accept application/json
+// @Param body body payload.CreateBotRequest true "Bot profile and callback URL"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.CreateBotResponse}
+// @Router /api/v1/bots [post]
+func (h BotHandler) Create(ctx echo.Context) error {
+	var body payload.CreateBotRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.OwnerID = user.ID
+	data, err := h.usecases.Bot.Create(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to create bot: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetAllBots godoc
+// @Summary Get All Bots
+// @Description get the caller's bots
+// @Tags Bot
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetAllBotsResponse}
+// @Router /api/v1/bots [get]
+func (h BotHandler) GetAll(ctx echo.Context) error {
+	var body payload.GetAllBotsRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.OwnerID = user.ID
+	data, err := h.usecases.Bot.GetAll(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get bots: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// UpdateBot godoc
+// @Summary Update Bot
+// @Description change the callback URL of one of the caller's bots or whether it can search the user directory
+// @Tags Bot
+// @Accept application/json
+// @Param id path string true "Bot ID"
+// @Param body body payload.UpdateBotRequest true "Fields to change"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.UpdateBotResponse}
+// @Router /api/v1/bots/{id} [put]
+func (h BotHandler) Update(ctx echo.Context) error {
+	var body payload.UpdateBotRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.OwnerID = user.ID
+	data, err := h.usecases.Bot.Update(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to update bot: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RemoveBot godoc
+// @Summary Remove Bot
+// @Description remove one of the caller's bots, its API keys stop working
+// @Tags Bot
+// @Accept application/json
+// @Param id path string true "Bot ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RemoveBotResponse}
+// @Router /api/v1/bots/{id} [delete]
+func (h BotHandler) Remove(ctx echo.Context) error {
+	var body payload.RemoveBotRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.OwnerID = user.ID
+	data, err := h.usecases.Bot.Remove(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to remove bot: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// CreateBotKey godoc
+// @Summary Create Bot API Key
+// @Description create another API key for a bot, it is not shown again
+// @Tags Bot
+// @Accept application/json
+// @Param id path string true "Bot ID"
+// @Param body body payload.CreateKeyRequest true "Key name"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.CreateKeyResponse}
+// @Router /api/v1/bots/{id}/keys [post]
+func (h BotHandler) CreateKey(ctx echo.Context) error {
+	var body payload.CreateKeyRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.OwnerID = user.ID
+	data, err := h.usecases.Bot.CreateKey(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to create API key: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetBotKeys godoc
+// @Summary Get Bot API Keys
+// @Description get a bot's API keys, only their prefixes are shown
+// @Tags Bot
+// @Accept application/json
+// @Param id path string true "Bot ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetKeysResponse}
+// @Router /api/v1/bots/{id}/keys [get]
+func (h BotHandler) GetKeys(ctx echo.Context) error {
+	var body payload.GetKeysRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.OwnerID = user.ID
+	data, err := h.usecases.Bot.GetKeys(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get API keys: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RevokeBotKey godoc
+// @Summary Revoke Bot API Key
+// @Description revoke one of a bot's API keys
+// @Tags Bot
+// @Accept application/json
+// @Param id path string true "Bot ID"
+// @Param key_id path string true "API key ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RevokeKeyResponse}
+// @Router /api/v1/bots/{id}/keys/{key_id} [delete]
+func (h BotHandler) RevokeKey(ctx echo.Context) error {
+	var body payload.RevokeKeyRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.OwnerID = user.ID
+	data, err := h.usecases.Bot.RevokeKey(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to revoke API key: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
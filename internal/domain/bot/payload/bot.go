@@ -0,0 +1,54 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+// Inbound messages are POSTed to CallbackURL as signed message.received webhook events
+type CreateBotRequest struct {
+	OwnerID            string `json:"-"`
+	Name               string `json:"name" validate:"required,max=100"`
+	PhotoURL           string `json:"photo_url" validate:"omitempty,url,max=2048"`
+	CallbackURL        string `json:"callback_url" validate:"omitempty,url,max=2048"`
+	CanSearchDirectory bool   `json:"can_search_directory"`
+}
+
+// APIKey and CallbackSecret aren't shown again
+type CreateBotResponse struct {
+	Bot            *model.Bot    `json:"bot"`
+	Key            *model.APIKey `json:"key"`
+	APIKey         string        `json:"api_key"`
+	CallbackSecret string        `json:"callback_secret,omitempty"`
+	Message        string        `json:"message"`
+}
+
+type GetAllBotsRequest struct {
+	OwnerID string `json:"-"`
+}
+
+type GetAllBotsResponse struct {
+	Bots    []*model.Bot `json:"bots"`
+	Message string       `json:"message"`
+}
+
+// Fields left out are unchanged, an empty callback_url removes the callback
+type UpdateBotRequest struct {
+	OwnerID            string  `json:"-"`
+	BotID              string  `param:"id"`
+	CallbackURL        *string `json:"callback_url" validate:"omitempty,max=2048"`
+	CanSearchDirectory *bool   `json:"can_search_directory"`
+}
+
+// CallbackSecret is only set when the callback URL changed
+type UpdateBotResponse struct {
+	Bot            *model.Bot `json:"bot"`
+	CallbackSecret string     `json:"callback_secret,omitempty"`
+	Message        string     `json:"message"`
+}
+
+type RemoveBotRequest struct {
+	OwnerID string `json:"-"`
+	BotID   string `param:"id"`
+}
+
+type RemoveBotResponse struct {
+	Message string `json:"message"`
+}
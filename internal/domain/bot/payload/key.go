@@ -0,0 +1,44 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type CreateKeyRequest struct {
+	OwnerID string `json:"-"`
+	BotID   string `param:"id"`
+	Name    string `json:"name" validate:"max=100"`
+}
+
+// APIKey isn't shown again
+type CreateKeyResponse struct {
+	Key     *model.APIKey `json:"key"`
+	APIKey  string        `json:"api_key"`
+	Message string        `json:"message"`
+}
+
+type GetKeysRequest struct {
+	OwnerID string `json:"-"`
+	BotID   string `param:"id"`
+}
+
+type GetKeysResponse struct {
+	Keys    []*model.APIKey `json:"keys"`
+	Message string          `json:"message"`
+}
+
+type RevokeKeyRequest struct {
+	OwnerID string `json:"-"`
+	BotID   string `param:"id"`
+	KeyID   string `param:"key_id"`
+}
+
+type RevokeKeyResponse struct {
+	Message string `json:"message"`
+}
+
+type AuthenticateRequest struct {
+	Key string
+}
+
+type AuthenticateResponse struct {
+	User *model.User `json:"user"`
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type BotRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) bot.Repository {
+	return &BotRepository{
+		DB: gormDB,
+	}
+}
+
+// The bot's user account is created along with it
+func (r BotRepository) Create(ctx context.Context, bot *model.Bot) (*model.Bot, error) {
+	db := postgres.Conn(ctx, r.DB)
+	bot.User.IsBot = true
+	if err := db.Create(bot.User).Error; err != nil {
+		return nil, err
+	}
+	bot.UserID = bot.User.ID
+	result := db.Omit("User").Create(bot)
+	return bot, result.Error
+}
+
+func (r BotRepository) GetById(ctx context.Context, id string) (*model.Bot, error) {
+	return r.getBy(ctx, "id = ?", id)
+}
+
+func (r BotRepository) GetByUserId(ctx context.Context, userId string) (*model.Bot, error) {
+	return r.getBy(ctx, "user_id = ?", userId)
+}
+
+func (r BotRepository) getBy(ctx context.Context, query string, arg string) (*model.Bot, error) {
+	var bot *model.Bot
+	result := postgres.Conn(ctx, r.DB).Preload("User").Where(query, arg).Limit(1).Find(&bot)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return bot, nil
+}
+
+func (r BotRepository) GetAllByOwnerId(ctx context.Context, ownerId string) ([]*model.Bot, error) {
+	var bots []*model.Bot
+	result := r.DB.WithContext(ctx).Preload("User").Where("owner_id = ?", ownerId).Order("created_at ASC").Find(&bots)
+	return bots, result.Error
+}
+
+func (r BotRepository) Update(ctx context.Context, bot *model.Bot) error {
+	result := postgres.Conn(ctx, r.DB).Table(constant.BotTable).Where("id = ?", bot.ID).
+		Updates(map[string]interface{}{
+			"callback_webhook_id":  bot.CallbackWebhookID,
+			"can_search_directory": bot.CanSearchDirectory,
+			"updated_at":           time.Now(),
+		})
+	return result.Error
+}
+
+// Delete removes the bot with its keys and deletes its user account, the messages it
+// sent stay in their conversations
+func (r BotRepository) Delete(ctx context.Context, bot *model.Bot) error {
+	db := postgres.Conn(ctx, r.DB)
+	if err := db.Where("user_id = ?", bot.UserID).Delete(&model.APIKey{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("id = ?", bot.ID).Delete(&model.Bot{}).Error; err != nil {
+		return err
+	}
+	return db.Where("id = ?", bot.UserID).Delete(&model.User{}).Error
+}
+
+func (r BotRepository) CreateKey(ctx context.Context, key *model.APIKey) (*model.APIKey, error) {
+	result := postgres.Conn(ctx, r.DB).Create(key)
+	return key, result.Error
+}
+
+func (r BotRepository) GetKeyById(ctx context.Context, id string) (*model.APIKey, error) {
+	return r.getKeyBy(ctx, "id = ?", id)
+}
+
+func (r BotRepository) GetKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	return r.getKeyBy(ctx, "key_hash = ?", hash)
+}
+
+func (r BotRepository) getKeyBy(ctx context.Context, query string, arg string) (*model.APIKey, error) {
+	var key *model.APIKey
+	result := r.DB.WithContext(ctx).Where(query, arg).Limit(1).Find(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return key, nil
+}
+
+func (r BotRepository) GetKeysByUserId(ctx context.Context, userId string) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	result := r.DB.WithContext(ctx).Where("user_id = ?", userId).Order("created_at ASC").Find(&keys)
+	return keys, result.Error
+}
+
+func (r BotRepository) DeleteKey(ctx context.Context, id string) error {
+	result := r.DB.WithContext(ctx).Where("id = ?", id).Delete(&model.APIKey{})
+	return result.Error
+}
+
+func (r BotRepository) TouchKey(ctx context.Context, id string, at time.Time) error {
+	result := r.DB.WithContext(ctx).Table(constant.APIKeyTable).Where("id = ?", id).Update("last_used_at", at)
+	return result.Error
+}
@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Characters of the key kept in the clear to tell keys apart
+const KeyPrefixLength = 12
+
+// Last use of a key is written at most this often, not on every request
+const KeyUsedResolution = time.Minute
+
+type BotUsecase struct {
+	repositories *dependency.Repositories
+	gateways     *dependency.Gateways
+}
+
+func New(r *dependency.Repositories, g *dependency.Gateways) bot.Usecase {
+	return &BotUsecase{
+		repositories: r,
+		gateways:     g,
+	}
+}
+
+// Create makes the bot's user account, its callback webhook and its first API key
+func (u BotUsecase) Create(ctx context.Context, req *payload.CreateBotRequest) (*payload.CreateBotResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	owner, err := u.repositories.User.GetById(ctx, req.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if owner.IsBot {
+		return nil, http_error.Forbidden("Bots can't create bots")
+	}
+	if err := u.validateCallbackURL(ctx, req.CallbackURL); err != nil {
+		return nil, err
+	}
+
+	res := &payload.CreateBotResponse{Message: "Create bot success"}
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		result, err := u.repositories.Bot.Create(ctx, &model.Bot{
+			OwnerID:            req.OwnerID,
+			CanSearchDirectory: req.CanSearchDirectory,
+			User: &model.User{
+				Name:     req.Name,
+				PhotoURL: req.PhotoURL,
+			},
+		})
+		if err != nil {
+			log.Error("Failed to create bot: ", zap.Error(err))
+			return err
+		}
+		if req.CallbackURL != "" {
+			hook, secret, err := u.createCallback(ctx, result.UserID, req.CallbackURL)
+			if err != nil {
+				return err
+			}
+			result.CallbackWebhookID = hook.ID
+			if err := u.repositories.Bot.Update(ctx, result); err != nil {
+				log.Error("Failed to update bot: ", zap.Error(err))
+				return err
+			}
+			result.CallbackURL = hook.URL
+			res.CallbackSecret = secret
+		}
+		res.Bot = result
+		res.Key, res.APIKey, err = u.createKey(ctx, result.UserID, "default")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u BotUsecase) GetAll(ctx context.Context, req *payload.GetAllBotsRequest) (*payload.GetAllBotsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	bots, err := u.repositories.Bot.GetAllByOwnerId(ctx, req.OwnerID)
+	if err != nil {
+		log.Error("Failed to get bots: ", zap.Error(err))
+		return nil, err
+	}
+	for _, b := range bots {
+		u.fillCallbackURL(ctx, b)
+	}
+
+	return &payload.GetAllBotsResponse{
+		Bots:    bots,
+		Message: "Successfully get bots",
+	}, nil
+}
+
+// A new callback URL replaces the webhook, so it gets a new secret
+func (u BotUsecase) Update(ctx context.Context, req *payload.UpdateBotRequest) (*payload.UpdateBotResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	result, err := u.getOwnBot(ctx, req.OwnerID, req.BotID)
+	if err != nil {
+		return nil, err
+	}
+	if req.CallbackURL != nil {
+		if err := u.validateCallbackURL(ctx, *req.CallbackURL); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &payload.UpdateBotResponse{Message: "Update bot success"}
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if req.CanSearchDirectory != nil {
+			result.CanSearchDirectory = *req.CanSearchDirectory
+		}
+		if req.CallbackURL != nil {
+			if result.CallbackWebhookID != "" {
+				if err := u.repositories.Webhook.Delete(ctx, result.CallbackWebhookID); err != nil {
+					log.Error("Failed to remove bot callback: ", zap.Error(err))
+					return err
+				}
+				result.CallbackWebhookID = ""
+			}
+			if *req.CallbackURL != "" {
+				hook, secret, err := u.createCallback(ctx, result.UserID, *req.CallbackURL)
+				if err != nil {
+					return err
+				}
+				result.CallbackWebhookID = hook.ID
+				res.CallbackSecret = secret
+			}
+		}
+		if err := u.repositories.Bot.Update(ctx, result); err != nil {
+			log.Error("Failed to update bot: ", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.fillCallbackURL(ctx, result)
+	res.Bot = result
+
+	return res, nil
+}
+
+// The bot's keys stop working right away and its callback is removed
+func (u BotUsecase) Remove(ctx context.Context, req *payload.RemoveBotRequest) (*payload.RemoveBotResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	result, err := u.getOwnBot(ctx, req.OwnerID, req.BotID)
+	if err != nil {
+		return nil, err
+	}
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if result.CallbackWebhookID != "" {
+			if err := u.repositories.Webhook.Delete(ctx, result.CallbackWebhookID); err != nil {
+				log.Error("Failed to remove bot callback: ", zap.Error(err))
+				return err
+			}
+		}
+		if err := u.repositories.Bot.Delete(ctx, result); err != nil {
+			log.Error("Failed to remove bot: ", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.RemoveBotResponse{
+		Message: "Remove bot success",
+	}, nil
+}
+
+func (u BotUsecase) CreateKey(ctx context.Context, req *payload.CreateKeyRequest) (*payload.CreateKeyResponse, error) {
+	result, err := u.getOwnBot(ctx, req.OwnerID, req.BotID)
+	if err != nil {
+		return nil, err
+	}
+	key, apiKey, err := u.createKey(ctx, result.UserID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.CreateKeyResponse{
+		Key:     key,
+		APIKey:  apiKey,
+		Message: "Create API key success",
+	}, nil
+}
+
+func (u BotUsecase) GetKeys(ctx context.Context, req *payload.GetKeysRequest) (*payload.GetKeysResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	result, err := u.getOwnBot(ctx, req.OwnerID, req.BotID)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := u.repositories.Bot.GetKeysByUserId(ctx, result.UserID)
+	if err != nil {
+		log.Error("Failed to get API keys: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.GetKeysResponse{
+		Keys:    keys,
+		Message: "Successfully get API keys",
+	}, nil
+}
+
+func (u BotUsecase) RevokeKey(ctx context.Context, req *payload.RevokeKeyRequest) (*payload.RevokeKeyResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	result, err := u.getOwnBot(ctx, req.OwnerID, req.BotID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := u.repositories.Bot.GetKeyById(ctx, req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.UserID != result.UserID {
+		return nil, errors.New("not found")
+	}
+	if err := u.repositories.Bot.DeleteKey(ctx, key.ID); err != nil {
+		log.Error("Failed to revoke API key: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RevokeKeyResponse{
+		Message: "Revoke API key success",
+	}, nil
+}
+
+// Authenticate returns the bot user an API key belongs to
+func (u BotUsecase) Authenticate(ctx context.Context, req *payload.AuthenticateRequest) (*payload.AuthenticateResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if !model.IsAPIKey(req.Key) {
+		return nil, http_error.Unauthorized("Invalid API key")
+	}
+	key, err := u.repositories.Bot.GetKeyByHash(ctx, hashKey(req.Key))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, http_error.Unauthorized("Invalid API key")
+		}
+		return nil, err
+	}
+	user, err := u.repositories.User.GetById(ctx, key.UserID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, http_error.Unauthorized("Invalid API key")
+		}
+		return nil, err
+	}
+	if !user.IsBot {
+		return nil, http_error.Unauthorized("Invalid API key")
+	}
+
+	// Only bookkeeping, it mustn't fail the request
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= KeyUsedResolution {
+		if err := u.repositories.Bot.TouchKey(ctx, key.ID, now); err != nil {
+			log.Error("Failed to record API key use: ", zap.Error(err))
+		}
+	}
+
+	return &payload.AuthenticateResponse{
+		User: user,
+	}, nil
+}
+
+func (u BotUsecase) getOwnBot(ctx context.Context, ownerId string, botId string) (*model.Bot, error) {
+	result, err := u.repositories.Bot.GetById(ctx, botId)
+	if err != nil {
+		return nil, err
+	}
+	if result.OwnerID != ownerId {
+		return nil, errors.New("unauthorized")
+	}
+	return result, nil
+}
+
+// The callback is a webhook of the bot's own account, subscribed to the messages it receives
+func (u BotUsecase) createCallback(ctx context.Context, userId string, callbackURL string) (*model.Webhook, string, error) {
+	secret := newSecret()
+	hook, err := u.repositories.Webhook.Create(ctx, &model.Webhook{
+		UserID: userId,
+		URL:    callbackURL,
+		Secret: secret,
+		Events: []string{model.WebhookEventMessageReceived},
+	})
+	if err != nil {
+		logger.GetLogger(ctx).Error("Failed to create bot callback: ", zap.Error(err))
+		return nil, "", err
+	}
+	return hook, secret, nil
+}
+
+func (u BotUsecase) createKey(ctx context.Context, userId string, name string) (*model.APIKey, string, error) {
+	apiKey := model.APIKeyPrefix + newSecret()
+	key, err := u.repositories.Bot.CreateKey(ctx, &model.APIKey{
+		UserID:  userId,
+		Name:    name,
+		Prefix:  apiKey[:KeyPrefixLength],
+		KeyHash: hashKey(apiKey),
+	})
+	if err != nil {
+		logger.GetLogger(ctx).Error("Failed to create API key: ", zap.Error(err))
+		return nil, "", err
+	}
+	return key, apiKey, nil
+}
+
+func (u BotUsecase) fillCallbackURL(ctx context.Context, b *model.Bot) {
+	if b.CallbackWebhookID == "" {
+		return
+	}
+	hook, err := u.repositories.Webhook.GetById(ctx, b.CallbackWebhookID)
+	if err != nil {
+		logger.GetLogger(ctx).Error("Failed to get bot callback: ", zap.Error(err))
+		return
+	}
+	b.CallbackURL = hook.URL
+}
+
+func (u BotUsecase) validateCallbackURL(ctx context.Context, callbackURL string) error {
+	if callbackURL == "" {
+		return nil
+	}
+	if err := u.gateways.URLGuard.CheckURL(ctx, callbackURL); err != nil {
+		return http_error.BadRequest(fmt.Errorf("callback_url %w", err))
+	}
+	return nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
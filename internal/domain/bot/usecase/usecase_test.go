@@ -0,0 +1,194 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/bot/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_bot "gitlab.com/raihanlh/messenger-api/testing/mocks/bot"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+	mock_webhook "gitlab.com/raihanlh/messenger-api/testing/mocks/webhook"
+)
+
+func Test_BotUsecase_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetById(ctx, "u1").Return(&model.User{Model: model.Model{ID: "u1"}}, nil)
+	botRepoMock := mock_bot.NewMockRepository(ctrl)
+	botRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, bot *model.Bot) (*model.Bot, error) {
+		assert.Equal(t, "u1", bot.OwnerID)
+		assert.Equal(t, "Helper", bot.User.Name)
+		bot.ID = "bot1"
+		bot.UserID = "b1"
+		return bot, nil
+	})
+	webhookRepoMock := mock_webhook.NewMockRepository(ctrl)
+	webhookRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, hook *model.Webhook) (*model.Webhook, error) {
+		assert.Equal(t, "b1", hook.UserID, "the callback belongs to the bot's account")
+		assert.Equal(t, []string{model.WebhookEventMessageReceived}, hook.Events)
+		hook.ID = "w1"
+		return hook, nil
+	})
+	botRepoMock.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, bot *model.Bot) error {
+		assert.Equal(t, "w1", bot.CallbackWebhookID)
+		return nil
+	})
+	var stored *model.APIKey
+	botRepoMock.EXPECT().CreateKey(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key *model.APIKey) (*model.APIKey, error) {
+		stored = key
+		return key, nil
+	})
+
+	botUsecase := usecase.New(&dependency.Repositories{
+		Transactor: helper.NoTransaction{},
+		User:       userRepoMock,
+		Bot:        botRepoMock,
+		Webhook:    webhookRepoMock,
+	}, &dependency.Gateways{URLGuard: helper.URLGuard(helper.StaticResolver{"bot.example.id": {"93.184.216.34"}})})
+
+	res, err := botUsecase.Create(ctx, &payload.CreateBotRequest{
+		OwnerID:     "u1",
+		Name:        "Helper",
+		CallbackURL: "https://bot.example.id/inbox",
+	})
+	if assert.NoError(t, err) {
+		assert.True(t, model.IsAPIKey(res.APIKey))
+		assert.NotEmpty(t, res.CallbackSecret)
+		assert.Equal(t, "https://bot.example.id/inbox", res.Bot.CallbackURL)
+		sum := sha256.Sum256([]byte(res.APIKey))
+		assert.Equal(t, hex.EncodeToString(sum[:]), stored.KeyHash, "only the hash is stored")
+		assert.Equal(t, res.APIKey[:usecase.KeyPrefixLength], stored.Prefix)
+	}
+}
+
+func Test_BotUsecase_Create_ByBot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetById(ctx, "b1").Return(&model.User{Model: model.Model{ID: "b1"}, IsBot: true}, nil)
+
+	botUsecase := usecase.New(&dependency.Repositories{
+		Transactor: helper.NoTransaction{},
+		User:       userRepoMock,
+	}, nil)
+
+	_, err := botUsecase.Create(ctx, &payload.CreateBotRequest{OwnerID: "b1", Name: "Helper"})
+	assert.Error(t, err)
+}
+
+func Test_BotUsecase_Create_PrivateCallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	// Nothing is created for a callback into the server's own network
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetById(ctx, "u1").Return(&model.User{Model: model.Model{ID: "u1"}}, nil)
+
+	botUsecase := usecase.New(&dependency.Repositories{
+		Transactor: helper.NoTransaction{},
+		User:       userRepoMock,
+	}, &dependency.Gateways{URLGuard: helper.URLGuard(helper.StaticResolver{})})
+
+	_, err := botUsecase.Create(ctx, &payload.CreateBotRequest{
+		OwnerID:     "u1",
+		Name:        "Helper",
+		CallbackURL: "http://169.254.169.254/latest/meta-data",
+	})
+	if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+		assert.Equal(t, http.StatusBadRequest, httpErr.HTTPCode)
+	}
+}
+
+func Test_BotUsecase_Authenticate(t *testing.T) {
+	const apiKey = model.APIKeyPrefix + "0123456789abcdef"
+	sum := sha256.Sum256([]byte(apiKey))
+	hash := hex.EncodeToString(sum[:])
+	recently := time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name    string
+		key     string
+		stored  *model.APIKey
+		user    *model.User
+		touch   bool
+		wantErr bool
+	}{
+		{
+			name:   "Valid key",
+			key:    apiKey,
+			stored: &model.APIKey{Model: model.Model{ID: "k1"}, UserID: "b1"},
+			user:   &model.User{Model: model.Model{ID: "b1"}, IsBot: true},
+			touch:  true,
+		},
+		{
+			name:   "Used recently",
+			key:    apiKey,
+			stored: &model.APIKey{Model: model.Model{ID: "k1"}, UserID: "b1", LastUsedAt: &recently},
+			user:   &model.User{Model: model.Model{ID: "b1"}, IsBot: true},
+		},
+		{name: "Revoked key", key: apiKey, wantErr: true},
+		{
+			name:    "Not a bot",
+			key:     apiKey,
+			stored:  &model.APIKey{Model: model.Model{ID: "k1"}, UserID: "u1"},
+			user:    &model.User{Model: model.Model{ID: "u1"}},
+			wantErr: true,
+		},
+		{name: "Not an API key", key: "0123456789abcdef", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			botRepoMock := mock_bot.NewMockRepository(ctrl)
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			if model.IsAPIKey(tt.key) {
+				if tt.stored == nil {
+					botRepoMock.EXPECT().GetKeyByHash(ctx, hash).Return(nil, errors.New("not found"))
+				} else {
+					botRepoMock.EXPECT().GetKeyByHash(ctx, hash).Return(tt.stored, nil)
+					userRepoMock.EXPECT().GetById(ctx, tt.stored.UserID).Return(tt.user, nil)
+				}
+			}
+			if tt.touch {
+				botRepoMock.EXPECT().TouchKey(ctx, "k1", gomock.Any()).Return(nil)
+			}
+
+			botUsecase := usecase.New(&dependency.Repositories{
+				User: userRepoMock,
+				Bot:  botRepoMock,
+			}, nil)
+
+			res, err := botUsecase.Authenticate(ctx, &payload.AuthenticateRequest{Key: tt.key})
+			if tt.wantErr {
+				if assert.Error(t, err) {
+					assert.Equal(t, "Invalid API key", err.Error())
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.user, res.User)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package builtin has the slash commands every conversation gets
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
+)
+
+// Most options a poll can have
+const MaxPollOptions = 10
+
+// Registry returns a new registry with the built-in commands
+func Registry() *slash.Registry {
+	return slash.NewRegistry(
+		&slash.Command{Name: "help", Description: "List the commands you can use here", Handler: slash.HandlerFunc(help)},
+		&slash.Command{Name: "me", Description: "Post an action in the third person", Usage: "<action>", Handler: slash.HandlerFunc(me)},
+		&slash.Command{Name: "shrug", Description: "Append ¯\\_(ツ)_/¯ to your message", Usage: "[message]", Handler: slash.HandlerFunc(shrug)},
+		&slash.Command{Name: "roll", Description: "Roll a die, 6 sides unless given", Usage: "[sides]", Handler: slash.HandlerFunc(roll)},
+		&slash.Command{Name: "poll", Description: "Post a numbered poll", Usage: "<question> | <option> | <option> ...", Handler: slash.HandlerFunc(poll)},
+	)
+}
+
+func help(ctx context.Context, inv *slash.Invocation) (*slash.Reply, error) {
+	var b strings.Builder
+	b.WriteString("Commands:")
+	for _, c := range inv.Available {
+		b.WriteString("\n/" + c.Name)
+		if c.Usage != "" {
+			b.WriteString(" " + c.Usage)
+		}
+		if c.Description != "" {
+			b.WriteString(" - " + c.Description)
+		}
+	}
+	return slash.Ephemeral(b.String()), nil
+}
+
+func me(ctx context.Context, inv *slash.Invocation) (*slash.Reply, error) {
+	if inv.Text == "" {
+		return slash.Ephemeral("Usage: /me <action>"), nil
+	}
+	return slash.Post(fmt.Sprintf("_%s %s_", inv.UserName, inv.Text)), nil
+}
+
+func shrug(ctx context.Context, inv *slash.Invocation) (*slash.Reply, error) {
+	return slash.Post(strings.TrimSpace(inv.Text + ` ¯\_(ツ)_/¯`)), nil
+}
+
+func roll(ctx context.Context, inv *slash.Invocation) (*slash.Reply, error) {
+	sides := 6
+	if inv.Text != "" {
+		n, err := strconv.Atoi(inv.Text)
+		if err != nil || n < 2 || n > 1000 {
+			return slash.Ephemeral("Usage: /roll [sides], between 2 and 1000"), nil
+		}
+		sides = n
+	}
+	return slash.Post(fmt.Sprintf("rolled %d (1-%d)", rand.Intn(sides)+1, sides)), nil
+}
+
+func poll(ctx context.Context, inv *slash.Invocation) (*slash.Reply, error) {
+	var parts []string
+	for _, p := range strings.Split(inv.Text, "|") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) < 3 || len(parts) > MaxPollOptions+1 {
+		return slash.Ephemeral(fmt.Sprintf("Usage: /poll <question> | <option> | <option> ..., with 2 to %d options", MaxPollOptions)), nil
+	}
+	var b strings.Builder
+	b.WriteString("Poll: " + parts[0])
+	for i, option := range parts[1:] {
+		b.WriteString(fmt.Sprintf("\n%d. %s", i+1, option))
+	}
+	return slash.Post(b.String()), nil
+}
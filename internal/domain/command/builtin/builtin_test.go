@@ -0,0 +1,48 @@
+package builtin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/builtin"
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
+)
+
+func Test_Builtins(t *testing.T) {
+	registry := builtin.Registry()
+
+	tests := []struct {
+		command       string
+		text          string
+		wantText      string
+		wantEphemeral bool
+	}{
+		{command: "me", text: "waves", wantText: "_Alice waves_"},
+		{command: "me", wantText: "Usage: /me <action>", wantEphemeral: true},
+		{command: "shrug", text: "no idea", wantText: `no idea ¯\_(ツ)_/¯`},
+		{command: "roll", text: "1", wantText: "Usage: /roll [sides], between 2 and 1000", wantEphemeral: true},
+		{command: "poll", text: "Lunch? | pizza | sushi", wantText: "Poll: Lunch?\n1. pizza\n2. sushi"},
+		{command: "poll", text: "Lunch? | pizza", wantText: "Usage: /poll <question> | <option> | <option> ..., with 2 to 10 options", wantEphemeral: true},
+		{command: "help", wantText: "Commands:\n/me <action> - Post an action in the third person", wantEphemeral: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command+" "+tt.text, func(t *testing.T) {
+			c, ok := registry.Get(tt.command)
+			if !assert.True(t, ok) {
+				return
+			}
+			me, _ := registry.Get("me")
+			reply, err := c.Handler.Run(context.TODO(), &slash.Invocation{
+				Command:   tt.command,
+				Text:      tt.text,
+				UserName:  "Alice",
+				Available: []*slash.Command{me},
+			})
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantText, reply.Text)
+				assert.Equal(t, tt.wantEphemeral, reply.IsEphemeral())
+			}
+		})
+	}
+}
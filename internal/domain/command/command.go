@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	Create(ctx context.Context, command *model.SlashCommand) (*model.SlashCommand, error)
+	GetById(ctx context.Context, id string) (*model.SlashCommand, error)
+	GetByName(ctx context.Context, conversationId string, name string) (*model.SlashCommand, error)
+	GetAllByConversationId(ctx context.Context, conversationId string) ([]*model.SlashCommand, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type Usecase interface {
+	Create(ctx context.Context, req *payload.CreateCommandRequest) (*payload.CreateCommandResponse, error)
+	GetAll(ctx context.Context, req *payload.GetAllCommandsRequest) (*payload.GetAllCommandsResponse, error)
+	Remove(ctx context.Context, req *payload.RemoveCommandRequest) (*payload.RemoveCommandResponse, error)
+}
+
+type Handler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Remove(ctx echo.Context) error
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type CommandHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) command.Handler {
+	return &CommandHandler{
+		usecases: u,
+	}
+}
+
+// CreateCommand godoc
+// @Summary Create Command
+// @Description add a slash command to the conversation that calls a URL, invocations are signed with the returned secret
+// @Tags Command
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param body body payload.CreateCommandRequest true "Command name and callback URL"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.CreateCommandResponse}
+// @Router /api/v1/conversations/{convo_id}/commands [post]
+func (h CommandHandler) Create(ctx echo.Context) error {
+	var body payload.CreateCommandRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Command.Create(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to create command: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetAllCommands godoc
+// @Summary Get All Commands
+// @Description get the slash commands available in the conversation, built-in ones first
+// @Tags Command
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetAllCommandsResponse}
+// @Router /api/v1/conversations/{convo_id}/commands [get]
+func (h CommandHandler) GetAll(ctx echo.Context) error {
+	var body payload.GetAllCommandsRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Command.GetAll(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get commands: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RemoveCommand godoc
+// @Summary Remove Command
+// @Description remove a slash command the conversation added
+// @Tags Command
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param id path string true "Command ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RemoveCommandResponse}
+// @Router /api/v1/conversations/{convo_id}/commands/{id} [delete]
+func (h CommandHandler) Remove(ctx echo.Context) error {
+	var body payload.RemoveCommandRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Command.Remove(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to remove command: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
@@ -0,0 +1,49 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+// Name is without the slash and can't be one of the built-in commands
+type CreateCommandRequest struct {
+	UserID         string `json:"-"`
+	ConversationID string `param:"convo_id"`
+	Name           string `json:"name" validate:"required,max=32,alphanum,lowercase"`
+	Description    string `json:"description" validate:"max=200"`
+	Usage          string `json:"usage" validate:"max=100"`
+	URL            string `json:"url" validate:"required,url,max=2048"`
+}
+
+// Secret signs every invocation sent to the command, it isn't shown again
+type CreateCommandResponse struct {
+	Command *model.SlashCommand `json:"command"`
+	Secret  string              `json:"secret"`
+	Message string              `json:"message"`
+}
+
+type GetAllCommandsRequest struct {
+	UserID         string `json:"-"`
+	ConversationID string `param:"convo_id"`
+}
+
+// ID is empty for built-in commands, they can't be removed
+type CommandInfo struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Usage       string `json:"usage,omitempty"`
+	Builtin     bool   `json:"builtin"`
+}
+
+type GetAllCommandsResponse struct {
+	Commands []*CommandInfo `json:"commands"`
+	Message  string         `json:"message"`
+}
+
+type RemoveCommandRequest struct {
+	UserID         string `json:"-"`
+	ConversationID string `param:"convo_id"`
+	CommandID      string `param:"id"`
+}
+
+type RemoveCommandResponse struct {
+	Message string `json:"message"`
+}
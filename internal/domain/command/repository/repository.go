@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/gorm"
+)
+
+type CommandRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) command.Repository {
+	return &CommandRepository{
+		DB: gormDB,
+	}
+}
+
+func (r CommandRepository) Create(ctx context.Context, command *model.SlashCommand) (*model.SlashCommand, error) {
+	result := r.DB.WithContext(ctx).Create(command)
+	return command, result.Error
+}
+
+func (r CommandRepository) GetById(ctx context.Context, id string) (*model.SlashCommand, error) {
+	var command *model.SlashCommand
+	result := r.DB.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&command)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return command, nil
+}
+
+// Returns nil when the conversation has no command with the name
+func (r CommandRepository) GetByName(ctx context.Context, conversationId string, name string) (*model.SlashCommand, error) {
+	var command *model.SlashCommand
+	result := r.DB.WithContext(ctx).Where("conversation_id = ? AND name = ?", conversationId, name).Limit(1).Find(&command)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return command, nil
+}
+
+func (r CommandRepository) GetAllByConversationId(ctx context.Context, conversationId string) ([]*model.SlashCommand, error) {
+	var commands []*model.SlashCommand
+	result := r.DB.WithContext(ctx).Where("conversation_id = ?", conversationId).Order("name ASC").Find(&commands)
+	return commands, result.Error
+}
+
+// Deleted for good, so the name can be registered again
+func (r CommandRepository) Delete(ctx context.Context, id string) error {
+	result := r.DB.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&model.SlashCommand{})
+	return result.Error
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type CommandUsecase struct {
+	repositories *dependency.Repositories
+	gateways     *dependency.Gateways
+}
+
+func New(r *dependency.Repositories, g *dependency.Gateways) command.Usecase {
+	return &CommandUsecase{
+		repositories: r,
+		gateways:     g,
+	}
+}
+
+func (u CommandUsecase) Create(ctx context.Context, req *payload.CreateCommandRequest) (*payload.CreateCommandResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnConversation(ctx, req.UserID, req.ConversationID); err != nil {
+		return nil, err
+	}
+	if err := u.gateways.URLGuard.CheckURL(ctx, req.URL); err != nil {
+		return nil, http_error.BadRequest(fmt.Errorf("url %w", err))
+	}
+	if _, ok := u.gateways.Commands.Get(req.Name); ok {
+		return nil, http_error.DataExisted(fmt.Sprintf("/%s is a built-in command", req.Name))
+	}
+	existing, err := u.repositories.Command.GetByName(ctx, req.ConversationID, req.Name)
+	if err != nil {
+		log.Error("Failed to get command: ", zap.Error(err))
+		return nil, err
+	}
+	if existing != nil {
+		return nil, http_error.DataExisted(fmt.Sprintf("/%s already exists in this conversation", req.Name))
+	}
+
+	secret := newSecret()
+	result, err := u.repositories.Command.Create(ctx, &model.SlashCommand{
+		ConversationID: req.ConversationID,
+		CreatorID:      req.UserID,
+		Name:           req.Name,
+		Description:    req.Description,
+		Usage:          req.Usage,
+		URL:            req.URL,
+		Secret:         secret,
+	})
+	if err != nil {
+		log.Error("Failed to create command: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.CreateCommandResponse{
+		Command: result,
+		Secret:  secret,
+		Message: "Create command success",
+	}, nil
+}
+
+// GetAll lists the built-in commands followed by the conversation's own
+func (u CommandUsecase) GetAll(ctx context.Context, req *payload.GetAllCommandsRequest) (*payload.GetAllCommandsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnConversation(ctx, req.UserID, req.ConversationID); err != nil {
+		return nil, err
+	}
+	custom, err := u.repositories.Command.GetAllByConversationId(ctx, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get commands: ", zap.Error(err))
+		return nil, err
+	}
+
+	commands := []*payload.CommandInfo{}
+	for _, c := range u.gateways.Commands.All() {
+		commands = append(commands, &payload.CommandInfo{
+			Name:        c.Name,
+			Description: c.Description,
+			Usage:       c.Usage,
+			Builtin:     true,
+		})
+	}
+	for _, c := range custom {
+		commands = append(commands, &payload.CommandInfo{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			Usage:       c.Usage,
+		})
+	}
+
+	return &payload.GetAllCommandsResponse{
+		Commands: commands,
+		Message:  "Successfully get commands",
+	}, nil
+}
+
+// Any participant can remove a command, not only the one who added it
+func (u CommandUsecase) Remove(ctx context.Context, req *payload.RemoveCommandRequest) (*payload.RemoveCommandResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnConversation(ctx, req.UserID, req.ConversationID); err != nil {
+		return nil, err
+	}
+	result, err := u.repositories.Command.GetById(ctx, req.CommandID)
+	if err != nil {
+		return nil, err
+	}
+	if result.ConversationID != req.ConversationID {
+		return nil, errors.New("not found")
+	}
+	if err := u.repositories.Command.Delete(ctx, result.ID); err != nil {
+		log.Error("Failed to remove command: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RemoveCommandResponse{
+		Message: "Remove command success",
+	}, nil
+}
+
+func (u CommandUsecase) getOwnConversation(ctx context.Context, userId string, conversationId string) (*model.Conversation, error) {
+	convo, err := u.repositories.Conversation.GetById(ctx, conversationId)
+	if err != nil {
+		return nil, err
+	}
+	if convo.SenderID != userId && convo.ReceiverID != userId {
+		return nil, errors.New("unauthorized")
+	}
+	return convo, nil
+}
+
+func newSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/builtin"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_command "gitlab.com/raihanlh/messenger-api/testing/mocks/command"
+	mock_conversation "gitlab.com/raihanlh/messenger-api/testing/mocks/conversation"
+)
+
+func Test_CommandUsecase_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		url      string
+		existing *model.SlashCommand
+		wantCode string
+	}{
+		{name: "New command", command: "deploy", url: "https://ci.example.id/slash"},
+		{name: "Built-in name", command: "shrug", url: "https://ci.example.id/slash", wantCode: http_error.DataExistedCode},
+		{name: "Taken name", command: "deploy", url: "https://ci.example.id/slash", existing: &model.SlashCommand{Name: "deploy"}, wantCode: http_error.DataExistedCode},
+		{name: "Callback on the private network", command: "deploy", url: "http://10.0.0.5/slash", wantCode: http_error.BadRequestCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, "c1").Return(&model.Conversation{Model: model.Model{ID: "c1"}, SenderID: "u1", ReceiverID: "u2"}, nil)
+			commandRepoMock := mock_command.NewMockRepository(ctrl)
+			if tt.command != "shrug" && tt.wantCode != http_error.BadRequestCode {
+				commandRepoMock.EXPECT().GetByName(ctx, "c1", tt.command).Return(tt.existing, nil)
+			}
+			if tt.wantCode == "" {
+				commandRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *model.SlashCommand) (*model.SlashCommand, error) {
+					assert.Equal(t, "u1", c.CreatorID)
+					assert.NotEmpty(t, c.Secret)
+					return c, nil
+				})
+			}
+
+			commandUsecase := usecase.New(&dependency.Repositories{
+				Conversation: convRepoMock,
+				Command:      commandRepoMock,
+			}, &dependency.Gateways{
+				Commands: builtin.Registry(),
+				URLGuard: helper.URLGuard(helper.StaticResolver{"ci.example.id": {"93.184.216.34"}}),
+			})
+
+			res, err := commandUsecase.Create(ctx, &payload.CreateCommandRequest{
+				UserID:         "u1",
+				ConversationID: "c1",
+				Name:           tt.command,
+				URL:            tt.url,
+			})
+			if tt.wantCode != "" {
+				if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+					assert.Equal(t, tt.wantCode, httpErr.ErrorCode)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, res.Command.Secret, res.Secret)
+			}
+		})
+	}
+}
+
+func Test_CommandUsecase_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetById(ctx, "c1").Return(&model.Conversation{Model: model.Model{ID: "c1"}, SenderID: "u1", ReceiverID: "u2"}, nil)
+	commandRepoMock := mock_command.NewMockRepository(ctrl)
+	commandRepoMock.EXPECT().GetAllByConversationId(ctx, "c1").Return([]*model.SlashCommand{
+		{Model: model.Model{ID: "cmd1"}, Name: "deploy", Description: "Deploy a service"},
+	}, nil)
+
+	commandUsecase := usecase.New(&dependency.Repositories{
+		Conversation: convRepoMock,
+		Command:      commandRepoMock,
+	}, &dependency.Gateways{Commands: builtin.Registry()})
+
+	res, err := commandUsecase.GetAll(ctx, &payload.GetAllCommandsRequest{UserID: "u2", ConversationID: "c1"})
+	if assert.NoError(t, err) {
+		var names []string
+		for _, c := range res.Commands {
+			names = append(names, c.Name)
+		}
+		assert.Equal(t, []string{"help", "me", "poll", "roll", "shrug", "deploy"}, names)
+		assert.True(t, res.Commands[0].Builtin)
+		assert.Equal(t, "cmd1", res.Commands[5].ID)
+	}
+}
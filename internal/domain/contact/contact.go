@@ -0,0 +1,33 @@
+package contact
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	Create(ctx context.Context, contact *model.Contact) (*model.Contact, error)
+	Delete(ctx context.Context, userId string, contactId string) error
+	UpdateNickname(ctx context.Context, userId string, contactId string, nickname string) error
+	Get(ctx context.Context, userId string, contactId string) (*model.Contact, error)
+	GetAllByUserId(ctx context.Context, userId string) ([]*model.Contact, error)
+}
+
+type Usecase interface {
+	Add(ctx context.Context, req *payload.AddContactRequest) (*payload.AddContactResponse, error)
+	Remove(ctx context.Context, req *payload.RemoveContactRequest) (*payload.RemoveContactResponse, error)
+	UpdateNickname(ctx context.Context, req *payload.UpdateNicknameRequest) (*payload.UpdateNicknameResponse, error)
+	GetAll(ctx context.Context, req *payload.GetAllContactRequest) (*payload.GetAllContactResponse, error)
+	Import(ctx context.Context, req *payload.ImportContactRequest) (*payload.ImportContactResponse, error)
+}
+
+type Handler interface {
+	Add(ctx echo.Context) error
+	Remove(ctx echo.Context) error
+	UpdateNickname(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Import(ctx echo.Context) error
+}
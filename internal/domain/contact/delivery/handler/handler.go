@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type ContactHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) contact.Handler {
+	return &ContactHandler{
+		usecases: u,
+	}
+}
+
+// AddContact godoc
+// @Summary Add Contact
+// @Description add a user to the caller's contacts with an optional private nickname
+// @Tags Contact
+// @Accept application/json
+// @Param body body payload.AddContactRequest true "Add Contact"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.AddContactResponse}
+// @Router /api/v1/contacts [post]
+func (h ContactHandler) Add(ctx echo.Context) error {
+	var body payload.AddContactRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Contact.Add(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to add contact: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RemoveContact godoc
+// @Summary Remove Contact
+// @Description remove a user from the caller's contacts
+// @Tags Contact
+// @Accept application/json
+// @Param id path string true "Contact User ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RemoveContactResponse}
+// @Router /api/v1/contacts/{id} [delete]
+func (h ContactHandler) Remove(ctx echo.Context) error {
+	var body payload.RemoveContactRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Contact.Remove(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to remove contact: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// UpdateContactNickname godoc
+// @Summary Update Contact Nickname
+// @Description set the private nickname of a contact
+// @Tags Contact
+// @Accept application/json
+// @Param id path string true "Contact User ID"
+// @Param body body payload.UpdateNicknameRequest true "Update Nickname"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.UpdateNicknameResponse}
+// @Router /api/v1/contacts/{id} [patch]
+func (h ContactHandler) UpdateNickname(ctx echo.Context) error {
+	var body payload.UpdateNicknameRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Contact.UpdateNickname(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to update contact nickname: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetAllContact godoc
+// @Summary Get All Contact
+// @Description get the caller's contacts
+// @Tags Contact
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetAllContactResponse}
+// @Router /api/v1/contacts [get]
+func (h ContactHandler) GetAll(ctx echo.Context) error {
+	var body payload.GetAllContactRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Contact.GetAll(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get contacts: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// ImportContact godoc
+// @Summary Import Contact
+// @Description add every existing user matching the given emails to the caller's contacts
+// @Tags Contact
+// @Accept application/json
+// @Param body body payload.ImportContactRequest true "Import Contact"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.ImportContactResponse}
+// @Router /api/v1/contacts/import [post]
+func (h ContactHandler) Import(ctx echo.Context) error {
+	var body payload.ImportContactRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Contact.Import(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to import contacts: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
@@ -0,0 +1,14 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type AddContactRequest struct {
+	UserID    string `json:"-"`
+	ContactID string `json:"user_id" validate:"required"`
+	Nickname  string `json:"nickname"`
+}
+
+type AddContactResponse struct {
+	Contact *model.Contact `json:"contact"`
+	Message string         `json:"message"`
+}
@@ -0,0 +1,12 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type GetAllContactRequest struct {
+	UserID string `json:"-"`
+}
+
+type GetAllContactResponse struct {
+	Contacts []*model.Contact `json:"contacts"`
+	Message  string           `json:"message"`
+}
@@ -0,0 +1,15 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type ImportContactRequest struct {
+	UserID string   `json:"-"`
+	Emails []string `json:"emails" validate:"required,max=500,dive,email"`
+}
+
+type ImportContactResponse struct {
+	Imported []*model.Contact `json:"imported"`
+	// Emails that don't belong to any user
+	NotFound []string `json:"not_found"`
+	Message  string   `json:"message"`
+}
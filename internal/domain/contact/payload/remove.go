@@ -0,0 +1,10 @@
+package payload
+
+type RemoveContactRequest struct {
+	UserID    string `json:"-"`
+	ContactID string `param:"id"`
+}
+
+type RemoveContactResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,12 @@
+package payload
+
+type UpdateNicknameRequest struct {
+	UserID    string `json:"-"`
+	ContactID string `param:"id" json:"-"`
+	// Empty nickname falls back to the user's own name
+	Nickname string `json:"nickname"`
+}
+
+type UpdateNicknameResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ContactRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) contact.Repository {
+	return &ContactRepository{
+		DB: gormDB,
+	}
+}
+
+// Adding an existing contact again only updates its nickname
+func (r ContactRepository) Create(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	result := r.DB.WithContext(ctx).Model(contact).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "contact_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"nickname", "updated_at"}),
+	}).Create(contact)
+	return contact, result.Error
+}
+
+// Contacts are hard deleted so the unique (user_id, contact_id) index stays usable for upserts
+func (r ContactRepository) Delete(ctx context.Context, userId string, contactId string) error {
+	result := r.DB.WithContext(ctx).Unscoped().Where("user_id = ? AND contact_id = ?", userId, contactId).Delete(&model.Contact{})
+	return result.Error
+}
+
+func (r ContactRepository) UpdateNickname(ctx context.Context, userId string, contactId string, nickname string) error {
+	result := r.DB.WithContext(ctx).Table(constant.ContactTable).Where("user_id = ? AND contact_id = ?", userId, contactId).
+		Updates(map[string]interface{}{"nickname": nickname, "updated_at": time.Now()})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r ContactRepository) Get(ctx context.Context, userId string, contactId string) (*model.Contact, error) {
+	var contact *model.Contact
+	result := r.DB.WithContext(ctx).Table(constant.ContactTable).
+		Where("user_id = ? AND contact_id = ?", userId, contactId).Limit(1).Find(&contact)
+	if result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return contact, result.Error
+}
+
+func (r ContactRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Contact, error) {
+	var contacts []*model.Contact
+	result := r.DB.WithContext(ctx).Preload("Contact", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "email", "photo_url")
+	}).Where("user_id = ?", userId).Find(&contacts)
+	return contacts, result.Error
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ContactUsecase struct {
+	repositories *dependency.Repositories
+}
+
+func New(r *dependency.Repositories) contact.Usecase {
+	return &ContactUsecase{
+		repositories: r,
+	}
+}
+
+func (u ContactUsecase) Add(ctx context.Context, req *payload.AddContactRequest) (*payload.AddContactResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if req.UserID == req.ContactID {
+		return nil, http_error.BadRequest(errors.New("cannot add yourself as a contact"))
+	}
+	user, err := u.repositories.User.GetById(ctx, req.ContactID)
+	if err != nil {
+		log.Error("Failed to get user to add as contact: ", zap.Error(err))
+		return nil, http_error.RecordNotFound("user")
+	}
+
+	c, err := u.repositories.Contact.Create(ctx, &model.Contact{
+		UserID:    req.UserID,
+		ContactID: user.ID,
+		Nickname:  strings.TrimSpace(req.Nickname),
+	})
+	if err != nil {
+		log.Error("Failed to add contact: ", zap.Error(err))
+		return nil, err
+	}
+	c.Contact = publicUser(user)
+
+	return &payload.AddContactResponse{
+		Contact: c,
+		Message: "Add contact success",
+	}, nil
+}
+
+func (u ContactUsecase) Remove(ctx context.Context, req *payload.RemoveContactRequest) (*payload.RemoveContactResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	err := u.repositories.Contact.Delete(ctx, req.UserID, req.ContactID)
+	if err != nil {
+		log.Error("Failed to remove contact: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RemoveContactResponse{
+		Message: "Remove contact success",
+	}, nil
+}
+
+func (u ContactUsecase) UpdateNickname(ctx context.Context, req *payload.UpdateNicknameRequest) (*payload.UpdateNicknameResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	err := u.repositories.Contact.UpdateNickname(ctx, req.UserID, req.ContactID, strings.TrimSpace(req.Nickname))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http_error.RecordNotFound("contact")
+	}
+	if err != nil {
+		log.Error("Failed to update contact nickname: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.UpdateNicknameResponse{
+		Message: "Update nickname success",
+	}, nil
+}
+
+func (u ContactUsecase) GetAll(ctx context.Context, req *payload.GetAllContactRequest) (*payload.GetAllContactResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	contacts, err := u.repositories.Contact.GetAllByUserId(ctx, req.UserID)
+	if err != nil {
+		log.Error("Failed to get contacts: ", zap.Error(err))
+		return nil, err
+	}
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return strings.ToLower(displayName(contacts[i])) < strings.ToLower(displayName(contacts[j]))
+	})
+
+	return &payload.GetAllContactResponse{
+		Contacts: contacts,
+		Message:  "Successfully get contacts",
+	}, nil
+}
+
+func (u ContactUsecase) Import(ctx context.Context, req *payload.ImportContactRequest) (*payload.ImportContactResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	users, err := u.repositories.User.GetByEmails(ctx, req.Emails)
+	if err != nil {
+		log.Error("Failed to get users by emails: ", zap.Error(err))
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(users))
+	imported := make([]*model.Contact, 0, len(users))
+	for _, user := range users {
+		found[strings.ToLower(user.Email)] = true
+		if user.ID == req.UserID {
+			continue
+		}
+
+		// Keep the nickname of contacts that already exist
+		c, err := u.repositories.Contact.Get(ctx, req.UserID, user.ID)
+		if err != nil {
+			log.Error("Failed to get contact: ", zap.Error(err))
+			return nil, err
+		}
+		if c == nil {
+			c, err = u.repositories.Contact.Create(ctx, &model.Contact{
+				UserID:    req.UserID,
+				ContactID: user.ID,
+			})
+			if err != nil {
+				log.Error("Failed to import contact: ", zap.Error(err))
+				return nil, err
+			}
+		}
+		c.Contact = publicUser(user)
+		imported = append(imported, c)
+	}
+
+	notFound := make([]string, 0)
+	for _, email := range req.Emails {
+		if !found[strings.ToLower(email)] {
+			notFound = append(notFound, email)
+		}
+	}
+
+	return &payload.ImportContactResponse{
+		Imported: imported,
+		NotFound: notFound,
+		Message:  "Import contacts success",
+	}, nil
+}
+
+func publicUser(user *model.User) *model.User {
+	return &model.User{
+		Model:    model.Model{ID: user.ID},
+		Name:     user.Name,
+		Email:    user.Email,
+		PhotoURL: user.PhotoURL,
+	}
+}
+
+func displayName(c *model.Contact) string {
+	if c.Nickname != "" {
+		return c.Nickname
+	}
+	if c.Contact != nil {
+		return c.Contact.Name
+	}
+	return ""
+}
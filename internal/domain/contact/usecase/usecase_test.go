@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/contact/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	mock_contact "gitlab.com/raihanlh/messenger-api/testing/mocks/contact"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+)
+
+func Test_ContactUsecase_Import(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	me := &model.User{Model: model.Model{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2"}, Name: "Me", Email: "me@example.id"}
+	known := &model.User{Model: model.Model{ID: "34251esd-d76e-401a-a3ba-7a03352812c2"}, Name: "Known", Email: "known@example.id"}
+	fresh := &model.User{Model: model.Model{ID: "47dsga9t-d76e-401a-a3ba-7a03352812c2"}, Name: "Fresh", Email: "fresh@example.id"}
+
+	req := &payload.ImportContactRequest{
+		UserID: me.ID,
+		Emails: []string{"me@example.id", "Known@example.id", "fresh@example.id", "nobody@example.id"},
+	}
+
+	ctx := context.TODO()
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetByEmails(ctx, req.Emails).Return([]*model.User{me, known, fresh}, nil)
+
+	contactRepoMock := mock_contact.NewMockRepository(ctrl)
+	contactRepoMock.EXPECT().Get(ctx, me.ID, known.ID).Return(&model.Contact{UserID: me.ID, ContactID: known.ID, Nickname: "Buddy"}, nil)
+	contactRepoMock.EXPECT().Get(ctx, me.ID, fresh.ID).Return(nil, nil)
+	contactRepoMock.EXPECT().Create(ctx, &model.Contact{UserID: me.ID, ContactID: fresh.ID}).
+		Return(&model.Contact{UserID: me.ID, ContactID: fresh.ID}, nil)
+
+	contactUsecase := usecase.New(&dependency.Repositories{
+		User:    userRepoMock,
+		Contact: contactRepoMock,
+	})
+
+	res, err := contactUsecase.Import(ctx, req)
+	if assert.NoError(t, err) {
+		assert.Len(t, res.Imported, 2)
+		assert.Equal(t, "Buddy", res.Imported[0].Nickname, "existing nickname is kept")
+		assert.Equal(t, fresh.ID, res.Imported[1].Contact.ID)
+		assert.Equal(t, []string{"nobody@example.id"}, res.NotFound)
+	}
+}
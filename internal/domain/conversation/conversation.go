@@ -6,23 +6,45 @@ import (
 	"github.com/labstack/echo/v4"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation/payload"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
 )
 
 type Repository interface {
 	Create(ctx context.Context, conv *model.Conversation) (*model.Conversation, error)
 	GetById(ctx context.Context, id string) (*model.Conversation, error)
 	GetAllByUserId(ctx context.Context, userId string) ([]*model.Conversation, error)
+	GetListByUserId(ctx context.Context, pgn *pagination.Pagination, req *payload.GetAllByUserIdConvRequest) ([]*payload.ConversationListRow, error)
 	GetBySenderReceiverIds(ctx context.Context, senderId string, receiverId string) (*model.Conversation, error)
+	GetRequestsByUserId(ctx context.Context, pgn *pagination.Pagination, userId string) ([]*payload.ConversationListRow, error)
+	UpdateStatus(ctx context.Context, id string, status string) error
+	GetParticipant(ctx context.Context, userId string, conversationId string) (*model.UserParticipant, error)
+	GetParticipantsByUserId(ctx context.Context, userId string) ([]*model.UserParticipant, error)
+	UpsertParticipant(ctx context.Context, participant *model.UserParticipant) (*model.UserParticipant, error)
 }
 
 type Usecase interface {
 	Create(ctx context.Context, req *payload.CreateConversationRequest) (*payload.CreateConversationResponse, error)
 	GetById(ctx context.Context, req *payload.GetByIdConversationRequest) (*payload.GetByIdConversationResponse, error)
 	GetAllByUserId(ctx context.Context, req *payload.GetAllByUserIdConvRequest) (*payload.GetAllByUserIdConvResponse, error)
+	Archive(ctx context.Context, req *payload.ArchiveConversationRequest) (*payload.ConversationSettingsResponse, error)
+	Mute(ctx context.Context, req *payload.MuteConversationRequest) (*payload.ConversationSettingsResponse, error)
+	Pin(ctx context.Context, req *payload.PinConversationRequest) (*payload.ConversationSettingsResponse, error)
+	Clear(ctx context.Context, req *payload.ClearConversationRequest) (*payload.ConversationSettingsResponse, error)
+	GetRequests(ctx context.Context, req *payload.GetRequestsRequest) (*payload.GetRequestsResponse, error)
+	AcceptRequest(ctx context.Context, req *payload.RespondRequestRequest) (*payload.RespondRequestResponse, error)
+	DeclineRequest(ctx context.Context, req *payload.RespondRequestRequest) (*payload.RespondRequestResponse, error)
 }
 
 type Handler interface {
 	// Create(ctx echo.Context) error
 	GetById(ctx echo.Context) error
 	GetAllByUserId(ctx echo.Context) error
+	Archive(ctx echo.Context) error
+	Mute(ctx echo.Context) error
+	Pin(ctx echo.Context) error
+	Clear(ctx echo.Context) error
+	GetRequests(ctx echo.Context) error
+	AcceptRequest(ctx echo.Context) error
+	DeclineRequest(ctx echo.Context) error
+	DeclineAndBlockRequest(ctx echo.Context) error
 }
@@ -59,7 +59,7 @@ func (h ConversationHandler) GetById(ctx echo.Context) error {
 		}
 		httpErr, ok := err.(*http_error.Error)
 		if !ok {
-			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get user by id: %s", httpErr.Error())))
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get user by id: %s", err.Error())))
 		}
 		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
 	}
@@ -71,9 +71,12 @@ func (h ConversationHandler) GetById(ctx echo.Context) error {
 
 // GetAllConversationByUserId godoc
 // @Summary Get All Conversation By User Id
-// @Description get all conversation by user id
+// @Description get all conversation by user id, pinned conversations first then by last activity
 // @Tags Conversation
 // @Accept application/json
+// @Param archived query bool false "List archived conversations instead of the inbox"
+// @Param currentPage query int false "Page number, starts at 1"
+// @Param itemsPerPage query int false "Conversations per page, at most 100"
 // @Produce json
 // @Success 200 {object} object{status=string,data=payload.GetAllByUserIdConvResponse}
 // @Router /api/v1/conversations [get]
@@ -104,7 +107,380 @@ func (h ConversationHandler) GetAllByUserId(ctx echo.Context) error {
 		}
 		httpErr, ok := err.(*http_error.Error)
 		if !ok {
-			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get user by id: %s", httpErr.Error())))
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get user by id: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// ArchiveConversation godoc
+// @Summary Archive Conversation
+// @Description archive or unarchive a conversation for the caller
+// @Tags Conversation
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param body body payload.ArchiveConversationRequest true "Archive Conversation"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.ConversationSettingsResponse}
+// @Router /api/v1/conversations/{convo_id}/archive [put]
+func (h ConversationHandler) Archive(ctx echo.Context) error {
+	var body payload.ArchiveConversationRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Conversation.Archive(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to archive conversation: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// MuteConversation godoc
+// @Summary Mute Conversation
+// @Description mute a conversation for the caller until the given time, null unmutes it
+// @Tags Conversation
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param body body payload.MuteConversationRequest true "Mute Conversation"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.ConversationSettingsResponse}
+// @Router /api/v1/conversations/{convo_id}/mute [put]
+func (h ConversationHandler) Mute(ctx echo.Context) error {
+	var body payload.MuteConversationRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Conversation.Mute(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to mute conversation: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// PinConversation godoc
+// @Summary Pin Conversation
+// @Description pin a conversation to the top of the caller's list or unpin it
+// @Tags Conversation
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param body body payload.PinConversationRequest true "Pin Conversation"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.ConversationSettingsResponse}
+// @Router /api/v1/conversations/{convo_id}/pin [put]
+func (h ConversationHandler) Pin(ctx echo.Context) error {
+	var body payload.PinConversationRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Conversation.Pin(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to pin conversation: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// ClearConversation godoc
+// @Summary Clear Conversation
+// @Description delete the conversation history for the caller only, the other participant is not affected
+// @Tags Conversation
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.ConversationSettingsResponse}
+// @Router /api/v1/conversations/{convo_id}/history [delete]
+func (h ConversationHandler) Clear(ctx echo.Context) error {
+	var body payload.ClearConversationRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Conversation.Clear(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to clear conversation: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetMessageRequests godoc
+// @Summary Get Message Requests
+// @Description get conversations started by users the caller has not accepted yet
+// @Tags Conversation
+// @Accept application/json
+// @Param currentPage query int false "Page number, starts at 1"
+// @Param itemsPerPage query int false "Requests per page, at most 100"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetRequestsResponse}
+// @Router /api/v1/conversations/requests [get]
+func (h ConversationHandler) GetRequests(ctx echo.Context) error {
+	var body payload.GetRequestsRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Conversation.GetRequests(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get message requests: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// AcceptMessageRequest godoc
+// @Summary Accept Message Request
+// @Description accept a message request, the conversation moves to the inbox
+// @Tags Conversation
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RespondRequestResponse}
+// @Router /api/v1/conversations/requests/{convo_id}/accept [post]
+func (h ConversationHandler) AcceptRequest(ctx echo.Context) error {
+	var body payload.RespondRequestRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Conversation.AcceptRequest(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to accept message request: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// DeclineMessageRequest godoc
+// @Summary Decline Message Request
+// @Description decline a message request, the sender can no longer message the caller in it
+// @Tags Conversation
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RespondRequestResponse}
+// @Router /api/v1/conversations/requests/{convo_id}/decline [post]
+func (h ConversationHandler) DeclineRequest(ctx echo.Context) error {
+	var body payload.RespondRequestRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Conversation.DeclineRequest(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to decline message request: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// DeclineAndBlockMessageRequest godoc
+// @Summary Decline And Block Message Request
+// @Description decline a message request and block its sender
+// @Tags Conversation
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RespondRequestResponse}
+// @Router /api/v1/conversations/requests/{convo_id}/block [post]
+func (h ConversationHandler) DeclineAndBlockRequest(ctx echo.Context) error {
+	var body payload.RespondRequestRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	body.Block = true
+	data, err := h.usecases.Conversation.DeclineRequest(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to decline message request: %s", err.Error())))
 		}
 		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
 	}
@@ -1,14 +1,57 @@
 package payload
 
-import "gitlab.com/raihanlh/messenger-api/internal/model"
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
+)
 
 type GetAllByUserIdConvRequest struct {
+	// Conversations are always sorted pinned first then by last activity, sort is ignored
+	pagination.Pagination
 	UserID string `json:"-"`
+	// List archived conversations instead of the inbox
+	Archived bool `query:"archived"`
+}
+
+type GetAllByUserIdConvResponse struct {
+	*pagination.Pagination
+	PaginatedData []*GetAllByUserIdConv `json:"paginatedData"`
+	Message       string                `json:"message"`
 }
-type GetAllByUserIdConvResponse []*GetAllByUserIdConv
 
 type GetAllByUserIdConv struct {
 	GetByIdConversationResponse
 	LastMessage *model.Message `json:"last_message"`
-	UnreadCount int64          `json:"unread_count"`
+	// Start of the last message, cut to a fixed length
+	LastMessagePreview string `json:"last_message_preview,omitempty"`
+	UnreadCount        int64  `json:"unread_count"`
+	Draft              string `json:"draft,omitempty"`
+	ConversationSettings
+}
+
+// ConversationListRow is a conversation as seen by one participant, read from their inbox
+// together with the other participant
+type ConversationListRow struct {
+	ID                    string
+	CreatedAt             time.Time
+	Status                string
+	WithUserID            string
+	WithUserName          string
+	WithUserEmail         string
+	WithUserPhotoURL      string
+	WithUserDeleted       bool
+	Nickname              string
+	LastMessageID         string
+	LastMessageSentAt     *time.Time
+	LastMessageSenderID   string
+	LastMessageSenderName string
+	LastMessagePreview    string
+	UnreadCount           int64
+	Draft                 string
+	IsArchived            bool
+	MutedUntil            *time.Time
+	PinnedOrder           *int
+	ClearedAt             *time.Time
 }
@@ -1,6 +1,10 @@
 package payload
 
-import "gitlab.com/raihanlh/messenger-api/internal/model"
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
 
 type GetByIdConversationRequest struct {
 	ConversationID string `param:"convo_id"`
@@ -10,4 +14,6 @@ type GetByIdConversationRequest struct {
 type GetByIdConversationResponse struct {
 	ConversationID string      `json:"id"`
 	WithUser       *model.User `json:"with_user"`
+	// Presence of the other user, left out when they blocked the caller
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
 }
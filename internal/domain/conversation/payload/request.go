@@ -0,0 +1,28 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/pkg/pagination"
+
+type GetRequestsRequest struct {
+	// Requests are always sorted newest first, sort is ignored
+	pagination.Pagination
+	UserID string `json:"-"`
+}
+
+type GetRequestsResponse struct {
+	*pagination.Pagination
+	PaginatedData []*GetAllByUserIdConv `json:"paginatedData"`
+	Message       string                `json:"message"`
+}
+
+type RespondRequestRequest struct {
+	ConversationID string `param:"convo_id"`
+	UserID         string `json:"-"`
+	// Also block the user who sent the request
+	Block bool `json:"-"`
+}
+
+type RespondRequestResponse struct {
+	ConversationID string `json:"id"`
+	Status         string `json:"status"`
+	Message        string `json:"message"`
+}
@@ -0,0 +1,41 @@
+package payload
+
+import "time"
+
+type ArchiveConversationRequest struct {
+	ConversationID string `param:"convo_id" json:"-"`
+	UserID         string `json:"-"`
+	Archived       bool   `json:"archived"`
+}
+
+type MuteConversationRequest struct {
+	ConversationID string `param:"convo_id" json:"-"`
+	UserID         string `json:"-"`
+	// Leave empty or null to unmute
+	MutedUntil *time.Time `json:"muted_until"`
+}
+
+type PinConversationRequest struct {
+	ConversationID string `param:"convo_id" json:"-"`
+	UserID         string `json:"-"`
+	Pinned         bool   `json:"pinned"`
+	// Lower order is shown first among pinned conversations
+	Order int `json:"order"`
+}
+
+type ClearConversationRequest struct {
+	ConversationID string `param:"convo_id"`
+	UserID         string `json:"-"`
+}
+
+type ConversationSettings struct {
+	Archived    bool       `json:"archived"`
+	MutedUntil  *time.Time `json:"muted_until,omitempty"`
+	PinnedOrder *int       `json:"pinned_order,omitempty"`
+	ClearedAt   *time.Time `json:"cleared_at,omitempty"`
+}
+
+type ConversationSettingsResponse struct {
+	ConversationID string `json:"id"`
+	ConversationSettings
+}
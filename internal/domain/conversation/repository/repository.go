@@ -3,26 +3,35 @@ package repository
 import (
 	"context"
 	"errors"
+	"math"
+	"time"
 
 	"gitlab.com/raihanlh/messenger-api/internal/constant"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation/payload"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/cipher"
+	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type ConversationRepository struct {
 	DB *gorm.DB
+	// Decrypts the preloaded last messages
+	Keys *cipher.Keyring
 }
 
-func New(gormDB *gorm.DB) conversation.Repository {
+func New(gormDB *gorm.DB, keys *cipher.Keyring) conversation.Repository {
 	return &ConversationRepository{
-		DB: gormDB,
+		DB:   gormDB,
+		Keys: keys,
 	}
 }
 
 func (r ConversationRepository) Create(ctx context.Context, conv *model.Conversation) (*model.Conversation, error) {
-	result := r.DB.WithContext(ctx).Model(conv).Clauses(clause.OnConflict{DoNothing: true}).Create(&conv)
+	result := postgres.Conn(ctx, r.DB).Model(conv).Clauses(clause.OnConflict{DoNothing: true}).Create(&conv)
 	return conv, result.Error
 }
 
@@ -38,13 +47,97 @@ func (r ConversationRepository) GetById(ctx context.Context, id string) (*model.
 func (r ConversationRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Conversation, error) {
 	var convs []*model.Conversation
 
-	result := r.DB.WithContext(ctx).Preload("Sender").Preload("Receiver").Preload("LastMessage", func(db *gorm.DB) *gorm.DB {
+	result := r.DB.WithContext(ctx).Preload("Sender", withDeleted).Preload("Receiver", withDeleted).Preload("LastMessage", func(db *gorm.DB) *gorm.DB {
 		return db.Preload("Sender", func(db *gorm.DB) *gorm.DB {
-			return db.Select("id", "name")
-		}).Select("id", "message_text", "sent_at", "sender_id", "conversation_id").Order("sent_at DESC").Limit(1)
+			return db.Unscoped().Select("id", "name")
+		}).Select("id", "message_text", "key_id", "data_key", "sent_at", "sender_id", "conversation_id").Order("sent_at DESC").Limit(1)
 	}).Where("sender_id = ?", userId).Or("receiver_id = ?", userId).Find(&convs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return convs, r.openLastMessages(convs)
+}
+
+// GetListByUserId reads one page of the user's conversation list from the inbox projection
+// with two queries, a count and the page itself. The inbox already holds the last message
+// and the unread count, the joins only add the other participant, the caller's nickname
+// for them, their draft and their settings.
+func (r ConversationRepository) GetListByUserId(ctx context.Context, pgn *pagination.Pagination, req *payload.GetAllByUserIdConvRequest) ([]*payload.ConversationListRow, error) {
+	userId := req.UserID
+
+	query := r.inboxQuery(ctx, userId).
+		// Requests sent to the user live in their own inbox until accepted
+		Where("NOT (c.receiver_id = ? AND c.status IN ?)", userId, []string{model.ConversationStatusRequest, model.ConversationStatusDeclined}).
+		Where("COALESCE(p.is_archived, false) = ?", req.Archived).
+		// A cleared conversation stays hidden until someone sends a new message
+		Where("p.cleared_at IS NULL OR i.last_message_id <> ''").
+		Session(&gorm.Session{})
+
+	return r.getInboxPage(query, pgn, "p.pinned_order IS NULL, p.pinned_order ASC, i.last_activity_at DESC, c.id DESC")
+}
+
+// GetRequestsByUserId reads one page of the pending message requests other users sent to
+// userId, newest first, from the inbox projection like GetListByUserId
+func (r ConversationRepository) GetRequestsByUserId(ctx context.Context, pgn *pagination.Pagination, userId string) ([]*payload.ConversationListRow, error) {
+	query := r.inboxQuery(ctx, userId).
+		Where("c.receiver_id = ? AND c.status = ?", userId, model.ConversationStatusRequest).
+		Session(&gorm.Session{})
+
+	return r.getInboxPage(query, pgn, "i.last_activity_at DESC, c.id DESC")
+}
+
+// Inbox rows of the user joined with their conversation and their settings for it
+func (r ConversationRepository) inboxQuery(ctx context.Context, userId string) *gorm.DB {
+	return r.DB.WithContext(ctx).Table(constant.InboxTable+" i").
+		Joins("JOIN "+constant.ConversationTable+" c ON c.id::text = i.conversation_id AND c.deleted_at IS NULL").
+		Joins("LEFT JOIN "+constant.UserParticipantTable+" p ON p.conversation_id = i.conversation_id AND p.user_id = i.user_id AND p.deleted_at IS NULL").
+		Where("i.user_id = ? AND i.deleted_at IS NULL", userId)
+}
+
+// Count the rows of an inbox query and read the page of them in order
+func (r ConversationRepository) getInboxPage(query *gorm.DB, pgn *pagination.Pagination, order string) ([]*payload.ConversationListRow, error) {
+	var rows []*payload.ConversationListRow
+
+	if result := query.Count(&pgn.TotalItems); result.Error != nil {
+		return nil, result.Error
+	}
+	pgn.TotalPages = int(math.Ceil(float64(pgn.TotalItems) / float64(pgn.GetLimit())))
 
-	return convs, result.Error
+	result := query.Select(`c.id, c.created_at, c.status,
+		u.id AS with_user_id, u.name AS with_user_name, u.email AS with_user_email, u.photo_url AS with_user_photo_url,
+		u.deleted_at IS NOT NULL AS with_user_deleted, ct.nickname,
+		i.last_message_id, i.last_activity_at AS last_message_sent_at, i.last_message_sender_id, lms.name AS last_message_sender_name,
+		i.last_message_preview, i.unread_count, d.text AS draft,
+		COALESCE(p.is_archived, false) AS is_archived, p.muted_until, p.pinned_order, p.cleared_at`).
+		// Participants who deleted their account are still shown, anonymized
+		Joins("JOIN " + constant.UserTable + " u ON u.id::text = CASE WHEN c.sender_id = i.user_id THEN c.receiver_id ELSE c.sender_id END").
+		Joins("LEFT JOIN " + constant.UserTable + " lms ON lms.id::text = i.last_message_sender_id").
+		Joins("LEFT JOIN " + constant.ContactTable + " ct ON ct.user_id = i.user_id AND ct.contact_id = u.id::text AND ct.deleted_at IS NULL").
+		Joins("LEFT JOIN " + constant.DraftTable + " d ON d.user_id = i.user_id AND d.conversation_id = i.conversation_id AND d.deleted_at IS NULL").
+		Order(order).
+		Offset(pgn.GetOffset()).Limit(pgn.GetLimit()).
+		Scan(&rows)
+
+	return rows, result.Error
+}
+
+func (r ConversationRepository) openLastMessages(convs []*model.Conversation) error {
+	for _, conv := range convs {
+		if conv.LastMessage == nil {
+			continue
+		}
+		if err := conv.LastMessage.Open(r.Keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r ConversationRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+	result := postgres.Conn(ctx, r.DB).Table(constant.ConversationTable).Where("id = ?", id).
+		Updates(map[string]interface{}{"status": status, "updated_at": time.Now()})
+	return result.Error
 }
 
 func (r ConversationRepository) GetBySenderReceiverIds(ctx context.Context, senderId string, receiverId string) (*model.Conversation, error) {
@@ -59,3 +152,32 @@ func (r ConversationRepository) GetBySenderReceiverIds(ctx context.Context, send
 		return nil, result.Error
 	}
 }
+
+func (r ConversationRepository) GetParticipant(ctx context.Context, userId string, conversationId string) (*model.UserParticipant, error) {
+	var participant *model.UserParticipant
+	result := r.DB.WithContext(ctx).Table(constant.UserParticipantTable).
+		Where("user_id = ? AND conversation_id = ?", userId, conversationId).Limit(1).Find(&participant)
+	if result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return participant, result.Error
+}
+
+func (r ConversationRepository) GetParticipantsByUserId(ctx context.Context, userId string) ([]*model.UserParticipant, error) {
+	var participants []*model.UserParticipant
+	result := r.DB.WithContext(ctx).Table(constant.UserParticipantTable).Where("user_id = ?", userId).Find(&participants)
+	return participants, result.Error
+}
+
+func (r ConversationRepository) UpsertParticipant(ctx context.Context, participant *model.UserParticipant) (*model.UserParticipant, error) {
+	result := postgres.Conn(ctx, r.DB).Model(participant).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "conversation_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_archived", "muted_until", "pinned_order", "cleared_at", "updated_at"}),
+	}).Create(participant)
+	return participant, result.Error
+}
+
+// Participants who deleted their account are still loaded, anonymized
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation/payload"
 	repo "gitlab.com/raihanlh/messenger-api/internal/domain/conversation/repository"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
 	"gorm.io/driver/postgres"
@@ -69,7 +70,7 @@ func Test_UserRepository_Conversation(t *testing.T) {
 		Model: model.Model{
 			ID: id,
 		},
-		SenderID: sender.ID,
+		SenderID:   sender.ID,
 		ReceiverID: receiver.ID,
 		// Sender: sender,
 		// Receiver: receiver,
@@ -150,3 +151,132 @@ func Test_UserRepository_Conversation(t *testing.T) {
 		})
 	}
 }
+
+func Test_ConversationRepository_UpsertParticipant(t *testing.T) {
+	db, mock := Setup()
+
+	query := `INSERT INTO "user_participants" ("created_at","updated_at","deleted_at","user_id","conversation_id","is_archived","muted_until","pinned_order","cleared_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) ON CONFLICT ("user_id","conversation_id") DO UPDATE SET "is_archived"="excluded"."is_archived","muted_until"="excluded"."muted_until","pinned_order"="excluded"."pinned_order","cleared_at"="excluded"."cleared_at","updated_at"="excluded"."updated_at" RETURNING "id"`
+
+	order := 1
+	participant := &model.UserParticipant{
+		UserID:         "34251esd-d76e-401a-a3ba-7a03352812c2",
+		ConversationID: "6fd33930-d76e-401a-a3ba-7a03352812c2",
+		IsArchived:     true,
+		PinnedOrder:    &order,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(AnyTime{}, AnyTime{}, nil, participant.UserID, participant.ConversationID, true, nil, order, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1b6b1c5e-8c55-4a6c-9a38-3cbf0fe5a0a1"))
+	mock.ExpectCommit()
+
+	r := &repo.ConversationRepository{
+		DB: db,
+	}
+
+	res, err := r.UpsertParticipant(context.TODO(), participant)
+	assert.NoError(t, err)
+	assert.True(t, res.IsArchived)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_ConversationRepository_GetListByUserId(t *testing.T) {
+	db, mock := Setup()
+
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	sentAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// The whole page is read from the inbox with a count and a single select, whatever its size
+	mock.ExpectQuery(`SELECT count\(\*\) FROM inboxes i JOIN conversations c .* LEFT JOIN user_participants p .* WHERE \(i\.user_id = \$1 AND i\.deleted_at IS NULL\)`).
+		WithArgs(userId, userId, model.ConversationStatusRequest, model.ConversationStatusDeclined, false).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(25))
+	mock.ExpectQuery(`SELECT c\.id, .* FROM inboxes i JOIN conversations c .* JOIN users u .* WHERE .* ORDER BY p\.pinned_order IS NULL, p\.pinned_order ASC, i\.last_activity_at DESC, c\.id DESC LIMIT 10 OFFSET 10`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "with_user_id", "with_user_name", "nickname", "last_message_id", "last_message_sent_at", "last_message_preview", "unread_count", "pinned_order"}).
+			AddRow("c1", "u2", "Bob", "Bobby", "m1", sentAt, "see you", 3, 1).
+			AddRow("c2", "u3", "Carol", nil, "", sentAt, "", 0, nil))
+
+	r := &repo.ConversationRepository{
+		DB: db,
+	}
+
+	req := &payload.GetAllByUserIdConvRequest{UserID: userId}
+	req.Page = 2
+	rows, err := r.GetListByUserId(context.TODO(), &req.Pagination, req)
+	if assert.NoError(t, err) && assert.Len(t, rows, 2) {
+		assert.Equal(t, "Bobby", rows[0].Nickname)
+		assert.Equal(t, int64(3), rows[0].UnreadCount)
+		assert.Equal(t, sentAt, *rows[0].LastMessageSentAt)
+		assert.Equal(t, 1, *rows[0].PinnedOrder)
+		assert.Equal(t, "see you", rows[0].LastMessagePreview)
+		assert.Empty(t, rows[1].LastMessageID)
+		assert.Nil(t, rows[1].PinnedOrder)
+	}
+	assert.Equal(t, int64(25), req.TotalItems)
+	assert.Equal(t, 3, req.TotalPages)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_ConversationRepository_GetListByUserId_ArchivedPinnedFirst(t *testing.T) {
+	db, mock := Setup()
+
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	sentAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// Only the caller's archived conversations are counted and listed
+	mock.ExpectQuery(`SELECT count\(\*\) FROM inboxes i .* AND COALESCE\(p\.is_archived, false\) = \$5`).
+		WithArgs(userId, userId, model.ConversationStatusRequest, model.ConversationStatusDeclined, true).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	// Pinned conversations come first by their order, the rest by last activity
+	mock.ExpectQuery(`SELECT c\.id, .* AND COALESCE\(p\.is_archived, false\) = \$5 .* ORDER BY p\.pinned_order IS NULL, p\.pinned_order ASC, i\.last_activity_at DESC`).
+		WithArgs(userId, userId, model.ConversationStatusRequest, model.ConversationStatusDeclined, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "is_archived", "pinned_order", "last_message_sent_at"}).
+			AddRow("c1", true, 1, sentAt.Add(-time.Hour)).
+			AddRow("c2", true, nil, sentAt))
+
+	r := &repo.ConversationRepository{
+		DB: db,
+	}
+
+	req := &payload.GetAllByUserIdConvRequest{UserID: userId, Archived: true}
+	rows, err := r.GetListByUserId(context.TODO(), &req.Pagination, req)
+	if assert.NoError(t, err) && assert.Len(t, rows, 2) {
+		assert.Equal(t, "c1", rows[0].ID)
+		assert.True(t, rows[0].IsArchived)
+		assert.Equal(t, 1, *rows[0].PinnedOrder)
+		assert.Nil(t, rows[1].PinnedOrder)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_ConversationRepository_GetRequestsByUserId(t *testing.T) {
+	db, mock := Setup()
+
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	sentAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// Requests are read from the inbox like the conversation list, every one with its own last message
+	mock.ExpectQuery(`SELECT count\(\*\) FROM inboxes i JOIN conversations c .* WHERE \(i\.user_id = \$1 AND i\.deleted_at IS NULL\) AND \(c\.receiver_id = \$2 AND c\.status = \$3\)`).
+		WithArgs(userId, userId, model.ConversationStatusRequest).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery(`SELECT c\.id, .* FROM inboxes i JOIN conversations c .* JOIN users u .* AND \(c\.receiver_id = \$2 AND c\.status = \$3\) ORDER BY i\.last_activity_at DESC, c\.id DESC LIMIT 10$`).
+		WithArgs(userId, userId, model.ConversationStatusRequest).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "with_user_id", "with_user_name", "last_message_id", "last_message_sent_at", "last_message_preview", "unread_count"}).
+			AddRow("c1", model.ConversationStatusRequest, "u2", "Bob", "m2", sentAt, "hi again", 2).
+			AddRow("c2", model.ConversationStatusRequest, "u3", "Carol", "m3", sentAt.Add(-time.Hour), "hello", 1))
+
+	r := &repo.ConversationRepository{
+		DB: db,
+	}
+
+	req := &payload.GetRequestsRequest{UserID: userId}
+	rows, err := r.GetRequestsByUserId(context.TODO(), &req.Pagination, userId)
+	if assert.NoError(t, err) && assert.Len(t, rows, 2) {
+		assert.Equal(t, "m2", rows[0].LastMessageID)
+		assert.Equal(t, "m3", rows[1].LastMessageID)
+		assert.Equal(t, int64(2), rows[0].UnreadCount)
+	}
+	assert.Equal(t, int64(12), req.TotalItems)
+	assert.Equal(t, 2, req.TotalPages)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"time"
 
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
 	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation/payload"
@@ -12,6 +14,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// Maximum number of characters of a draft shown in the conversation list
+const DraftPreviewLength = 100
+
 type ConversationUsecase struct {
 	repositories *dependency.Repositories
 }
@@ -22,9 +27,21 @@ func New(r *dependency.Repositories) conversation.Usecase {
 	}
 }
 
+// Create starts a conversation under the same rules as a first message: blocked senders are
+// refused and strangers land in the receiver's message requests. An existing conversation
+// between the two users is returned as is.
 func (u ConversationUsecase) Create(ctx context.Context, req *payload.CreateConversationRequest) (*payload.CreateConversationResponse, error) {
 	log := logger.GetLogger(ctx)
 
+	blocked, err := u.repositories.Block.IsBlocked(ctx, req.ReceiverID, req.SenderID)
+	if err != nil {
+		log.Error("Failed to check block: ", zap.Error(err))
+		return nil, err
+	}
+	if blocked {
+		return nil, http_error.Blocked("You can't send messages to this user")
+	}
+
 	sender, err := u.repositories.User.GetById(ctx, req.SenderID)
 	if err != nil {
 		log.Error("Failed to get sender: ", zap.Error(err))
@@ -36,9 +53,50 @@ func (u ConversationUsecase) Create(ctx context.Context, req *payload.CreateConv
 		return nil, err
 	}
 
-	conv, err := u.repositories.Conversation.Create(ctx, &model.Conversation{
-		SenderID:   sender.ID,
-		ReceiverID: receiver.ID,
+	conv, err := u.repositories.Conversation.GetBySenderReceiverIds(ctx, sender.ID, receiver.ID)
+	if err != nil {
+		log.Error("Failed to get conversation: ", zap.Error(err))
+		return nil, err
+	}
+	if conv != nil {
+		if conv.Status == model.ConversationStatusDeclined && conv.SenderID == sender.ID {
+			return nil, http_error.Forbidden("Your message request was declined")
+		}
+		return &payload.CreateConversationResponse{
+			Conversation: conv,
+		}, nil
+	}
+
+	// First contact from someone the receiver doesn't know goes to their message requests
+	status := model.ConversationStatusRequest
+	contact, err := u.repositories.Contact.Get(ctx, receiver.ID, sender.ID)
+	if err != nil {
+		log.Error("Failed to get contact: ", zap.Error(err))
+		return nil, err
+	}
+	if contact != nil {
+		status = model.ConversationStatusAccepted
+	}
+
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		conv, err = u.repositories.Conversation.Create(ctx, &model.Conversation{
+			SenderID:   sender.ID,
+			ReceiverID: receiver.ID,
+			Status:     status,
+		})
+		if err != nil {
+			return err
+		}
+		if err := u.repositories.Inbox.Refresh(ctx, conv.ID); err != nil {
+			return err
+		}
+		return u.repositories.Outbox.Append(ctx, model.EventConversationCreated, &model.ConversationCreatedEvent{
+			ConversationID: conv.ID,
+			SenderID:       conv.SenderID,
+			ReceiverID:     conv.ReceiverID,
+			Status:         conv.Status,
+		})
 	})
 	if err != nil {
 		log.Error("Failed to create conversation: ", zap.Error(err))
@@ -58,77 +116,348 @@ func (u ConversationUsecase) GetById(ctx context.Context, req *payload.GetByIdCo
 		log.Error("Failed to get conversation by id: ", zap.Error(err))
 		return nil, err
 	}
+	var withUserId string
 	if conv.SenderID == req.UserID {
-		userWith, err := u.repositories.User.GetById(ctx, conv.ReceiverID)
-		if err != nil {
-			log.Error("Failed to get receiver: ", zap.Error(err))
-			return nil, err
-		}
-		return &payload.GetByIdConversationResponse{
-			ConversationID: conv.ID,
-			WithUser: &model.User{
-				Model:    model.Model{ID: conv.ReceiverID},
-				Name:     userWith.Name,
-				PhotoURL: userWith.PhotoURL,
-			},
-		}, nil
+		withUserId = conv.ReceiverID
 	} else if conv.ReceiverID == req.UserID {
-		userWith, err := u.repositories.User.GetById(ctx, conv.SenderID)
-		if err != nil {
-			log.Error("Failed to get sender: ", zap.Error(err))
-			return nil, err
-		}
-		return &payload.GetByIdConversationResponse{
-			ConversationID: conv.ID,
-			WithUser: &model.User{
-				Model:    model.Model{ID: conv.ReceiverID},
-				Name:     userWith.Name,
-				PhotoURL: userWith.PhotoURL,
-			},
-		}, nil
+		withUserId = conv.SenderID
+	} else {
+		return nil, errors.New("unauthorized")
+	}
+
+	userWith, err := u.repositories.User.GetByIdWithDeleted(ctx, withUserId)
+	if err != nil {
+		log.Error("Failed to get user in conversation: ", zap.Error(err))
+		return nil, err
+	}
+	contact, err := u.repositories.Contact.Get(ctx, req.UserID, withUserId)
+	if err != nil {
+		log.Error("Failed to get contact: ", zap.Error(err))
+		return nil, err
+	}
+	blocked, err := u.repositories.Block.IsBlocked(ctx, withUserId, req.UserID)
+	if err != nil {
+		log.Error("Failed to check block: ", zap.Error(err))
+		return nil, err
+	}
+	name := userWith.Name
+	if contact != nil && contact.Nickname != "" && !userWith.IsDeleted() {
+		name = contact.Nickname
 	}
 
-	return nil, errors.New("unauthorized")
+	res := &payload.GetByIdConversationResponse{
+		ConversationID: conv.ID,
+		WithUser: &model.User{
+			Model:    model.Model{ID: withUserId},
+			Name:     name,
+			PhotoURL: userWith.PhotoURL,
+		},
+	}
+	if !blocked && !userWith.IsDeleted() {
+		res.LastSeenAt = userWith.LastSeenAt
+	}
+	return res, nil
 }
 
 func (u ConversationUsecase) GetAllByUserId(ctx context.Context, req *payload.GetAllByUserIdConvRequest) (*payload.GetAllByUserIdConvResponse, error) {
 	log := logger.GetLogger(ctx)
-	results := make([]*payload.GetAllByUserIdConv, 0)
 
-	convs, err := u.repositories.Conversation.GetAllByUserId(ctx, req.UserID)
+	pgn := &req.Pagination
+	rows, err := u.repositories.Conversation.GetListByUserId(ctx, pgn, req)
 	if err != nil {
 		log.Error("Failed to get conversations: ", zap.Error(err))
 		return nil, err
 	}
+	if err := u.fillPreviews(ctx, rows); err != nil {
+		log.Error("Failed to get last messages: ", zap.Error(err))
+		return nil, err
+	}
+
+	results := make([]*payload.GetAllByUserIdConv, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, toListItem(row))
+	}
 
-	for _, conv := range convs {
-		var userWith *model.User
-		unreadCount, err := u.repositories.Message.GetUnreadCount(ctx, req.UserID, conv.ID)
-		if conv.SenderID == req.UserID {
-			userWith, err = u.repositories.User.GetById(ctx, conv.ReceiverID)
-			if err != nil {
-				log.Error("Failed to get receiver: ", zap.Error(err))
-				return nil, err
-			}
-		} else if conv.ReceiverID == req.UserID {
-			userWith, err = u.repositories.User.GetById(ctx, conv.SenderID)
-			if err != nil {
-				log.Error("Failed to get sender: ", zap.Error(err))
-				return nil, err
-			}
+	return &payload.GetAllByUserIdConvResponse{
+		Pagination:    pgn,
+		PaginatedData: results,
+		Message:       "Successfully get all conversation",
+	}, nil
+}
+
+func (u ConversationUsecase) Archive(ctx context.Context, req *payload.ArchiveConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	return u.updateSettings(ctx, req.ConversationID, req.UserID, func(p *model.UserParticipant) {
+		p.IsArchived = req.Archived
+	})
+}
+
+func (u ConversationUsecase) Mute(ctx context.Context, req *payload.MuteConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	return u.updateSettings(ctx, req.ConversationID, req.UserID, func(p *model.UserParticipant) {
+		p.MutedUntil = req.MutedUntil
+	})
+}
+
+func (u ConversationUsecase) Pin(ctx context.Context, req *payload.PinConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	return u.updateSettings(ctx, req.ConversationID, req.UserID, func(p *model.UserParticipant) {
+		if !req.Pinned {
+			p.PinnedOrder = nil
+			return
 		}
-		res := &payload.GetAllByUserIdConv{
-			GetByIdConversationResponse: payload.GetByIdConversationResponse{
-				ConversationID: conv.ID,
-				WithUser:       userWith,
+		order := req.Order
+		p.PinnedOrder = &order
+	})
+}
+
+func (u ConversationUsecase) GetRequests(ctx context.Context, req *payload.GetRequestsRequest) (*payload.GetRequestsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	pgn := &req.Pagination
+	rows, err := u.repositories.Conversation.GetRequestsByUserId(ctx, pgn, req.UserID)
+	if err != nil {
+		log.Error("Failed to get message requests: ", zap.Error(err))
+		return nil, err
+	}
+	if err := u.fillPreviews(ctx, rows); err != nil {
+		log.Error("Failed to get last messages: ", zap.Error(err))
+		return nil, err
+	}
+
+	results := make([]*payload.GetAllByUserIdConv, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, toListItem(row))
+	}
+
+	return &payload.GetRequestsResponse{
+		Pagination:    pgn,
+		PaginatedData: results,
+		Message:       "Successfully get message requests",
+	}, nil
+}
+
+func (u ConversationUsecase) AcceptRequest(ctx context.Context, req *payload.RespondRequestRequest) (*payload.RespondRequestResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	conv, err := u.getPendingRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repositories.Conversation.UpdateStatus(ctx, conv.ID, model.ConversationStatusAccepted); err != nil {
+		log.Error("Failed to accept message request: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RespondRequestResponse{
+		ConversationID: conv.ID,
+		Status:         model.ConversationStatusAccepted,
+		Message:        "Message request accepted",
+	}, nil
+}
+
+func (u ConversationUsecase) DeclineRequest(ctx context.Context, req *payload.RespondRequestRequest) (*payload.RespondRequestResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	conv, err := u.getPendingRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// A failed block keeps the request pending, so declining it can be retried
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repositories.Conversation.UpdateStatus(ctx, conv.ID, model.ConversationStatusDeclined); err != nil {
+			log.Error("Failed to decline message request: ", zap.Error(err))
+			return err
+		}
+		if !req.Block {
+			return nil
+		}
+		_, err := u.repositories.Block.Create(ctx, &model.Block{
+			BlockerID: req.UserID,
+			BlockedID: conv.SenderID,
+		})
+		if err != nil {
+			log.Error("Failed to block user: ", zap.Error(err))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	message := "Message request declined"
+	if req.Block {
+		message = "Message request declined and user blocked"
+	}
+
+	return &payload.RespondRequestResponse{
+		ConversationID: conv.ID,
+		Status:         model.ConversationStatusDeclined,
+		Message:        message,
+	}, nil
+}
+
+// Only the receiver of a request that is still pending can respond to it
+func (u ConversationUsecase) getPendingRequest(ctx context.Context, req *payload.RespondRequestRequest) (*model.Conversation, error) {
+	log := logger.GetLogger(ctx)
+
+	conv, err := u.repositories.Conversation.GetById(ctx, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get conversation by id: ", zap.Error(err))
+		return nil, err
+	}
+	if conv.ReceiverID != req.UserID {
+		return nil, errors.New("unauthorized")
+	}
+	if !conv.IsRequest() {
+		return nil, http_error.BadRequest(errors.New("conversation is not a pending message request"))
+	}
+	return conv, nil
+}
+
+// Clearing only moves the caller's cutoff, the other participant keeps the full history
+func (u ConversationUsecase) Clear(ctx context.Context, req *payload.ClearConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	now := time.Now()
+	var res *payload.ConversationSettingsResponse
+	// The cleared messages drop out of the inbox together with the history
+	err := u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = u.updateSettings(ctx, req.ConversationID, req.UserID, func(p *model.UserParticipant) {
+			p.ClearedAt = &now
+		})
+		if err != nil {
+			return err
+		}
+		if err := u.repositories.Inbox.Refresh(ctx, req.ConversationID); err != nil {
+			log.Error("Failed to update inbox: ", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	return res, err
+}
+
+// Load the caller's settings for a conversation, apply the change and store them back
+func (u ConversationUsecase) updateSettings(ctx context.Context, conversationId string, userId string, apply func(p *model.UserParticipant)) (*payload.ConversationSettingsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	conv, err := u.repositories.Conversation.GetById(ctx, conversationId)
+	if err != nil {
+		log.Error("Failed to get conversation by id: ", zap.Error(err))
+		return nil, err
+	}
+	if conv.SenderID != userId && conv.ReceiverID != userId {
+		return nil, errors.New("unauthorized")
+	}
+
+	participant, err := u.repositories.Conversation.GetParticipant(ctx, userId, conversationId)
+	if err != nil {
+		log.Error("Failed to get conversation settings: ", zap.Error(err))
+		return nil, err
+	}
+	if participant == nil {
+		participant = &model.UserParticipant{
+			UserID:         userId,
+			ConversationID: conversationId,
+		}
+	}
+	apply(participant)
+
+	participant, err = u.repositories.Conversation.UpsertParticipant(ctx, participant)
+	if err != nil {
+		log.Error("Failed to update conversation settings: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.ConversationSettingsResponse{
+		ConversationID:       conversationId,
+		ConversationSettings: toSettings(participant),
+	}, nil
+}
+
+func toSettings(p *model.UserParticipant) payload.ConversationSettings {
+	if p == nil {
+		return payload.ConversationSettings{}
+	}
+	return payload.ConversationSettings{
+		Archived:    p.IsArchived,
+		MutedUntil:  p.MutedUntil,
+		PinnedOrder: p.PinnedOrder,
+		ClearedAt:   p.ClearedAt,
+	}
+}
+
+// The inbox holds no preview when message text is encrypted and can't be kept in plaintext,
+// the last messages of the page are then read and decrypted in one query
+func (u ConversationUsecase) fillPreviews(ctx context.Context, rows []*payload.ConversationListRow) error {
+	var ids []string
+	for _, row := range rows {
+		if row.LastMessageID != "" && row.LastMessagePreview == "" {
+			ids = append(ids, row.LastMessageID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	msgs, err := u.repositories.Message.GetByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+	previews := make(map[string]string, len(msgs))
+	for _, msg := range msgs {
+		previews[msg.ID] = model.InboxPreview(msg.MessageText)
+	}
+	for _, row := range rows {
+		if row.LastMessagePreview == "" {
+			row.LastMessagePreview = previews[row.LastMessageID]
+		}
+	}
+	return nil
+}
+
+func toListItem(row *payload.ConversationListRow) *payload.GetAllByUserIdConv {
+	name := row.WithUserName
+	if row.Nickname != "" && !row.WithUserDeleted {
+		name = row.Nickname
+	}
+	item := &payload.GetAllByUserIdConv{
+		GetByIdConversationResponse: payload.GetByIdConversationResponse{
+			ConversationID: row.ID,
+			WithUser: &model.User{
+				Model:    model.Model{ID: row.WithUserID},
+				Name:     name,
+				Email:    row.WithUserEmail,
+				PhotoURL: row.WithUserPhotoURL,
+			},
+		},
+		LastMessagePreview: row.LastMessagePreview,
+		UnreadCount:        row.UnreadCount,
+		Draft:              draftPreview(row.Draft),
+		ConversationSettings: payload.ConversationSettings{
+			Archived:    row.IsArchived,
+			MutedUntil:  row.MutedUntil,
+			PinnedOrder: row.PinnedOrder,
+			ClearedAt:   row.ClearedAt,
+		},
+	}
+	if row.LastMessageID != "" && row.LastMessageSentAt != nil {
+		item.LastMessage = &model.Message{
+			Model:  model.Model{ID: row.LastMessageID},
+			SentAt: *row.LastMessageSentAt,
+			Sender: &model.User{
+				Model: model.Model{ID: row.LastMessageSenderID},
+				Name:  row.LastMessageSenderName,
 			},
-			LastMessage: conv.LastMessage,
-			UnreadCount: unreadCount,
 		}
-		res.LastMessage.MessageText = ""
-		res.LastMessage.ConversationID = ""
-		results = append(results, res)
 	}
+	return item
+}
 
-	return (*payload.GetAllByUserIdConvResponse)(&results), nil
+// Trim a draft down to what a conversation list needs to render
+func draftPreview(text string) string {
+	runes := []rune(text)
+	if len(runes) <= DraftPreviewLength {
+		return text
+	}
+	return string(runes[:DraftPreviewLength]) + "…"
 }
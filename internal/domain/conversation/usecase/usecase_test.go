@@ -0,0 +1,617 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/conversation/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_block "gitlab.com/raihanlh/messenger-api/testing/mocks/block"
+	mock_contact "gitlab.com/raihanlh/messenger-api/testing/mocks/contact"
+	mock_conversation "gitlab.com/raihanlh/messenger-api/testing/mocks/conversation"
+	mock_inbox "gitlab.com/raihanlh/messenger-api/testing/mocks/inbox"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	mock_outbox "gitlab.com/raihanlh/messenger-api/testing/mocks/outbox"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+)
+
+const largeInboxSize = 500
+
+// A user with many conversations, newest first as the repository returns them
+func largeInbox(userId string) []*payload.ConversationListRow {
+	start := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	rows := make([]*payload.ConversationListRow, 0, largeInboxSize)
+	for i := 0; i < largeInboxSize; i++ {
+		sentAt := start.Add(-time.Duration(i) * time.Minute)
+		row := &payload.ConversationListRow{
+			ID:                    fmt.Sprintf("conv-%d", i),
+			WithUserID:            fmt.Sprintf("user-%d", i),
+			WithUserName:          fmt.Sprintf("User %d", i),
+			LastMessageID:         fmt.Sprintf("msg-%d", i),
+			LastMessageSentAt:     &sentAt,
+			LastMessageSenderID:   userId,
+			LastMessageSenderName: "Me",
+			LastMessagePreview:    fmt.Sprintf("Message %d", i),
+			UnreadCount:           int64(i % 5),
+		}
+		if i%10 == 0 {
+			row.Nickname = fmt.Sprintf("Friend %d", i)
+		}
+		if i%50 == 0 {
+			row.WithUserName = "Deleted account"
+			row.WithUserDeleted = true
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// The list must come from the conversation repository alone, any per conversation
+// lookup on the other repositories fails the test because no call is expected there
+func setupLargeInbox(ctrl *gomock.Controller, userId string) *dependency.Repositories {
+	rows := largeInbox(userId)
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetListByUserId(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, pgn *pagination.Pagination, _ *payload.GetAllByUserIdConvRequest) ([]*payload.ConversationListRow, error) {
+			pgn.TotalItems = int64(len(rows))
+			pgn.TotalPages = 1
+			return rows, nil
+		})
+
+	return &dependency.Repositories{
+		Conversation: convRepoMock,
+		User:         mock_user.NewMockRepository(ctrl),
+		Message:      mock_message.NewMockRepository(ctrl),
+		Contact:      mock_contact.NewMockRepository(ctrl),
+	}
+}
+
+func Test_ConversationUsecase_GetAllByUserId_LargeInbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	conversationUsecase := usecase.New(setupLargeInbox(ctrl, userId))
+
+	req := &payload.GetAllByUserIdConvRequest{UserID: userId}
+	req.ItemsPerPage = pagination.MaxPerPage
+	res, err := conversationUsecase.GetAllByUserId(context.TODO(), req)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, res.PaginatedData, largeInboxSize)
+	assert.Equal(t, int64(largeInboxSize), res.TotalItems)
+	for i, conv := range res.PaginatedData {
+		assert.Equal(t, fmt.Sprintf("conv-%d", i), conv.ConversationID, "order from the repository is kept")
+	}
+
+	first := res.PaginatedData[0]
+	assert.Equal(t, "Deleted account", first.WithUser.Name, "nicknames don't apply to deleted accounts")
+	assert.Equal(t, "Friend 10", res.PaginatedData[10].WithUser.Name)
+	assert.Equal(t, "User 11", res.PaginatedData[11].WithUser.Name)
+	assert.Equal(t, int64(1), res.PaginatedData[11].UnreadCount)
+	if assert.NotNil(t, first.LastMessage) {
+		assert.Equal(t, "msg-0", first.LastMessage.ID)
+		assert.Empty(t, first.LastMessage.MessageText)
+	}
+}
+
+func Test_ConversationUsecase_GetAllByUserId_HiddenPreviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	sentAt := time.Now()
+	rows := []*payload.ConversationListRow{
+		{ID: "c1", WithUserName: "Bob", LastMessageID: "m1", LastMessageSentAt: &sentAt},
+		{ID: "c2", WithUserName: "Carol", LastMessageID: "m2", LastMessageSentAt: &sentAt, LastMessagePreview: "kept"},
+		{ID: "c3", WithUserName: "Dave"},
+	}
+
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetListByUserId(ctx, gomock.Any(), gomock.Any()).Return(rows, nil)
+	// Only the rows missing a preview are read, in one query
+	msgRepoMock := mock_message.NewMockRepository(ctrl)
+	msgRepoMock.EXPECT().GetByIds(ctx, []string{"m1"}).
+		Return([]*model.Message{{Model: model.Model{ID: "m1"}, MessageText: "decrypted text"}}, nil)
+
+	conversationUsecase := usecase.New(&dependency.Repositories{
+		Conversation: convRepoMock,
+		Message:      msgRepoMock,
+	})
+	res, err := conversationUsecase.GetAllByUserId(ctx, &payload.GetAllByUserIdConvRequest{UserID: userId})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "decrypted text", res.PaginatedData[0].LastMessagePreview)
+	assert.Equal(t, "kept", res.PaginatedData[1].LastMessagePreview)
+	assert.Empty(t, res.PaginatedData[2].LastMessagePreview)
+}
+
+func Test_ConversationUsecase_GetAllByUserId_Archived(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	sentAt := time.Now()
+	first, second := 1, 2
+	rows := []*payload.ConversationListRow{
+		{ID: "c1", WithUserName: "Bob", LastMessageID: "m1", LastMessageSentAt: &sentAt, LastMessagePreview: "hi", IsArchived: true, PinnedOrder: &first},
+		{ID: "c2", WithUserName: "Carol", LastMessageID: "m2", LastMessageSentAt: &sentAt, LastMessagePreview: "hey", IsArchived: true, PinnedOrder: &second},
+		{ID: "c3", WithUserName: "Dave", LastMessageID: "m3", LastMessageSentAt: &sentAt, LastMessagePreview: "yo", IsArchived: true},
+	}
+
+	// Filtering and ordering happen in the query, the usecase passes the filter on and keeps the order
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetListByUserId(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *pagination.Pagination, req *payload.GetAllByUserIdConvRequest) ([]*payload.ConversationListRow, error) {
+			assert.True(t, req.Archived)
+			assert.Equal(t, userId, req.UserID)
+			return rows, nil
+		})
+
+	conversationUsecase := usecase.New(&dependency.Repositories{
+		Conversation: convRepoMock,
+	})
+	res, err := conversationUsecase.GetAllByUserId(ctx, &payload.GetAllByUserIdConvRequest{UserID: userId, Archived: true})
+	if !assert.NoError(t, err) || !assert.Len(t, res.PaginatedData, 3) {
+		return
+	}
+
+	for i, id := range []string{"c1", "c2", "c3"} {
+		assert.Equal(t, id, res.PaginatedData[i].ConversationID)
+		assert.True(t, res.PaginatedData[i].Archived)
+	}
+	assert.Equal(t, &first, res.PaginatedData[0].PinnedOrder)
+	assert.Equal(t, &second, res.PaginatedData[1].PinnedOrder)
+	assert.Nil(t, res.PaginatedData[2].PinnedOrder)
+}
+
+func Test_ConversationUsecase_Archive(t *testing.T) {
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	order := 1
+	conv := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: userId, ReceiverID: "47dsga9t-d76e-401a-a3ba-7a03352812c2"}
+
+	tests := []struct {
+		name         string
+		userId       string
+		archived     bool
+		participant  *model.UserParticipant
+		wantArchived bool
+		wantErr      bool
+	}{
+		{
+			name:         "Archive without settings yet",
+			userId:       userId,
+			archived:     true,
+			wantArchived: true,
+		},
+		{
+			name:         "Unarchive keeps the other settings",
+			userId:       userId,
+			archived:     false,
+			participant:  &model.UserParticipant{UserID: userId, ConversationID: "c1", IsArchived: true, PinnedOrder: &order},
+			wantArchived: false,
+		},
+		{
+			name:     "Not a participant",
+			userId:   "9b2c1d5e-d76e-401a-a3ba-7a03352812c2",
+			archived: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, conv.ID).Return(conv, nil)
+			if !tt.wantErr {
+				convRepoMock.EXPECT().GetParticipant(ctx, tt.userId, conv.ID).Return(tt.participant, nil)
+				convRepoMock.EXPECT().UpsertParticipant(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, p *model.UserParticipant) (*model.UserParticipant, error) {
+						assert.Equal(t, tt.userId, p.UserID)
+						assert.Equal(t, conv.ID, p.ConversationID)
+						assert.Equal(t, tt.wantArchived, p.IsArchived)
+						return p, nil
+					})
+			}
+
+			conversationUsecase := usecase.New(&dependency.Repositories{
+				Conversation: convRepoMock,
+			})
+			res, err := conversationUsecase.Archive(ctx, &payload.ArchiveConversationRequest{
+				ConversationID: conv.ID,
+				UserID:         tt.userId,
+				Archived:       tt.archived,
+			})
+			if tt.wantErr {
+				assert.EqualError(t, err, "unauthorized")
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantArchived, res.Archived)
+				if tt.participant != nil {
+					assert.Equal(t, &order, res.PinnedOrder)
+				}
+			}
+		})
+	}
+}
+
+func Test_ConversationUsecase_Pin(t *testing.T) {
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	order := 3
+	conv := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: "47dsga9t-d76e-401a-a3ba-7a03352812c2", ReceiverID: userId}
+
+	tests := []struct {
+		name        string
+		req         *payload.PinConversationRequest
+		participant *model.UserParticipant
+		wantOrder   *int
+	}{
+		{
+			name:      "Pin",
+			req:       &payload.PinConversationRequest{ConversationID: conv.ID, UserID: userId, Pinned: true, Order: 2},
+			wantOrder: func() *int { o := 2; return &o }(),
+		},
+		{
+			name:        "Unpin",
+			req:         &payload.PinConversationRequest{ConversationID: conv.ID, UserID: userId, Pinned: false},
+			participant: &model.UserParticipant{UserID: userId, ConversationID: conv.ID, PinnedOrder: &order},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, conv.ID).Return(conv, nil)
+			convRepoMock.EXPECT().GetParticipant(ctx, userId, conv.ID).Return(tt.participant, nil)
+			convRepoMock.EXPECT().UpsertParticipant(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, p *model.UserParticipant) (*model.UserParticipant, error) {
+					assert.Equal(t, tt.wantOrder, p.PinnedOrder)
+					return p, nil
+				})
+
+			conversationUsecase := usecase.New(&dependency.Repositories{
+				Conversation: convRepoMock,
+			})
+			res, err := conversationUsecase.Pin(ctx, tt.req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantOrder, res.PinnedOrder)
+			}
+		})
+	}
+}
+
+func Test_ConversationUsecase_Clear(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	otherId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	conv := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: otherId, ReceiverID: userId}
+	before := time.Now()
+
+	// Only the caller's settings are written, the other participant's are never read or touched
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetById(ctx, conv.ID).Return(conv, nil)
+	convRepoMock.EXPECT().GetParticipant(ctx, userId, conv.ID).Return(nil, nil)
+	convRepoMock.EXPECT().UpsertParticipant(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, p *model.UserParticipant) (*model.UserParticipant, error) {
+			assert.Equal(t, userId, p.UserID)
+			if assert.NotNil(t, p.ClearedAt) {
+				assert.False(t, p.ClearedAt.Before(before))
+			}
+			return p, nil
+		})
+	inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+	inboxRepoMock.EXPECT().Refresh(ctx, conv.ID).Return(nil)
+
+	conversationUsecase := usecase.New(&dependency.Repositories{
+		Transactor:   helper.NoTransaction{},
+		Conversation: convRepoMock,
+		Inbox:        inboxRepoMock,
+	})
+	res, err := conversationUsecase.Clear(ctx, &payload.ClearConversationRequest{ConversationID: conv.ID, UserID: userId})
+	if assert.NoError(t, err) {
+		assert.NotNil(t, res.ClearedAt)
+	}
+}
+
+func Test_ConversationUsecase_GetById_Presence(t *testing.T) {
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	otherId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	conv := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: userId, ReceiverID: otherId}
+	lastSeen := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		blocked      bool
+		wantLastSeen bool
+	}{
+		{
+			name:         "Presence of the other user is shown",
+			wantLastSeen: true,
+		},
+		{
+			name:    "Hidden when the other user blocked the caller",
+			blocked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, conv.ID).Return(conv, nil)
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			userRepoMock.EXPECT().GetByIdWithDeleted(ctx, otherId).
+				Return(&model.User{Model: model.Model{ID: otherId}, Name: "Bob", LastSeenAt: &lastSeen}, nil)
+			contactRepoMock := mock_contact.NewMockRepository(ctrl)
+			contactRepoMock.EXPECT().Get(ctx, userId, otherId).Return(nil, nil)
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			blockRepoMock.EXPECT().IsBlocked(ctx, otherId, userId).Return(tt.blocked, nil)
+
+			conversationUsecase := usecase.New(&dependency.Repositories{
+				Conversation: convRepoMock,
+				User:         userRepoMock,
+				Contact:      contactRepoMock,
+				Block:        blockRepoMock,
+			})
+			res, err := conversationUsecase.GetById(ctx, &payload.GetByIdConversationRequest{ConversationID: conv.ID, UserID: userId})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "Bob", res.WithUser.Name)
+			if tt.wantLastSeen {
+				assert.Equal(t, &lastSeen, res.LastSeenAt)
+			} else {
+				assert.Nil(t, res.LastSeenAt)
+			}
+		})
+	}
+}
+
+func Test_ConversationUsecase_Create(t *testing.T) {
+	senderId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	receiverId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name       string
+		blocked    bool
+		existing   *model.Conversation
+		contact    *model.Contact
+		wantStatus string
+		wantCode   string
+	}{
+		{
+			name:       "Stranger starts a message request",
+			wantStatus: model.ConversationStatusRequest,
+		},
+		{
+			name:       "Contact of the receiver starts an accepted conversation",
+			contact:    &model.Contact{UserID: receiverId, ContactID: senderId},
+			wantStatus: model.ConversationStatusAccepted,
+		},
+		{
+			name:       "Existing conversation is returned",
+			existing:   &model.Conversation{Model: model.Model{ID: "c0"}, SenderID: receiverId, ReceiverID: senderId, Status: model.ConversationStatusAccepted},
+			wantStatus: model.ConversationStatusAccepted,
+		},
+		{
+			name:     "Declined request can't be started again",
+			existing: &model.Conversation{Model: model.Model{ID: "c0"}, SenderID: senderId, ReceiverID: receiverId, Status: model.ConversationStatusDeclined},
+			wantCode: http_error.ForbiddenCode,
+		},
+		{
+			name:     "Blocked sender",
+			blocked:  true,
+			wantCode: http_error.BlockedCode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			blockRepoMock.EXPECT().IsBlocked(ctx, receiverId, senderId).Return(tt.blocked, nil)
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			contactRepoMock := mock_contact.NewMockRepository(ctrl)
+			inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+			outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+			if !tt.blocked {
+				userRepoMock.EXPECT().GetById(ctx, senderId).Return(&model.User{Model: model.Model{ID: senderId}}, nil)
+				userRepoMock.EXPECT().GetById(ctx, receiverId).Return(&model.User{Model: model.Model{ID: receiverId}}, nil)
+				convRepoMock.EXPECT().GetBySenderReceiverIds(ctx, senderId, receiverId).Return(tt.existing, nil)
+			}
+			if !tt.blocked && tt.existing == nil {
+				contactRepoMock.EXPECT().Get(ctx, receiverId, senderId).Return(tt.contact, nil)
+				convRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, conv *model.Conversation) (*model.Conversation, error) {
+					conv.ID = "c1"
+					return conv, nil
+				})
+				inboxRepoMock.EXPECT().Refresh(ctx, "c1").Return(nil)
+				outboxRepoMock.EXPECT().Append(ctx, model.EventConversationCreated, gomock.Any()).Return(nil)
+			}
+
+			conversationUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				User:         userRepoMock,
+				Conversation: convRepoMock,
+				Contact:      contactRepoMock,
+				Block:        blockRepoMock,
+				Inbox:        inboxRepoMock,
+				Outbox:       outboxRepoMock,
+			})
+			res, err := conversationUsecase.Create(ctx, &payload.CreateConversationRequest{SenderID: senderId, ReceiverID: receiverId})
+			if tt.wantCode != "" {
+				httpErr, ok := err.(*http_error.Error)
+				if assert.True(t, ok) {
+					assert.Equal(t, tt.wantCode, httpErr.ErrorCode)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantStatus, res.Conversation.Status)
+			}
+		})
+	}
+}
+
+func Test_ConversationUsecase_GetRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	sentAt := time.Now()
+	rows := []*payload.ConversationListRow{
+		{ID: "c1", Status: model.ConversationStatusRequest, WithUserID: "u2", WithUserName: "Bob", LastMessageID: "m2", LastMessageSentAt: &sentAt, LastMessagePreview: "hi again", UnreadCount: 2},
+		// Encrypted message without a preview in the inbox
+		{ID: "c2", Status: model.ConversationStatusRequest, WithUserID: "u3", WithUserName: "Carol", LastMessageID: "m3", LastMessageSentAt: &sentAt, UnreadCount: 1},
+	}
+
+	// The whole page takes one inbox read and one read of the previews it is missing,
+	// nothing per request
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetRequestsByUserId(ctx, gomock.Any(), userId).
+		DoAndReturn(func(_ context.Context, pgn *pagination.Pagination, _ string) ([]*payload.ConversationListRow, error) {
+			pgn.TotalItems = 2
+			pgn.TotalPages = 1
+			return rows, nil
+		})
+	msgRepoMock := mock_message.NewMockRepository(ctrl)
+	msgRepoMock.EXPECT().GetByIds(ctx, []string{"m3"}).Return([]*model.Message{{Model: model.Model{ID: "m3"}, MessageText: "hello"}}, nil)
+
+	conversationUsecase := usecase.New(&dependency.Repositories{
+		Conversation: convRepoMock,
+		Message:      msgRepoMock,
+	})
+	res, err := conversationUsecase.GetRequests(ctx, &payload.GetRequestsRequest{UserID: userId})
+	if assert.NoError(t, err) && assert.Len(t, res.PaginatedData, 2) {
+		assert.Equal(t, int64(2), res.TotalItems)
+		assert.Equal(t, "m2", res.PaginatedData[0].LastMessage.ID)
+		assert.Equal(t, "hi again", res.PaginatedData[0].LastMessagePreview)
+		assert.Equal(t, int64(2), res.PaginatedData[0].UnreadCount)
+		assert.Equal(t, "m3", res.PaginatedData[1].LastMessage.ID)
+		assert.Equal(t, "hello", res.PaginatedData[1].LastMessagePreview)
+	}
+}
+
+func Test_ConversationUsecase_RespondRequest(t *testing.T) {
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	senderId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	request := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: senderId, ReceiverID: userId, Status: model.ConversationStatusRequest}
+
+	tests := []struct {
+		name       string
+		conv       *model.Conversation
+		accept     bool
+		block      bool
+		blockErr   error
+		wantStatus string
+		wantErr    bool
+	}{
+		{
+			name:       "Accept",
+			conv:       request,
+			accept:     true,
+			wantStatus: model.ConversationStatusAccepted,
+		},
+		{
+			name:       "Decline",
+			conv:       request,
+			wantStatus: model.ConversationStatusDeclined,
+		},
+		{
+			name:       "Decline and block",
+			conv:       request,
+			block:      true,
+			wantStatus: model.ConversationStatusDeclined,
+		},
+		{
+			name:       "Decline fails to block",
+			conv:       request,
+			block:      true,
+			blockErr:   errors.New("connection reset"),
+			wantStatus: model.ConversationStatusDeclined,
+			wantErr:    true,
+		},
+		{
+			name:    "Only the receiver can respond",
+			conv:    &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: userId, ReceiverID: senderId, Status: model.ConversationStatusRequest},
+			accept:  true,
+			wantErr: true,
+		},
+		{
+			name:    "Conversation that isn't a pending request",
+			conv:    &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: senderId, ReceiverID: userId, Status: model.ConversationStatusAccepted},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, tt.conv.ID).Return(tt.conv, nil)
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			if tt.wantStatus != "" {
+				convRepoMock.EXPECT().UpdateStatus(ctx, tt.conv.ID, tt.wantStatus).Return(nil)
+			}
+			if tt.block {
+				blockRepoMock.EXPECT().Create(ctx, &model.Block{BlockerID: userId, BlockedID: senderId}).
+					Return(&model.Block{BlockerID: userId, BlockedID: senderId}, tt.blockErr)
+			}
+
+			conversationUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				Conversation: convRepoMock,
+				Block:        blockRepoMock,
+			})
+			req := &payload.RespondRequestRequest{ConversationID: tt.conv.ID, UserID: userId, Block: tt.block}
+			var res *payload.RespondRequestResponse
+			var err error
+			if tt.accept {
+				res, err = conversationUsecase.AcceptRequest(ctx, req)
+			} else {
+				res, err = conversationUsecase.DeclineRequest(ctx, req)
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantStatus, res.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package dataexport
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+type Repository interface {
+	Create(ctx context.Context, export *model.DataExport) (*model.DataExport, error)
+	Update(ctx context.Context, export *model.DataExport) (*model.DataExport, error)
+	GetById(ctx context.Context, id string) (*model.DataExport, error)
+	GetActiveByUserId(ctx context.Context, userId string, since time.Time) (*model.DataExport, error)
+	FailStale(ctx context.Context, before time.Time) (int64, error)
+	GetExpiredArchives(ctx context.Context, at time.Time) ([]*model.DataExport, error)
+	GetArchivesByUserId(ctx context.Context, userId string) ([]*model.DataExport, error)
+}
+
+type Usecase interface {
+	Request(ctx context.Context, req *payload.RequestDataExportRequest) (*payload.DataExportResponse, error)
+	GetById(ctx context.Context, req *payload.GetDataExportRequest) (*payload.DataExportResponse, error)
+	Download(ctx context.Context, req *payload.DownloadDataExportRequest) (*payload.DownloadDataExportResponse, error)
+	CleanUp(ctx context.Context) error
+	DeleteUserArchives(ctx context.Context, e *eventbus.Event) error
+}
+
+type Handler interface {
+	Request(ctx echo.Context) error
+	GetById(ctx echo.Context) error
+	Download(ctx echo.Context) error
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type DataExportHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) dataexport.Handler {
+	return &DataExportHandler{
+		usecases: u,
+	}
+}
+
+// RequestDataExport godoc
+// @Summary Request Data Export
+// @Description start building an archive of all the caller's data, poll the returned export for its status
+// @Tags Data Export
+// @Accept application/json
+// @Produce json
+// @Success 202 {object} object{status=string,data=payload.DataExportResponse}
+// @Router /api/v1/me/data-exports [post]
+func (h DataExportHandler) Request(ctx echo.Context) error {
+	var body payload.RequestDataExportRequest
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.DataExport.Request(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to request data export: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusAccepted).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetDataExport godoc
+// @Summary Get Data Export
+// @Description get the status of a data export, includes a download url once it is ready. Every call issues a new url and the previous one stops working
+// @Tags Data Export
+// @Accept application/json
+// @Param id path string true "Data Export ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.DataExportResponse}
+// @Router /api/v1/me/data-exports/{id} [get]
+func (h DataExportHandler) GetById(ctx echo.Context) error {
+	var body payload.GetDataExportRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.DataExport.GetById(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" || err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get data export: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// DownloadDataExport godoc
+// @Summary Download Data Export
+// @Description download a ready data export as a zip archive, the token comes from the export's download url
+// @Tags Data Export
+// @Param id path string true "Data Export ID"
+// @Param token query string true "Download token"
+// @Produce application/zip
+// @Success 200 {file} file
+// @Router /api/v1/me/data-exports/{id}/download [get]
+func (h DataExportHandler) Download(ctx echo.Context) error {
+	var body payload.DownloadDataExportRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	data, err := h.usecases.DataExport.Download(ctx.Request().Context(), &body)
+	if err != nil {
+		// A wrong token looks the same as a missing export
+		if err.Error() == "unauthorized" || err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to download data export: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+	defer data.File.Close()
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, data.FileName))
+	return ctx.Stream(http.StatusOK, "application/zip", data.File)
+}
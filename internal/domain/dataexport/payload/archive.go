@@ -0,0 +1,57 @@
+package payload
+
+import "time"
+
+// Files written into the export archive
+
+type ArchivedProfile struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	PhotoURL  string    `json:"photo_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ArchivedConversation struct {
+	ID          string    `json:"id"`
+	WithUserID  string    `json:"with_user_id"`
+	StartedByMe bool      `json:"started_by_me"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ArchivedMessage struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	SentAt         time.Time  `json:"sent_at"`
+	Message        string     `json:"message"`
+	EditedAt       *time.Time `json:"edited_at,omitempty"`
+}
+
+type ArchivedContact struct {
+	UserID   string `json:"user_id"`
+	Name     string `json:"name,omitempty"`
+	Nickname string `json:"nickname,omitempty"`
+}
+
+type ArchivedDraft struct {
+	ConversationID string    `json:"conversation_id"`
+	Text           string    `json:"text"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type ArchivedConversationSettings struct {
+	ConversationID string     `json:"conversation_id"`
+	Archived       bool       `json:"archived"`
+	MutedUntil     *time.Time `json:"muted_until,omitempty"`
+	PinnedOrder    *int       `json:"pinned_order,omitempty"`
+	ClearedAt      *time.Time `json:"cleared_at,omitempty"`
+}
+
+type ArchivedSettings struct {
+	Conversations  []*ArchivedConversationSettings `json:"conversations"`
+	Contacts       []*ArchivedContact              `json:"contacts"`
+	BlockedUserIDs []string                        `json:"blocked_user_ids"`
+	Drafts         []*ArchivedDraft                `json:"drafts"`
+}
@@ -0,0 +1,13 @@
+package payload
+
+import "io"
+
+type DownloadDataExportRequest struct {
+	ID    string `param:"id"`
+	Token string `query:"token" validate:"required"`
+}
+
+type DownloadDataExportResponse struct {
+	FileName string
+	File     io.ReadCloser
+}
@@ -0,0 +1,6 @@
+package payload
+
+type GetDataExportRequest struct {
+	ID     string `param:"id"`
+	UserID string `json:"-"`
+}
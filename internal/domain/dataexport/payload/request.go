@@ -0,0 +1,18 @@
+package payload
+
+import "time"
+
+type RequestDataExportRequest struct {
+	UserID string `json:"-"`
+}
+
+type DataExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// Set by getting a ready export that hasn't expired, a new link replaces the previous one
+	DownloadURL string `json:"download_url,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/gorm"
+)
+
+var activeStatuses = []string{model.DataExportStatusPending, model.DataExportStatusProcessing}
+
+type DataExportRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) dataexport.Repository {
+	return &DataExportRepository{
+		DB: gormDB,
+	}
+}
+
+func (r DataExportRepository) Create(ctx context.Context, export *model.DataExport) (*model.DataExport, error) {
+	result := r.DB.WithContext(ctx).Create(export)
+	return export, result.Error
+}
+
+func (r DataExportRepository) Update(ctx context.Context, export *model.DataExport) (*model.DataExport, error) {
+	result := r.DB.WithContext(ctx).Save(export)
+	return export, result.Error
+}
+
+func (r DataExportRepository) GetById(ctx context.Context, id string) (*model.DataExport, error) {
+	var export *model.DataExport
+	result := r.DB.WithContext(ctx).Table(constant.DataExportTable).Where("id = ?", id).Limit(1).Find(&export)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return export, nil
+}
+
+// The export that is still being built for the user, if any. Builds not updated since
+// were interrupted and don't count.
+func (r DataExportRepository) GetActiveByUserId(ctx context.Context, userId string, since time.Time) (*model.DataExport, error) {
+	var export *model.DataExport
+	result := r.DB.WithContext(ctx).Table(constant.DataExportTable).
+		Where("user_id = ? AND status IN ? AND updated_at >= ?", userId, activeStatuses, since).
+		Order("created_at DESC").Limit(1).Find(&export)
+	if result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return export, result.Error
+}
+
+// Exports whose link expired but whose archive is still stored
+func (r DataExportRepository) GetExpiredArchives(ctx context.Context, at time.Time) ([]*model.DataExport, error) {
+	var exports []*model.DataExport
+	result := r.DB.WithContext(ctx).Table(constant.DataExportTable).
+		Where("file_key <> '' AND expires_at <= ?", at).Find(&exports)
+	return exports, result.Error
+}
+
+func (r DataExportRepository) GetArchivesByUserId(ctx context.Context, userId string) ([]*model.DataExport, error) {
+	var exports []*model.DataExport
+	result := r.DB.WithContext(ctx).Table(constant.DataExportTable).
+		Where("user_id = ? AND file_key <> ''", userId).Find(&exports)
+	return exports, result.Error
+}
+
+// FailStale marks the builds not updated since before as failed and returns how many
+func (r DataExportRepository) FailStale(ctx context.Context, before time.Time) (int64, error) {
+	now := time.Now()
+	result := r.DB.WithContext(ctx).Table(constant.DataExportTable).
+		Where("status IN ? AND updated_at < ?", activeStatuses, before).
+		Updates(map[string]interface{}{
+			"status":       model.DataExportStatusFailed,
+			"error":        "archive build was interrupted",
+			"completed_at": now,
+			"updated_at":   now,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// Number of messages read from the database at a time while building the archive
+const ArchiveBatchSize = 500
+
+// writeArchive writes a zip with everything stored for the user: their profile,
+// conversations, the messages they sent and their settings
+func (u DataExportUsecase) writeArchive(ctx context.Context, userId string, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	user, err := u.repositories.User.GetById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	err = writeJSON(zw, "profile.json", &payload.ArchivedProfile{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		PhotoURL:  user.PhotoURL,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	convos, err := u.repositories.Conversation.GetAllByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+	archivedConvos := make([]*payload.ArchivedConversation, 0, len(convos))
+	for _, c := range convos {
+		archived := &payload.ArchivedConversation{
+			ID:          c.ID,
+			WithUserID:  c.ReceiverID,
+			StartedByMe: c.SenderID == userId,
+			Status:      c.Status,
+			CreatedAt:   c.CreatedAt,
+		}
+		if !archived.StartedByMe {
+			archived.WithUserID = c.SenderID
+		}
+		archivedConvos = append(archivedConvos, archived)
+	}
+	if err := writeJSON(zw, "conversations.json", archivedConvos); err != nil {
+		return err
+	}
+
+	if err := u.writeMessages(ctx, zw, userId); err != nil {
+		return err
+	}
+
+	settings, err := u.getSettings(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "settings.json", settings); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Messages can be many, so they are written as a JSON array one batch at a time
+func (u DataExportUsecase) writeMessages(ctx context.Context, zw *zip.Writer, userId string) error {
+	f, err := zw.Create("messages.json")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, "["); err != nil {
+		return err
+	}
+	first := true
+	err = u.repositories.Message.StreamBySenderId(ctx, userId, ArchiveBatchSize, func(messages []*model.Message) error {
+		for _, m := range messages {
+			b, err := json.Marshal(&payload.ArchivedMessage{
+				ID:             m.ID,
+				ConversationID: m.ConversationID,
+				SentAt:         m.SentAt,
+				Message:        m.MessageText,
+				EditedAt:       m.EditedAt,
+			})
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(f, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := f.Write(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, "]")
+	return err
+}
+
+func (u DataExportUsecase) getSettings(ctx context.Context, userId string) (*payload.ArchivedSettings, error) {
+	settings := &payload.ArchivedSettings{
+		Conversations:  []*payload.ArchivedConversationSettings{},
+		Contacts:       []*payload.ArchivedContact{},
+		BlockedUserIDs: []string{},
+		Drafts:         []*payload.ArchivedDraft{},
+	}
+
+	participants, err := u.repositories.Conversation.GetParticipantsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		settings.Conversations = append(settings.Conversations, &payload.ArchivedConversationSettings{
+			ConversationID: p.ConversationID,
+			Archived:       p.IsArchived,
+			MutedUntil:     p.MutedUntil,
+			PinnedOrder:    p.PinnedOrder,
+			ClearedAt:      p.ClearedAt,
+		})
+	}
+
+	contacts, err := u.repositories.Contact.GetAllByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contacts {
+		archived := &payload.ArchivedContact{
+			UserID:   c.ContactID,
+			Nickname: c.Nickname,
+		}
+		if c.Contact != nil {
+			archived.Name = c.Contact.Name
+		}
+		settings.Contacts = append(settings.Contacts, archived)
+	}
+
+	blocks, err := u.repositories.Block.GetAllByBlockerId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blocks {
+		settings.BlockedUserIDs = append(settings.BlockedUserIDs, b.BlockedID)
+	}
+
+	drafts, err := u.repositories.Draft.GetAllByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drafts {
+		settings.Drafts = append(settings.Drafts, &payload.ArchivedDraft{
+			ConversationID: d.ConversationID,
+			Text:           d.Text,
+			UpdatedAt:      d.UpdatedAt,
+		})
+	}
+
+	return settings, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+// DeleteUserArchives deletes the data exports of a deleted account right away instead of
+// waiting for their links to expire
+func (u DataExportUsecase) DeleteUserArchives(ctx context.Context, e *eventbus.Event) error {
+	var data model.UserDeletedEvent
+	if err := e.Decode(&data); err != nil {
+		return err
+	}
+	exports, err := u.repositories.DataExport.GetArchivesByUserId(ctx, data.UserID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, export := range exports {
+		if err := u.deleteArchive(ctx, export, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
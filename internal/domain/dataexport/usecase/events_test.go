@@ -0,0 +1,46 @@
+package usecase_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+	"gitlab.com/raihanlh/messenger-api/pkg/storage/local"
+	mock_dataexport "gitlab.com/raihanlh/messenger-api/testing/mocks/dataexport"
+)
+
+func Test_DataExportUsecase_DeleteUserArchives(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	dir := t.TempDir()
+	files := local.New(dir)
+	key := "data-exports/u1/e1.zip"
+	assert.NoError(t, files.Put(ctx, key, strings.NewReader("archive")))
+	export := &model.DataExport{Model: model.Model{ID: "e1"}, UserID: "u1", Status: model.DataExportStatusReady, FileKey: key}
+
+	repoMock := mock_dataexport.NewMockRepository(ctrl)
+	repoMock.EXPECT().GetArchivesByUserId(ctx, "u1").Return([]*model.DataExport{export}, nil)
+	repoMock.EXPECT().Update(ctx, export).Return(export, nil)
+
+	err := usecase.New(&dependency.Repositories{DataExport: repoMock}, &dependency.Storages{File: files}).DeleteUserArchives(ctx, &eventbus.Event{
+		ID:      "ev1",
+		Type:    model.EventUserDeleted,
+		Payload: []byte(`{"user_id":"u1"}`),
+	})
+	if assert.NoError(t, err) {
+		_, statErr := os.Stat(filepath.Join(dir, key))
+		assert.True(t, os.IsNotExist(statErr))
+		assert.Empty(t, export.FileKey)
+		assert.NotNil(t, export.ExpiresAt, "the link stops working")
+	}
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/config"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// How long a download link stays valid when DATA_EXPORT_LINK_TTL isn't set
+const DefaultLinkTTL = 24 * time.Hour
+
+// Builds run in the process that took the request. One that hasn't progressed for this
+// long was cut off, e.g. by a restart, and is taken as failed so the user can ask again.
+const BuildLease = time.Hour
+
+type DataExportUsecase struct {
+	repositories *dependency.Repositories
+	storages     *dependency.Storages
+}
+
+func New(r *dependency.Repositories, s *dependency.Storages) dataexport.Usecase {
+	return &DataExportUsecase{
+		repositories: r,
+		storages:     s,
+	}
+}
+
+// Request queues a new export, or returns the one still being built for the user
+func (u DataExportUsecase) Request(ctx context.Context, req *payload.RequestDataExportRequest) (*payload.DataExportResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	active, err := u.repositories.DataExport.GetActiveByUserId(ctx, req.UserID, time.Now().Add(-BuildLease))
+	if err != nil {
+		log.Error("Failed to get active data export: ", zap.Error(err))
+		return nil, err
+	}
+	if active != nil {
+		return toResponse(active, ""), nil
+	}
+
+	export, err := u.repositories.DataExport.Create(ctx, &model.DataExport{
+		UserID: req.UserID,
+		Status: model.DataExportStatusPending,
+	})
+	if err != nil {
+		log.Error("Failed to create data export: ", zap.Error(err))
+		return nil, err
+	}
+
+	// The archive outlives the request, so it's built on a fresh context
+	go u.build(context.Background(), export)
+
+	return toResponse(export, ""), nil
+}
+
+func (u DataExportUsecase) GetById(ctx context.Context, req *payload.GetDataExportRequest) (*payload.DataExportResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	export, err := u.repositories.DataExport.GetById(ctx, req.ID)
+	if err != nil {
+		log.Error("Failed to get data export: ", zap.Error(err))
+		return nil, err
+	}
+	if export.UserID != req.UserID {
+		return nil, errors.New("unauthorized")
+	}
+	if !export.IsDownloadable(time.Now()) {
+		return toResponse(export, ""), nil
+	}
+
+	// Only the hash of the token is stored, so every look at a ready export issues a new
+	// link and the previous one stops working
+	token := newToken()
+	export.DownloadTokenHash = hashToken(token)
+	if _, err := u.repositories.DataExport.Update(ctx, export); err != nil {
+		log.Error("Failed to update data export: ", zap.Error(err))
+		return nil, err
+	}
+	return toResponse(export, token), nil
+}
+
+func (u DataExportUsecase) Download(ctx context.Context, req *payload.DownloadDataExportRequest) (*payload.DownloadDataExportResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	export, err := u.repositories.DataExport.GetById(ctx, req.ID)
+	if err != nil {
+		log.Error("Failed to get data export: ", zap.Error(err))
+		return nil, err
+	}
+	if export.Status != model.DataExportStatusReady || export.DownloadTokenHash == "" ||
+		subtle.ConstantTimeCompare([]byte(export.DownloadTokenHash), []byte(hashToken(req.Token))) != 1 {
+		return nil, errors.New("unauthorized")
+	}
+	// The archive is deleted once the link expired or the account was deleted
+	if !export.IsDownloadable(time.Now()) {
+		return nil, http_error.Expired("download link has expired, please request a new export")
+	}
+
+	file, err := u.storages.File.Get(ctx, export.FileKey)
+	if err != nil {
+		log.Error("Failed to open data export archive: ", zap.Error(err))
+		return nil, err
+	}
+	return &payload.DownloadDataExportResponse{
+		FileName: fmt.Sprintf("data-export-%s.zip", export.CreatedAt.Format("20060102")),
+		File:     file,
+	}, nil
+}
+
+// CleanUp fails the builds that were interrupted and deletes the archives whose link expired,
+// they hold personal data nobody can download anymore
+func (u DataExportUsecase) CleanUp(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+
+	now := time.Now()
+	failed, err := u.repositories.DataExport.FailStale(ctx, now.Add(-BuildLease))
+	if err != nil {
+		log.Error("Failed to fail interrupted data exports: ", zap.Error(err))
+		return err
+	}
+	if failed > 0 {
+		log.Info("Failed interrupted data exports", zap.Int64("count", failed))
+	}
+
+	exports, err := u.repositories.DataExport.GetExpiredArchives(ctx, now)
+	if err != nil {
+		log.Error("Failed to get expired data exports: ", zap.Error(err))
+		return err
+	}
+	for _, export := range exports {
+		if err := u.deleteArchive(ctx, export, now); err != nil {
+			log.Error("Failed to delete data export archive: ", zap.Error(err))
+			return err
+		}
+	}
+	if len(exports) > 0 {
+		log.Info("Deleted expired data export archives", zap.Int("count", len(exports)))
+	}
+	return nil
+}
+
+// Remove the stored archive and forget its key, the link stops working if it hadn't expired yet
+func (u DataExportUsecase) deleteArchive(ctx context.Context, export *model.DataExport, now time.Time) error {
+	if err := u.storages.File.Delete(ctx, export.FileKey); err != nil {
+		return err
+	}
+	export.FileKey = ""
+	if !export.IsExpired(now) {
+		export.ExpiresAt = &now
+	}
+	_, err := u.repositories.DataExport.Update(ctx, export)
+	return err
+}
+
+func (u DataExportUsecase) build(ctx context.Context, export *model.DataExport) {
+	log := logger.GetLogger(ctx)
+
+	export.Status = model.DataExportStatusProcessing
+	if _, err := u.repositories.DataExport.Update(ctx, export); err != nil {
+		log.Error("Failed to update data export: ", zap.Error(err))
+		return
+	}
+
+	key := fmt.Sprintf("data-exports/%s/%s.zip", export.UserID, export.ID)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(u.writeArchive(ctx, export.UserID, pw))
+	}()
+	err := u.storages.File.Put(ctx, key, pr)
+	pr.Close()
+
+	now := time.Now()
+	export.CompletedAt = &now
+	if err != nil {
+		log.Error("Failed to build data export: ", zap.Error(err))
+		export.Status = model.DataExportStatusFailed
+		export.Error = "failed to build archive"
+	} else {
+		expiresAt := now.Add(linkTTL())
+		export.Status = model.DataExportStatusReady
+		export.FileKey = key
+		export.ExpiresAt = &expiresAt
+	}
+	if _, err := u.repositories.DataExport.Update(ctx, export); err != nil {
+		log.Error("Failed to update data export: ", zap.Error(err))
+	}
+}
+
+func linkTTL() time.Duration {
+	conf := config.New()
+	if conf.DataExportLinkTTL <= 0 {
+		return DefaultLinkTTL
+	}
+	return conf.DataExportLinkTTL
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// The download URL is only set when a token was just issued
+func toResponse(export *model.DataExport, token string) *payload.DataExportResponse {
+	res := &payload.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+		Error:       export.Error,
+	}
+	if token != "" {
+		res.DownloadURL = fmt.Sprintf("/api/v1/me/data-exports/%s/download?token=%s", export.ID, token)
+	}
+	return res
+}
@@ -0,0 +1,189 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/storage/local"
+	mock_dataexport "gitlab.com/raihanlh/messenger-api/testing/mocks/dataexport"
+)
+
+func Test_DataExportUsecase_Download(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	files := local.New(t.TempDir())
+	key := "data-exports/u1/e1.zip"
+	assert.NoError(t, files.Put(ctx, key, strings.NewReader("archive")))
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	ready := func(expiresAt time.Time) *model.DataExport {
+		return &model.DataExport{
+			Model:             model.Model{ID: "e1"},
+			UserID:            "u1",
+			Status:            model.DataExportStatusReady,
+			FileKey:           key,
+			DownloadTokenHash: hashOf("secret"),
+			ExpiresAt:         &expiresAt,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		export  *model.DataExport
+		token   string
+		wantErr func(t *testing.T, err error)
+	}{
+		{
+			name:   "Download ready export",
+			export: ready(future),
+			token:  "secret",
+		},
+		{
+			name:   "Wrong token",
+			export: ready(future),
+			token:  "guess",
+			wantErr: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "unauthorized")
+			},
+		},
+		{
+			name:   "Still processing",
+			export: &model.DataExport{Model: model.Model{ID: "e1"}, UserID: "u1", Status: model.DataExportStatusProcessing},
+			token:  "",
+			wantErr: func(t *testing.T, err error) {
+				assert.EqualError(t, err, "unauthorized")
+			},
+		},
+		{
+			name:   "Expired link",
+			export: ready(past),
+			token:  "secret",
+			wantErr: func(t *testing.T, err error) {
+				httpErr, ok := err.(*http_error.Error)
+				if assert.True(t, ok) {
+					assert.Equal(t, 410, httpErr.HTTPCode)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoMock := mock_dataexport.NewMockRepository(ctrl)
+			repoMock.EXPECT().GetById(ctx, "e1").Return(tt.export, nil)
+
+			dataExportUsecase := usecase.New(&dependency.Repositories{DataExport: repoMock}, &dependency.Storages{File: files})
+			res, err := dataExportUsecase.Download(ctx, &payload.DownloadDataExportRequest{ID: "e1", Token: tt.token})
+			if tt.wantErr != nil {
+				tt.wantErr(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				defer res.File.Close()
+				b, _ := io.ReadAll(res.File)
+				assert.Equal(t, "archive", string(b))
+				assert.True(t, strings.HasSuffix(res.FileName, ".zip"))
+			}
+		})
+	}
+}
+
+func Test_DataExportUsecase_GetById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	expiresAt := time.Now().Add(time.Hour)
+	export := &model.DataExport{
+		Model:             model.Model{ID: "e1"},
+		UserID:            "u1",
+		Status:            model.DataExportStatusReady,
+		FileKey:           "data-exports/u1/e1.zip",
+		DownloadTokenHash: hashOf("old"),
+		ExpiresAt:         &expiresAt,
+	}
+
+	repoMock := mock_dataexport.NewMockRepository(ctrl)
+	repoMock.EXPECT().GetById(ctx, "e1").Return(export, nil)
+	repoMock.EXPECT().Update(ctx, export).Return(export, nil)
+
+	dataExportUsecase := usecase.New(&dependency.Repositories{DataExport: repoMock}, nil)
+	res, err := dataExportUsecase.GetById(ctx, &payload.GetDataExportRequest{ID: "e1", UserID: "u1"})
+	if assert.NoError(t, err) {
+		// A new token replaces the old one and only its hash is stored
+		parsed, _ := url.Parse(res.DownloadURL)
+		token := parsed.Query().Get("token")
+		assert.NotEmpty(t, token)
+		assert.Equal(t, hashOf(token), export.DownloadTokenHash)
+	}
+}
+
+func Test_DataExportUsecase_Request_Active(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	active := &model.DataExport{Model: model.Model{ID: "e1"}, UserID: "u1", Status: model.DataExportStatusProcessing}
+
+	// Builds that stopped progressing within the lease aren't returned as active
+	repoMock := mock_dataexport.NewMockRepository(ctrl)
+	repoMock.EXPECT().GetActiveByUserId(ctx, "u1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, since time.Time) (*model.DataExport, error) {
+		assert.WithinDuration(t, time.Now().Add(-usecase.BuildLease), since, time.Minute)
+		return active, nil
+	})
+
+	dataExportUsecase := usecase.New(&dependency.Repositories{DataExport: repoMock}, nil)
+	res, err := dataExportUsecase.Request(ctx, &payload.RequestDataExportRequest{UserID: "u1"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "e1", res.ID)
+	}
+}
+
+func Test_DataExportUsecase_CleanUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	files := local.New(t.TempDir())
+	key := "data-exports/u1/e1.zip"
+	assert.NoError(t, files.Put(ctx, key, strings.NewReader("archive")))
+	expiredAt := time.Now().Add(-time.Hour)
+	expired := &model.DataExport{Model: model.Model{ID: "e1"}, UserID: "u1", Status: model.DataExportStatusReady, FileKey: key, ExpiresAt: &expiredAt}
+
+	repoMock := mock_dataexport.NewMockRepository(ctrl)
+	repoMock.EXPECT().FailStale(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+		assert.WithinDuration(t, time.Now().Add(-usecase.BuildLease), before, time.Minute)
+		return 1, nil
+	})
+	repoMock.EXPECT().GetExpiredArchives(ctx, gomock.Any()).Return([]*model.DataExport{expired}, nil)
+	repoMock.EXPECT().Update(ctx, expired).Return(expired, nil)
+
+	dataExportUsecase := usecase.New(&dependency.Repositories{DataExport: repoMock}, &dependency.Storages{File: files})
+	if assert.NoError(t, dataExportUsecase.CleanUp(ctx)) {
+		_, err := files.Get(ctx, key)
+		assert.Error(t, err, "the archive is deleted")
+		assert.Empty(t, expired.FileKey)
+		assert.Equal(t, &expiredAt, expired.ExpiresAt)
+	}
+}
+
+func hashOf(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
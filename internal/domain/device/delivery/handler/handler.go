@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type DeviceHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) device.Handler {
+	return &DeviceHandler{
+		usecases: u,
+	}
+}
+
+// RegisterDevice godoc
+// @Summary Register Device
+// @Description register a device with its public identity key, signed prekey and a first batch of one-time prekeys for end-to-end encryption
+// @Tags Device
+// @Accept application/json
+// @Param body body payload.RegisterDeviceRequest true "Public keys of the device"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.RegisterDeviceResponse}
+// @Router /api/v1/devices [post]
+func (h DeviceHandler) Register(ctx echo.Context) error {
+	var body payload.RegisterDeviceRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Device.Register(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to register device: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetAllDevices godoc
+// @Summary Get All Devices
+// @Description get the caller's devices with the number of one-time prekeys they have left
+// @Tags Device
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetAllDevicesResponse}
+// @Router /api/v1/devices [get]
+func (h DeviceHandler) GetAll(ctx echo.Context) error {
+	var body payload.GetAllDevicesRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Device.GetAll(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get devices: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RemoveDevice godoc
+// @Summary Remove Device
+// @Description remove one of the caller's devices along with its prekeys
+// @Tags Device
+// @Accept application/json
+// @Param id path string true "Device ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RemoveDeviceResponse}
+// @Router /api/v1/devices/{id} [delete]
+func (h DeviceHandler) Remove(ctx echo.Context) error {
+	var body payload.RemoveDeviceRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Device.Remove(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to remove device: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RotateSignedPrekey godoc
+// @Summary Rotate Signed Prekey
+// @Description replace the signed prekey of one of the caller's devices
+// @Tags Device
+// @Accept application/json
+// @Param id path string true "Device ID"
+// @Param body body payload.RotateSignedPrekeyRequest true "New signed prekey"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RotateSignedPrekeyResponse}
+// @Router /api/v1/devices/{id}/signed-prekey [put]
+func (h DeviceHandler) RotateSignedPrekey(ctx echo.Context) error {
+	var body payload.RotateSignedPrekeyRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Device.RotateSignedPrekey(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to rotate signed prekey: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// UploadPrekeys godoc
+// @Summary Upload Prekeys
+// @Description add one-time prekeys to one of the caller's devices, key ids already uploaded are skipped
+// @Tags Device
+// @Accept application/json
+// @Param id path string true "Device ID"
+// @Param body body payload.UploadPrekeysRequest true "One-time prekeys"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.UploadPrekeysResponse}
+// @Router /api/v1/devices/{id}/prekeys [post]
+func (h DeviceHandler) UploadPrekeys(ctx echo.Context) error {
+	var body payload.UploadPrekeysRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Device.UploadPrekeys(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to upload prekeys: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// ClaimPrekeyBundles godoc
+// @Summary Claim Prekey Bundles
+// @Description get a prekey bundle for every device of a user to start end-to-end encrypted sessions, each bundle uses up one of the device's one-time prekeys
+// @Tags Device
+// @Accept application/json
+// @Param id path string true "User ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.ClaimBundlesResponse}
+// @Router /api/v1/user/{id}/prekey-bundles [post]
+func (h DeviceHandler) ClaimBundles(ctx echo.Context) error {
+	var body payload.ClaimBundlesRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Device.ClaimBundles(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to claim prekey bundles: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
@@ -0,0 +1,37 @@
+package device
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	Create(ctx context.Context, device *model.Device, prekeys []*model.OneTimePrekey) (*model.Device, error)
+	GetById(ctx context.Context, id string) (*model.Device, error)
+	GetAllByUserId(ctx context.Context, userId string) ([]*model.Device, error)
+	UpdateSignedPrekey(ctx context.Context, device *model.Device) error
+	AddPrekeys(ctx context.Context, prekeys []*model.OneTimePrekey) error
+	ClaimPrekey(ctx context.Context, deviceId string) (*model.OneTimePrekey, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type Usecase interface {
+	Register(ctx context.Context, req *payload.RegisterDeviceRequest) (*payload.RegisterDeviceResponse, error)
+	GetAll(ctx context.Context, req *payload.GetAllDevicesRequest) (*payload.GetAllDevicesResponse, error)
+	Remove(ctx context.Context, req *payload.RemoveDeviceRequest) (*payload.RemoveDeviceResponse, error)
+	RotateSignedPrekey(ctx context.Context, req *payload.RotateSignedPrekeyRequest) (*payload.RotateSignedPrekeyResponse, error)
+	UploadPrekeys(ctx context.Context, req *payload.UploadPrekeysRequest) (*payload.UploadPrekeysResponse, error)
+	ClaimBundles(ctx context.Context, req *payload.ClaimBundlesRequest) (*payload.ClaimBundlesResponse, error)
+}
+
+type Handler interface {
+	Register(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Remove(ctx echo.Context) error
+	RotateSignedPrekey(ctx echo.Context) error
+	UploadPrekeys(ctx echo.Context) error
+	ClaimBundles(ctx echo.Context) error
+}
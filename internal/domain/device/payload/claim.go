@@ -0,0 +1,23 @@
+package payload
+
+type ClaimBundlesRequest struct {
+	UserID       string `json:"-"`
+	TargetUserID string `param:"id"`
+}
+
+// PrekeyBundle is what a client needs to open a session with one device.
+// OneTimePrekey is empty once the device ran out of them, the session then
+// starts from the signed prekey alone.
+type PrekeyBundle struct {
+	DeviceID              string  `json:"device_id"`
+	IdentityKey           string  `json:"identity_key"`
+	SignedPrekeyID        int64   `json:"signed_prekey_id"`
+	SignedPrekey          string  `json:"signed_prekey"`
+	SignedPrekeySignature string  `json:"signed_prekey_signature"`
+	OneTimePrekey         *Prekey `json:"one_time_prekey,omitempty"`
+}
+
+type ClaimBundlesResponse struct {
+	Bundles []*PrekeyBundle `json:"bundles"`
+	Message string          `json:"message"`
+}
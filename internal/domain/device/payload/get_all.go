@@ -0,0 +1,12 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type GetAllDevicesRequest struct {
+	UserID string `json:"-"`
+}
+
+type GetAllDevicesResponse struct {
+	Devices []*model.Device `json:"devices"`
+	Message string          `json:"message"`
+}
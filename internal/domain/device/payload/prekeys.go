@@ -0,0 +1,25 @@
+package payload
+
+type RotateSignedPrekeyRequest struct {
+	UserID                string `json:"-"`
+	DeviceID              string `param:"id" json:"-"`
+	SignedPrekeyID        int64  `json:"signed_prekey_id" validate:"min=0"`
+	SignedPrekey          string `json:"signed_prekey" validate:"required,base64,max=256"`
+	SignedPrekeySignature string `json:"signed_prekey_signature" validate:"required,base64,max=256"`
+}
+
+type RotateSignedPrekeyResponse struct {
+	Message string `json:"message"`
+}
+
+// Key ids already uploaded for the device are skipped
+type UploadPrekeysRequest struct {
+	UserID         string    `json:"-"`
+	DeviceID       string    `param:"id" json:"-"`
+	OneTimePrekeys []*Prekey `json:"one_time_prekeys" validate:"required,min=1,max=100,dive"`
+}
+
+type UploadPrekeysResponse struct {
+	PrekeysLeft int64  `json:"prekeys_left"`
+	Message     string `json:"message"`
+}
@@ -0,0 +1,25 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+// Keys are the base64 public halves generated on the device, the server never checks
+// the signature, clients verify it against the identity key before trusting a bundle
+type RegisterDeviceRequest struct {
+	UserID                string    `json:"-"`
+	Name                  string    `json:"name" validate:"max=100"`
+	IdentityKey           string    `json:"identity_key" validate:"required,base64,max=256"`
+	SignedPrekeyID        int64     `json:"signed_prekey_id" validate:"min=0"`
+	SignedPrekey          string    `json:"signed_prekey" validate:"required,base64,max=256"`
+	SignedPrekeySignature string    `json:"signed_prekey_signature" validate:"required,base64,max=256"`
+	OneTimePrekeys        []*Prekey `json:"one_time_prekeys" validate:"max=100,dive"`
+}
+
+type Prekey struct {
+	KeyID     int64  `json:"key_id" validate:"min=0"`
+	PublicKey string `json:"public_key" validate:"required,base64,max=256"`
+}
+
+type RegisterDeviceResponse struct {
+	Device  *model.Device `json:"device"`
+	Message string        `json:"message"`
+}
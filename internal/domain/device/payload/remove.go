@@ -0,0 +1,10 @@
+package payload
+
+type RemoveDeviceRequest struct {
+	UserID   string `json:"-"`
+	DeviceID string `param:"id"`
+}
+
+type RemoveDeviceResponse struct {
+	Message string `json:"message"`
+}
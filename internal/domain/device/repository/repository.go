@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeviceRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) device.Repository {
+	return &DeviceRepository{
+		DB: gormDB,
+	}
+}
+
+// Unclaimed one-time prekeys of the device, selected alongside it
+const prekeysLeft = "(SELECT COUNT(*) FROM " + constant.OneTimePrekeyTable + " k WHERE k.device_id = devices.id::text AND k.deleted_at IS NULL) AS prekeys_left"
+
+// The device and its first batch of one-time prekeys are stored together
+func (r DeviceRepository) Create(ctx context.Context, device *model.Device, prekeys []*model.OneTimePrekey) (*model.Device, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(device).Error; err != nil {
+			return err
+		}
+		if len(prekeys) == 0 {
+			return nil
+		}
+		for _, prekey := range prekeys {
+			prekey.DeviceID = device.ID
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&prekeys)
+		device.PrekeysLeft = result.RowsAffected
+		return result.Error
+	})
+	return device, err
+}
+
+func (r DeviceRepository) GetById(ctx context.Context, id string) (*model.Device, error) {
+	var device *model.Device
+	result := r.DB.WithContext(ctx).Model(&model.Device{}).Select("devices.*", prekeysLeft).
+		Where("id = ?", id).Limit(1).Find(&device)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return device, nil
+}
+
+func (r DeviceRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Device, error) {
+	var devices []*model.Device
+	result := r.DB.WithContext(ctx).Model(&model.Device{}).Select("devices.*", prekeysLeft).
+		Where("user_id = ?", userId).Order("created_at ASC").Find(&devices)
+	return devices, result.Error
+}
+
+func (r DeviceRepository) UpdateSignedPrekey(ctx context.Context, device *model.Device) error {
+	result := r.DB.WithContext(ctx).Table(constant.DeviceTable).Where("id = ?", device.ID).
+		Updates(map[string]interface{}{
+			"signed_prekey_id":        device.SignedPrekeyID,
+			"signed_prekey":           device.SignedPrekey,
+			"signed_prekey_signature": device.SignedPrekeySignature,
+			"updated_at":              time.Now(),
+		})
+	return result.Error
+}
+
+func (r DeviceRepository) AddPrekeys(ctx context.Context, prekeys []*model.OneTimePrekey) error {
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&prekeys)
+	return result.Error
+}
+
+// ClaimPrekey hands out one unclaimed prekey of the device and deletes it in the same
+// statement, so two clients can never get the same key. Rows locked by a concurrent
+// claim are skipped instead of waited on. It returns nil when none is left.
+func (r DeviceRepository) ClaimPrekey(ctx context.Context, deviceId string) (*model.OneTimePrekey, error) {
+	var prekeys []*model.OneTimePrekey
+	result := r.DB.WithContext(ctx).Raw(`DELETE FROM `+constant.OneTimePrekeyTable+` WHERE id = (
+		SELECT id FROM `+constant.OneTimePrekeyTable+` WHERE device_id = ? AND deleted_at IS NULL
+		ORDER BY key_id ASC LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING *`, deviceId).Scan(&prekeys)
+	if result.Error != nil || len(prekeys) == 0 {
+		return nil, result.Error
+	}
+	return prekeys[0], nil
+}
+
+// Devices and their prekeys are hard deleted, a removed device's keys must never be handed out again
+func (r DeviceRepository) Delete(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("device_id = ?", id).Delete(&model.OneTimePrekey{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", id).Delete(&model.Device{}).Error
+	})
+}
@@ -0,0 +1,69 @@
+package repository_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/device/repository"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_DeviceRepository_ClaimPrekey(t *testing.T) {
+	deviceId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	// Picking and deleting the key is one statement, concurrent claims skip locked rows
+	query := regexp.QuoteMeta(`DELETE FROM one_time_prekeys WHERE id = (`) + `\s+` +
+		regexp.QuoteMeta(`SELECT id FROM one_time_prekeys WHERE device_id = $1 AND deleted_at IS NULL`) + `\s+` +
+		regexp.QuoteMeta(`ORDER BY key_id ASC LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING *`)
+
+	tests := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		wantKey int64
+	}{
+		{
+			name:    "Claim the lowest key",
+			rows:    sqlmock.NewRows([]string{"id", "device_id", "key_id", "public_key"}).AddRow("k1", deviceId, 7, "cHVibGlj"),
+			wantKey: 7,
+		},
+		{
+			name: "No key left",
+			rows: sqlmock.NewRows([]string{"id", "device_id", "key_id", "public_key"}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := Setup()
+			mock.ExpectQuery(query).WithArgs(deviceId).WillReturnRows(tt.rows)
+
+			r := &repo.DeviceRepository{DB: db}
+			prekey, err := r.ClaimPrekey(context.TODO(), deviceId)
+			assert.NoError(t, err)
+			if tt.wantKey == 0 {
+				assert.Nil(t, prekey)
+			} else if assert.NotNil(t, prekey) {
+				assert.Equal(t, tt.wantKey, prekey.KeyID)
+				assert.Equal(t, "cHVibGlj", prekey.PublicKey)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
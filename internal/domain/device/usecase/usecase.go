@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type DeviceUsecase struct {
+	repositories *dependency.Repositories
+}
+
+func New(r *dependency.Repositories) device.Usecase {
+	return &DeviceUsecase{
+		repositories: r,
+	}
+}
+
+func (u DeviceUsecase) Register(ctx context.Context, req *payload.RegisterDeviceRequest) (*payload.RegisterDeviceResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	device, err := u.repositories.Device.Create(ctx, &model.Device{
+		UserID:                req.UserID,
+		Name:                  req.Name,
+		IdentityKey:           req.IdentityKey,
+		SignedPrekeyID:        req.SignedPrekeyID,
+		SignedPrekey:          req.SignedPrekey,
+		SignedPrekeySignature: req.SignedPrekeySignature,
+	}, toPrekeys(req.OneTimePrekeys))
+	if err != nil {
+		log.Error("Failed to register device: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RegisterDeviceResponse{
+		Device:  device,
+		Message: "Register device success",
+	}, nil
+}
+
+func (u DeviceUsecase) GetAll(ctx context.Context, req *payload.GetAllDevicesRequest) (*payload.GetAllDevicesResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	devices, err := u.repositories.Device.GetAllByUserId(ctx, req.UserID)
+	if err != nil {
+		log.Error("Failed to get devices: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.GetAllDevicesResponse{
+		Devices: devices,
+		Message: "Successfully get devices",
+	}, nil
+}
+
+func (u DeviceUsecase) Remove(ctx context.Context, req *payload.RemoveDeviceRequest) (*payload.RemoveDeviceResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnDevice(ctx, req.UserID, req.DeviceID); err != nil {
+		return nil, err
+	}
+	if err := u.repositories.Device.Delete(ctx, req.DeviceID); err != nil {
+		log.Error("Failed to remove device: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RemoveDeviceResponse{
+		Message: "Remove device success",
+	}, nil
+}
+
+func (u DeviceUsecase) RotateSignedPrekey(ctx context.Context, req *payload.RotateSignedPrekeyRequest) (*payload.RotateSignedPrekeyResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	device, err := u.getOwnDevice(ctx, req.UserID, req.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	device.SignedPrekeyID = req.SignedPrekeyID
+	device.SignedPrekey = req.SignedPrekey
+	device.SignedPrekeySignature = req.SignedPrekeySignature
+	if err := u.repositories.Device.UpdateSignedPrekey(ctx, device); err != nil {
+		log.Error("Failed to rotate signed prekey: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RotateSignedPrekeyResponse{
+		Message: "Rotate signed prekey success",
+	}, nil
+}
+
+func (u DeviceUsecase) UploadPrekeys(ctx context.Context, req *payload.UploadPrekeysRequest) (*payload.UploadPrekeysResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnDevice(ctx, req.UserID, req.DeviceID); err != nil {
+		return nil, err
+	}
+	prekeys := toPrekeys(req.OneTimePrekeys)
+	for _, prekey := range prekeys {
+		prekey.DeviceID = req.DeviceID
+	}
+	if err := u.repositories.Device.AddPrekeys(ctx, prekeys); err != nil {
+		log.Error("Failed to upload prekeys: ", zap.Error(err))
+		return nil, err
+	}
+
+	device, err := u.repositories.Device.GetById(ctx, req.DeviceID)
+	if err != nil {
+		log.Error("Failed to get device: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.UploadPrekeysResponse{
+		PrekeysLeft: device.PrekeysLeft,
+		Message:     "Upload prekeys success",
+	}, nil
+}
+
+// ClaimBundles returns a prekey bundle for every device of the target user, each one
+// using up one of that device's one-time prekeys
+func (u DeviceUsecase) ClaimBundles(ctx context.Context, req *payload.ClaimBundlesRequest) (*payload.ClaimBundlesResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.repositories.User.GetById(ctx, req.TargetUserID); err != nil {
+		log.Error("Failed to get user: ", zap.Error(err))
+		return nil, http_error.RecordNotFound("user")
+	}
+	// Blocked users could otherwise drain the target's prekeys
+	blocked, err := u.repositories.Block.IsBlocked(ctx, req.TargetUserID, req.UserID)
+	if err != nil {
+		log.Error("Failed to check block: ", zap.Error(err))
+		return nil, err
+	}
+	if blocked {
+		return nil, http_error.Blocked("You can't start a session with this user")
+	}
+
+	devices, err := u.repositories.Device.GetAllByUserId(ctx, req.TargetUserID)
+	if err != nil {
+		log.Error("Failed to get devices: ", zap.Error(err))
+		return nil, err
+	}
+
+	bundles := make([]*payload.PrekeyBundle, 0, len(devices))
+	for _, d := range devices {
+		bundle := &payload.PrekeyBundle{
+			DeviceID:              d.ID,
+			IdentityKey:           d.IdentityKey,
+			SignedPrekeyID:        d.SignedPrekeyID,
+			SignedPrekey:          d.SignedPrekey,
+			SignedPrekeySignature: d.SignedPrekeySignature,
+		}
+		prekey, err := u.repositories.Device.ClaimPrekey(ctx, d.ID)
+		if err != nil {
+			log.Error("Failed to claim prekey: ", zap.Error(err))
+			return nil, err
+		}
+		if prekey != nil {
+			bundle.OneTimePrekey = &payload.Prekey{KeyID: prekey.KeyID, PublicKey: prekey.PublicKey}
+		}
+		bundles = append(bundles, bundle)
+	}
+
+	return &payload.ClaimBundlesResponse{
+		Bundles: bundles,
+		Message: "Successfully claim prekey bundles",
+	}, nil
+}
+
+func (u DeviceUsecase) getOwnDevice(ctx context.Context, userId string, deviceId string) (*model.Device, error) {
+	device, err := u.repositories.Device.GetById(ctx, deviceId)
+	if err != nil {
+		return nil, err
+	}
+	if device.UserID != userId {
+		return nil, errors.New("unauthorized")
+	}
+	return device, nil
+}
+
+func toPrekeys(keys []*payload.Prekey) []*model.OneTimePrekey {
+	prekeys := make([]*model.OneTimePrekey, 0, len(keys))
+	for _, k := range keys {
+		prekeys = append(prekeys, &model.OneTimePrekey{KeyID: k.KeyID, PublicKey: k.PublicKey})
+	}
+	return prekeys
+}
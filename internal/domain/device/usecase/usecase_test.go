@@ -0,0 +1,90 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/device/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	mock_block "gitlab.com/raihanlh/messenger-api/testing/mocks/block"
+	mock_device "gitlab.com/raihanlh/messenger-api/testing/mocks/device"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+)
+
+func Test_DeviceUsecase_ClaimBundles(t *testing.T) {
+	callerId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	targetId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	devices := []*model.Device{
+		{Model: model.Model{ID: "d1"}, UserID: targetId, IdentityKey: "aWQx", SignedPrekeyID: 1, SignedPrekey: "c3Br", SignedPrekeySignature: "c2ln"},
+		{Model: model.Model{ID: "d2"}, UserID: targetId, IdentityKey: "aWQy", SignedPrekeyID: 3, SignedPrekey: "c3Br", SignedPrekeySignature: "c2ln"},
+	}
+
+	tests := []struct {
+		name    string
+		blocked bool
+		wantErr string
+	}{
+		{name: "One bundle per device"},
+		{name: "Blocked by the target", blocked: true, wantErr: http_error.BlockedCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			userRepoMock.EXPECT().GetById(ctx, targetId).Return(&model.User{Model: model.Model{ID: targetId}}, nil)
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			blockRepoMock.EXPECT().IsBlocked(ctx, targetId, callerId).Return(tt.blocked, nil)
+			deviceRepoMock := mock_device.NewMockRepository(ctrl)
+			if !tt.blocked {
+				deviceRepoMock.EXPECT().GetAllByUserId(ctx, targetId).Return(devices, nil)
+				deviceRepoMock.EXPECT().ClaimPrekey(ctx, "d1").Return(&model.OneTimePrekey{KeyID: 7, PublicKey: "b3Rr"}, nil)
+				// d2 ran out of one-time prekeys
+				deviceRepoMock.EXPECT().ClaimPrekey(ctx, "d2").Return(nil, nil)
+			}
+
+			deviceUsecase := usecase.New(&dependency.Repositories{
+				User:   userRepoMock,
+				Block:  blockRepoMock,
+				Device: deviceRepoMock,
+			})
+			res, err := deviceUsecase.ClaimBundles(ctx, &payload.ClaimBundlesRequest{UserID: callerId, TargetUserID: targetId})
+			if tt.wantErr != "" {
+				if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+					assert.Equal(t, tt.wantErr, httpErr.ErrorCode)
+				}
+				return
+			}
+			if !assert.NoError(t, err) || !assert.Len(t, res.Bundles, 2) {
+				return
+			}
+			assert.Equal(t, "d1", res.Bundles[0].DeviceID)
+			assert.Equal(t, "aWQx", res.Bundles[0].IdentityKey)
+			if assert.NotNil(t, res.Bundles[0].OneTimePrekey) {
+				assert.Equal(t, int64(7), res.Bundles[0].OneTimePrekey.KeyID)
+			}
+			assert.Equal(t, int64(3), res.Bundles[1].SignedPrekeyID)
+			assert.Nil(t, res.Bundles[1].OneTimePrekey)
+		})
+	}
+}
+
+func Test_DeviceUsecase_Remove_OtherUsersDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	deviceRepoMock := mock_device.NewMockRepository(ctrl)
+	deviceRepoMock.EXPECT().GetById(ctx, "d1").Return(&model.Device{Model: model.Model{ID: "d1"}, UserID: "someone-else"}, nil)
+
+	deviceUsecase := usecase.New(&dependency.Repositories{Device: deviceRepoMock})
+	_, err := deviceUsecase.Remove(ctx, &payload.RemoveDeviceRequest{UserID: "me", DeviceID: "d1"})
+	assert.EqualError(t, err, "unauthorized")
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type DigestHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) digest.Handler {
+	return &DigestHandler{
+		usecases: u,
+	}
+}
+
+// GetEmailDigestSettings godoc
+// @Summary Get Email Digest Settings
+// @Description get whether the caller is emailed a digest of messages left unread
+// @Tags Digest
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.SettingsResponse}
+// @Router /api/v1/me/email-digest [get]
+func (h DigestHandler) GetSettings(ctx echo.Context) error {
+	var body payload.GetSettingsRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Digest.GetSettings(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get email digest settings: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// UpdateEmailDigestSettings godoc
+// @Summary Update Email Digest Settings
+// @Description turn the email digest of unread messages on or off for the caller
+// @Tags Digest
+// @Accept application/json
+// @Param body body payload.UpdateSettingsRequest true "Whether to send the digest"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.SettingsResponse}
+// @Router /api/v1/me/email-digest [put]
+func (h DigestHandler) UpdateSettings(ctx echo.Context) error {
+	var body payload.UpdateSettingsRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Digest.UpdateSettings(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to update email digest settings: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
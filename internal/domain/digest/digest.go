@@ -0,0 +1,29 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	GetDueUserIds(ctx context.Context, from time.Time, to time.Time, limit int) ([]string, error)
+	GetDueMessages(ctx context.Context, userId string, from time.Time, to time.Time) ([]*payload.DueMessage, error)
+	ClaimUser(ctx context.Context, userId string) (bool, error)
+	MarkDigested(ctx context.Context, entries []*model.DigestedMessage) error
+	SetOptOut(ctx context.Context, userId string, optOut bool) error
+}
+
+type Usecase interface {
+	GetSettings(ctx context.Context, req *payload.GetSettingsRequest) (*payload.SettingsResponse, error)
+	UpdateSettings(ctx context.Context, req *payload.UpdateSettingsRequest) (*payload.SettingsResponse, error)
+	SendDigests(ctx context.Context) error
+}
+
+type Handler interface {
+	GetSettings(ctx echo.Context) error
+	UpdateSettings(ctx echo.Context) error
+}
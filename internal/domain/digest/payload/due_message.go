@@ -0,0 +1,13 @@
+package payload
+
+import "time"
+
+// DueMessage is an unread message old enough to be emailed to its receiver
+type DueMessage struct {
+	MessageID      string
+	ConversationID string
+	SenderID       string
+	SenderName     string
+	SenderDeleted  bool
+	SentAt         time.Time
+}
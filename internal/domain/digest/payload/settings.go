@@ -0,0 +1,15 @@
+package payload
+
+type GetSettingsRequest struct {
+	UserID string `json:"-"`
+}
+
+type UpdateSettingsRequest struct {
+	UserID  string `json:"-"`
+	Enabled *bool  `json:"enabled" validate:"required"`
+}
+
+type SettingsResponse struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
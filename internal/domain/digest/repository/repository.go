@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// First key of the advisory locks taken on digests, the second is derived from the user id
+const digestLockKey = 4101
+
+type DigestRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) digest.Repository {
+	return &DigestRepository{
+		DB: gormDB,
+	}
+}
+
+// Unread messages sent between from and to that their receiver wasn't emailed about yet.
+// Receivers who opted out or have no email are left out, as are pending requests and
+// conversations the receiver archived, muted or cleared, same as the unread badge.
+func (r DigestRepository) due(ctx context.Context, from time.Time, to time.Time) *gorm.DB {
+	return postgres.Conn(ctx, r.DB).Table(constant.MessageTable+" m").
+		Joins("JOIN "+constant.ConversationTable+" c ON c.id::text = m.conversation_id AND c.deleted_at IS NULL").
+		Joins("JOIN "+constant.UserTable+" u ON u.id::text = CASE WHEN c.sender_id = m.sender_id THEN c.receiver_id ELSE c.sender_id END").
+		Joins("LEFT JOIN "+constant.UserParticipantTable+" p ON p.conversation_id = c.id::text AND p.user_id = u.id::text AND p.deleted_at IS NULL").
+		Joins("LEFT JOIN "+constant.DigestedMessageTable+" d ON d.message_id = m.id::text AND d.user_id = u.id::text").
+		Where("m.is_read = false AND m.deleted_at IS NULL AND m.sent_at > ? AND m.sent_at <= ?", from, to).
+		Where("u.deleted_at IS NULL AND u.digest_opt_out = false AND u.email <> ''").
+		Where("d.id IS NULL").
+		Where("NOT (c.receiver_id = u.id::text AND c.status IN ?)", []string{model.ConversationStatusRequest, model.ConversationStatusDeclined}).
+		Where("p.id IS NULL OR (p.is_archived = false AND (p.muted_until IS NULL OR p.muted_until <= ?) AND (p.cleared_at IS NULL OR m.sent_at > p.cleared_at))", time.Now())
+}
+
+func (r DigestRepository) GetDueUserIds(ctx context.Context, from time.Time, to time.Time, limit int) ([]string, error) {
+	var userIds []string
+	result := r.due(ctx, from, to).Distinct("u.id::text").Limit(limit).Pluck("u.id::text", &userIds)
+	return userIds, result.Error
+}
+
+// Oldest first. The sender is named as the receiver saved them in their contacts.
+func (r DigestRepository) GetDueMessages(ctx context.Context, userId string, from time.Time, to time.Time) ([]*payload.DueMessage, error) {
+	var messages []*payload.DueMessage
+	result := r.due(ctx, from, to).
+		Joins("JOIN "+constant.UserTable+" s ON s.id::text = m.sender_id").
+		Joins("LEFT JOIN "+constant.ContactTable+" ct ON ct.user_id = u.id::text AND ct.contact_id = m.sender_id AND ct.deleted_at IS NULL").
+		Select("m.id::text AS message_id, m.conversation_id, m.sender_id, m.sent_at, "+
+			"COALESCE(NULLIF(ct.nickname, ''), s.name) AS sender_name, s.deleted_at IS NOT NULL AS sender_deleted").
+		Where("u.id::text = ?", userId).
+		Order("m.sent_at ASC").Scan(&messages)
+	return messages, result.Error
+}
+
+// ClaimUser locks the user's digest until the caller's transaction ends and reports whether
+// it got the lock, another instance sending the same digest holds it otherwise. Two users
+// whose ids hash alike can't be sent at once, the second one waits for the next run.
+func (r DigestRepository) ClaimUser(ctx context.Context, userId string) (bool, error) {
+	var claimed bool
+	result := postgres.Conn(ctx, r.DB).Raw("SELECT pg_try_advisory_xact_lock(?, hashtext(?))", digestLockKey, userId).Scan(&claimed)
+	return claimed, result.Error
+}
+
+// MarkDigested joins the caller's transaction, so the messages are only recorded when the email went out
+func (r DigestRepository) MarkDigested(ctx context.Context, entries []*model.DigestedMessage) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return postgres.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(&entries).Error
+}
+
+func (r DigestRepository) SetOptOut(ctx context.Context, userId string, optOut bool) error {
+	result := r.DB.WithContext(ctx).Table(constant.UserTable).Where("id = ?", userId).
+		Updates(map[string]interface{}{
+			"digest_opt_out": optOut,
+			"updated_at":     time.Now(),
+		})
+	return result.Error
+}
@@ -0,0 +1,77 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/digest/repository"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_DigestRepository_GetDueUserIds(t *testing.T) {
+	db, mock := Setup()
+	to := time.Now().Add(-time.Hour)
+	from := to.Add(-7 * 24 * time.Hour)
+
+	// Messages already digested for the receiver and opted out receivers are filtered in the query
+	mock.ExpectQuery(`SELECT DISTINCT u\.id::text FROM messages m JOIN conversations c .* LEFT JOIN digested_messages d ON d\.message_id = m\.id::text AND d\.user_id = u\.id::text `+
+		`WHERE \(m\.is_read = false AND m\.deleted_at IS NULL AND m\.sent_at > \$1 AND m\.sent_at <= \$2\) AND \(u\.deleted_at IS NULL AND u\.digest_opt_out = false AND u\.email <> ''\) AND d\.id IS NULL .* LIMIT 100`).
+		WithArgs(from, to, "request", "declined", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("u1").AddRow("u2"))
+
+	userIds, err := repo.New(db).GetDueUserIds(context.TODO(), from, to, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, userIds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_DigestRepository_ClaimUser(t *testing.T) {
+	db, mock := Setup()
+
+	mock.ExpectQuery(`SELECT pg_try_advisory_xact_lock\(\$1, hashtext\(\$2\)\)`).
+		WithArgs(sqlmock.AnyArg(), "u1").
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(false))
+
+	claimed, err := repo.New(db).ClaimUser(context.TODO(), "u1")
+	assert.NoError(t, err)
+	assert.False(t, claimed, "held by another instance")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_DigestRepository_MarkDigested(t *testing.T) {
+	db, mock := Setup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "digested_messages" .* ON CONFLICT DO NOTHING`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "u1", "m1", "c1", sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "u1", "m2", "c1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("d1").AddRow("d2"))
+	mock.ExpectCommit()
+
+	err := repo.New(db).MarkDigested(context.TODO(), []*model.DigestedMessage{
+		{UserID: "u1", MessageID: "m1", ConversationID: "c1"},
+		{UserID: "u1", MessageID: "m2", ConversationID: "c1"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	textTemplate "text/template"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"gitlab.com/raihanlh/messenger-api/pkg/mail"
+	"go.uber.org/zap"
+)
+
+const (
+	// Users emailed per run of SendDigests
+	DigestBatchSize    = 100
+	DefaultDigestDelay = time.Hour
+	// Messages older than this are never emailed, e.g. the backlog when digests are first turned on
+	DigestLookback = 7 * 24 * time.Hour
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	textDigest = textTemplate.Must(textTemplate.ParseFS(templates, "templates/digest.txt"))
+	htmlDigest = htmlTemplate.Must(htmlTemplate.ParseFS(templates, "templates/digest.html"))
+)
+
+type DigestUsecase struct {
+	repositories *dependency.Repositories
+	gateways     *dependency.Gateways
+}
+
+func New(r *dependency.Repositories, g *dependency.Gateways) digest.Usecase {
+	return &DigestUsecase{
+		repositories: r,
+		gateways:     g,
+	}
+}
+
+func (u DigestUsecase) GetSettings(ctx context.Context, req *payload.GetSettingsRequest) (*payload.SettingsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	user, err := u.repositories.User.GetById(ctx, req.UserID)
+	if err != nil {
+		log.Error("Failed to get email digest settings: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.SettingsResponse{
+		Enabled: !user.DigestOptOut,
+		Message: "Successfully get email digest settings",
+	}, nil
+}
+
+func (u DigestUsecase) UpdateSettings(ctx context.Context, req *payload.UpdateSettingsRequest) (*payload.SettingsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if err := u.repositories.Digest.SetOptOut(ctx, req.UserID, !*req.Enabled); err != nil {
+		log.Error("Failed to update email digest settings: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.SettingsResponse{
+		Enabled: *req.Enabled,
+		Message: "Update email digest settings success",
+	}, nil
+}
+
+// SendDigests emails every user who has messages unread for longer than the digest delay,
+// one email per user listing the conversations they're in
+func (u DigestUsecase) SendDigests(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+	if u.gateways.Mail == nil {
+		return nil
+	}
+
+	now := time.Now()
+	from, to := now.Add(-DigestLookback), now.Add(-u.delay())
+	userIds, err := u.repositories.Digest.GetDueUserIds(ctx, from, to, DigestBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, userId := range userIds {
+		if err := u.send(ctx, userId, from, to); err != nil {
+			log.Error("Failed to send email digest: ", zap.String("user_id", userId), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+type digestConversation struct {
+	Name       string
+	Count      int
+	LastSentAt time.Time
+}
+
+type digestData struct {
+	Name          string
+	Total         int
+	Conversations []*digestConversation
+}
+
+// The messages are recorded in the same transaction the email is sent in, a failed send
+// leaves them for the next run and a sent email is never repeated. The user is claimed
+// first, so instances running the job at once don't both email them.
+func (u DigestUsecase) send(ctx context.Context, userId string, from time.Time, to time.Time) error {
+	user, err := u.repositories.User.GetById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		claimed, err := u.repositories.Digest.ClaimUser(ctx, userId)
+		if err != nil || !claimed {
+			return err
+		}
+		// Read after the claim, what another instance sent before is already recorded
+		messages, err := u.repositories.Digest.GetDueMessages(ctx, userId, from, to)
+		if err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		msg, entries, err := compose(user, messages)
+		if err != nil {
+			return err
+		}
+		if err := u.repositories.Digest.MarkDigested(ctx, entries); err != nil {
+			return err
+		}
+		return u.gateways.Mail.Send(ctx, msg)
+	})
+}
+
+func compose(user *model.User, messages []*payload.DueMessage) (*mail.Message, []*model.DigestedMessage, error) {
+	data := &digestData{Name: user.Name, Total: len(messages)}
+	conversations := map[string]*digestConversation{}
+	entries := make([]*model.DigestedMessage, 0, len(messages))
+	for _, m := range messages {
+		c, ok := conversations[m.ConversationID]
+		if !ok {
+			name := m.SenderName
+			if m.SenderDeleted {
+				name = model.DeletedAccountName
+			}
+			c = &digestConversation{Name: name}
+			conversations[m.ConversationID] = c
+			data.Conversations = append(data.Conversations, c)
+		}
+		c.Count++
+		c.LastSentAt = m.SentAt
+		entries = append(entries, &model.DigestedMessage{UserID: user.ID, MessageID: m.MessageID, ConversationID: m.ConversationID})
+	}
+
+	msg, err := render(user.Email, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, entries, nil
+}
+
+func render(to string, data *digestData) (*mail.Message, error) {
+	if to == "" {
+		return nil, errors.New("user has no email")
+	}
+	var text, html bytes.Buffer
+	if err := textDigest.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := htmlDigest.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	subject := fmt.Sprintf("You have %d unread messages", data.Total)
+	if data.Total == 1 {
+		subject = "You have 1 unread message"
+	}
+	return &mail.Message{
+		To:      []string{to},
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+func (u DigestUsecase) delay() time.Duration {
+	if u.gateways.DigestDelay <= 0 {
+		return DefaultDigestDelay
+	}
+	return u.gateways.DigestDelay
+}
@@ -0,0 +1,146 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/digest/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/mail/smtp"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_digest "gitlab.com/raihanlh/messenger-api/testing/mocks/digest"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+)
+
+func Test_DigestUsecase_SendDigests(t *testing.T) {
+	userId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	sentAt := time.Date(2023, 3, 1, 9, 30, 0, 0, time.UTC)
+	due := []*payload.DueMessage{
+		{MessageID: "m1", ConversationID: "c1", SenderID: "s1", SenderName: "Bob", SentAt: sentAt},
+		{MessageID: "m2", ConversationID: "c2", SenderID: "s2", SenderName: "Carol", SenderDeleted: true, SentAt: sentAt},
+		{MessageID: "m3", ConversationID: "c1", SenderID: "s1", SenderName: "Bob", SentAt: sentAt.Add(time.Minute)},
+	}
+
+	tests := []struct {
+		name       string
+		rejectRcpt string
+		claimedBy  bool
+		wantMails  int
+	}{
+		{name: "One email listing each conversation", wantMails: 1},
+		{name: "Rejected by the server", rejectRcpt: "550"},
+		{name: "Claimed by another instance", claimedBy: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+			server := helper.NewSMTPServer(t)
+			server.RejectRcpt = tt.rejectRcpt
+
+			digestRepoMock := mock_digest.NewMockRepository(ctrl)
+			digestRepoMock.EXPECT().GetDueUserIds(ctx, gomock.Any(), gomock.Any(), usecase.DigestBatchSize).
+				DoAndReturn(func(_ context.Context, from time.Time, to time.Time, _ int) ([]string, error) {
+					assert.WithinDuration(t, time.Now().Add(-30*time.Minute), to, time.Minute, "the configured delay")
+					assert.Equal(t, usecase.DigestLookback-30*time.Minute, to.Sub(from))
+					return []string{userId}, nil
+				})
+			digestRepoMock.EXPECT().ClaimUser(ctx, userId).Return(!tt.claimedBy, nil)
+			if !tt.claimedBy {
+				digestRepoMock.EXPECT().GetDueMessages(ctx, userId, gomock.Any(), gomock.Any()).Return(due, nil)
+				digestRepoMock.EXPECT().MarkDigested(ctx, []*model.DigestedMessage{
+					{UserID: userId, MessageID: "m1", ConversationID: "c1"},
+					{UserID: userId, MessageID: "m2", ConversationID: "c2"},
+					{UserID: userId, MessageID: "m3", ConversationID: "c1"},
+				}).Return(nil)
+			}
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			userRepoMock.EXPECT().GetById(ctx, userId).Return(&model.User{Model: model.Model{ID: userId}, Name: "Alice <3", Email: "alice@example.com"}, nil)
+
+			digestUsecase := usecase.New(&dependency.Repositories{
+				Transactor: helper.NoTransaction{},
+				User:       userRepoMock,
+				Digest:     digestRepoMock,
+			}, &dependency.Gateways{
+				Mail:        smtp.New(server.Host, server.Port, "", "", "Messenger <no-reply@example.com>"),
+				DigestDelay: 30 * time.Minute,
+			})
+			assert.NoError(t, digestUsecase.SendDigests(ctx))
+
+			mails := server.Mails()
+			if !assert.Len(t, mails, tt.wantMails) || tt.wantMails == 0 {
+				return
+			}
+			assert.Equal(t, []string{"alice@example.com"}, mails[0].To)
+			subject, text, html := readMail(t, mails[0].Data)
+			assert.Equal(t, "You have 3 unread messages", subject)
+			assert.Contains(t, text, "Hi Alice <3,")
+			assert.Contains(t, text, "- Bob: 2 messages, last at Mar 1, 09:31 UTC")
+			assert.Contains(t, text, "- "+model.DeletedAccountName+": 1 message, last at Mar 1, 09:30 UTC")
+			assert.Contains(t, html, "Hi Alice &lt;3,", "names are escaped in HTML")
+			assert.Contains(t, html, "<strong>Bob</strong>: 2 messages")
+		})
+	}
+}
+
+func Test_DigestUsecase_SendDigests_NoMailer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	digestUsecase := usecase.New(&dependency.Repositories{
+		Digest: mock_digest.NewMockRepository(ctrl),
+	}, &dependency.Gateways{})
+	assert.NoError(t, digestUsecase.SendDigests(context.TODO()))
+}
+
+func Test_DigestUsecase_UpdateSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+	userId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	digestRepoMock := mock_digest.NewMockRepository(ctrl)
+	digestRepoMock.EXPECT().SetOptOut(ctx, userId, true).Return(nil)
+
+	enabled := false
+	res, err := usecase.New(&dependency.Repositories{Digest: digestRepoMock}, &dependency.Gateways{}).
+		UpdateSettings(ctx, &payload.UpdateSettingsRequest{UserID: userId, Enabled: &enabled})
+	assert.NoError(t, err)
+	assert.False(t, res.Enabled)
+}
+
+// Subject, text and HTML of a multipart/alternative email
+func readMail(t *testing.T, data string) (string, string, string) {
+	msg, err := netmail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	_, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	bodies := map[string]string{}
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part)
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[mediaType] = string(body)
+	}
+	return subject, bodies["text/plain"], bodies["text/html"]
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type DraftHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) draft.Handler {
+	return &DraftHandler{
+		usecases: u,
+	}
+}
+
+// SaveDraft godoc
+// @Summary Save Draft
+// @Description save the caller's draft for a conversation, an empty text clears it
+// @Tags Draft
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param body body payload.SaveDraftRequest true "Save Draft"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.SaveDraftResponse}
+// @Router /api/v1/conversations/{convo_id}/draft [put]
+func (h DraftHandler) Save(ctx echo.Context) error {
+	var body payload.SaveDraftRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Draft.Save(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to save draft: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetDraft godoc
+// @Summary Get Draft
+// @Description get the caller's draft for a conversation
+// @Tags Draft
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetDraftResponse}
+// @Router /api/v1/conversations/{convo_id}/draft [get]
+func (h DraftHandler) Get(ctx echo.Context) error {
+	var body payload.GetDraftRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Draft.Get(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get draft: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
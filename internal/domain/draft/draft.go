@@ -0,0 +1,26 @@
+package draft
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	Upsert(ctx context.Context, draft *model.Draft) (*model.Draft, error)
+	Get(ctx context.Context, userId string, conversationId string) (*model.Draft, error)
+	GetAllByUserId(ctx context.Context, userId string) ([]*model.Draft, error)
+	Delete(ctx context.Context, userId string, conversationId string) error
+}
+
+type Usecase interface {
+	Save(ctx context.Context, req *payload.SaveDraftRequest) (*payload.SaveDraftResponse, error)
+	Get(ctx context.Context, req *payload.GetDraftRequest) (*payload.GetDraftResponse, error)
+}
+
+type Handler interface {
+	Save(ctx echo.Context) error
+	Get(ctx echo.Context) error
+}
@@ -0,0 +1,14 @@
+package payload
+
+import "time"
+
+type GetDraftRequest struct {
+	ConversationID string `param:"convo_id"`
+	UserID         string `json:"-"`
+}
+
+type GetDraftResponse struct {
+	ConversationID string    `json:"conversation_id"`
+	Text           string    `json:"text"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+}
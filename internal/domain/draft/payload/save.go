@@ -0,0 +1,15 @@
+package payload
+
+import "time"
+
+type SaveDraftRequest struct {
+	ConversationID string `param:"convo_id" json:"-"`
+	UserID         string `json:"-"`
+	Text           string `json:"text"`
+}
+
+type SaveDraftResponse struct {
+	ConversationID string    `json:"conversation_id"`
+	Text           string    `json:"text"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DraftRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) draft.Repository {
+	return &DraftRepository{
+		DB: gormDB,
+	}
+}
+
+func (r DraftRepository) Upsert(ctx context.Context, draft *model.Draft) (*model.Draft, error) {
+	result := r.DB.WithContext(ctx).Model(draft).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "conversation_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "updated_at"}),
+	}).Create(draft)
+	return draft, result.Error
+}
+
+func (r DraftRepository) Get(ctx context.Context, userId string, conversationId string) (*model.Draft, error) {
+	var draft *model.Draft
+	result := r.DB.WithContext(ctx).Table(constant.DraftTable).
+		Where("user_id = ? AND conversation_id = ?", userId, conversationId).Limit(1).Find(&draft)
+	if result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return draft, result.Error
+}
+
+func (r DraftRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Draft, error) {
+	var drafts []*model.Draft
+	result := r.DB.WithContext(ctx).Table(constant.DraftTable).Where("user_id = ?", userId).Find(&drafts)
+	return drafts, result.Error
+}
+
+// Drafts are hard deleted so the unique (user_id, conversation_id) index stays usable for upserts
+func (r DraftRepository) Delete(ctx context.Context, userId string, conversationId string) error {
+	result := r.DB.WithContext(ctx).Unscoped().Where("user_id = ? AND conversation_id = ?", userId, conversationId).Delete(&model.Draft{})
+	return result.Error
+}
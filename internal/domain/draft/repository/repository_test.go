@@ -0,0 +1,122 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/draft/repository"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type AnyTime struct{}
+
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_DraftRepository_Upsert(t *testing.T) {
+	db, mock := Setup()
+
+	query := `INSERT INTO "drafts" ("created_at","updated_at","deleted_at","user_id","conversation_id","text","id") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("user_id","conversation_id") DO UPDATE SET "text"="excluded"."text","updated_at"="excluded"."updated_at" RETURNING "id"`
+
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	convId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name      string
+		draft     *model.Draft
+		wantErrDB error
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Upsert Draft Success",
+			draft: &model.Draft{
+				UserID:         userId,
+				ConversationID: convId,
+				Text:           "half written",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "Upsert Draft Failed",
+			draft: &model.Draft{
+				UserID:         userId,
+				ConversationID: convId,
+				Text:           "half written",
+			},
+			wantErrDB: errors.New("test error"),
+			wantErr:   assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			expect := mock.ExpectQuery(regexp.QuoteMeta(query)).
+				WithArgs(AnyTime{}, AnyTime{}, nil, tt.draft.UserID, tt.draft.ConversationID, tt.draft.Text, sqlmock.AnyArg())
+			if tt.wantErrDB != nil {
+				expect.WillReturnError(tt.wantErrDB)
+				mock.ExpectRollback()
+			} else {
+				expect.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1b6b1c5e-8c55-4a6c-9a38-3cbf0fe5a0a1"))
+				mock.ExpectCommit()
+			}
+
+			r := &repo.DraftRepository{
+				DB: db,
+			}
+
+			res, err := r.Upsert(context.TODO(), tt.draft)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.draft.Text, res.Text)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_DraftRepository_Delete(t *testing.T) {
+	db, mock := Setup()
+
+	query := `DELETE FROM "drafts" WHERE user_id = $1 AND conversation_id = $2`
+
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	convId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(query)).
+		WithArgs(userId, convId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := &repo.DraftRepository{
+		DB: db,
+	}
+
+	err := r.Delete(context.TODO(), userId, convId)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
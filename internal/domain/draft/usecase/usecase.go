@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/draft/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type DraftUsecase struct {
+	repositories *dependency.Repositories
+}
+
+func New(r *dependency.Repositories) draft.Usecase {
+	return &DraftUsecase{
+		repositories: r,
+	}
+}
+
+func (u DraftUsecase) Save(ctx context.Context, req *payload.SaveDraftRequest) (*payload.SaveDraftResponse, error) {
+	log := logger.GetLogger(ctx)
+	if err := u.checkParticipant(ctx, req.ConversationID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	// An empty draft is the same as no draft
+	if req.Text == "" {
+		err := u.repositories.Draft.Delete(ctx, req.UserID, req.ConversationID)
+		if err != nil {
+			log.Error("Failed to delete draft: ", zap.Error(err))
+			return nil, err
+		}
+		return &payload.SaveDraftResponse{
+			ConversationID: req.ConversationID,
+		}, nil
+	}
+
+	d, err := u.repositories.Draft.Upsert(ctx, &model.Draft{
+		UserID:         req.UserID,
+		ConversationID: req.ConversationID,
+		Text:           req.Text,
+	})
+	if err != nil {
+		log.Error("Failed to save draft: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.SaveDraftResponse{
+		ConversationID: d.ConversationID,
+		Text:           d.Text,
+		UpdatedAt:      d.UpdatedAt,
+	}, nil
+}
+
+func (u DraftUsecase) Get(ctx context.Context, req *payload.GetDraftRequest) (*payload.GetDraftResponse, error) {
+	log := logger.GetLogger(ctx)
+	if err := u.checkParticipant(ctx, req.ConversationID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	d, err := u.repositories.Draft.Get(ctx, req.UserID, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get draft: ", zap.Error(err))
+		return nil, err
+	}
+	if d == nil {
+		return &payload.GetDraftResponse{
+			ConversationID: req.ConversationID,
+		}, nil
+	}
+
+	return &payload.GetDraftResponse{
+		ConversationID: d.ConversationID,
+		Text:           d.Text,
+		UpdatedAt:      d.UpdatedAt,
+	}, nil
+}
+
+func (u DraftUsecase) checkParticipant(ctx context.Context, conversationId string, userId string) error {
+	log := logger.GetLogger(ctx)
+	convo, err := u.repositories.Conversation.GetById(ctx, conversationId)
+	if err != nil {
+		log.Error("Failed to get conversation: ", zap.Error(err))
+		return err
+	}
+	if convo.SenderID != userId && convo.ReceiverID != userId {
+		log.Error("Unauthorized: user is not a participant of the conversation")
+		return errors.New("unauthorized")
+	}
+	return nil
+}
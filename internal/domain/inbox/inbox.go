@@ -0,0 +1,18 @@
+package inbox
+
+import (
+	"context"
+
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// The inbox is only written through other domains and read by the conversation list,
+// so it has no usecase or handler of its own
+type Repository interface {
+	RecordMessage(ctx context.Context, message *model.Message, receiverId string) error
+	RecordIncoming(ctx context.Context, message *model.Message, userIds []string) error
+	MarkRead(ctx context.Context, userId string, conversationId string) error
+	Refresh(ctx context.Context, conversationId string) error
+	RefreshByUserId(ctx context.Context, userId string) error
+	Rebuild(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/inbox"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HidePreviews leaves last_message_preview empty, for when message text is encrypted
+// and the policy doesn't allow keeping plaintext copies of it
+type InboxRepository struct {
+	DB           *gorm.DB
+	HidePreviews bool
+}
+
+func New(gormDB *gorm.DB, hidePreviews bool) inbox.Repository {
+	return &InboxRepository{
+		DB:           gormDB,
+		HidePreviews: hidePreviews,
+	}
+}
+
+// RecordMessage moves the conversation to the top of both participants' inboxes,
+// only the receiver gets another unread message
+func (r InboxRepository) RecordMessage(ctx context.Context, message *model.Message, receiverId string) error {
+	db := postgres.Conn(ctx, r.DB)
+	preview := model.InboxPreview(message.MessageText)
+	if r.HidePreviews || message.IsE2E() {
+		preview = ""
+	}
+	for _, userId := range []string{message.SenderID, receiverId} {
+		unread := 0
+		if userId == receiverId {
+			unread = 1
+		}
+		if err := r.record(db, message, preview, userId, unread); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordIncoming is RecordMessage for a message sent from outside the conversation, by
+// an integration, every participant gets it as unread
+func (r InboxRepository) RecordIncoming(ctx context.Context, message *model.Message, userIds []string) error {
+	db := postgres.Conn(ctx, r.DB)
+	preview := model.InboxPreview(message.MessageText)
+	if r.HidePreviews {
+		preview = ""
+	}
+	for _, userId := range userIds {
+		if err := r.record(db, message, preview, userId, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r InboxRepository) record(db *gorm.DB, message *model.Message, preview string, userId string, unread int) error {
+	row := &model.Inbox{
+		UserID:              userId,
+		ConversationID:      message.ConversationID,
+		LastMessageID:       message.ID,
+		LastMessageSenderID: message.SenderID,
+		LastMessagePreview:  preview,
+		LastActivityAt:      message.SentAt,
+		UnreadCount:         int64(unread),
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "conversation_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_message_id":        row.LastMessageID,
+			"last_message_sender_id": row.LastMessageSenderID,
+			"last_message_preview":   row.LastMessagePreview,
+			"last_activity_at":       row.LastActivityAt,
+			"unread_count":           gorm.Expr(constant.InboxTable+".unread_count + ?", unread),
+			"updated_at":             time.Now(),
+		}),
+	}).Create(row)
+	return result.Error
+}
+
+func (r InboxRepository) MarkRead(ctx context.Context, userId string, conversationId string) error {
+	result := postgres.Conn(ctx, r.DB).Table(constant.InboxTable).
+		Where("user_id = ? AND conversation_id = ? AND unread_count <> 0", userId, conversationId).
+		Updates(map[string]interface{}{"unread_count": 0, "updated_at": time.Now()})
+	return result.Error
+}
+
+// Refresh recomputes both inbox rows of a conversation from its messages,
+// used when messages go away and the last message or unread count may change
+func (r InboxRepository) Refresh(ctx context.Context, conversationId string) error {
+	return postgres.Conn(ctx, r.DB).Exec(r.recomputeQuery("c.id::text = ?"), conversationId).Error
+}
+
+// RefreshByUserId recomputes every conversation the user takes part in, for both participants
+func (r InboxRepository) RefreshByUserId(ctx context.Context, userId string) error {
+	return postgres.Conn(ctx, r.DB).Exec(r.recomputeQuery("(c.sender_id = ? OR c.receiver_id = ?)"), userId, userId).Error
+}
+
+// Rebuild recomputes the whole projection from the messages table and drops rows
+// of conversations that no longer exist. It returns the number of rows written.
+func (r InboxRepository) Rebuild(ctx context.Context) (int64, error) {
+	var rows int64
+	err := postgres.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(r.recomputeQuery("TRUE"))
+		if result.Error != nil {
+			return result.Error
+		}
+		rows = result.RowsAffected
+		return tx.Exec("DELETE FROM " + constant.InboxTable + " i WHERE NOT EXISTS (SELECT 1 FROM " + constant.ConversationTable +
+			" c WHERE c.id::text = i.conversation_id AND c.deleted_at IS NULL AND i.user_id IN (c.sender_id, c.receiver_id))").Error
+	})
+	return rows, err
+}
+
+// Upsert the inbox rows of both participants of the conversations matching where.
+// Messages from before a participant cleared the history don't count for them.
+// Encrypted messages take their preview from the search index when they are in it.
+func (r InboxRepository) recomputeQuery(where string) string {
+	preview := `COALESCE(LEFT(CASE WHEN lm.key_id = '' THEN lm.message_text ELSE s.body END, ` + previewLength + `), '')`
+	if r.HidePreviews {
+		preview = `''`
+	}
+	return `INSERT INTO ` + constant.InboxTable + ` (id, created_at, updated_at, user_id, conversation_id,
+		last_message_id, last_message_sender_id, last_message_preview, last_activity_at, unread_count)
+	SELECT uuid_generate_v4(), NOW(), NOW(), pu.user_id, c.id::text,
+		COALESCE(lm.id::text, ''), COALESCE(lm.sender_id, ''), ` + preview + `,
+		COALESCE(lm.sent_at, c.created_at), unread.count
+	FROM ` + constant.ConversationTable + ` c
+	CROSS JOIN LATERAL (VALUES (c.sender_id), (c.receiver_id)) AS pu(user_id)
+	LEFT JOIN ` + constant.UserParticipantTable + ` p ON p.conversation_id = c.id::text AND p.user_id = pu.user_id AND p.deleted_at IS NULL
+	LEFT JOIN LATERAL (SELECT id, sender_id, message_text, key_id, sent_at FROM ` + constant.MessageTable + `
+		WHERE conversation_id = c.id::text AND deleted_at IS NULL AND (p.cleared_at IS NULL OR sent_at > p.cleared_at)
+		ORDER BY sent_at DESC, id DESC LIMIT 1) lm ON true
+	LEFT JOIN ` + constant.MessageSearchEntryTable + ` s ON s.message_id = lm.id::text AND s.deleted_at IS NULL
+	CROSS JOIN LATERAL (SELECT COUNT(*) AS count FROM ` + constant.MessageTable + `
+		WHERE conversation_id = c.id::text AND sender_id <> pu.user_id AND is_read = false AND deleted_at IS NULL
+		AND (p.cleared_at IS NULL OR sent_at > p.cleared_at)) unread
+	WHERE c.deleted_at IS NULL AND ` + where + `
+	ON CONFLICT (user_id, conversation_id) DO UPDATE SET
+		last_message_id = EXCLUDED.last_message_id,
+		last_message_sender_id = EXCLUDED.last_message_sender_id,
+		last_message_preview = EXCLUDED.last_message_preview,
+		last_activity_at = EXCLUDED.last_activity_at,
+		unread_count = EXCLUDED.unread_count,
+		updated_at = EXCLUDED.updated_at,
+		deleted_at = NULL`
+}
+
+var previewLength = strconv.Itoa(model.InboxPreviewLength)
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/inbox/repository"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type AnyTime struct{}
+
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_InboxRepository_RecordMessage(t *testing.T) {
+	db, mock := Setup()
+
+	sentAt := time.Now()
+	message := &model.Message{
+		Model:          model.Model{ID: "m1"},
+		ConversationID: "c1",
+		SenderID:       "u1",
+		MessageText:    "hello there",
+		SentAt:         sentAt,
+	}
+
+	// Both participants are upserted, only the receiver's unread count goes up
+	for _, row := range []struct {
+		userId string
+		unread int
+	}{{"u1", 0}, {"u2", 1}} {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO "inboxes" .* ON CONFLICT \("user_id","conversation_id"\) DO UPDATE SET .*"unread_count"=inboxes\.unread_count \+ \$`).
+			WithArgs(AnyTime{}, AnyTime{}, nil, row.userId, "c1", "m1", "u1", "hello there", sentAt, row.unread, sqlmock.AnyArg(), sentAt, "m1", "hello there", "u1", row.unread, AnyTime{}).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("i-" + row.userId))
+		mock.ExpectCommit()
+	}
+
+	r := &repo.InboxRepository{DB: db}
+	assert.NoError(t, r.RecordMessage(context.TODO(), message, "u2"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_InboxRepository_MarkRead(t *testing.T) {
+	db, mock := Setup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "inboxes" SET "unread_count"=\$1,"updated_at"=\$2 WHERE user_id = \$3 AND conversation_id = \$4 AND unread_count <> 0`).
+		WithArgs(0, AnyTime{}, "u1", "c1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	r := &repo.InboxRepository{DB: db}
+	assert.NoError(t, r.MarkRead(context.TODO(), "u1", "c1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type IncomingWebhookHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) incominghook.Handler {
+	return &IncomingWebhookHandler{
+		usecases: u,
+	}
+}
+
+// CreateIncomingWebhook godoc
+// @Summary Create Incoming Webhook
+// @Description create a secret URL that posts into the conversation, the URL is not shown again
+// @Tags Incoming Webhook
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param body body payload.CreateIncomingWebhookRequest true "Integration name and rate limit"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.CreateIncomingWebhookResponse}
+// @Router /api/v1/conversations/{convo_id}/incoming-webhooks [post]
+func (h IncomingWebhookHandler) Create(ctx echo.Context) error {
+	var body payload.CreateIncomingWebhookRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.IncomingHook.Create(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to create incoming webhook: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetAllIncomingWebhooks godoc
+// @Summary Get All Incoming Webhooks
+// @Description get the incoming webhooks of the conversation
+// @Tags Incoming Webhook
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetAllIncomingWebhooksResponse}
+// @Router /api/v1/conversations/{convo_id}/incoming-webhooks [get]
+func (h IncomingWebhookHandler) GetAll(ctx echo.Context) error {
+	var body payload.GetAllIncomingWebhooksRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.IncomingHook.GetAll(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get incoming webhooks: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RevokeIncomingWebhook godoc
+// @Summary Revoke Incoming Webhook
+// @Description revoke an incoming webhook of the conversation, its URL stops working
+// @Tags Incoming Webhook
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param id path string true "Incoming webhook ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RevokeIncomingWebhookResponse}
+// @Router /api/v1/conversations/{convo_id}/incoming-webhooks/{id} [delete]
+func (h IncomingWebhookHandler) Revoke(ctx echo.Context) error {
+	var body payload.RevokeIncomingWebhookRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.IncomingHook.Revoke(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to revoke incoming webhook: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// PostIncomingWebhook godoc
+// @Summary Post Incoming Webhook
+// @Description post a message into the hook's conversation, authorized by the token in the URL and rate limited per hook
+// @Tags Incoming Webhook
+// @Accept application/json
+// @Param token path string true "Token from the incoming webhook URL"
+// @Param body body payload.PostRequest true "Message text"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.PostResponse}
+// @Router /api/v1/hooks/{token} [post]
+func (h IncomingWebhookHandler) Post(ctx echo.Context) error {
+	var body payload.PostRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	data, err := h.usecases.IncomingHook.Post(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to post message: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
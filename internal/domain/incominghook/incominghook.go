@@ -0,0 +1,33 @@
+package incominghook
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type Repository interface {
+	Create(ctx context.Context, hook *model.IncomingWebhook) (*model.IncomingWebhook, error)
+	GetById(ctx context.Context, id string) (*model.IncomingWebhook, error)
+	GetByTokenHash(ctx context.Context, hash string) (*model.IncomingWebhook, error)
+	GetAllByConversationId(ctx context.Context, conversationId string) ([]*model.IncomingWebhook, error)
+	Delete(ctx context.Context, id string) error
+	Hit(ctx context.Context, id string, at time.Time, window time.Duration) (bool, error)
+}
+
+type Usecase interface {
+	Create(ctx context.Context, req *payload.CreateIncomingWebhookRequest) (*payload.CreateIncomingWebhookResponse, error)
+	GetAll(ctx context.Context, req *payload.GetAllIncomingWebhooksRequest) (*payload.GetAllIncomingWebhooksResponse, error)
+	Revoke(ctx context.Context, req *payload.RevokeIncomingWebhookRequest) (*payload.RevokeIncomingWebhookResponse, error)
+	Post(ctx context.Context, req *payload.PostRequest) (*payload.PostResponse, error)
+}
+
+type Handler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Revoke(ctx echo.Context) error
+	Post(ctx echo.Context) error
+}
@@ -0,0 +1,40 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+// Name and photo are what the conversation shows as the sender of the hook's messages
+type CreateIncomingWebhookRequest struct {
+	UserID         string `json:"-"`
+	ConversationID string `param:"convo_id"`
+	Name           string `json:"name" validate:"required,max=100"`
+	PhotoURL       string `json:"photo_url" validate:"omitempty,url,max=2048"`
+	// Posts allowed per minute, defaults to 60
+	RateLimit int `json:"rate_limit" validate:"min=0,max=600"`
+}
+
+// URL is the path to POST to, it holds the token and isn't shown again
+type CreateIncomingWebhookResponse struct {
+	Hook    *model.IncomingWebhook `json:"hook"`
+	URL     string                 `json:"url"`
+	Message string                 `json:"message"`
+}
+
+type GetAllIncomingWebhooksRequest struct {
+	UserID         string `json:"-"`
+	ConversationID string `param:"convo_id"`
+}
+
+type GetAllIncomingWebhooksResponse struct {
+	Hooks   []*model.IncomingWebhook `json:"hooks"`
+	Message string                   `json:"message"`
+}
+
+type RevokeIncomingWebhookRequest struct {
+	UserID         string `json:"-"`
+	ConversationID string `param:"convo_id"`
+	HookID         string `param:"id"`
+}
+
+type RevokeIncomingWebhookResponse struct {
+	Message string `json:"message"`
+}
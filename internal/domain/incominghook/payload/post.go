@@ -0,0 +1,15 @@
+package payload
+
+import "time"
+
+type PostRequest struct {
+	Token string `param:"token"`
+	Text  string `json:"text" validate:"required,max=4000"`
+}
+
+type PostResponse struct {
+	ID             string    `json:"id"` // Message ID
+	ConversationID string    `json:"conversation_id"`
+	SentAt         time.Time `json:"sent_at"`
+	Message        string    `json:"message"`
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type IncomingWebhookRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) incominghook.Repository {
+	return &IncomingWebhookRepository{
+		DB: gormDB,
+	}
+}
+
+// The hook's integration identity is created along with it
+func (r IncomingWebhookRepository) Create(ctx context.Context, hook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+	db := postgres.Conn(ctx, r.DB)
+	hook.Integration.IsBot = true
+	if err := db.Create(hook.Integration).Error; err != nil {
+		return nil, err
+	}
+	hook.UserID = hook.Integration.ID
+	result := db.Omit("Integration").Create(hook)
+	return hook, result.Error
+}
+
+func (r IncomingWebhookRepository) GetById(ctx context.Context, id string) (*model.IncomingWebhook, error) {
+	return r.getBy(ctx, "id = ?", id)
+}
+
+func (r IncomingWebhookRepository) GetByTokenHash(ctx context.Context, hash string) (*model.IncomingWebhook, error) {
+	return r.getBy(ctx, "token_hash = ?", hash)
+}
+
+func (r IncomingWebhookRepository) getBy(ctx context.Context, query string, arg string) (*model.IncomingWebhook, error) {
+	var hook *model.IncomingWebhook
+	result := r.DB.WithContext(ctx).Preload("Integration").Where(query, arg).Limit(1).Find(&hook)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return hook, nil
+}
+
+func (r IncomingWebhookRepository) GetAllByConversationId(ctx context.Context, conversationId string) ([]*model.IncomingWebhook, error) {
+	var hooks []*model.IncomingWebhook
+	result := r.DB.WithContext(ctx).Preload("Integration").Where("conversation_id = ?", conversationId).
+		Order("created_at ASC").Find(&hooks)
+	return hooks, result.Error
+}
+
+// Messages the hook posted keep its integration identity as their sender
+func (r IncomingWebhookRepository) Delete(ctx context.Context, id string) error {
+	result := r.DB.WithContext(ctx).Where("id = ?", id).Delete(&model.IncomingWebhook{})
+	return result.Error
+}
+
+// Hit counts a post in the hook's current window, or starts a new window when it's over.
+// It returns false without counting when the window is full. The check and the count
+// are one statement, so concurrent posts can't both take the last slot.
+func (r IncomingWebhookRepository) Hit(ctx context.Context, id string, at time.Time, window time.Duration) (bool, error) {
+	expired := at.Add(-window)
+	result := postgres.Conn(ctx, r.DB).Table(constant.IncomingWebhookTable).
+		Where("id = ? AND deleted_at IS NULL AND (window_started_at <= ? OR window_count < rate_limit)", id, expired).
+		Updates(map[string]interface{}{
+			"window_count":      gorm.Expr("CASE WHEN window_started_at <= ? THEN 1 ELSE window_count + 1 END", expired),
+			"window_started_at": gorm.Expr("CASE WHEN window_started_at <= ? THEN ? ELSE window_started_at END", expired, at),
+			"last_used_at":      at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/repository"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_IncomingWebhookRepository_Hit(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    int64
+		allowed bool
+	}{
+		{name: "Room in the window", rows: 1, allowed: true},
+		{name: "Window full", rows: 0, allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := Setup()
+			at := time.Now()
+			expired := at.Add(-time.Minute)
+
+			// The limit is checked in the same statement that counts the post
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "incoming_webhooks" SET "last_used_at"=\$1,"window_count"=CASE WHEN window_started_at <= \$2 THEN 1 ELSE window_count \+ 1 END,"window_started_at"=CASE WHEN window_started_at <= \$3 THEN \$4 ELSE window_started_at END WHERE id = \$5 AND deleted_at IS NULL AND \(window_started_at <= \$6 OR window_count < rate_limit\)`).
+				WithArgs(at, expired, expired, at, "h1", expired).
+				WillReturnResult(sqlmock.NewResult(0, tt.rows))
+			mock.ExpectCommit()
+
+			allowed, err := repo.New(db).Hit(context.TODO(), "h1", at, time.Minute)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.allowed, allowed)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// Posts per minute when the hook is created without a rate limit
+	DefaultRateLimit = 60
+	RateLimitWindow  = time.Minute
+	// Path the token is appended to, see the router
+	PostPath = "/api/v1/hooks/"
+)
+
+type IncomingWebhookUsecase struct {
+	repositories *dependency.Repositories
+}
+
+func New(r *dependency.Repositories) incominghook.Usecase {
+	return &IncomingWebhookUsecase{
+		repositories: r,
+	}
+}
+
+func (u IncomingWebhookUsecase) Create(ctx context.Context, req *payload.CreateIncomingWebhookRequest) (*payload.CreateIncomingWebhookResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnConversation(ctx, req.UserID, req.ConversationID); err != nil {
+		return nil, err
+	}
+	rateLimit := req.RateLimit
+	if rateLimit == 0 {
+		rateLimit = DefaultRateLimit
+	}
+
+	token := newToken()
+	var hook *model.IncomingWebhook
+	err := u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		hook, err = u.repositories.IncomingHook.Create(ctx, &model.IncomingWebhook{
+			ConversationID: req.ConversationID,
+			CreatorID:      req.UserID,
+			TokenHash:      hashToken(token),
+			RateLimit:      rateLimit,
+			Integration: &model.User{
+				Name:     req.Name,
+				PhotoURL: req.PhotoURL,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		log.Error("Failed to create incoming webhook: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.CreateIncomingWebhookResponse{
+		Hook:    hook,
+		URL:     PostPath + token,
+		Message: "Create incoming webhook success",
+	}, nil
+}
+
+func (u IncomingWebhookUsecase) GetAll(ctx context.Context, req *payload.GetAllIncomingWebhooksRequest) (*payload.GetAllIncomingWebhooksResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnConversation(ctx, req.UserID, req.ConversationID); err != nil {
+		return nil, err
+	}
+	hooks, err := u.repositories.IncomingHook.GetAllByConversationId(ctx, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get incoming webhooks: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.GetAllIncomingWebhooksResponse{
+		Hooks:   hooks,
+		Message: "Successfully get incoming webhooks",
+	}, nil
+}
+
+// Any participant can revoke a hook, not only the one who created it
+func (u IncomingWebhookUsecase) Revoke(ctx context.Context, req *payload.RevokeIncomingWebhookRequest) (*payload.RevokeIncomingWebhookResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnConversation(ctx, req.UserID, req.ConversationID); err != nil {
+		return nil, err
+	}
+	hook, err := u.repositories.IncomingHook.GetById(ctx, req.HookID)
+	if err != nil {
+		return nil, err
+	}
+	if hook.ConversationID != req.ConversationID {
+		return nil, errors.New("not found")
+	}
+	if err := u.repositories.IncomingHook.Delete(ctx, hook.ID); err != nil {
+		log.Error("Failed to revoke incoming webhook: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RevokeIncomingWebhookResponse{
+		Message: "Revoke incoming webhook success",
+	}, nil
+}
+
+// Post stores the text as a message from the hook's integration identity. Both
+// participants get it as unread, with the same push notifications and webhook events
+// as any other message.
+func (u IncomingWebhookUsecase) Post(ctx context.Context, req *payload.PostRequest) (*payload.PostResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	hook, err := u.repositories.IncomingHook.GetByTokenHash(ctx, hashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := u.repositories.IncomingHook.Hit(ctx, hook.ID, time.Now(), RateLimitWindow)
+	if err != nil {
+		log.Error("Failed to count incoming webhook post: ", zap.Error(err))
+		return nil, err
+	}
+	if !allowed {
+		return nil, http_error.TooManyRequests("Rate limit of the incoming webhook exceeded")
+	}
+	convo, err := u.repositories.Conversation.GetById(ctx, hook.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	participants := []string{convo.SenderID, convo.ReceiverID}
+
+	var msg *model.Message
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		msg, err = u.repositories.Message.Create(ctx, &model.Message{
+			SentAt:         time.Now(),
+			ConversationID: convo.ID,
+			SenderID:       hook.UserID,
+			MessageText:    req.Text,
+			Mode:           model.MessageModePlain,
+		})
+		if err != nil {
+			log.Error("Failed to create message: ", zap.Error(err))
+			return err
+		}
+		if err := u.repositories.Inbox.RecordIncoming(ctx, msg, participants); err != nil {
+			log.Error("Failed to update inbox: ", zap.Error(err))
+			return err
+		}
+		// Both participants get the message as unread, so both are recipients
+		err = u.repositories.Outbox.Append(ctx, model.EventMessageCreated, &model.MessageCreatedEvent{
+			MessageID:      msg.ID,
+			ConversationID: convo.ID,
+			SenderID:       hook.UserID,
+			RecipientIDs:   participants,
+			ParticipantIDs: participants,
+			Mode:           msg.Mode,
+			SentAt:         msg.SentAt,
+		})
+		if err != nil {
+			log.Error("Failed to append event: ", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.PostResponse{
+		ID:             msg.ID,
+		ConversationID: convo.ID,
+		SentAt:         msg.SentAt,
+		Message:        "Post message success",
+	}, nil
+}
+
+func (u IncomingWebhookUsecase) getOwnConversation(ctx context.Context, userId string, conversationId string) (*model.Conversation, error) {
+	convo, err := u.repositories.Conversation.GetById(ctx, conversationId)
+	if err != nil {
+		return nil, err
+	}
+	if convo.SenderID != userId && convo.ReceiverID != userId {
+		return nil, errors.New("unauthorized")
+	}
+	return convo, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,150 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/incominghook/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_conversation "gitlab.com/raihanlh/messenger-api/testing/mocks/conversation"
+	mock_inbox "gitlab.com/raihanlh/messenger-api/testing/mocks/inbox"
+	mock_incominghook "gitlab.com/raihanlh/messenger-api/testing/mocks/incominghook"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	mock_outbox "gitlab.com/raihanlh/messenger-api/testing/mocks/outbox"
+)
+
+func Test_IncomingWebhookUsecase_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		userId  string
+		wantErr string
+	}{
+		{name: "Participant", userId: "u2"},
+		{name: "Not a participant", userId: "u3", wantErr: "unauthorized"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, "c1").Return(&model.Conversation{Model: model.Model{ID: "c1"}, SenderID: "u1", ReceiverID: "u2"}, nil)
+			hookRepoMock := mock_incominghook.NewMockRepository(ctrl)
+			var stored *model.IncomingWebhook
+			if tt.wantErr == "" {
+				hookRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, hook *model.IncomingWebhook) (*model.IncomingWebhook, error) {
+					stored = hook
+					return hook, nil
+				})
+			}
+
+			hookUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				Conversation: convRepoMock,
+				IncomingHook: hookRepoMock,
+			})
+
+			res, err := hookUsecase.Create(ctx, &payload.CreateIncomingWebhookRequest{
+				UserID:         tt.userId,
+				ConversationID: "c1",
+				Name:           "CI",
+			})
+			if tt.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Equal(t, tt.wantErr, err.Error())
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, usecase.DefaultRateLimit, stored.RateLimit)
+				assert.Equal(t, "CI", stored.Integration.Name)
+				token := res.URL[len(usecase.PostPath):]
+				sum := sha256.Sum256([]byte(token))
+				assert.Equal(t, hex.EncodeToString(sum[:]), stored.TokenHash, "only the hash is stored")
+			}
+		})
+	}
+}
+
+func Test_IncomingWebhookUsecase_Post(t *testing.T) {
+	const token = "t0ken"
+	sum := sha256.Sum256([]byte(token))
+	hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		allowed bool
+	}{
+		{name: "Posted", allowed: true},
+		{name: "Rate limited", allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+			participants := []string{"u1", "u2"}
+
+			hookRepoMock := mock_incominghook.NewMockRepository(ctrl)
+			hookRepoMock.EXPECT().GetByTokenHash(ctx, hash).Return(&model.IncomingWebhook{
+				Model:          model.Model{ID: "h1"},
+				ConversationID: "c1",
+				UserID:         "i1",
+			}, nil)
+			hookRepoMock.EXPECT().Hit(ctx, "h1", gomock.Any(), usecase.RateLimitWindow).Return(tt.allowed, nil)
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+			outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+			if tt.allowed {
+				convRepoMock.EXPECT().GetById(ctx, "c1").Return(&model.Conversation{Model: model.Model{ID: "c1"}, SenderID: "u1", ReceiverID: "u2"}, nil)
+				msgRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msg *model.Message) (*model.Message, error) {
+					assert.Equal(t, "i1", msg.SenderID, "sent by the integration identity")
+					assert.Equal(t, "Build passed", msg.MessageText)
+					msg.ID = "m1"
+					return msg, nil
+				})
+				inboxRepoMock.EXPECT().RecordIncoming(ctx, gomock.Any(), participants).Return(nil)
+				outboxRepoMock.EXPECT().Append(ctx, model.EventMessageCreated, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, payload interface{}) error {
+						if event, ok := payload.(*model.MessageCreatedEvent); assert.True(t, ok) {
+							assert.Equal(t, "m1", event.MessageID)
+							assert.Equal(t, "i1", event.SenderID)
+							assert.Equal(t, participants, event.RecipientIDs, "both participants get it as unread")
+						}
+						return nil
+					})
+			}
+
+			hookUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				Conversation: convRepoMock,
+				Message:      msgRepoMock,
+				Inbox:        inboxRepoMock,
+				Outbox:       outboxRepoMock,
+				IncomingHook: hookRepoMock,
+			})
+
+			res, err := hookUsecase.Post(ctx, &payload.PostRequest{Token: token, Text: "Build passed"})
+			if !tt.allowed {
+				if assert.Error(t, err) {
+					assert.Equal(t, "Rate limit of the incoming webhook exceeded", err.Error())
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, "m1", res.ID)
+				assert.WithinDuration(t, time.Now(), res.SentAt, time.Minute)
+			}
+		})
+	}
+}
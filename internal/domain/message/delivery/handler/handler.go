@@ -25,7 +25,7 @@ func New(u *dependency.Usecases) message.Handler {
 
 // CreateNewMessage godoc
 // @Summary Create New Message
-// @Description create message from request body
+// @Description create message from request body. With mode e2e the message is opaque ciphertext per recipient device, a device list that doesn't match the receiver's devices is answered with 409
 // @Tags Message
 // @Accept application/json
 // @Param body body payload.CreateMessageRequest true "Create User"
@@ -48,9 +48,15 @@ func (h MessageHandler) Create(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, errCustom)
 	}
 
-	// Check if message is empty
-	if body.Message == "" {
+	// Check if message is empty, e2e messages carry their ciphertext in content instead
+	if body.Mode == model.MessageModeE2E {
+		if body.Message != "" || len(body.Content) == 0 {
+			return ctx.JSON(http.StatusUnprocessableEntity, "e2e messages need content and no message")
+		}
+	} else if body.Message == "" {
 		return ctx.JSON(http.StatusUnprocessableEntity, "message can't be empty")
+	} else if len(body.Content) > 0 {
+		return ctx.JSON(http.StatusUnprocessableEntity, "only e2e messages have content")
 	}
 
 	// Pass body to usecase
@@ -58,7 +64,7 @@ func (h MessageHandler) Create(ctx echo.Context) error {
 	if err != nil {
 		httpErr, ok := err.(*http_error.Error)
 		if !ok {
-			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to create user: %s", httpErr.Error())))
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to create user: %s", err.Error())))
 		}
 		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
 	}
@@ -70,7 +76,7 @@ func (h MessageHandler) Create(ctx echo.Context) error {
 
 // GetMessageByConversationId godoc
 // @Summary Get Message By Conversation Id
-// @Description get message by conversation id
+// @Description get message by conversation id, the messages the caller received are marked as read
 // @Tags Message
 // @Accept application/json
 // @Param convo_id path string true "Conversation ID"
@@ -104,7 +110,7 @@ func (h MessageHandler) GetByConversationId(ctx echo.Context) error {
 		}
 		httpErr, ok := err.(*http_error.Error)
 		if !ok {
-			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get user by id: %s", httpErr.Error())))
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get user by id: %s", err.Error())))
 		}
 		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
 	}
@@ -114,3 +120,202 @@ func (h MessageHandler) GetByConversationId(ctx echo.Context) error {
 	res.AddHTTPCode(http.StatusOK).AddData(msgs)
 	return ctx.JSON(res.HTTPCode, res)
 }
+
+// DeleteMessage godoc
+// @Summary Delete Message
+// @Description delete a message the caller sent, it is removed for both participants
+// @Tags Message
+// @Accept application/json
+// @Param id path string true "Message ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.DeleteMessageResponse}
+// @Router /api/v1/messages/{id} [delete]
+func (h MessageHandler) Delete(ctx echo.Context) error {
+	var body payload.DeleteMessageRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Message.Delete(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to delete message: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// GetUnreadSummary godoc
+// @Summary Get Unread Summary
+// @Description get the total of unread messages and unread conversations for the app badge, muted and archived conversations are not counted. Messages are read once the receiver opens their conversation
+// @Tags Message
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.UnreadSummaryResponse}
+// @Router /api/v1/me/unread [get]
+func (h MessageHandler) GetUnreadSummary(ctx echo.Context) error {
+	var body payload.GetUnreadSummaryRequest
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Message.GetUnreadSummary(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get unread summary: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// SearchMessages godoc
+// @Summary Search Messages
+// @Description search the messages of a conversation, newest first
+// @Tags Message
+// @Accept application/json
+// @Param convo_id path string true "Conversation ID"
+// @Param q query string true "Text to look for"
+// @Param limit query int false "Maximum number of results, defaults to 20"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.SearchMessagesResponse}
+// @Router /api/v1/conversations/{convo_id}/messages/search [get]
+func (h MessageHandler) Search(ctx echo.Context) error {
+	var body payload.SearchMessagesRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Message.Search(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to search messages: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// ExportConversation godoc
+// @Summary Export Conversation
+// @Description stream the full history of a conversation as a JSON, CSV or HTML transcript
+// @Tags Message
+// @Param convo_id path string true "Conversation ID"
+// @Param format query string false "Transcript format" Enums(json, csv, html)
+// @Param timezone query string false "IANA timezone for timestamps, defaults to UTC"
+// @Produce json
+// @Produce text/csv
+// @Produce text/html
+// @Success 200 {file} file
+// @Router /api/v1/conversations/{convo_id}/export [get]
+func (h MessageHandler) Export(ctx echo.Context) error {
+	var body payload.ExportRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+	if body.Format == "" {
+		body.Format = payload.ExportFormatJSON
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	w := &exportWriter{ctx: ctx, format: body.Format, conversationId: body.ConversationID}
+	err := h.usecases.Message.Export(ctx.Request().Context(), &body, w)
+	if err != nil {
+		// Once the transcript started streaming the status can't change anymore
+		if w.started {
+			return err
+		}
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to export conversation: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+	return nil
+}
+
+// exportWriter sends the download headers right before the first byte of the transcript,
+// so errors found before that can still be answered with a regular JSON error
+type exportWriter struct {
+	ctx            echo.Context
+	format         string
+	conversationId string
+	started        bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	res := w.ctx.Response()
+	if !w.started {
+		w.started = true
+		contentType := echo.MIMEApplicationJSONCharsetUTF8
+		switch w.format {
+		case payload.ExportFormatCSV:
+			contentType = "text/csv; charset=UTF-8"
+		case payload.ExportFormatHTML:
+			contentType = echo.MIMETextHTMLCharsetUTF8
+		}
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="conversation-%s.%s"`, w.conversationId, w.format))
+		res.WriteHeader(http.StatusOK)
+	}
+	n, err := res.Write(p)
+	res.Flush()
+	return n, err
+}
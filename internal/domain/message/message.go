@@ -2,6 +2,8 @@ package message
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
@@ -10,16 +12,35 @@ import (
 
 type Repository interface {
 	Create(ctx context.Context, message *model.Message) (*model.Message, error)
-	GetAllByConversationId(ctx context.Context, conversationId string) ([]*model.Message, error)
-	GetUnreadCount(ctx context.Context, userId string, conversationId string) (int64, error)
+	GetById(ctx context.Context, id string) (*model.Message, error)
+	GetByIds(ctx context.Context, ids []string) ([]*model.Message, error)
+	GetDeviceContents(ctx context.Context, messageIds []string, userId string) ([]*model.MessageDeviceContent, error)
+	Delete(ctx context.Context, id string) error
+	MarkRead(ctx context.Context, conversationId string, userId string) (int64, error)
+	GetAllByConversationId(ctx context.Context, conversationId string, since *time.Time) ([]*model.Message, error)
+	StreamByConversationId(ctx context.Context, conversationId string, since *time.Time, batchSize int, fn func(messages []*model.Message) error) error
+	StreamBySenderId(ctx context.Context, senderId string, batchSize int, fn func(messages []*model.Message) error) error
+	PurgeBySenderId(ctx context.Context, senderId string) (int64, error)
+	GetUnreadSummary(ctx context.Context, userId string, at time.Time) (*payload.UnreadSummaryResponse, error)
+	Search(ctx context.Context, conversationId string, term string, since *time.Time, limit int) ([]*model.Message, error)
+	ReencryptBatch(ctx context.Context, afterId string, batchSize int) (string, int, error)
+	DropSearchIndex(ctx context.Context) (int64, error)
 }
 
 type Usecase interface {
 	Create(ctx context.Context, req *payload.CreateMessageRequest) (*payload.CreateMessageResponse, error)
 	GetAllByConversationId(ctx context.Context, req *payload.GetMessagesByConvIdRequest) (*payload.GetMessagesByConvIdResponse, error)
+	Delete(ctx context.Context, req *payload.DeleteMessageRequest) (*payload.DeleteMessageResponse, error)
+	Export(ctx context.Context, req *payload.ExportRequest, w io.Writer) error
+	GetUnreadSummary(ctx context.Context, req *payload.GetUnreadSummaryRequest) (*payload.UnreadSummaryResponse, error)
+	Search(ctx context.Context, req *payload.SearchMessagesRequest) (*payload.SearchMessagesResponse, error)
 }
 
 type Handler interface {
 	Create(ctx echo.Context) error
 	GetByConversationId(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+	Export(ctx echo.Context) error
+	GetUnreadSummary(ctx echo.Context) error
+	Search(ctx echo.Context) error
 }
@@ -10,12 +10,24 @@ type CreateMessageRequest struct {
 	Message    string `json:"message"`
 	SenderID   string `json:"-"`
 	ReceiverID string `json:"user_id"`
+	// Defaults to plain, e2e messages leave message empty and send content instead
+	Mode string `json:"mode" validate:"omitempty,oneof=plain e2e"`
+	// Ciphertext for every device of the receiver, optionally also for the sender's other devices
+	Content []*DeviceContent `json:"content" validate:"max=100,dive"`
+}
+
+type DeviceContent struct {
+	DeviceID   string `json:"device_id" validate:"required"`
+	Ciphertext string `json:"ciphertext" validate:"required,base64,max=65536"`
 }
 
 type CreateMessageResponse struct {
 	ID                   string                  `json:"id"` // Message ID
 	MessageText          string                  `json:"message"`
+	Mode                 string                  `json:"mode"`
 	Sender               *model.User             `json:"sender"`
 	SentAt               time.Time               `json:"sent_at"`
 	ConversationResponse GetConversationResponse `json:"conversation"`
+	// Reply of a slash command only the sender sees, no message was stored
+	Ephemeral string `json:"ephemeral,omitempty"`
 }
@@ -0,0 +1,10 @@
+package payload
+
+type DeleteMessageRequest struct {
+	MessageID string `param:"id"`
+	UserID    string `json:"-"`
+}
+
+type DeleteMessageResponse struct {
+	Message string `json:"message"`
+}
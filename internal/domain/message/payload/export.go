@@ -0,0 +1,25 @@
+package payload
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+	ExportFormatHTML = "html"
+)
+
+type ExportRequest struct {
+	ConversationID string `param:"convo_id"`
+	UserID         string `json:"-"`
+	Format         string `query:"format" validate:"omitempty,oneof=json csv html"`
+	// IANA timezone name used for timestamps, defaults to UTC
+	Timezone string `query:"timezone"`
+}
+
+type ExportedMessage struct {
+	ID         string `json:"id"`
+	SenderID   string `json:"sender_id"`
+	SenderName string `json:"sender_name"`
+	SentAt     string `json:"sent_at"`
+	Message    string `json:"message"`
+	EditedAt   string `json:"edited_at,omitempty"`
+	Deleted    bool   `json:"deleted"`
+}
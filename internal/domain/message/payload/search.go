@@ -0,0 +1,15 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+const DefaultSearchLimit = 20
+
+type SearchMessagesRequest struct {
+	ConversationID string `param:"convo_id"`
+	UserID         string `json:"-"`
+	Query          string `query:"q" validate:"required"`
+	Limit          int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// Matching messages, newest first
+type SearchMessagesResponse []*model.Message
@@ -0,0 +1,11 @@
+package payload
+
+type GetUnreadSummaryRequest struct {
+	UserID string `json:"-"`
+}
+
+// Totals for the app-icon badge, muted and archived conversations are left out
+type UnreadSummaryResponse struct {
+	UnreadMessages      int64 `json:"unread_messages"`
+	UnreadConversations int64 `json:"unread_conversations"`
+}
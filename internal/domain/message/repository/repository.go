@@ -2,41 +2,374 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"gitlab.com/raihanlh/messenger-api/internal/constant"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/message"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/cipher"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// Messages decrypted per round trip when searching without the index
+const searchBatchSize = 500
+
+// Message text is encrypted with Keys before it is written and decrypted after it is read,
+// callers only ever see plaintext. SearchIndex keeps a plaintext copy of every message in
+// message_search_entries, without it search decrypts the conversation as it goes.
 type MessageRepository struct {
-	DB *gorm.DB
+	DB          *gorm.DB
+	Keys        *cipher.Keyring
+	SearchIndex bool
 }
 
-func New(gormDB *gorm.DB) message.Repository {
+func New(gormDB *gorm.DB, keys *cipher.Keyring, searchIndex bool) message.Repository {
 	return &MessageRepository{
-		DB: gormDB,
+		DB:          gormDB,
+		Keys:        keys,
+		SearchIndex: searchIndex,
 	}
 }
 
+// E2E messages are stored as they come, their per device ciphertext is never
+// encrypted again nor indexed
 func (r MessageRepository) Create(ctx context.Context, message *model.Message) (*model.Message, error) {
-	result := r.DB.WithContext(ctx).Model(message).Clauses(clause.OnConflict{DoNothing: true}).Create(&message)
-	return message, result.Error
+	text := message.MessageText
+	if !message.IsE2E() {
+		if err := message.Seal(r.Keys); err != nil {
+			return nil, err
+		}
+	}
+	db := postgres.Conn(ctx, r.DB)
+	result := db.Model(message).Clauses(clause.OnConflict{DoNothing: true}).Create(&message)
+	message.MessageText, message.KeyID, message.DataKey = text, "", ""
+	if result.Error != nil {
+		return message, result.Error
+	}
+
+	if r.SearchIndex && !message.IsE2E() && result.RowsAffected > 0 {
+		if err := db.Create(searchEntry(message)).Error; err != nil {
+			return message, err
+		}
+	}
+	return message, nil
+}
+
+func (r MessageRepository) GetById(ctx context.Context, id string) (*model.Message, error) {
+	var message *model.Message
+	result := r.DB.WithContext(ctx).Table(constant.MessageTable).Where("id = ? AND deleted_at IS NULL", id).Limit(1).Find(&message)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	if err := message.Open(r.Keys); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// Messages that don't exist or were deleted are left out
+func (r MessageRepository) GetByIds(ctx context.Context, ids []string) ([]*model.Message, error) {
+	var messages []*model.Message
+	if len(ids) == 0 {
+		return messages, nil
+	}
+	result := r.DB.WithContext(ctx).Where("id IN ?", ids).Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return messages, r.open(messages)
+}
+
+// The ciphertext of the devices whose user owns them
+func (r MessageRepository) GetDeviceContents(ctx context.Context, messageIds []string, userId string) ([]*model.MessageDeviceContent, error) {
+	var contents []*model.MessageDeviceContent
+	result := r.DB.WithContext(ctx).Table(constant.MessageDeviceContentTable+" mc").Select("mc.*").
+		Joins("JOIN "+constant.DeviceTable+" d ON d.id::text = mc.device_id AND d.deleted_at IS NULL").
+		Where("mc.message_id IN ? AND d.user_id = ? AND mc.deleted_at IS NULL", messageIds, userId).
+		Scan(&contents)
+	return contents, result.Error
 }
 
-func (r MessageRepository) GetAllByConversationId(ctx context.Context, conversationId string) ([]*model.Message, error) {
+func (r MessageRepository) Delete(ctx context.Context, id string) error {
+	db := postgres.Conn(ctx, r.DB)
+	if result := db.Where("id = ?", id).Delete(&model.Message{}); result.Error != nil {
+		return result.Error
+	}
+	if result := db.Unscoped().Where("message_id = ?", id).Delete(&model.MessageDeviceContent{}); result.Error != nil {
+		return result.Error
+	}
+	result := db.Unscoped().Where("message_id = ?", id).Delete(&model.MessageSearchEntry{})
+	return result.Error
+}
+
+// MarkRead marks the messages userId received in a conversation as read
+func (r MessageRepository) MarkRead(ctx context.Context, conversationId string, userId string) (int64, error) {
+	result := postgres.Conn(ctx, r.DB).Table(constant.MessageTable).
+		Where("conversation_id = ? AND sender_id <> ? AND is_read = false AND deleted_at IS NULL", conversationId, userId).
+		Updates(map[string]interface{}{"is_read": true, "updated_at": time.Now()})
+	return result.RowsAffected, result.Error
+}
+
+// Messages sent at or before since are left out, pass nil to get the whole history
+func (r MessageRepository) GetAllByConversationId(ctx context.Context, conversationId string, since *time.Time) ([]*model.Message, error) {
 	var messages []*model.Message
-	result := r.DB.WithContext(ctx).Preload("Sender", func(db *gorm.DB) *gorm.DB {
-		return db.Select("id", "name")
-	}).Where("conversation_id = ?", conversationId).Select("messages.id", "messages.message_text", "messages.sent_at", "messages.sender_id").Find(&messages)
-	return messages, result.Error
+	query := r.DB.WithContext(ctx).Preload("Sender", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id", "name")
+	}).Where("conversation_id = ?", conversationId)
+	if since != nil {
+		query = query.Where("sent_at > ?", *since)
+	}
+	result := query.Select("messages.id", "messages.message_text", "messages.key_id", "messages.data_key", "messages.mode", "messages.sent_at", "messages.sender_id", "messages.is_read").Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return messages, r.open(messages)
 }
 
-func (r MessageRepository) GetUnreadCount(ctx context.Context, userId string, conversationId string) (int64, error) {
-	var unreadCount int64
-	result := r.DB.WithContext(ctx).Table(constant.MessageTable).
-		Where("conversation_id = ? AND sender_id != ?", conversationId, userId).Count(&unreadCount)
+// Walk the whole history oldest first, including deleted messages, one batch at a time
+// so a long conversation never has to fit in memory
+func (r MessageRepository) StreamByConversationId(ctx context.Context, conversationId string, since *time.Time, batchSize int, fn func(messages []*model.Message) error) error {
+	var lastSentAt time.Time
+	var lastId string
+	for {
+		var messages []*model.Message
+		query := r.DB.WithContext(ctx).Unscoped().Preload("Sender", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Select("id", "name")
+		}).Where("conversation_id = ?", conversationId)
+		if since != nil {
+			query = query.Where("sent_at > ?", *since)
+		}
+		if lastId != "" {
+			query = query.Where("(sent_at, id) > (?, ?)", lastSentAt, lastId)
+		}
+		result := query.Order("sent_at ASC, id ASC").Limit(batchSize).Find(&messages)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		if err := r.open(messages); err != nil {
+			return err
+		}
+		if err := fn(messages); err != nil {
+			return err
+		}
+		if len(messages) < batchSize {
+			return nil
+		}
+		last := messages[len(messages)-1]
+		lastSentAt, lastId = last.SentAt, last.ID
+	}
+}
+
+// Walk every message a user sent, oldest first, one batch at a time
+func (r MessageRepository) StreamBySenderId(ctx context.Context, senderId string, batchSize int, fn func(messages []*model.Message) error) error {
+	var lastSentAt time.Time
+	var lastId string
+	for {
+		var messages []*model.Message
+		query := r.DB.WithContext(ctx).Where("sender_id = ?", senderId)
+		if lastId != "" {
+			query = query.Where("(sent_at, id) > (?, ?)", lastSentAt, lastId)
+		}
+		result := query.Order("sent_at ASC, id ASC").Limit(batchSize).Find(&messages)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+		if err := r.open(messages); err != nil {
+			return err
+		}
+		if err := fn(messages); err != nil {
+			return err
+		}
+		if len(messages) < batchSize {
+			return nil
+		}
+		last := messages[len(messages)-1]
+		lastSentAt, lastId = last.SentAt, last.ID
+	}
+}
 
-	return unreadCount, result.Error
+// PurgeBySenderId erases the text of every message the user sent, along with its search entry
+// and e2e ciphertext, and marks them deleted
+func (r MessageRepository) PurgeBySenderId(ctx context.Context, senderId string) (int64, error) {
+	db := postgres.Conn(ctx, r.DB)
+	result := db.Unscoped().Table(constant.MessageTable).Where("sender_id = ?", senderId).
+		Updates(map[string]interface{}{
+			"message_text": "",
+			"key_id":       "",
+			"data_key":     "",
+			"deleted_at":   gorm.Expr("COALESCE(deleted_at, ?)", time.Now()),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if err := db.Unscoped().Where("sender_id = ?", senderId).Delete(&model.MessageSearchEntry{}).Error; err != nil {
+		return 0, err
+	}
+	err := db.Unscoped().Where("message_id IN (?)", db.Unscoped().Model(&model.Message{}).Select("id::text").Where("sender_id = ?", senderId)).
+		Delete(&model.MessageDeviceContent{}).Error
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected, nil
+}
+
+// GetUnreadSummary counts unread messages and the conversations holding them in one query.
+// Pending requests, archived conversations, conversations muted at the given time and
+// messages from before the user cleared the history are not counted. A message stays unread
+// until MarkRead flags it, which happens when the receiver opens the conversation.
+func (r MessageRepository) GetUnreadSummary(ctx context.Context, userId string, at time.Time) (*payload.UnreadSummaryResponse, error) {
+	var summary payload.UnreadSummaryResponse
+	result := r.DB.WithContext(ctx).Table(constant.MessageTable+" m").
+		Select("COUNT(*) AS unread_messages, COUNT(DISTINCT m.conversation_id) AS unread_conversations").
+		Joins("JOIN "+constant.ConversationTable+" c ON c.id::text = m.conversation_id AND c.deleted_at IS NULL").
+		Joins("LEFT JOIN "+constant.UserParticipantTable+" p ON p.conversation_id = c.id::text AND p.user_id = ? AND p.deleted_at IS NULL", userId).
+		Where("c.sender_id = ? OR c.receiver_id = ?", userId, userId).
+		Where("m.sender_id <> ? AND m.is_read = false AND m.deleted_at IS NULL", userId).
+		Where("NOT (c.receiver_id = ? AND c.status IN ?)", userId, []string{model.ConversationStatusRequest, model.ConversationStatusDeclined}).
+		Where("p.id IS NULL OR (p.is_archived = false AND (p.muted_until IS NULL OR p.muted_until <= ?) AND (p.cleared_at IS NULL OR m.sent_at > p.cleared_at))", at).
+		Scan(&summary)
+	return &summary, result.Error
+}
+
+// Search finds the messages of a conversation containing term, newest first. Messages sent
+// at or before since are left out. Without the search index the conversation is decrypted
+// and matched batch by batch.
+func (r MessageRepository) Search(ctx context.Context, conversationId string, term string, since *time.Time, limit int) ([]*model.Message, error) {
+	if !r.SearchIndex {
+		return r.scan(ctx, conversationId, term, since, limit)
+	}
+
+	var messages []*model.Message
+	escapedTerm := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(term)
+	query := r.DB.WithContext(ctx).Preload("Sender", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("id", "name")
+	}).Joins("JOIN "+constant.MessageSearchEntryTable+" s ON s.message_id = messages.id::text AND s.deleted_at IS NULL").
+		Where("s.conversation_id = ? AND s.body ILIKE ?", conversationId, "%"+escapedTerm+"%")
+	if since != nil {
+		query = query.Where("messages.sent_at > ?", *since)
+	}
+	result := query.Order("messages.sent_at DESC").Limit(limit).Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return messages, r.open(messages)
+}
+
+func (r MessageRepository) scan(ctx context.Context, conversationId string, term string, since *time.Time, limit int) ([]*model.Message, error) {
+	var matches []*model.Message
+	term = strings.ToLower(term)
+	err := r.StreamByConversationId(ctx, conversationId, since, searchBatchSize, func(messages []*model.Message) error {
+		for _, m := range messages {
+			if !m.IsDeleted() && strings.Contains(strings.ToLower(m.MessageText), term) {
+				matches = append(matches, m)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Streamed oldest first, keep the newest matches
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// ReencryptBatch moves up to batchSize messages with ids after afterId from older keys, or from
+// plaintext, to the active key. E2E messages are left alone. It returns the last id it looked at, empty once every message is
+// done. With the search index on, messages missing from it are indexed on the way.
+func (r MessageRepository) ReencryptBatch(ctx context.Context, afterId string, batchSize int) (string, int, error) {
+	if !r.Keys.Enabled() {
+		return "", 0, errors.New("no active message key is configured")
+	}
+
+	var messages []*model.Message
+	query := r.DB.WithContext(ctx).Unscoped().Where("key_id <> ? AND mode <> ?", r.Keys.ActiveKeyID(), model.MessageModeE2E)
+	if afterId != "" {
+		query = query.Where("id > ?", afterId)
+	}
+	if result := query.Order("id ASC").Limit(batchSize).Find(&messages); result.Error != nil {
+		return "", 0, result.Error
+	}
+	if len(messages) == 0 {
+		return "", 0, nil
+	}
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, m := range messages {
+			envelope, err := r.Keys.Rewrap(m.Envelope())
+			if err != nil {
+				return fmt.Errorf("message %s: %w", m.ID, err)
+			}
+			result := tx.Unscoped().Table(constant.MessageTable).Where("id = ?", m.ID).
+				Updates(map[string]interface{}{
+					"message_text": envelope.Ciphertext,
+					"key_id":       envelope.KeyID,
+					"data_key":     envelope.DataKey,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if !r.SearchIndex || m.IsDeleted() {
+				continue
+			}
+			if err := m.Open(r.Keys); err != nil {
+				return fmt.Errorf("message %s: %w", m.ID, err)
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(searchEntry(m)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return messages[len(messages)-1].ID, len(messages), nil
+}
+
+// DropSearchIndex deletes every search entry, for when the search index policy is turned off
+func (r MessageRepository) DropSearchIndex(ctx context.Context) (int64, error) {
+	result := r.DB.WithContext(ctx).Exec("DELETE FROM " + constant.MessageSearchEntryTable)
+	return result.RowsAffected, result.Error
+}
+
+func (r MessageRepository) open(messages []*model.Message) error {
+	for _, m := range messages {
+		if err := m.Open(r.Keys); err != nil {
+			return fmt.Errorf("message %s: %w", m.ID, err)
+		}
+	}
+	return nil
+}
+
+func searchEntry(m *model.Message) *model.MessageSearchEntry {
+	return &model.MessageSearchEntry{
+		MessageID:      m.ID,
+		ConversationID: m.ConversationID,
+		SenderID:       m.SenderID,
+		Body:           m.MessageText,
+		SentAt:         m.SentAt,
+	}
 }
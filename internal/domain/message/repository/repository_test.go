@@ -0,0 +1,182 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/message/repository"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/cipher"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type AnyTime struct{}
+
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_MessageRepository_GetUnreadSummary(t *testing.T) {
+	db, mock := Setup()
+
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	query := `SELECT COUNT(*) AS unread_messages, COUNT(DISTINCT m.conversation_id) AS unread_conversations FROM messages m ` +
+		`JOIN conversations c ON c.id::text = m.conversation_id AND c.deleted_at IS NULL ` +
+		`LEFT JOIN user_participants p ON p.conversation_id = c.id::text AND p.user_id = $1 AND p.deleted_at IS NULL ` +
+		`WHERE (c.sender_id = $2 OR c.receiver_id = $3) AND (m.sender_id <> $4 AND m.is_read = false AND m.deleted_at IS NULL) ` +
+		`AND (NOT (c.receiver_id = $5 AND c.status IN ($6,$7))) ` +
+		`AND (p.id IS NULL OR (p.is_archived = false AND (p.muted_until IS NULL OR p.muted_until <= $8) AND (p.cleared_at IS NULL OR m.sent_at > p.cleared_at)))`
+
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs(userId, userId, userId, userId, userId, model.ConversationStatusRequest, model.ConversationStatusDeclined, AnyTime{}).
+		WillReturnRows(sqlmock.NewRows([]string{"unread_messages", "unread_conversations"}).AddRow(7, 3))
+
+	r := &repo.MessageRepository{
+		DB: db,
+	}
+
+	summary, err := r.GetUnreadSummary(context.TODO(), userId, time.Now())
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(7), summary.UnreadMessages)
+		assert.Equal(t, int64(3), summary.UnreadConversations)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Matches a value only if it isn't the given plaintext, e.g. a column that must hold ciphertext
+type NotPlaintext string
+
+func (p NotPlaintext) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	return ok && s != "" && s != string(p)
+}
+
+func testKeyring() *cipher.Keyring {
+	keys, _ := cipher.NewKeyring("k1", map[string][]byte{"k1": bytes.Repeat([]byte{7}, 32)})
+	return keys
+}
+
+func Test_MessageRepository_Create_Encrypted(t *testing.T) {
+	db, mock := Setup()
+	sentAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "messages" .*"message_text",.*"key_id","data_key".* ON CONFLICT DO NOTHING RETURNING "id"`).
+		WithArgs(AnyTime{}, AnyTime{}, nil, sentAt, "c1", "u1", NotPlaintext("hello there"), false, nil, model.MessageModePlain, "k1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("m1"))
+	mock.ExpectCommit()
+	// The search index keeps the plaintext
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "message_search_entries"`).
+		WithArgs(AnyTime{}, AnyTime{}, nil, "m1", "c1", "u1", "hello there", sentAt, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("s1"))
+	mock.ExpectCommit()
+
+	r := &repo.MessageRepository{DB: db, Keys: testKeyring(), SearchIndex: true}
+	msg, err := r.Create(context.TODO(), &model.Message{SentAt: sentAt, ConversationID: "c1", SenderID: "u1", MessageText: "hello there"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "m1", msg.ID)
+		assert.Equal(t, "hello there", msg.MessageText, "callers keep the plaintext")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_MessageRepository_GetById_Decrypts(t *testing.T) {
+	db, mock := Setup()
+	keys := testKeyring()
+	sealed := &model.Message{MessageText: "hello there"}
+	_ = sealed.Seal(keys)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "messages" WHERE (id = $1 AND deleted_at IS NULL) AND "messages"."deleted_at" IS NULL LIMIT 1`)).
+		WithArgs("m1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "message_text", "key_id", "data_key"}).
+			AddRow("m1", sealed.MessageText, sealed.KeyID, sealed.DataKey))
+
+	r := &repo.MessageRepository{DB: db, Keys: keys}
+	msg, err := r.GetById(context.TODO(), "m1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "hello there", msg.MessageText)
+		assert.Empty(t, msg.KeyID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_MessageRepository_Search_WithoutIndex(t *testing.T) {
+	db, mock := Setup()
+	keys := testKeyring()
+	rows := sqlmock.NewRows([]string{"id", "sent_at", "sender_id", "message_text", "key_id", "data_key"})
+	start := time.Now()
+	for i, text := range []string{"Lunch tomorrow?", "sure", "where for lunch"} {
+		m := &model.Message{MessageText: text}
+		_ = m.Seal(keys)
+		rows.AddRow(fmt.Sprintf("m%d", i), start.Add(time.Duration(i)*time.Minute), "u1", m.MessageText, m.KeyID, m.DataKey)
+	}
+
+	// Without the index the conversation is decrypted batch by batch and matched in memory
+	mock.ExpectQuery(`SELECT \* FROM "messages" WHERE conversation_id = \$1 ORDER BY sent_at ASC, id ASC LIMIT 500`).
+		WithArgs("c1").
+		WillReturnRows(rows)
+	mock.ExpectQuery(`SELECT "id","name" FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("u1", "Alice"))
+
+	r := &repo.MessageRepository{DB: db, Keys: keys}
+	msgs, err := r.Search(context.TODO(), "c1", "LUNCH", nil, 20)
+	if assert.NoError(t, err) && assert.Len(t, msgs, 2) {
+		assert.Equal(t, "where for lunch", msgs[0].MessageText, "newest first")
+		assert.Equal(t, "Lunch tomorrow?", msgs[1].MessageText)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_MessageRepository_GetAllByConversationId_Since(t *testing.T) {
+	db, mock := Setup()
+	clearedAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// Messages sent before the participant cleared the conversation are left out
+	mock.ExpectQuery(`SELECT messages\.id,.* FROM "messages" WHERE conversation_id = \$1 AND sent_at > \$2`).
+		WithArgs("c1", clearedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "message_text", "sender_id"}).AddRow("m2", "after", "u2"))
+	mock.ExpectQuery(`SELECT "id","name" FROM "users" WHERE "users"\."id" = \$1`).
+		WithArgs("u2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("u2", "Bob"))
+	// Without a cutoff the whole history is read
+	mock.ExpectQuery(`SELECT messages\.id,.* FROM "messages" WHERE conversation_id = \$1 AND "messages"\."deleted_at" IS NULL$`).
+		WithArgs("c1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "message_text"}))
+
+	r := &repo.MessageRepository{
+		DB: db,
+	}
+
+	msgs, err := r.GetAllByConversationId(context.TODO(), "c1", &clearedAt)
+	if assert.NoError(t, err) && assert.Len(t, msgs, 1) {
+		assert.Equal(t, "after", msgs[0].MessageText)
+	}
+	_, err = r.GetAllByConversationId(context.TODO(), "c1", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
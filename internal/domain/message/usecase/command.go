@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
+	"go.uber.org/zap"
+)
+
+// runCommand runs a built-in command or one the conversation added. Unknown commands and
+// callbacks that fail get an ephemeral reply, so a typo isn't sent as a message.
+func (u MessageUsecase) runCommand(ctx context.Context, req *payload.CreateMessageRequest, sender *model.User, name string, args string) (*slash.Reply, error) {
+	log := logger.GetLogger(ctx)
+
+	inv := &slash.Invocation{
+		Command:  name,
+		Text:     args,
+		UserID:   sender.ID,
+		UserName: sender.Name,
+	}
+	convo, err := u.repositories.Conversation.GetBySenderReceiverIds(ctx, req.SenderID, req.ReceiverID)
+	if err != nil {
+		log.Error("Failed to get conversation: ", zap.Error(err))
+		return nil, err
+	}
+	// Before the first message there's no conversation to add commands to
+	var custom []*model.SlashCommand
+	if convo != nil {
+		inv.ConversationID = convo.ID
+		custom, err = u.repositories.Command.GetAllByConversationId(ctx, convo.ID)
+		if err != nil {
+			log.Error("Failed to get commands: ", zap.Error(err))
+			return nil, err
+		}
+	}
+	inv.Available = u.gateways.Commands.All()
+	for _, c := range custom {
+		inv.Available = append(inv.Available, &slash.Command{Name: c.Name, Description: c.Description, Usage: c.Usage})
+	}
+
+	if c, ok := u.gateways.Commands.Get(name); ok {
+		return c.Handler.Run(ctx, inv)
+	}
+	for _, c := range custom {
+		if c.Name != name {
+			continue
+		}
+		reply, err := u.gateways.SlashCommands.Call(ctx, c.URL, c.Secret, inv)
+		if err != nil {
+			log.Error("Failed to run command: ", zap.String("command", name), zap.Error(err))
+			return slash.Ephemeral(fmt.Sprintf("/%s didn't respond", name)), nil
+		}
+		return reply, nil
+	}
+	return slash.Ephemeral(fmt.Sprintf("/%s isn't a command here, send //%s to post it as text", name, name)), nil
+}
@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/command/builtin"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_block "gitlab.com/raihanlh/messenger-api/testing/mocks/block"
+	mock_command "gitlab.com/raihanlh/messenger-api/testing/mocks/command"
+	mock_contact "gitlab.com/raihanlh/messenger-api/testing/mocks/contact"
+	mock_conversation "gitlab.com/raihanlh/messenger-api/testing/mocks/conversation"
+	mock_draft "gitlab.com/raihanlh/messenger-api/testing/mocks/draft"
+	mock_inbox "gitlab.com/raihanlh/messenger-api/testing/mocks/inbox"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	mock_outbox "gitlab.com/raihanlh/messenger-api/testing/mocks/outbox"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+)
+
+type fakeCaller struct {
+	reply *slash.Reply
+	err   error
+	inv   *slash.Invocation
+}
+
+func (c *fakeCaller) Call(ctx context.Context, url string, secret string, inv *slash.Invocation) (*slash.Reply, error) {
+	c.inv = inv
+	return c.reply, c.err
+}
+
+func Test_MessageUsecase_Create_Command(t *testing.T) {
+	senderId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	receiverId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	convo := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: senderId, ReceiverID: receiverId, Status: model.ConversationStatusAccepted}
+	deploy := &model.SlashCommand{Model: model.Model{ID: "cmd1"}, ConversationID: "c1", Name: "deploy", URL: "https://ci.example.id/slash", Secret: "s3cret"}
+
+	tests := []struct {
+		name          string
+		message       string
+		caller        *fakeCaller
+		wantEphemeral string
+		// Text of the stored message, empty when nothing is stored
+		wantPosted string
+	}{
+		{name: "Built-in posts", message: "/shrug fine", wantPosted: `fine ¯\_(ツ)_/¯`},
+		{name: "Built-in replies to the sender", message: "/me", wantEphemeral: "Usage: /me <action>"},
+		{name: "Unknown command", message: "/nope", wantEphemeral: "/nope isn't a command here, send //nope to post it as text"},
+		{name: "Escaped", message: "//nope", wantPosted: "/nope"},
+		{name: "Custom command", message: "/deploy api", caller: &fakeCaller{reply: slash.Ephemeral("deploying api")}, wantEphemeral: "deploying api"},
+		{name: "Custom command posts", message: "/deploy api", caller: &fakeCaller{reply: slash.Post("deployed api")}, wantPosted: "deployed api"},
+		{name: "Custom command fails", message: "/deploy api", caller: &fakeCaller{err: errors.New("timeout")}, wantEphemeral: "/deploy didn't respond"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			blockRepoMock.EXPECT().IsBlocked(ctx, receiverId, senderId).Return(false, nil)
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			userRepoMock.EXPECT().GetById(ctx, senderId).Return(&model.User{Model: model.Model{ID: senderId}, Name: "Alice"}, nil)
+			userRepoMock.EXPECT().GetById(ctx, receiverId).Return(&model.User{Model: model.Model{ID: receiverId}}, nil)
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetBySenderReceiverIds(ctx, senderId, receiverId).Return(convo, nil).AnyTimes()
+			commandRepoMock := mock_command.NewMockRepository(ctrl)
+			commandRepoMock.EXPECT().GetAllByConversationId(ctx, "c1").Return([]*model.SlashCommand{deploy}, nil).AnyTimes()
+			contactRepoMock := mock_contact.NewMockRepository(ctrl)
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+			outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+			draftRepoMock := mock_draft.NewMockRepository(ctrl)
+			if tt.wantPosted != "" {
+				contactRepoMock.EXPECT().Get(ctx, senderId, receiverId).Return(nil, nil)
+				msgRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msg *model.Message) (*model.Message, error) {
+					assert.Equal(t, tt.wantPosted, msg.MessageText)
+					assert.Equal(t, senderId, msg.SenderID)
+					msg.ID = "m1"
+					return msg, nil
+				})
+				inboxRepoMock.EXPECT().RecordMessage(ctx, gomock.Any(), receiverId).Return(nil)
+				outboxRepoMock.EXPECT().Append(ctx, model.EventMessageCreated, gomock.Any()).Return(nil)
+				draftRepoMock.EXPECT().Delete(ctx, senderId, convo.ID).Return(nil)
+				convRepoMock.EXPECT().GetParticipant(ctx, receiverId, convo.ID).Return(nil, nil)
+			}
+			gateways := &dependency.Gateways{Commands: builtin.Registry()}
+			if tt.caller != nil {
+				gateways.SlashCommands = tt.caller
+			}
+
+			messageUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				User:         userRepoMock,
+				Message:      msgRepoMock,
+				Conversation: convRepoMock,
+				Draft:        draftRepoMock,
+				Block:        blockRepoMock,
+				Contact:      contactRepoMock,
+				Inbox:        inboxRepoMock,
+				Outbox:       outboxRepoMock,
+				Command:      commandRepoMock,
+			}, gateways)
+			res, err := messageUsecase.Create(ctx, &payload.CreateMessageRequest{
+				SenderID:   senderId,
+				ReceiverID: receiverId,
+				Message:    tt.message,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.wantEphemeral, res.Ephemeral)
+			if tt.wantPosted != "" {
+				assert.Equal(t, "m1", res.ID)
+			} else {
+				assert.Empty(t, res.ID, "ephemeral replies aren't stored")
+			}
+			if tt.caller != nil {
+				assert.Equal(t, &slash.Invocation{
+					Command:        "deploy",
+					Text:           "api",
+					UserID:         senderId,
+					UserName:       "Alice",
+					ConversationID: "c1",
+					Available:      tt.caller.inv.Available,
+				}, tt.caller.inv)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Number of messages read from the database at a time while exporting
+const ExportBatchSize = 500
+
+func (u MessageUsecase) Export(ctx context.Context, req *payload.ExportRequest, w io.Writer) error {
+	log := logger.GetLogger(ctx)
+
+	convo, err := u.repositories.Conversation.GetById(ctx, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get conversation: ", zap.Error(err))
+		return err
+	}
+	if convo.SenderID != req.UserID && convo.ReceiverID != req.UserID {
+		return errors.New("unauthorized")
+	}
+
+	loc := time.UTC
+	if req.Timezone != "" {
+		loc, err = time.LoadLocation(req.Timezone)
+		if err != nil {
+			return http_error.BadRequest(fmt.Errorf("unknown timezone %q", req.Timezone))
+		}
+	}
+
+	participant, err := u.repositories.Conversation.GetParticipant(ctx, req.UserID, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get conversation settings: ", zap.Error(err))
+		return err
+	}
+	var since *time.Time
+	if participant != nil {
+		since = participant.ClearedAt
+	}
+
+	tw := newTranscriptWriter(req.Format, w)
+	if err := tw.Begin(convo.ID, loc, time.Now().In(loc)); err != nil {
+		return err
+	}
+	err = u.repositories.Message.StreamByConversationId(ctx, convo.ID, since, ExportBatchSize, func(messages []*model.Message) error {
+		for _, m := range messages {
+			if err := tw.Write(toExportedMessage(m, loc)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to export conversation: ", zap.Error(err))
+		return err
+	}
+	return tw.End()
+}
+
+func toExportedMessage(m *model.Message, loc *time.Location) *payload.ExportedMessage {
+	exported := &payload.ExportedMessage{
+		ID:         m.ID,
+		SenderID:   m.SenderID,
+		SenderName: "Unknown",
+		SentAt:     m.SentAt.In(loc).Format(time.RFC3339),
+		Message:    m.MessageText,
+		Deleted:    m.IsDeleted(),
+	}
+	if m.Sender != nil {
+		exported.SenderName = m.Sender.Name
+	}
+	if m.EditedAt != nil {
+		exported.EditedAt = m.EditedAt.In(loc).Format(time.RFC3339)
+	}
+	if exported.Deleted {
+		exported.Message = ""
+	}
+	return exported
+}
+
+type transcriptWriter interface {
+	Begin(conversationId string, loc *time.Location, exportedAt time.Time) error
+	Write(m *payload.ExportedMessage) error
+	End() error
+}
+
+func newTranscriptWriter(format string, w io.Writer) transcriptWriter {
+	switch format {
+	case payload.ExportFormatCSV:
+		return &csvTranscriptWriter{w: csv.NewWriter(w)}
+	case payload.ExportFormatHTML:
+		return &htmlTranscriptWriter{w: w}
+	default:
+		return &jsonTranscriptWriter{w: w}
+	}
+}
+
+// Writes a single JSON document, messages are appended to the array as they are read
+type jsonTranscriptWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (t *jsonTranscriptWriter) Begin(conversationId string, loc *time.Location, exportedAt time.Time) error {
+	header, err := json.Marshal(map[string]string{
+		"conversation_id": conversationId,
+		"timezone":        loc.String(),
+		"exported_at":     exportedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	// Reopen the object to append the messages array
+	_, err = fmt.Fprintf(t.w, `%s,"messages":[`, header[:len(header)-1])
+	return err
+}
+
+func (t *jsonTranscriptWriter) Write(m *payload.ExportedMessage) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if t.count > 0 {
+		if _, err := io.WriteString(t.w, ","); err != nil {
+			return err
+		}
+	}
+	t.count++
+	_, err = t.w.Write(data)
+	return err
+}
+
+func (t *jsonTranscriptWriter) End() error {
+	_, err := io.WriteString(t.w, "]}\n")
+	return err
+}
+
+type csvTranscriptWriter struct {
+	w *csv.Writer
+}
+
+func (t *csvTranscriptWriter) Begin(conversationId string, loc *time.Location, exportedAt time.Time) error {
+	return t.w.Write([]string{"id", "sender_id", "sender_name", "sent_at", "message", "edited_at", "deleted"})
+}
+
+func (t *csvTranscriptWriter) Write(m *payload.ExportedMessage) error {
+	err := t.w.Write([]string{m.ID, m.SenderID, m.SenderName, m.SentAt, m.Message, m.EditedAt, strconv.FormatBool(m.Deleted)})
+	if err != nil {
+		return err
+	}
+	// Flush every row so the transcript streams instead of piling up in the csv buffer
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func (t *csvTranscriptWriter) End() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+var (
+	htmlTranscriptHeader = template.Must(template.New("header").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Conversation {{.ConversationID}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.message { margin-bottom: 1em; }
+.meta { color: #666; font-size: 0.85em; }
+.deleted { color: #999; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Conversation {{.ConversationID}}</h1>
+<p class="meta">Exported at {{.ExportedAt}} ({{.Timezone}})</p>
+`))
+	htmlTranscriptMessage = template.Must(template.New("message").Parse(`<div class="message">
+<div class="meta"><strong>{{.SenderName}}</strong> &middot; {{.SentAt}}{{if .EditedAt}} &middot; edited {{.EditedAt}}{{end}}</div>
+{{if .Deleted}}<div class="deleted">This message was deleted</div>{{else}}<div>{{.Message}}</div>{{end}}
+</div>
+`))
+)
+
+type htmlTranscriptWriter struct {
+	w io.Writer
+}
+
+func (t *htmlTranscriptWriter) Begin(conversationId string, loc *time.Location, exportedAt time.Time) error {
+	return htmlTranscriptHeader.Execute(t.w, map[string]string{
+		"ConversationID": conversationId,
+		"ExportedAt":     exportedAt.Format(time.RFC3339),
+		"Timezone":       loc.String(),
+	})
+}
+
+func (t *htmlTranscriptWriter) Write(m *payload.ExportedMessage) error {
+	return htmlTranscriptMessage.Execute(t.w, m)
+}
+
+func (t *htmlTranscriptWriter) End() error {
+	_, err := io.WriteString(t.w, "</body>\n</html>\n")
+	return err
+}
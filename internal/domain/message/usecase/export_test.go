@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	mock_conversation "gitlab.com/raihanlh/messenger-api/testing/mocks/conversation"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	"gorm.io/gorm"
+)
+
+func Test_MessageUsecase_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	convo := &model.Conversation{
+		Model:      model.Model{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2"},
+		SenderID:   "34251esd-d76e-401a-a3ba-7a03352812c2",
+		ReceiverID: "47dsga9t-d76e-401a-a3ba-7a03352812c2",
+	}
+	sender := &model.User{Model: model.Model{ID: convo.SenderID}, Name: "Alice"}
+	sentAt := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	edited := sentAt.Add(time.Minute)
+	batches := [][]*model.Message{
+		{
+			{Model: model.Model{ID: "m1"}, SentAt: sentAt, SenderID: sender.ID, Sender: sender, MessageText: "hello, <b>world</b>"},
+			{Model: model.Model{ID: "m2"}, SentAt: sentAt, SenderID: sender.ID, Sender: sender, MessageText: "fixed typo", EditedAt: &edited},
+		},
+		{
+			{Model: model.Model{ID: "m3", DeletedAt: gorm.DeletedAt{Time: sentAt, Valid: true}}, SentAt: sentAt, SenderID: sender.ID, Sender: sender, MessageText: "secret"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		req    *payload.ExportRequest
+		assert func(t *testing.T, out string)
+	}{
+		{
+			name: "Export JSON",
+			req:  &payload.ExportRequest{ConversationID: convo.ID, UserID: convo.ReceiverID, Format: payload.ExportFormatJSON, Timezone: "Asia/Jakarta"},
+			assert: func(t *testing.T, out string) {
+				var doc struct {
+					ConversationID string                     `json:"conversation_id"`
+					Timezone       string                     `json:"timezone"`
+					Messages       []*payload.ExportedMessage `json:"messages"`
+				}
+				if assert.NoError(t, json.Unmarshal([]byte(out), &doc)) {
+					assert.Equal(t, convo.ID, doc.ConversationID)
+					assert.Equal(t, "Asia/Jakarta", doc.Timezone)
+					assert.Len(t, doc.Messages, 3)
+					assert.Equal(t, "2023-03-01T17:00:00+07:00", doc.Messages[0].SentAt)
+					assert.Equal(t, "Alice", doc.Messages[0].SenderName)
+					assert.NotEmpty(t, doc.Messages[1].EditedAt)
+					assert.True(t, doc.Messages[2].Deleted)
+					assert.Empty(t, doc.Messages[2].Message)
+				}
+			},
+		},
+		{
+			name: "Export CSV",
+			req:  &payload.ExportRequest{ConversationID: convo.ID, UserID: convo.SenderID, Format: payload.ExportFormatCSV},
+			assert: func(t *testing.T, out string) {
+				rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+				if assert.NoError(t, err) {
+					assert.Len(t, rows, 4)
+					assert.Equal(t, "hello, <b>world</b>", rows[1][4])
+					assert.Equal(t, "true", rows[3][6])
+				}
+			},
+		},
+		{
+			name: "Export HTML",
+			req:  &payload.ExportRequest{ConversationID: convo.ID, UserID: convo.SenderID, Format: payload.ExportFormatHTML},
+			assert: func(t *testing.T, out string) {
+				assert.Contains(t, out, "hello, &lt;b&gt;world&lt;/b&gt;")
+				assert.Contains(t, out, "This message was deleted")
+				assert.NotContains(t, out, "secret")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, convo.ID).Return(convo, nil)
+			convRepoMock.EXPECT().GetParticipant(ctx, tt.req.UserID, convo.ID).Return(nil, nil)
+
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			msgRepoMock.EXPECT().StreamByConversationId(ctx, convo.ID, nil, usecase.ExportBatchSize, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ *time.Time, _ int, fn func([]*model.Message) error) error {
+					for _, batch := range batches {
+						if err := fn(batch); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+
+			messageUsecase := usecase.New(&dependency.Repositories{
+				Conversation: convRepoMock,
+				Message:      msgRepoMock,
+			}, nil)
+
+			var out bytes.Buffer
+			err := messageUsecase.Export(ctx, tt.req, &out)
+			if assert.NoError(t, err) {
+				tt.assert(t, out.String())
+			}
+		})
+	}
+}
+
+func Test_MessageUsecase_Export_NotParticipant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetById(ctx, "c1").Return(&model.Conversation{Model: model.Model{ID: "c1"}, SenderID: "a", ReceiverID: "b"}, nil)
+
+	messageUsecase := usecase.New(&dependency.Repositories{
+		Conversation: convRepoMock,
+	}, nil)
+
+	var out bytes.Buffer
+	err := messageUsecase.Export(ctx, &payload.ExportRequest{ConversationID: "c1", UserID: "c"}, &out)
+	assert.EqualError(t, err, "unauthorized")
+	assert.Zero(t, out.Len())
+}
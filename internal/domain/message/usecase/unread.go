@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (u MessageUsecase) GetUnreadSummary(ctx context.Context, req *payload.GetUnreadSummaryRequest) (*payload.UnreadSummaryResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	summary, err := u.repositories.Message.GetUnreadSummary(ctx, req.UserID, time.Now())
+	if err != nil {
+		log.Error("Failed to get unread summary: ", zap.Error(err))
+		return nil, err
+	}
+	return summary, nil
+}
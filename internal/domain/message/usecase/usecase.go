@@ -3,42 +3,43 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
 	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/message"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
 	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type MessageUsecase struct {
 	repositories *dependency.Repositories
+	gateways     *dependency.Gateways
 }
 
-func New(r *dependency.Repositories) message.Usecase {
+func New(r *dependency.Repositories, g *dependency.Gateways) message.Usecase {
 	return &MessageUsecase{
 		repositories: r,
+		gateways:     g,
 	}
 }
 
 func (u MessageUsecase) Create(ctx context.Context, req *payload.CreateMessageRequest) (*payload.CreateMessageResponse, error) {
 	log := logger.GetLogger(ctx)
-	convo, err := u.repositories.Conversation.GetBySenderReceiverIds(ctx, req.SenderID, req.ReceiverID)
+
+	blocked, err := u.repositories.Block.IsBlocked(ctx, req.ReceiverID, req.SenderID)
 	if err != nil {
-		log.Error("Failed to get conversation: ", zap.Error(err))
+		log.Error("Failed to check block: ", zap.Error(err))
 		return nil, err
 	}
-	if convo == nil {
-		convo, err = u.repositories.Conversation.Create(ctx, &model.Conversation{
-			SenderID:   req.SenderID,
-			ReceiverID: req.ReceiverID,
-		})
-		if err != nil {
-			log.Error("Failed to create conversation: ", zap.Error(err))
-			return nil, err
-		}
+	if blocked {
+		return nil, http_error.Blocked("You can't send messages to this user")
 	}
 
 	sender, err := u.repositories.User.GetById(ctx, req.SenderID)
@@ -48,23 +49,115 @@ func (u MessageUsecase) Create(ctx context.Context, req *payload.CreateMessageRe
 	}
 	receiver, err := u.repositories.User.GetById(ctx, req.ReceiverID)
 	if err != nil {
+		// Deleted accounts stay in conversations but can't receive messages
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, http_error.RecordNotFound("receiver")
+		}
 		log.Error("Failed to create message: ", zap.Error(err))
 		return nil, err
 	}
 
-	msg, err := u.repositories.Message.Create(ctx, &model.Message{
-		SentAt:         time.Now(),
-		ConversationID: convo.ID,
-		SenderID:       req.SenderID,
-		MessageText:    req.Message,
+	// The server can't read e2e messages, so they're never commands
+	if req.Mode != model.MessageModeE2E {
+		if name, args, ok := slash.Parse(req.Message); ok {
+			reply, err := u.runCommand(ctx, req, sender, name, args)
+			if err != nil {
+				return nil, err
+			}
+			if reply == nil || reply.IsEphemeral() || reply.Text == "" {
+				res := &payload.CreateMessageResponse{
+					Sender: &model.User{
+						Model: model.Model{ID: sender.ID},
+						Name:  sender.Name,
+					},
+				}
+				if reply != nil {
+					res.Ephemeral = reply.Text
+				}
+				return res, nil
+			}
+			req.Message = reply.Text
+		} else {
+			req.Message = slash.Unescape(req.Message)
+		}
+	}
+
+	receiverName := receiver.Name
+	contact, err := u.repositories.Contact.Get(ctx, req.SenderID, req.ReceiverID)
+	if err != nil {
+		log.Error("Failed to get contact: ", zap.Error(err))
+		return nil, err
+	}
+	if contact != nil && contact.Nickname != "" {
+		receiverName = contact.Nickname
+	}
+
+	mode := model.MessageModePlain
+	var contents []*model.MessageDeviceContent
+	if req.Mode == model.MessageModeE2E {
+		mode = model.MessageModeE2E
+		contents, err = u.deviceContents(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The conversation, the message, both inboxes and the events are stored together
+	var convo *model.Conversation
+	var msg *model.Message
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		convo, err = u.getOrCreateConversation(ctx, req)
+		if err != nil {
+			return err
+		}
+		msg, err = u.repositories.Message.Create(ctx, &model.Message{
+			SentAt:         time.Now(),
+			ConversationID: convo.ID,
+			SenderID:       req.SenderID,
+			MessageText:    req.Message,
+			Mode:           mode,
+			Contents:       contents,
+		})
+		if err != nil {
+			log.Error("Failed to create message: ", zap.Error(err))
+			return err
+		}
+		if err := u.repositories.Inbox.RecordMessage(ctx, msg, req.ReceiverID); err != nil {
+			log.Error("Failed to update inbox: ", zap.Error(err))
+			return err
+		}
+		// Push notifications and webhooks are queued by the subscribers of the event
+		err = u.repositories.Outbox.Append(ctx, model.EventMessageCreated, &model.MessageCreatedEvent{
+			MessageID:      msg.ID,
+			ConversationID: convo.ID,
+			SenderID:       req.SenderID,
+			ReceiverID:     req.ReceiverID,
+			RecipientIDs:   []string{req.ReceiverID},
+			ParticipantIDs: []string{req.SenderID, req.ReceiverID},
+			Mode:           msg.Mode,
+			SentAt:         msg.SentAt,
+		})
+		if err != nil {
+			log.Error("Failed to append event: ", zap.Error(err))
+			return err
+		}
+		return nil
 	})
 	if err != nil {
-		log.Error("Failed to create message: ", zap.Error(err))
 		return nil, err
 	}
+
+	// The message is already stored, a stale draft shouldn't fail the request
+	if err := u.repositories.Draft.Delete(ctx, req.SenderID, convo.ID); err != nil {
+		log.Error("Failed to clear draft: ", zap.Error(err))
+	}
+	u.unarchiveForReceiver(ctx, req.ReceiverID, convo.ID, msg.SentAt)
+
 	return &payload.CreateMessageResponse{
 		ID:          msg.ID,
 		MessageText: msg.MessageText,
+		Mode:        msg.Mode,
 		Sender: &model.User{
 			Model: model.Model{ID: sender.ID},
 			Name:  sender.Name,
@@ -74,13 +167,123 @@ func (u MessageUsecase) Create(ctx context.Context, req *payload.CreateMessageRe
 			ConversationID: convo.ID,
 			WithUser: &model.User{
 				Model:    model.Model{ID: receiver.ID},
-				Name:     receiver.Name,
+				Name:     receiverName,
 				PhotoURL: receiver.PhotoURL,
 			},
 		},
 	}, nil
 }
 
+// An e2e message has to be encrypted for every device of the receiver and may carry copies
+// for the sender's other devices. Any other device means the sender's device list is stale,
+// the server never looks at the ciphertext itself.
+func (u MessageUsecase) deviceContents(ctx context.Context, req *payload.CreateMessageRequest) ([]*model.MessageDeviceContent, error) {
+	log := logger.GetLogger(ctx)
+
+	receiverDevices, err := u.repositories.Device.GetAllByUserId(ctx, req.ReceiverID)
+	if err != nil {
+		log.Error("Failed to get receiver devices: ", zap.Error(err))
+		return nil, err
+	}
+	senderDevices, err := u.repositories.Device.GetAllByUserId(ctx, req.SenderID)
+	if err != nil {
+		log.Error("Failed to get sender devices: ", zap.Error(err))
+		return nil, err
+	}
+	if len(receiverDevices) == 0 {
+		return nil, http_error.StaleDevices("The receiver has no devices registered for end-to-end encryption")
+	}
+
+	known := make(map[string]bool, len(receiverDevices)+len(senderDevices))
+	for _, d := range senderDevices {
+		known[d.ID] = true
+	}
+	for _, d := range receiverDevices {
+		known[d.ID] = true
+	}
+
+	covered := make(map[string]bool, len(req.Content))
+	contents := make([]*model.MessageDeviceContent, 0, len(req.Content))
+	var unknown []string
+	for _, c := range req.Content {
+		if !known[c.DeviceID] {
+			unknown = append(unknown, c.DeviceID)
+			continue
+		}
+		if covered[c.DeviceID] {
+			return nil, http_error.BadRequest(fmt.Errorf("device %s has more than one ciphertext", c.DeviceID))
+		}
+		covered[c.DeviceID] = true
+		contents = append(contents, &model.MessageDeviceContent{DeviceID: c.DeviceID, Ciphertext: c.Ciphertext})
+	}
+	var missing []string
+	for _, d := range receiverDevices {
+		if !covered[d.ID] {
+			missing = append(missing, d.ID)
+		}
+	}
+	if len(missing) > 0 || len(unknown) > 0 {
+		return nil, http_error.StaleDevices(fmt.Sprintf("Missing devices: [%s], unknown devices: [%s]", strings.Join(missing, ", "), strings.Join(unknown, ", ")))
+	}
+	return contents, nil
+}
+
+// Find the conversation between sender and receiver, starting one when there is none.
+// Replying to a message request accepts it.
+func (u MessageUsecase) getOrCreateConversation(ctx context.Context, req *payload.CreateMessageRequest) (*model.Conversation, error) {
+	log := logger.GetLogger(ctx)
+
+	convo, err := u.repositories.Conversation.GetBySenderReceiverIds(ctx, req.SenderID, req.ReceiverID)
+	if err != nil {
+		log.Error("Failed to get conversation: ", zap.Error(err))
+		return nil, err
+	}
+	if convo == nil {
+		// First contact from someone the receiver doesn't know goes to their message requests
+		status := model.ConversationStatusRequest
+		contact, err := u.repositories.Contact.Get(ctx, req.ReceiverID, req.SenderID)
+		if err != nil {
+			log.Error("Failed to get contact: ", zap.Error(err))
+			return nil, err
+		}
+		if contact != nil {
+			status = model.ConversationStatusAccepted
+		}
+
+		convo, err = u.repositories.Conversation.Create(ctx, &model.Conversation{
+			SenderID:   req.SenderID,
+			ReceiverID: req.ReceiverID,
+			Status:     status,
+		})
+		if err != nil {
+			log.Error("Failed to create conversation: ", zap.Error(err))
+			return nil, err
+		}
+		err = u.repositories.Outbox.Append(ctx, model.EventConversationCreated, &model.ConversationCreatedEvent{
+			ConversationID: convo.ID,
+			SenderID:       convo.SenderID,
+			ReceiverID:     convo.ReceiverID,
+			Status:         convo.Status,
+		})
+		if err != nil {
+			log.Error("Failed to append event: ", zap.Error(err))
+			return nil, err
+		}
+	} else if convo.Status != "" && convo.Status != model.ConversationStatusAccepted {
+		if req.SenderID == convo.ReceiverID {
+			// Replying to a request accepts it
+			if err := u.repositories.Conversation.UpdateStatus(ctx, convo.ID, model.ConversationStatusAccepted); err != nil {
+				log.Error("Failed to accept message request: ", zap.Error(err))
+				return nil, err
+			}
+			convo.Status = model.ConversationStatusAccepted
+		} else if convo.Status == model.ConversationStatusDeclined {
+			return nil, http_error.Forbidden("Your message request was declined")
+		}
+	}
+	return convo, nil
+}
+
 func (u MessageUsecase) GetAllByConversationId(ctx context.Context, req *payload.GetMessagesByConvIdRequest) (*payload.GetMessagesByConvIdResponse, error) {
 	log := logger.GetLogger(ctx)
 	convo, err := u.repositories.Conversation.GetById(ctx, req.ConversationID)
@@ -93,12 +296,175 @@ func (u MessageUsecase) GetAllByConversationId(ctx context.Context, req *payload
 		return nil, errors.New("unauthorized")
 	}
 
-	msgs, err := u.repositories.Message.GetAllByConversationId(ctx, req.ConversationID)
+	participant, err := u.repositories.Conversation.GetParticipant(ctx, req.UserID, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get conversation settings: ", zap.Error(err))
+		return nil, err
+	}
+	var since *time.Time
+	if participant != nil {
+		since = participant.ClearedAt
+	}
+
+	msgs, err := u.repositories.Message.GetAllByConversationId(ctx, req.ConversationID, since)
 	if err != nil {
 		log.Error("Failed get messages by conversation id: ", zap.Error(err))
 		return nil, err
 	}
+	if err := u.attachDeviceContents(ctx, msgs, req.UserID); err != nil {
+		log.Error("Failed to get e2e message content: ", zap.Error(err))
+		return nil, err
+	}
+	if err := u.attachReadReceipts(ctx, convo, msgs, req.UserID); err != nil {
+		log.Error("Failed to get read receipts: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Reading the conversation marks what the user received as read
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := u.repositories.Message.MarkRead(ctx, req.ConversationID, req.UserID); err != nil {
+			return err
+		}
+		return u.repositories.Inbox.MarkRead(ctx, req.UserID, req.ConversationID)
+	})
+	if err != nil {
+		log.Error("Failed to mark messages as read: ", zap.Error(err))
+		return nil, err
+	}
 
 	var res payload.GetMessagesByConvIdResponse = msgs
 	return &res, nil
 }
+
+// The user sees whether their own messages were read, unless the other participant blocked
+// them or hasn't accepted their message request yet
+func (u MessageUsecase) attachReadReceipts(ctx context.Context, convo *model.Conversation, msgs []*model.Message, userId string) error {
+	if convo.SenderID == userId && (convo.IsRequest() || convo.Status == model.ConversationStatusDeclined) {
+		return nil
+	}
+	otherId := convo.ReceiverID
+	if otherId == userId {
+		otherId = convo.SenderID
+	}
+	blocked, err := u.repositories.Block.IsBlocked(ctx, otherId, userId)
+	if err != nil || blocked {
+		return err
+	}
+	for _, msg := range msgs {
+		if msg.SenderID == userId {
+			read := msg.IsRead
+			msg.Read = &read
+		}
+	}
+	return nil
+}
+
+// Each e2e message gets the ciphertext addressed to the user's own devices
+func (u MessageUsecase) attachDeviceContents(ctx context.Context, msgs []*model.Message, userId string) error {
+	var ids []string
+	byId := make(map[string]*model.Message)
+	for _, msg := range msgs {
+		if msg.IsE2E() {
+			ids = append(ids, msg.ID)
+			byId[msg.ID] = msg
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	contents, err := u.repositories.Message.GetDeviceContents(ctx, ids, userId)
+	if err != nil {
+		return err
+	}
+	for _, c := range contents {
+		if msg, ok := byId[c.MessageID]; ok {
+			msg.Contents = append(msg.Contents, c)
+		}
+	}
+	return nil
+}
+
+// Search looks for messages containing the query in a conversation the user takes part in,
+// history they cleared is not searched
+func (u MessageUsecase) Search(ctx context.Context, req *payload.SearchMessagesRequest) (*payload.SearchMessagesResponse, error) {
+	log := logger.GetLogger(ctx)
+	convo, err := u.repositories.Conversation.GetById(ctx, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get conversation: ", zap.Error(err))
+		return nil, err
+	}
+	if convo.SenderID != req.UserID && convo.ReceiverID != req.UserID {
+		return nil, errors.New("unauthorized")
+	}
+
+	participant, err := u.repositories.Conversation.GetParticipant(ctx, req.UserID, req.ConversationID)
+	if err != nil {
+		log.Error("Failed to get conversation settings: ", zap.Error(err))
+		return nil, err
+	}
+	var since *time.Time
+	if participant != nil {
+		since = participant.ClearedAt
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = payload.DefaultSearchLimit
+	}
+	msgs, err := u.repositories.Message.Search(ctx, req.ConversationID, req.Query, since, limit)
+	if err != nil {
+		log.Error("Failed to search messages: ", zap.Error(err))
+		return nil, err
+	}
+
+	var res payload.SearchMessagesResponse = msgs
+	return &res, nil
+}
+
+// Delete removes a message for both participants, only its sender can delete it
+func (u MessageUsecase) Delete(ctx context.Context, req *payload.DeleteMessageRequest) (*payload.DeleteMessageResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	msg, err := u.repositories.Message.GetById(ctx, req.MessageID)
+	if err != nil {
+		log.Error("Failed to get message: ", zap.Error(err))
+		return nil, err
+	}
+	if msg.SenderID != req.UserID {
+		return nil, errors.New("unauthorized")
+	}
+
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repositories.Message.Delete(ctx, msg.ID); err != nil {
+			return err
+		}
+		return u.repositories.Inbox.Refresh(ctx, msg.ConversationID)
+	})
+	if err != nil {
+		log.Error("Failed to delete message: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.DeleteMessageResponse{
+		Message: "Delete message success",
+	}, nil
+}
+
+// A new message brings an archived conversation back to the receiver's inbox unless they muted it
+func (u MessageUsecase) unarchiveForReceiver(ctx context.Context, receiverId string, conversationId string, at time.Time) {
+	log := logger.GetLogger(ctx)
+	participant, err := u.repositories.Conversation.GetParticipant(ctx, receiverId, conversationId)
+	if err != nil {
+		log.Error("Failed to get conversation settings: ", zap.Error(err))
+		return
+	}
+	if participant == nil || !participant.IsArchived || participant.IsMuted(at) {
+		return
+	}
+
+	participant.IsArchived = false
+	if _, err := u.repositories.Conversation.UpsertParticipant(ctx, participant); err != nil {
+		log.Error("Failed to unarchive conversation: ", zap.Error(err))
+	}
+}
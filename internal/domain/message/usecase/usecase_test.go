@@ -0,0 +1,473 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/message/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_block "gitlab.com/raihanlh/messenger-api/testing/mocks/block"
+	mock_contact "gitlab.com/raihanlh/messenger-api/testing/mocks/contact"
+	mock_conversation "gitlab.com/raihanlh/messenger-api/testing/mocks/conversation"
+	mock_device "gitlab.com/raihanlh/messenger-api/testing/mocks/device"
+	mock_draft "gitlab.com/raihanlh/messenger-api/testing/mocks/draft"
+	mock_inbox "gitlab.com/raihanlh/messenger-api/testing/mocks/inbox"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	mock_outbox "gitlab.com/raihanlh/messenger-api/testing/mocks/outbox"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+	"gorm.io/gorm"
+)
+
+func Test_MessageUsecase_Create_E2E(t *testing.T) {
+	senderId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	receiverId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	convo := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: senderId, ReceiverID: receiverId, Status: model.ConversationStatusAccepted}
+	receiverDevices := []*model.Device{{Model: model.Model{ID: "r1"}}, {Model: model.Model{ID: "r2"}}}
+	senderDevices := []*model.Device{{Model: model.Model{ID: "s1"}}, {Model: model.Model{ID: "s2"}}}
+
+	tests := []struct {
+		name    string
+		content []*payload.DeviceContent
+		wantErr bool
+	}{
+		{
+			name: "Every receiver device and another sender device",
+			content: []*payload.DeviceContent{
+				{DeviceID: "r1", Ciphertext: "Y3Ix"}, {DeviceID: "r2", Ciphertext: "Y3Iy"}, {DeviceID: "s2", Ciphertext: "Y3My"},
+			},
+		},
+		{
+			name:    "Missing a receiver device",
+			content: []*payload.DeviceContent{{DeviceID: "r1", Ciphertext: "Y3Ix"}},
+			wantErr: true,
+		},
+		{
+			name: "Unknown device",
+			content: []*payload.DeviceContent{
+				{DeviceID: "r1", Ciphertext: "Y3Ix"}, {DeviceID: "r2", Ciphertext: "Y3Iy"}, {DeviceID: "x9", Ciphertext: "Y3g5"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			blockRepoMock.EXPECT().IsBlocked(ctx, receiverId, senderId).Return(false, nil)
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			userRepoMock.EXPECT().GetById(ctx, senderId).Return(&model.User{Model: model.Model{ID: senderId}}, nil)
+			userRepoMock.EXPECT().GetById(ctx, receiverId).Return(&model.User{Model: model.Model{ID: receiverId}}, nil)
+			contactRepoMock := mock_contact.NewMockRepository(ctrl)
+			contactRepoMock.EXPECT().Get(ctx, senderId, receiverId).Return(nil, nil)
+			deviceRepoMock := mock_device.NewMockRepository(ctrl)
+			deviceRepoMock.EXPECT().GetAllByUserId(ctx, receiverId).Return(receiverDevices, nil)
+			deviceRepoMock.EXPECT().GetAllByUserId(ctx, senderId).Return(senderDevices, nil)
+
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+			outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+			draftRepoMock := mock_draft.NewMockRepository(ctrl)
+			if !tt.wantErr {
+				convRepoMock.EXPECT().GetBySenderReceiverIds(ctx, senderId, receiverId).Return(convo, nil)
+				msgRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msg *model.Message) (*model.Message, error) {
+					assert.Equal(t, model.MessageModeE2E, msg.Mode)
+					assert.Empty(t, msg.MessageText)
+					assert.Len(t, msg.Contents, 3)
+					msg.ID = "m1"
+					return msg, nil
+				})
+				inboxRepoMock.EXPECT().RecordMessage(ctx, gomock.Any(), receiverId).Return(nil)
+				outboxRepoMock.EXPECT().Append(ctx, model.EventMessageCreated, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, payload interface{}) error {
+						if event, ok := payload.(*model.MessageCreatedEvent); assert.True(t, ok) {
+							assert.Equal(t, "m1", event.MessageID)
+							assert.Equal(t, model.MessageModeE2E, event.Mode)
+							assert.Equal(t, []string{receiverId}, event.RecipientIDs)
+							assert.Equal(t, []string{senderId, receiverId}, event.ParticipantIDs)
+						}
+						return nil
+					})
+				draftRepoMock.EXPECT().Delete(ctx, senderId, convo.ID).Return(nil)
+				convRepoMock.EXPECT().GetParticipant(ctx, receiverId, convo.ID).Return(nil, nil)
+			}
+
+			messageUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				User:         userRepoMock,
+				Message:      msgRepoMock,
+				Conversation: convRepoMock,
+				Draft:        draftRepoMock,
+				Block:        blockRepoMock,
+				Contact:      contactRepoMock,
+				Inbox:        inboxRepoMock,
+				Device:       deviceRepoMock,
+				Outbox:       outboxRepoMock,
+			}, nil)
+			res, err := messageUsecase.Create(ctx, &payload.CreateMessageRequest{
+				SenderID:   senderId,
+				ReceiverID: receiverId,
+				Mode:       model.MessageModeE2E,
+				Content:    tt.content,
+			})
+			if tt.wantErr {
+				if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+					assert.Equal(t, http_error.StaleDevicesCode, httpErr.ErrorCode)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, "m1", res.ID)
+				assert.Equal(t, model.MessageModeE2E, res.Mode)
+			}
+		})
+	}
+}
+
+// Repositories for sending a plain message in an existing accepted conversation, the
+// receiver's settings are left to the test
+func setupPlainCreate(ctrl *gomock.Controller, ctx context.Context, convo *model.Conversation) (*dependency.Repositories, *mock_conversation.MockRepository) {
+	senderId, receiverId := convo.SenderID, convo.ReceiverID
+
+	blockRepoMock := mock_block.NewMockRepository(ctrl)
+	blockRepoMock.EXPECT().IsBlocked(ctx, receiverId, senderId).Return(false, nil)
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetById(ctx, senderId).Return(&model.User{Model: model.Model{ID: senderId}}, nil)
+	userRepoMock.EXPECT().GetById(ctx, receiverId).Return(&model.User{Model: model.Model{ID: receiverId}}, nil)
+	contactRepoMock := mock_contact.NewMockRepository(ctrl)
+	contactRepoMock.EXPECT().Get(ctx, senderId, receiverId).Return(nil, nil)
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetBySenderReceiverIds(ctx, senderId, receiverId).Return(convo, nil)
+	msgRepoMock := mock_message.NewMockRepository(ctrl)
+	msgRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, msg *model.Message) (*model.Message, error) {
+		msg.ID = "m1"
+		return msg, nil
+	})
+	inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+	inboxRepoMock.EXPECT().RecordMessage(ctx, gomock.Any(), receiverId).Return(nil)
+	outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+	outboxRepoMock.EXPECT().Append(ctx, model.EventMessageCreated, gomock.Any()).Return(nil)
+	draftRepoMock := mock_draft.NewMockRepository(ctrl)
+	draftRepoMock.EXPECT().Delete(ctx, senderId, convo.ID).Return(nil)
+
+	return &dependency.Repositories{
+		Transactor:   helper.NoTransaction{},
+		User:         userRepoMock,
+		Message:      msgRepoMock,
+		Conversation: convRepoMock,
+		Draft:        draftRepoMock,
+		Block:        blockRepoMock,
+		Contact:      contactRepoMock,
+		Inbox:        inboxRepoMock,
+		Outbox:       outboxRepoMock,
+	}, convRepoMock
+}
+
+func Test_MessageUsecase_Create_Unarchive(t *testing.T) {
+	senderId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	receiverId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	convo := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: senderId, ReceiverID: receiverId, Status: model.ConversationStatusAccepted}
+	mutedUntil := time.Now().Add(time.Hour)
+	mutedBefore := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		participant   *model.UserParticipant
+		wantUnarchive bool
+	}{
+		{
+			name:          "Archived conversation comes back",
+			participant:   &model.UserParticipant{UserID: receiverId, ConversationID: convo.ID, IsArchived: true},
+			wantUnarchive: true,
+		},
+		{
+			name:          "Mute that ended doesn't keep it archived",
+			participant:   &model.UserParticipant{UserID: receiverId, ConversationID: convo.ID, IsArchived: true, MutedUntil: &mutedBefore},
+			wantUnarchive: true,
+		},
+		{
+			name:        "Muted conversation stays archived",
+			participant: &model.UserParticipant{UserID: receiverId, ConversationID: convo.ID, IsArchived: true, MutedUntil: &mutedUntil},
+		},
+		{
+			name:        "Conversation that isn't archived",
+			participant: &model.UserParticipant{UserID: receiverId, ConversationID: convo.ID},
+		},
+		{
+			name: "Receiver without settings",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			repositories, convRepoMock := setupPlainCreate(ctrl, ctx, convo)
+			// Only the receiver's settings are read, the sender's archive is left alone
+			convRepoMock.EXPECT().GetParticipant(ctx, receiverId, convo.ID).Return(tt.participant, nil)
+			if tt.wantUnarchive {
+				convRepoMock.EXPECT().UpsertParticipant(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, p *model.UserParticipant) (*model.UserParticipant, error) {
+						assert.Equal(t, receiverId, p.UserID)
+						assert.False(t, p.IsArchived)
+						return p, nil
+					})
+			}
+
+			messageUsecase := usecase.New(repositories, nil)
+			_, err := messageUsecase.Create(ctx, &payload.CreateMessageRequest{
+				SenderID:   senderId,
+				ReceiverID: receiverId,
+				Message:    "hello",
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_MessageUsecase_Create_Blocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	senderId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	receiverId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	// Nothing else is read or written once the receiver blocked the sender
+	blockRepoMock := mock_block.NewMockRepository(ctrl)
+	blockRepoMock.EXPECT().IsBlocked(ctx, receiverId, senderId).Return(true, nil)
+
+	messageUsecase := usecase.New(&dependency.Repositories{
+		Block: blockRepoMock,
+	}, nil)
+	_, err := messageUsecase.Create(ctx, &payload.CreateMessageRequest{
+		SenderID:   senderId,
+		ReceiverID: receiverId,
+		Message:    "hello",
+	})
+	httpErr, ok := err.(*http_error.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, http_error.BlockedCode, httpErr.ErrorCode)
+	}
+}
+
+func Test_MessageUsecase_Create_DeletedReceiver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	senderId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	receiverId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	blockRepoMock := mock_block.NewMockRepository(ctrl)
+	blockRepoMock.EXPECT().IsBlocked(ctx, receiverId, senderId).Return(false, nil)
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetById(ctx, senderId).Return(&model.User{Model: model.Model{ID: senderId}}, nil)
+	userRepoMock.EXPECT().GetById(ctx, receiverId).Return(nil, gorm.ErrRecordNotFound)
+
+	messageUsecase := usecase.New(&dependency.Repositories{
+		Block: blockRepoMock,
+		User:  userRepoMock,
+	}, nil)
+	_, err := messageUsecase.Create(ctx, &payload.CreateMessageRequest{
+		SenderID:   senderId,
+		ReceiverID: receiverId,
+		Message:    "hello",
+	})
+	httpErr, ok := err.(*http_error.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusNotFound, httpErr.HTTPCode)
+	}
+}
+
+func Test_MessageUsecase_GetAllByConversationId_Cleared(t *testing.T) {
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	otherId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	convo := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: userId, ReceiverID: otherId}
+	clearedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		userId      string
+		participant *model.UserParticipant
+		wantSince   *time.Time
+	}{
+		{
+			name:        "Participant who cleared only sees what came after",
+			userId:      userId,
+			participant: &model.UserParticipant{UserID: userId, ConversationID: convo.ID, ClearedAt: &clearedAt},
+			wantSince:   &clearedAt,
+		},
+		{
+			name:        "Other participant keeps the full history",
+			userId:      otherId,
+			participant: &model.UserParticipant{UserID: otherId, ConversationID: convo.ID},
+		},
+		{
+			name:   "Participant without settings",
+			userId: otherId,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			msgs := []*model.Message{{Model: model.Model{ID: "m2"}, ConversationID: convo.ID, SenderID: otherId}}
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, convo.ID).Return(convo, nil)
+			convRepoMock.EXPECT().GetParticipant(ctx, tt.userId, convo.ID).Return(tt.participant, nil)
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			msgRepoMock.EXPECT().GetAllByConversationId(ctx, convo.ID, tt.wantSince).Return(msgs, nil)
+			msgRepoMock.EXPECT().MarkRead(ctx, convo.ID, tt.userId).Return(int64(1), nil)
+			inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+			inboxRepoMock.EXPECT().MarkRead(ctx, tt.userId, convo.ID).Return(nil)
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			blockRepoMock.EXPECT().IsBlocked(ctx, gomock.Any(), tt.userId).Return(false, nil)
+
+			messageUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				Conversation: convRepoMock,
+				Message:      msgRepoMock,
+				Inbox:        inboxRepoMock,
+				Block:        blockRepoMock,
+			}, nil)
+			res, err := messageUsecase.GetAllByConversationId(ctx, &payload.GetMessagesByConvIdRequest{
+				ConversationID: convo.ID,
+				UserID:         tt.userId,
+			})
+			if assert.NoError(t, err) {
+				assert.Len(t, *res, 1)
+			}
+		})
+	}
+}
+
+func Test_MessageUsecase_GetAllByConversationId_ReadReceipts(t *testing.T) {
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	otherId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name         string
+		status       string
+		blocked      bool
+		wantReceipts bool
+	}{
+		{
+			name:         "Sender sees which of their messages were read",
+			status:       model.ConversationStatusAccepted,
+			wantReceipts: true,
+		},
+		{
+			name:    "Receiver who blocked the sender hides them",
+			status:  model.ConversationStatusAccepted,
+			blocked: true,
+		},
+		{
+			name:   "Hidden until the message request is accepted",
+			status: model.ConversationStatusRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			convo := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: userId, ReceiverID: otherId, Status: tt.status}
+			msgs := []*model.Message{
+				{Model: model.Model{ID: "m1"}, ConversationID: convo.ID, SenderID: userId, IsRead: true},
+				{Model: model.Model{ID: "m2"}, ConversationID: convo.ID, SenderID: userId},
+				{Model: model.Model{ID: "m3"}, ConversationID: convo.ID, SenderID: otherId, IsRead: true},
+			}
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			convRepoMock.EXPECT().GetById(ctx, convo.ID).Return(convo, nil)
+			convRepoMock.EXPECT().GetParticipant(ctx, userId, convo.ID).Return(nil, nil)
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			msgRepoMock.EXPECT().GetAllByConversationId(ctx, convo.ID, nil).Return(msgs, nil)
+			msgRepoMock.EXPECT().MarkRead(ctx, convo.ID, userId).Return(int64(0), nil)
+			inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+			inboxRepoMock.EXPECT().MarkRead(ctx, userId, convo.ID).Return(nil)
+			blockRepoMock := mock_block.NewMockRepository(ctrl)
+			if !convo.IsRequest() {
+				blockRepoMock.EXPECT().IsBlocked(ctx, otherId, userId).Return(tt.blocked, nil)
+			}
+
+			messageUsecase := usecase.New(&dependency.Repositories{
+				Transactor:   helper.NoTransaction{},
+				Conversation: convRepoMock,
+				Message:      msgRepoMock,
+				Inbox:        inboxRepoMock,
+				Block:        blockRepoMock,
+			}, nil)
+			res, err := messageUsecase.GetAllByConversationId(ctx, &payload.GetMessagesByConvIdRequest{
+				ConversationID: convo.ID,
+				UserID:         userId,
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			got := *res
+			if tt.wantReceipts {
+				if assert.NotNil(t, got[0].Read) && assert.NotNil(t, got[1].Read) {
+					assert.True(t, *got[0].Read)
+					assert.False(t, *got[1].Read)
+				}
+			} else {
+				assert.Nil(t, got[0].Read)
+				assert.Nil(t, got[1].Read)
+			}
+			// Messages the user received never carry a receipt
+			assert.Nil(t, got[2].Read)
+		})
+	}
+}
+
+func Test_MessageUsecase_GetAllByConversationId_MarksRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	otherId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	convo := &model.Conversation{Model: model.Model{ID: "c1"}, SenderID: otherId, ReceiverID: userId, Status: model.ConversationStatusAccepted}
+
+	// Opening the conversation is what takes its messages off the unread badge
+	convRepoMock := mock_conversation.NewMockRepository(ctrl)
+	convRepoMock.EXPECT().GetById(ctx, convo.ID).Return(convo, nil)
+	convRepoMock.EXPECT().GetParticipant(ctx, userId, convo.ID).Return(nil, nil)
+	msgRepoMock := mock_message.NewMockRepository(ctrl)
+	msgRepoMock.EXPECT().GetAllByConversationId(ctx, convo.ID, nil).
+		Return([]*model.Message{{Model: model.Model{ID: "m1"}, ConversationID: convo.ID, SenderID: otherId}}, nil)
+	msgRepoMock.EXPECT().MarkRead(ctx, convo.ID, userId).Return(int64(1), nil)
+	inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+	inboxRepoMock.EXPECT().MarkRead(ctx, userId, convo.ID).Return(nil)
+	blockRepoMock := mock_block.NewMockRepository(ctrl)
+	blockRepoMock.EXPECT().IsBlocked(ctx, otherId, userId).Return(false, nil)
+
+	messageUsecase := usecase.New(&dependency.Repositories{
+		Transactor:   helper.NoTransaction{},
+		Conversation: convRepoMock,
+		Message:      msgRepoMock,
+		Inbox:        inboxRepoMock,
+		Block:        blockRepoMock,
+	}, nil)
+	_, err := messageUsecase.GetAllByConversationId(ctx, &payload.GetMessagesByConvIdRequest{
+		ConversationID: convo.ID,
+		UserID:         userId,
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type NotificationHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) notification.Handler {
+	return &NotificationHandler{
+		usecases: u,
+	}
+}
+
+// RegisterPushToken godoc
+// @Summary Register Push Token
+// @Description register a push notification token of the caller's app install, a token registered by another user moves to the caller
+// @Tags Notification
+// @Accept application/json
+// @Param body body payload.RegisterTokenRequest true "Platform and token"
+// @Produce json
+// @Success 201 {object} object{status=string,data=payload.RegisterTokenResponse}
+// @Router /api/v1/me/push-tokens [post]
+func (h NotificationHandler) RegisterToken(ctx echo.Context) error {
+	var body payload.RegisterTokenRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Notification.RegisterToken(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to register push token: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusCreated).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// UnregisterPushToken godoc
+// @Summary Unregister Push Token
+// @Description stop sending push notifications to one of the caller's tokens, e.g. on logout
+// @Tags Notification
+// @Accept application/json
+// @Param body body payload.UnregisterTokenRequest true "Token"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.UnregisterTokenResponse}
+// @Router /api/v1/me/push-tokens [delete]
+func (h NotificationHandler) UnregisterToken(ctx echo.Context) error {
+	var body payload.UnregisterTokenRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Notification.UnregisterToken(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to unregister push token: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+type Repository interface {
+	UpsertToken(ctx context.Context, token *model.PushToken) (*model.PushToken, error)
+	DeleteToken(ctx context.Context, userId string, token string) error
+	DeleteTokens(ctx context.Context, tokens []string) error
+	GetTokensByUserId(ctx context.Context, userId string) ([]*model.PushToken, error)
+	Enqueue(ctx context.Context, job *model.PushJob) error
+	ClaimPending(ctx context.Context, limit int) ([]*model.PushJob, error)
+	UpdateJob(ctx context.Context, job *model.PushJob) error
+}
+
+type Usecase interface {
+	RegisterToken(ctx context.Context, req *payload.RegisterTokenRequest) (*payload.RegisterTokenResponse, error)
+	UnregisterToken(ctx context.Context, req *payload.UnregisterTokenRequest) (*payload.UnregisterTokenResponse, error)
+	SendPending(ctx context.Context) error
+	QueuePush(ctx context.Context, e *eventbus.Event) error
+}
+
+type Handler interface {
+	RegisterToken(ctx echo.Context) error
+	UnregisterToken(ctx echo.Context) error
+}
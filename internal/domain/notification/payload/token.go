@@ -0,0 +1,23 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type RegisterTokenRequest struct {
+	UserID   string `json:"-"`
+	Platform string `json:"platform" validate:"required,oneof=android ios"`
+	Token    string `json:"token" validate:"required,max=4096"`
+}
+
+type RegisterTokenResponse struct {
+	PushToken *model.PushToken `json:"push_token"`
+	Message   string           `json:"message"`
+}
+
+type UnregisterTokenRequest struct {
+	UserID string `json:"-"`
+	Token  string `json:"token" validate:"required,max=4096"`
+}
+
+type UnregisterTokenResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) notification.Repository {
+	return &NotificationRepository{
+		DB: gormDB,
+	}
+}
+
+// A token belongs to one app install, registering it again, e.g. after another user
+// logged in on the same phone, moves it to the new user
+func (r NotificationRepository) UpsertToken(ctx context.Context, token *model.PushToken) (*model.PushToken, error) {
+	result := r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"user_id":    token.UserID,
+			"platform":   token.Platform,
+			"updated_at": time.Now(),
+			"deleted_at": nil,
+		}),
+	}).Create(token)
+	return token, result.Error
+}
+
+func (r NotificationRepository) DeleteToken(ctx context.Context, userId string, token string) error {
+	result := r.DB.WithContext(ctx).Unscoped().Where("user_id = ? AND token = ?", userId, token).Delete(&model.PushToken{})
+	return result.Error
+}
+
+// Tokens the provider rejected are forgotten whoever they belong to
+func (r NotificationRepository) DeleteTokens(ctx context.Context, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	result := r.DB.WithContext(ctx).Unscoped().Where("token IN ?", tokens).Delete(&model.PushToken{})
+	return result.Error
+}
+
+func (r NotificationRepository) GetTokensByUserId(ctx context.Context, userId string) ([]*model.PushToken, error) {
+	var tokens []*model.PushToken
+	result := r.DB.WithContext(ctx).Where("user_id = ?", userId).Order("created_at ASC").Find(&tokens)
+	return tokens, result.Error
+}
+
+// Enqueue joins the caller's transaction. A job queued for the same receiver and message
+// before is left as it is.
+func (r NotificationRepository) Enqueue(ctx context.Context, job *model.PushJob) error {
+	job.Status = model.PushJobStatusPending
+	return postgres.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(job).Error
+}
+
+// ClaimPending marks up to limit pending jobs as processing and returns them, oldest first.
+// Rows locked by another worker are skipped, so concurrent workers never send the same job.
+func (r NotificationRepository) ClaimPending(ctx context.Context, limit int) ([]*model.PushJob, error) {
+	var jobs []*model.PushJob
+	result := r.DB.WithContext(ctx).Raw(`UPDATE `+constant.PushJobTable+` SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (SELECT id FROM `+constant.PushJobTable+` WHERE status = ? AND deleted_at IS NULL
+		ORDER BY created_at ASC LIMIT ? FOR UPDATE SKIP LOCKED) RETURNING *`,
+		model.PushJobStatusProcessing, time.Now(), model.PushJobStatusPending, limit).Scan(&jobs)
+	return jobs, result.Error
+}
+
+func (r NotificationRepository) UpdateJob(ctx context.Context, job *model.PushJob) error {
+	result := r.DB.WithContext(ctx).Table(constant.PushJobTable).Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":     job.Status,
+			"updated_at": time.Now(),
+		})
+	return result.Error
+}
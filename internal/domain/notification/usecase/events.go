@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+// QueuePush subscribes to message.created and queues a push job for every recipient.
+// Whether they get a notification is decided when the job runs.
+func (u NotificationUsecase) QueuePush(ctx context.Context, e *eventbus.Event) error {
+	var data model.MessageCreatedEvent
+	if err := e.Decode(&data); err != nil {
+		return err
+	}
+	for _, userId := range data.RecipientIDs {
+		err := u.repositories.Notification.Enqueue(ctx, &model.PushJob{
+			UserID:         userId,
+			MessageID:      data.MessageID,
+			ConversationID: data.ConversationID,
+			SenderID:       data.SenderID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
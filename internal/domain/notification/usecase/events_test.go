@@ -0,0 +1,38 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+	mock_notification "gitlab.com/raihanlh/messenger-api/testing/mocks/notification"
+)
+
+func Test_NotificationUsecase_QueuePush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	notificationRepoMock := mock_notification.NewMockRepository(ctrl)
+	// Only recipients are notified, not the sender
+	for _, userId := range []string{"u2", "u3"} {
+		notificationRepoMock.EXPECT().Enqueue(ctx, &model.PushJob{
+			UserID:         userId,
+			MessageID:      "m1",
+			ConversationID: "c1",
+			SenderID:       "u1",
+		}).Return(nil)
+	}
+
+	err := usecase.New(&dependency.Repositories{Notification: notificationRepoMock}, nil).QueuePush(ctx, &eventbus.Event{
+		ID:      "e1",
+		Type:    model.EventMessageCreated,
+		Payload: []byte(`{"message_id":"m1","conversation_id":"c1","sender_id":"u1","recipient_ids":["u2","u3"],"participant_ids":["u1","u2","u3"]}`),
+	})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// Jobs claimed per run of SendPending
+	PushBatchSize = 100
+	// A job failing this many times is given up on
+	MaxPushAttempts     = 3
+	DefaultOnlineWindow = 2 * time.Minute
+	// Sent instead of the message text unless previews are enabled
+	GenericPushBody = "New message"
+)
+
+type NotificationUsecase struct {
+	repositories *dependency.Repositories
+	gateways     *dependency.Gateways
+}
+
+func New(r *dependency.Repositories, g *dependency.Gateways) notification.Usecase {
+	return &NotificationUsecase{
+		repositories: r,
+		gateways:     g,
+	}
+}
+
+func (u NotificationUsecase) RegisterToken(ctx context.Context, req *payload.RegisterTokenRequest) (*payload.RegisterTokenResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	token, err := u.repositories.Notification.UpsertToken(ctx, &model.PushToken{
+		UserID:   req.UserID,
+		Platform: req.Platform,
+		Token:    req.Token,
+	})
+	if err != nil {
+		log.Error("Failed to register push token: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RegisterTokenResponse{
+		PushToken: token,
+		Message:   "Register push token success",
+	}, nil
+}
+
+func (u NotificationUsecase) UnregisterToken(ctx context.Context, req *payload.UnregisterTokenRequest) (*payload.UnregisterTokenResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if err := u.repositories.Notification.DeleteToken(ctx, req.UserID, req.Token); err != nil {
+		log.Error("Failed to unregister push token: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.UnregisterTokenResponse{
+		Message: "Unregister push token success",
+	}, nil
+}
+
+// SendPending notifies the receivers of new messages queued since the last run.
+// Failed jobs go back to the queue until they run out of attempts.
+func (u NotificationUsecase) SendPending(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+
+	jobs, err := u.repositories.Notification.ClaimPending(ctx, PushBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		status, err := u.send(ctx, job)
+		if err != nil {
+			log.Error("Failed to send push notification: ", zap.String("job_id", job.ID), zap.Error(err))
+			status = model.PushJobStatusPending
+			if job.Attempts >= MaxPushAttempts {
+				status = model.PushJobStatusFailed
+			}
+		}
+		job.Status = status
+		if err := u.repositories.Notification.UpdateJob(ctx, job); err != nil {
+			log.Error("Failed to update push job: ", zap.String("job_id", job.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// Send the job to every token of the receiver and return the job's new status.
+// Tokens the provider reports as invalid are pruned.
+func (u NotificationUsecase) send(ctx context.Context, job *model.PushJob) (string, error) {
+	log := logger.GetLogger(ctx)
+	now := time.Now()
+
+	receiver, err := u.repositories.User.GetById(ctx, job.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.PushJobStatusSkipped, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if receiver.IsOnline(now, u.onlineWindow()) {
+		return model.PushJobStatusSkipped, nil
+	}
+
+	tokens, err := u.repositories.Notification.GetTokensByUserId(ctx, job.UserID)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return model.PushJobStatusSkipped, nil
+	}
+
+	participant, err := u.repositories.Conversation.GetParticipant(ctx, job.UserID, job.ConversationID)
+	if err != nil {
+		return "", err
+	}
+	if participant != nil && participant.IsMuted(now) {
+		return model.PushJobStatusSkipped, nil
+	}
+
+	// The message may have been deleted before the job ran
+	messages, err := u.repositories.Message.GetByIds(ctx, []string{job.MessageID})
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return model.PushJobStatusSkipped, nil
+	}
+	n, err := u.notification(ctx, job, messages[0])
+	if err != nil {
+		return "", err
+	}
+
+	sent := 0
+	var invalid []string
+	var sendErr error
+	for _, token := range tokens {
+		if !u.gateways.Push.Supports(token.Platform) {
+			continue
+		}
+		n.Token = token.Token
+		err := u.gateways.Push.Send(ctx, token.Platform, n)
+		switch {
+		case errors.Is(err, push.ErrInvalidToken):
+			invalid = append(invalid, token.Token)
+		case err != nil:
+			sendErr = err
+		default:
+			sent++
+		}
+	}
+	if err := u.repositories.Notification.DeleteTokens(ctx, invalid); err != nil {
+		log.Error("Failed to prune push tokens: ", zap.Error(err))
+	}
+
+	// Once any device got it, retrying would notify that device twice
+	if sent > 0 {
+		return model.PushJobStatusSent, nil
+	}
+	if sendErr != nil {
+		return "", sendErr
+	}
+	return model.PushJobStatusSkipped, nil
+}
+
+// The title is the sender's name as the receiver saved it. The text is only shown when
+// previews are enabled, the server can't read e2e messages anyway.
+func (u NotificationUsecase) notification(ctx context.Context, job *model.PushJob, msg *model.Message) (*push.Notification, error) {
+	sender, err := u.repositories.User.GetByIdWithDeleted(ctx, job.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	title := sender.Name
+	if sender.IsDeleted() {
+		title = model.DeletedAccountName
+	}
+	contact, err := u.repositories.Contact.Get(ctx, job.UserID, job.SenderID)
+	if err != nil {
+		return nil, err
+	}
+	if contact != nil && contact.Nickname != "" && !sender.IsDeleted() {
+		title = contact.Nickname
+	}
+
+	body := GenericPushBody
+	if u.gateways.PushShowPreview && !msg.IsE2E() && msg.MessageText != "" {
+		body = model.InboxPreview(msg.MessageText)
+	}
+
+	return &push.Notification{
+		Title: title,
+		Body:  body,
+		Data: map[string]string{
+			"conversation_id": job.ConversationID,
+			"message_id":      job.MessageID,
+		},
+		CollapseKey: job.ConversationID,
+	}, nil
+}
+
+func (u NotificationUsecase) onlineWindow() time.Duration {
+	if u.gateways.PushOnlineWindow <= 0 {
+		return DefaultOnlineWindow
+	}
+	return u.gateways.PushOnlineWindow
+}
@@ -0,0 +1,166 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/notification/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+	mock_contact "gitlab.com/raihanlh/messenger-api/testing/mocks/contact"
+	mock_conversation "gitlab.com/raihanlh/messenger-api/testing/mocks/conversation"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	mock_notification "gitlab.com/raihanlh/messenger-api/testing/mocks/notification"
+	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
+)
+
+// Records what it sends and fails for the tokens in errs
+type fakeProvider struct {
+	sent []*push.Notification
+	errs map[string]error
+}
+
+func (p *fakeProvider) Send(ctx context.Context, n *push.Notification) error {
+	if err := p.errs[n.Token]; err != nil {
+		return err
+	}
+	sent := *n
+	p.sent = append(p.sent, &sent)
+	return nil
+}
+
+func Test_NotificationUsecase_SendPending(t *testing.T) {
+	receiverId := "47dsga9t-d76e-401a-a3ba-7a03352812c2"
+	senderId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	convoId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	now := time.Now()
+	seenJustNow := now.Add(-30 * time.Second)
+	seenLongAgo := now.Add(-time.Hour)
+	mutedUntil := now.Add(time.Hour)
+	tokens := []*model.PushToken{
+		{UserID: receiverId, Platform: push.PlatformAndroid, Token: "phone"},
+		{UserID: receiverId, Platform: push.PlatformAndroid, Token: "uninstalled"},
+		{UserID: receiverId, Platform: push.PlatformIOS, Token: "no-provider"},
+	}
+
+	tests := []struct {
+		name        string
+		lastSeen    *time.Time
+		muted       bool
+		attempts    int
+		showPreview bool
+		message     *model.Message
+		sendErr     error
+		wantStatus  string
+		wantBody    string
+	}{
+		{
+			name: "Offline receiver is notified", lastSeen: &seenLongAgo, attempts: 1,
+			message:    &model.Message{MessageText: "See you at 7"},
+			wantStatus: model.PushJobStatusSent, wantBody: usecase.GenericPushBody,
+		},
+		{
+			name: "Preview of the text", attempts: 1, showPreview: true,
+			message:    &model.Message{MessageText: "See you at 7"},
+			wantStatus: model.PushJobStatusSent, wantBody: "See you at 7",
+		},
+		{
+			name: "No preview of an e2e message", attempts: 1, showPreview: true,
+			message:    &model.Message{Mode: model.MessageModeE2E},
+			wantStatus: model.PushJobStatusSent, wantBody: usecase.GenericPushBody,
+		},
+		{name: "Online receiver", lastSeen: &seenJustNow, attempts: 1, wantStatus: model.PushJobStatusSkipped},
+		{name: "Muted conversation", muted: true, attempts: 1, wantStatus: model.PushJobStatusSkipped},
+		{name: "Deleted message", attempts: 1, wantStatus: model.PushJobStatusSkipped},
+		{
+			name: "Provider down is retried", attempts: 1, sendErr: errors.New("unavailable"),
+			message: &model.Message{MessageText: "hi"}, wantStatus: model.PushJobStatusPending,
+		},
+		{
+			name: "Given up after the last attempt", attempts: usecase.MaxPushAttempts, sendErr: errors.New("unavailable"),
+			message: &model.Message{MessageText: "hi"}, wantStatus: model.PushJobStatusFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			job := &model.PushJob{
+				Model:          model.Model{ID: "j1"},
+				UserID:         receiverId,
+				MessageID:      "m1",
+				ConversationID: convoId,
+				SenderID:       senderId,
+				Status:         model.PushJobStatusProcessing,
+				Attempts:       tt.attempts,
+			}
+			notificationRepoMock := mock_notification.NewMockRepository(ctrl)
+			notificationRepoMock.EXPECT().ClaimPending(ctx, usecase.PushBatchSize).Return([]*model.PushJob{job}, nil)
+			notificationRepoMock.EXPECT().UpdateJob(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, job *model.PushJob) error {
+				assert.Equal(t, tt.wantStatus, job.Status)
+				return nil
+			})
+
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			userRepoMock.EXPECT().GetById(ctx, receiverId).Return(&model.User{Model: model.Model{ID: receiverId}, LastSeenAt: tt.lastSeen}, nil)
+			convRepoMock := mock_conversation.NewMockRepository(ctrl)
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			contactRepoMock := mock_contact.NewMockRepository(ctrl)
+
+			provider := &fakeProvider{errs: map[string]error{"uninstalled": push.ErrInvalidToken, "phone": tt.sendErr}}
+			online := tt.lastSeen == &seenJustNow
+			if !online {
+				notificationRepoMock.EXPECT().GetTokensByUserId(ctx, receiverId).Return(tokens, nil)
+				participant := &model.UserParticipant{UserID: receiverId, ConversationID: convoId}
+				if tt.muted {
+					participant.MutedUntil = &mutedUntil
+				}
+				convRepoMock.EXPECT().GetParticipant(ctx, receiverId, convoId).Return(participant, nil)
+			}
+			if !online && !tt.muted {
+				var messages []*model.Message
+				if tt.message != nil {
+					messages = append(messages, tt.message)
+				}
+				msgRepoMock.EXPECT().GetByIds(ctx, []string{"m1"}).Return(messages, nil)
+			}
+			if tt.message != nil {
+				userRepoMock.EXPECT().GetByIdWithDeleted(ctx, senderId).Return(&model.User{Model: model.Model{ID: senderId}, Name: "Alice"}, nil)
+				contactRepoMock.EXPECT().Get(ctx, receiverId, senderId).Return(&model.Contact{Nickname: "Al"}, nil)
+				notificationRepoMock.EXPECT().DeleteTokens(ctx, []string{"uninstalled"}).Return(nil)
+			}
+
+			notificationUsecase := usecase.New(&dependency.Repositories{
+				User:         userRepoMock,
+				Message:      msgRepoMock,
+				Conversation: convRepoMock,
+				Contact:      contactRepoMock,
+				Notification: notificationRepoMock,
+			}, &dependency.Gateways{
+				Push:            push.NewGateway(map[string]push.Provider{push.PlatformAndroid: provider}),
+				PushShowPreview: tt.showPreview,
+			})
+			assert.NoError(t, notificationUsecase.SendPending(ctx))
+
+			if tt.wantStatus != model.PushJobStatusSent {
+				assert.Empty(t, provider.sent)
+				return
+			}
+			if assert.Len(t, provider.sent, 1) {
+				n := provider.sent[0]
+				assert.Equal(t, "phone", n.Token)
+				assert.Equal(t, "Al", n.Title)
+				assert.Equal(t, tt.wantBody, n.Body)
+				assert.Equal(t, convoId, n.CollapseKey)
+				assert.Equal(t, map[string]string{"conversation_id": convoId, "message_id": "m1"}, n.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// Events are written by other domains and dispatched in the background, so the outbox
+// has no handler
+type Repository interface {
+	Append(ctx context.Context, eventType string, payload interface{}) error
+	ClaimDue(ctx context.Context, at time.Time, leaseExpiredBefore time.Time, limit int) ([]*model.OutboxEvent, error)
+	Update(ctx context.Context, event *model.OutboxEvent) error
+}
+
+type Usecase interface {
+	DispatchPending(ctx context.Context) error
+}
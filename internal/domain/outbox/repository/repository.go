@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/outbox"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) outbox.Repository {
+	return &OutboxRepository{
+		DB: gormDB,
+	}
+}
+
+// Append joins the caller's transaction, the event is only dispatched when the change it
+// reports was stored
+func (r OutboxRepository) Append(ctx context.Context, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return postgres.Conn(ctx, r.DB).Create(&model.OutboxEvent{
+		Type:          eventType,
+		Payload:       string(data),
+		Status:        model.OutboxStatusPending,
+		NextAttemptAt: now,
+		OccurredAt:    now,
+	}).Error
+}
+
+// ClaimDue marks up to limit due events as dispatching and returns them, oldest first. An
+// event left dispatching by a worker that died is claimed again once its lease expired.
+func (r OutboxRepository) ClaimDue(ctx context.Context, at time.Time, leaseExpiredBefore time.Time, limit int) ([]*model.OutboxEvent, error) {
+	var events []*model.OutboxEvent
+	result := r.DB.WithContext(ctx).Raw(`UPDATE `+constant.OutboxEventTable+` SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (SELECT id FROM `+constant.OutboxEventTable+` WHERE deleted_at IS NULL
+		AND ((status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?))
+		ORDER BY occurred_at ASC LIMIT ? FOR UPDATE SKIP LOCKED) RETURNING *`,
+		model.OutboxStatusDispatching, at,
+		model.OutboxStatusPending, at, model.OutboxStatusDispatching, leaseExpiredBefore, limit).Scan(&events)
+	return events, result.Error
+}
+
+func (r OutboxRepository) Update(ctx context.Context, event *model.OutboxEvent) error {
+	pending, err := json.Marshal(event.Pending)
+	if err != nil {
+		return err
+	}
+	result := r.DB.WithContext(ctx).Table(constant.OutboxEventTable).Where("id = ?", event.ID).
+		Updates(map[string]interface{}{
+			"status":          event.Status,
+			"pending":         string(pending),
+			"next_attempt_at": event.NextAttemptAt,
+			"last_error":      event.LastError,
+			"dispatched_at":   event.DispatchedAt,
+			"updated_at":      time.Now(),
+		})
+	return result.Error
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/outbox/repository"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_OutboxRepository_Append(t *testing.T) {
+	db, mock := Setup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "outbox_events"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, model.EventUserRegistered, `{"user_id":"u1","name":"Alice"}`,
+			model.OutboxStatusPending, nil, 0, sqlmock.AnyArg(), "", sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("e1"))
+	mock.ExpectCommit()
+
+	err := repo.New(db).Append(context.TODO(), model.EventUserRegistered, &model.UserRegisteredEvent{UserID: "u1", Name: "Alice"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_OutboxRepository_ClaimDue(t *testing.T) {
+	db, mock := Setup()
+	now := time.Now()
+	leaseExpired := now.Add(-time.Minute)
+
+	// Due pending events and dispatching ones whose lease expired are claimed
+	mock.ExpectQuery(`UPDATE outbox_events SET status = \$1, attempts = attempts \+ 1`).
+		WithArgs(model.OutboxStatusDispatching, now, model.OutboxStatusPending, now, model.OutboxStatusDispatching, leaseExpired, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "payload", "pending", "attempts"}).
+			AddRow("e1", model.EventMessageCreated, `{}`, `["webhooks"]`, 2))
+
+	events, err := repo.New(db).ClaimDue(context.TODO(), now, leaseExpired, 10)
+	if assert.NoError(t, err) && assert.Len(t, events, 1) {
+		assert.Equal(t, "e1", events[0].ID)
+		assert.Equal(t, []string{"webhooks"}, events[0].Pending)
+		assert.Equal(t, 2, events[0].Attempts)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
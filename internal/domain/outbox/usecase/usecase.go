@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/outbox"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// Events claimed per run of DispatchPending
+	DispatchBatchSize = 100
+	// An event still marked dispatching after this long is claimed again
+	DispatchLease = 5 * time.Minute
+	// Attempts before an event is dead, the waits in between double from RetryBaseDelay
+	MaxDispatchAttempts = 10
+	RetryBaseDelay      = 5 * time.Second
+	RetryMaxDelay       = 30 * time.Minute
+	// Longest subscriber error kept on the event
+	MaxErrorLength = 500
+)
+
+type OutboxUsecase struct {
+	repositories *dependency.Repositories
+	bus          *eventbus.Bus
+}
+
+func New(r *dependency.Repositories, bus *eventbus.Bus) outbox.Usecase {
+	return &OutboxUsecase{
+		repositories: r,
+		bus:          bus,
+	}
+}
+
+// DispatchPending hands due events to their subscribers. Subscribers that fail get the
+// event again with backoff, the ones that handled it aren't called again.
+func (u OutboxUsecase) DispatchPending(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+	now := time.Now()
+
+	events, err := u.repositories.Outbox.ClaimDue(ctx, now, now.Add(-DispatchLease), DispatchBatchSize)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	for _, e := range events {
+		u.dispatch(ctx, e)
+		if err := u.repositories.Outbox.Update(ctx, e); err != nil {
+			log.Error("Failed to update outbox event: ", zap.String("event_id", e.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// Publish one event and record the outcome on it
+func (u OutboxUsecase) dispatch(ctx context.Context, e *model.OutboxEvent) {
+	failed, err := u.bus.Publish(ctx, &eventbus.Event{
+		ID:         e.ID,
+		Type:       e.Type,
+		OccurredAt: e.OccurredAt,
+		Payload:    []byte(e.Payload),
+	}, e.Pending)
+	if err == nil {
+		now := time.Now()
+		e.Status = model.OutboxStatusDispatched
+		e.Pending = []string{}
+		e.LastError = ""
+		e.DispatchedAt = &now
+		return
+	}
+
+	logger.GetLogger(ctx).Error("Failed to dispatch event: ", zap.String("event_id", e.ID), zap.Error(err))
+	e.Pending = failed
+	e.LastError = err.Error()
+	if len(e.LastError) > MaxErrorLength {
+		e.LastError = e.LastError[:MaxErrorLength]
+	}
+	if e.Attempts >= MaxDispatchAttempts {
+		e.Status = model.OutboxStatusDead
+		return
+	}
+	e.Status = model.OutboxStatusPending
+	e.NextAttemptAt = time.Now().Add(RetryDelay(e.Attempts))
+}
+
+// RetryDelay is the wait before the next attempt after the given number of attempts
+func RetryDelay(attempts int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempts && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > RetryMaxDelay {
+		return RetryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,100 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/outbox/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+	mock_outbox "gitlab.com/raihanlh/messenger-api/testing/mocks/outbox"
+)
+
+func Test_OutboxUsecase_DispatchPending(t *testing.T) {
+	tests := []struct {
+		name        string
+		pending     []string
+		attempts    int
+		webhooksErr error
+		wantCalls   []string
+		wantStatus  string
+		wantPending []string
+		wantRetry   time.Duration
+	}{
+		{name: "Dispatched", attempts: 1, wantCalls: []string{"push", "webhooks"}, wantStatus: model.OutboxStatusDispatched, wantPending: []string{}},
+		{
+			name: "Failed subscriber is retried", attempts: 1, webhooksErr: errors.New("db down"),
+			wantCalls: []string{"push", "webhooks"}, wantStatus: model.OutboxStatusPending, wantPending: []string{"webhooks"}, wantRetry: usecase.RetryBaseDelay,
+		},
+		{
+			name: "Retry only runs the failed subscriber", pending: []string{"webhooks"}, attempts: 2,
+			wantCalls: []string{"webhooks"}, wantStatus: model.OutboxStatusDispatched, wantPending: []string{},
+		},
+		{
+			name: "Out of attempts", pending: []string{"webhooks"}, attempts: usecase.MaxDispatchAttempts, webhooksErr: errors.New("db down"),
+			wantCalls: []string{"webhooks"}, wantStatus: model.OutboxStatusDead, wantPending: []string{"webhooks"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			var calls []string
+			bus := eventbus.New()
+			bus.Subscribe(model.EventMessageCreated, "push", func(ctx context.Context, e *eventbus.Event) error {
+				calls = append(calls, "push")
+				return nil
+			})
+			bus.Subscribe(model.EventMessageCreated, "webhooks", func(ctx context.Context, e *eventbus.Event) error {
+				calls = append(calls, "webhooks")
+				assert.Equal(t, "e1", e.ID)
+				var data model.MessageCreatedEvent
+				if assert.NoError(t, e.Decode(&data)) {
+					assert.Equal(t, "m1", data.MessageID)
+				}
+				return tt.webhooksErr
+			})
+
+			event := &model.OutboxEvent{
+				Model:    model.Model{ID: "e1"},
+				Type:     model.EventMessageCreated,
+				Payload:  `{"message_id":"m1"}`,
+				Status:   model.OutboxStatusDispatching,
+				Pending:  tt.pending,
+				Attempts: tt.attempts,
+			}
+			outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+			outboxRepoMock.EXPECT().ClaimDue(ctx, gomock.Any(), gomock.Any(), usecase.DispatchBatchSize).Return([]*model.OutboxEvent{event}, nil)
+			outboxRepoMock.EXPECT().Update(ctx, event).Return(nil)
+
+			start := time.Now()
+			err := usecase.New(&dependency.Repositories{Outbox: outboxRepoMock}, bus).DispatchPending(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantStatus, event.Status)
+			assert.Equal(t, tt.wantPending, event.Pending)
+			if tt.wantStatus == model.OutboxStatusDispatched {
+				assert.NotNil(t, event.DispatchedAt)
+				assert.Empty(t, event.LastError)
+			} else {
+				assert.Contains(t, event.LastError, "db down")
+			}
+			if tt.wantRetry > 0 {
+				assert.WithinDuration(t, start.Add(tt.wantRetry), event.NextAttemptAt, time.Second)
+			}
+		})
+	}
+}
+
+func Test_RetryDelay(t *testing.T) {
+	assert.Equal(t, usecase.RetryBaseDelay, usecase.RetryDelay(1))
+	assert.Equal(t, 2*usecase.RetryBaseDelay, usecase.RetryDelay(2))
+	assert.Equal(t, usecase.RetryMaxDelay, usecase.RetryDelay(100))
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/domain/revocation"
+)
+
+// MemoryRepository keeps the revocation list in the process. It's lost on restart and not
+// shared between instances, so it only suits a single instance or development.
+type MemoryRepository struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+func NewMemory() revocation.Repository {
+	return &MemoryRepository{
+		revoked: map[string]time.Time{},
+	}
+}
+
+func (r *MemoryRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.revoked[jti]; !ok {
+		r.revoked[jti] = expiresAt
+	}
+	return nil
+}
+
+func (r *MemoryRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
+
+func (r *MemoryRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for jti, expiresAt := range r.revoked {
+		if expiresAt.Before(before) {
+			delete(r.revoked, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/domain/revocation"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevocationRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) revocation.Repository {
+	return &RevocationRepository{
+		DB: gormDB,
+	}
+}
+
+// Revoking a token twice, e.g. a retried logout, keeps the first entry
+func (r RevocationRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	token := &model.RevokedToken{
+		JTI:       jti,
+		ExpiresAt: expiresAt,
+	}
+	return postgres.Conn(ctx, r.DB).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r RevocationRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var tokens []*model.RevokedToken
+	result := r.DB.WithContext(ctx).Where("jti = ?", jti).Limit(1).Find(&tokens)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteExpired removes tokens that expired before the given time and returns how many it removed
+func (r RevocationRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Unscoped().Where("expires_at < ?", before).Delete(&model.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,101 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/revocation/repository"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_RevocationRepository_Revoke(t *testing.T) {
+	db, mock := Setup()
+	exp := time.Now().Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "revoked_tokens" .* ON CONFLICT DO NOTHING`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "jti-1", exp, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("r1"))
+	mock.ExpectCommit()
+
+	err := repo.New(db).Revoke(context.TODO(), "jti-1", exp)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_RevocationRepository_IsRevoked(t *testing.T) {
+	db, mock := Setup()
+
+	mock.ExpectQuery(`SELECT \* FROM "revoked_tokens" WHERE jti = \$1`).
+		WithArgs("jti-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "jti"}).AddRow("r1", "jti-1"))
+	mock.ExpectQuery(`SELECT \* FROM "revoked_tokens" WHERE jti = \$1`).
+		WithArgs("jti-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "jti"}))
+
+	revoked, err := repo.New(db).IsRevoked(context.TODO(), "jti-1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.New(db).IsRevoked(context.TODO(), "jti-2")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_RevocationRepository_DeleteExpired(t *testing.T) {
+	db, mock := Setup()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "revoked_tokens" WHERE expires_at < \$1`).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	deleted, err := repo.New(db).DeleteExpired(context.TODO(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_MemoryRepository(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	r := repo.NewMemory()
+
+	assert.NoError(t, r.Revoke(ctx, "expired", now.Add(-time.Minute)))
+	assert.NoError(t, r.Revoke(ctx, "valid", now.Add(time.Hour)))
+
+	revoked, _ := r.IsRevoked(ctx, "valid")
+	assert.True(t, revoked)
+	revoked, _ = r.IsRevoked(ctx, "unknown")
+	assert.False(t, revoked)
+
+	deleted, err := r.DeleteExpired(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	revoked, _ = r.IsRevoked(ctx, "expired")
+	assert.False(t, revoked)
+	revoked, _ = r.IsRevoked(ctx, "valid")
+	assert.True(t, revoked)
+}
@@ -0,0 +1,18 @@
+package revocation
+
+import (
+	"context"
+	"time"
+)
+
+// Access tokens logged out before they expire. The list is only written on logout and read
+// when authenticating, so it has no handler.
+type Repository interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Usecase interface {
+	PurgeExpired(ctx context.Context) error
+}
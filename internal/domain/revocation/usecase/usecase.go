@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/revocation"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type RevocationUsecase struct {
+	repositories *dependency.Repositories
+}
+
+func New(r *dependency.Repositories) revocation.Usecase {
+	return &RevocationUsecase{
+		repositories: r,
+	}
+}
+
+// PurgeExpired drops revoked tokens that have expired since, they're rejected without
+// the list
+func (u RevocationUsecase) PurgeExpired(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+
+	deleted, err := u.repositories.Revocation.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		log.Error("Failed to delete expired revoked tokens: ", zap.Error(err))
+		return err
+	}
+	if deleted > 0 {
+		log.Info("Deleted expired revoked tokens", zap.Int64("count", deleted))
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/revocation/usecase"
+	mock_revocation "gitlab.com/raihanlh/messenger-api/testing/mocks/revocation"
+)
+
+func Test_RevocationUsecase_PurgeExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	revocationRepoMock := mock_revocation.NewMockRepository(ctrl)
+	revocationRepoMock.EXPECT().DeleteExpired(ctx, gomock.Any()).Return(int64(2), nil)
+	revocationRepoMock.EXPECT().DeleteExpired(ctx, gomock.Any()).Return(int64(0), errors.New("connection refused"))
+
+	revocationUsecase := usecase.New(&dependency.Repositories{
+		Revocation: revocationRepoMock,
+	})
+
+	assert.NoError(t, revocationUsecase.PurgeExpired(ctx))
+	assert.EqualError(t, revocationUsecase.PurgeExpired(ctx), "connection refused")
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	apiPayload "gitlab.com/raihanlh/messenger-api/api/payload"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+type SessionHandler struct {
+	usecases *dependency.Usecases
+}
+
+func New(u *dependency.Usecases) session.Handler {
+	return &SessionHandler{
+		usecases: u,
+	}
+}
+
+// GetSessions godoc
+// @Summary Get Sessions
+// @Description list the caller's active sessions, the one the request was made with is marked current
+// @Tags Session
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.GetSessionsResponse}
+// @Router /api/v1/me/sessions [get]
+func (h SessionHandler) GetAll(ctx echo.Context) error {
+	var body payload.GetSessionsRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	// Bots authenticate with an API key and have no session
+	body.CurrentSessionID, _ = ctx.Get("session_id").(string)
+	data, err := h.usecases.Session.GetAll(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to get sessions: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RevokeSession godoc
+// @Summary Revoke Session
+// @Description sign one of the caller's sessions out, its token is rejected from then on
+// @Tags Session
+// @Accept application/json
+// @Param id path string true "Session ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RevokeSessionResponse}
+// @Router /api/v1/me/sessions/{id} [delete]
+func (h SessionHandler) Revoke(ctx echo.Context) error {
+	var body payload.RevokeSessionRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	data, err := h.usecases.Session.Revoke(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to revoke session: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RevokeOtherSessions godoc
+// @Summary Revoke Other Sessions
+// @Description sign the caller out of every session but the current one
+// @Tags Session
+// @Accept application/json
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RevokeOtherSessionsResponse}
+// @Router /api/v1/me/sessions [delete]
+func (h SessionHandler) RevokeOthers(ctx echo.Context) error {
+	var body payload.RevokeOtherSessionsRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	user := ctx.Get("user").(*model.User)
+	body.UserID = user.ID
+	// Bots authenticate with an API key and have no session
+	body.CurrentSessionID, _ = ctx.Get("session_id").(string)
+	data, err := h.usecases.Session.RevokeOthers(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "unauthorized" {
+			return ctx.JSON(http.StatusForbidden, "forbidden")
+		}
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to revoke sessions: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
@@ -0,0 +1,44 @@
+package payload
+
+import "time"
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// The session the request was made with
+	Current bool `json:"current"`
+}
+
+type GetSessionsRequest struct {
+	UserID           string `json:"-"`
+	CurrentSessionID string `json:"-"`
+}
+
+type GetSessionsResponse struct {
+	Sessions []*SessionResponse `json:"sessions"`
+	Message  string             `json:"message"`
+}
+
+type RevokeSessionRequest struct {
+	UserID    string `json:"-"`
+	SessionID string `param:"id"`
+}
+
+type RevokeSessionResponse struct {
+	Message string `json:"message"`
+}
+
+type RevokeOtherSessionsRequest struct {
+	UserID           string `json:"-"`
+	CurrentSessionID string `json:"-"`
+}
+
+type RevokeOtherSessionsResponse struct {
+	Revoked int64  `json:"revoked"`
+	Message string `json:"message"`
+}
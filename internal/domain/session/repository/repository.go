@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) session.Repository {
+	return &SessionRepository{
+		DB: gormDB,
+	}
+}
+
+func (r SessionRepository) Create(ctx context.Context, session *model.Session) (*model.Session, error) {
+	result := postgres.Conn(ctx, r.DB).Create(session)
+	return session, result.Error
+}
+
+func (r SessionRepository) GetById(ctx context.Context, id string) (*model.Session, error) {
+	var session *model.Session
+	result := r.DB.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return session, nil
+}
+
+// GetActiveByUserId returns the sessions that are neither revoked nor expired at the given
+// time, most recently used first
+func (r SessionRepository) GetActiveByUserId(ctx context.Context, userId string, at time.Time) ([]*model.Session, error) {
+	var sessions []*model.Session
+	result := r.DB.WithContext(ctx).Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, at).
+		Order("last_used_at DESC").Find(&sessions)
+	return sessions, result.Error
+}
+
+func (r SessionRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	result := postgres.Conn(ctx, r.DB).Table(constant.SessionTable).Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": at, "updated_at": at})
+	return result.Error
+}
+
+// RevokeAllExcept revokes every session of the user but one and returns how many it revoked
+func (r SessionRepository) RevokeAllExcept(ctx context.Context, userId string, exceptId string, at time.Time) (int64, error) {
+	query := postgres.Conn(ctx, r.DB).Table(constant.SessionTable).Where("user_id = ? AND revoked_at IS NULL", userId)
+	if exceptId != "" {
+		query = query.Where("id <> ?", exceptId)
+	}
+	result := query.Updates(map[string]interface{}{"revoked_at": at, "updated_at": at})
+	return result.RowsAffected, result.Error
+}
+
+func (r SessionRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	result := postgres.Conn(ctx, r.DB).Table(constant.SessionTable).Where("id = ?", id).Update("last_used_at", at)
+	return result.Error
+}
+
+func (r SessionRepository) CreateRefreshToken(ctx context.Context, token *model.RefreshToken) (*model.RefreshToken, error) {
+	result := postgres.Conn(ctx, r.DB).Create(token)
+	return token, result.Error
+}
+
+func (r SessionRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error) {
+	var token *model.RefreshToken
+	result := r.DB.WithContext(ctx).Where("token_hash = ?", hash).Limit(1).Find(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return token, nil
+}
+
+// UseRefreshToken marks the token as used. It returns false when it was used already, also
+// by a concurrent request that got there first.
+func (r SessionRepository) UseRefreshToken(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := postgres.Conn(ctx, r.DB).Table(constant.RefreshTokenTable).Where("id = ? AND used_at IS NULL", id).
+		Updates(map[string]interface{}{"used_at": at, "updated_at": at})
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,35 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// Sessions are created by the user domain on login
+type Repository interface {
+	Create(ctx context.Context, session *model.Session) (*model.Session, error)
+	GetById(ctx context.Context, id string) (*model.Session, error)
+	GetActiveByUserId(ctx context.Context, userId string, at time.Time) ([]*model.Session, error)
+	Revoke(ctx context.Context, id string, at time.Time) error
+	RevokeAllExcept(ctx context.Context, userId string, exceptId string, at time.Time) (int64, error)
+	Touch(ctx context.Context, id string, at time.Time) error
+	CreateRefreshToken(ctx context.Context, token *model.RefreshToken) (*model.RefreshToken, error)
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*model.RefreshToken, error)
+	UseRefreshToken(ctx context.Context, id string, at time.Time) (bool, error)
+}
+
+type Usecase interface {
+	GetAll(ctx context.Context, req *payload.GetSessionsRequest) (*payload.GetSessionsResponse, error)
+	Revoke(ctx context.Context, req *payload.RevokeSessionRequest) (*payload.RevokeSessionResponse, error)
+	RevokeOthers(ctx context.Context, req *payload.RevokeOtherSessionsRequest) (*payload.RevokeOtherSessionsResponse, error)
+}
+
+type Handler interface {
+	GetAll(ctx echo.Context) error
+	Revoke(ctx echo.Context) error
+	RevokeOthers(ctx echo.Context) error
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type SessionUsecase struct {
+	repositories *dependency.Repositories
+}
+
+func New(r *dependency.Repositories) session.Usecase {
+	return &SessionUsecase{
+		repositories: r,
+	}
+}
+
+func (u SessionUsecase) GetAll(ctx context.Context, req *payload.GetSessionsRequest) (*payload.GetSessionsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	sessions, err := u.repositories.Session.GetActiveByUserId(ctx, req.UserID, time.Now())
+	if err != nil {
+		log.Error("Failed to get sessions: ", zap.Error(err))
+		return nil, err
+	}
+	res := make([]*payload.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, &payload.SessionResponse{
+			ID:         s.ID,
+			DeviceName: s.DeviceName,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastUsedAt: s.LastUsedAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.ID == req.CurrentSessionID,
+		})
+	}
+
+	return &payload.GetSessionsResponse{
+		Sessions: res,
+		Message:  "Get sessions success",
+	}, nil
+}
+
+// Revoke signs one of the user's sessions out, its token stops working right away
+func (u SessionUsecase) Revoke(ctx context.Context, req *payload.RevokeSessionRequest) (*payload.RevokeSessionResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	result, err := u.getOwnSession(ctx, req.UserID, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repositories.Session.Revoke(ctx, result.ID, time.Now()); err != nil {
+		log.Error("Failed to revoke session: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RevokeSessionResponse{
+		Message: "Revoke session success",
+	}, nil
+}
+
+// RevokeOthers signs the user out everywhere but the session the request was made with
+func (u SessionUsecase) RevokeOthers(ctx context.Context, req *payload.RevokeOtherSessionsRequest) (*payload.RevokeOtherSessionsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	revoked, err := u.repositories.Session.RevokeAllExcept(ctx, req.UserID, req.CurrentSessionID, time.Now())
+	if err != nil {
+		log.Error("Failed to revoke sessions: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RevokeOtherSessionsResponse{
+		Revoked: revoked,
+		Message: "Revoke other sessions success",
+	}, nil
+}
+
+func (u SessionUsecase) getOwnSession(ctx context.Context, userId string, sessionId string) (*model.Session, error) {
+	result, err := u.repositories.Session.GetById(ctx, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if result.UserID != userId {
+		return nil, errors.New("unauthorized")
+	}
+	return result, nil
+}
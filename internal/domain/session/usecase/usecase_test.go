@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/session/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	mock_session "gitlab.com/raihanlh/messenger-api/testing/mocks/session"
+)
+
+func Test_SessionUsecase_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	createdAt := time.Now().Add(-time.Hour)
+
+	sessionRepoMock := mock_session.NewMockRepository(ctrl)
+	sessionRepoMock.EXPECT().GetActiveByUserId(ctx, userId, gomock.Any()).Return([]*model.Session{
+		{Model: model.Model{ID: "s1", CreatedAt: createdAt}, UserID: userId, DeviceName: "Phone", IP: "10.0.0.1"},
+		{Model: model.Model{ID: "s2"}, UserID: userId, DeviceName: "Laptop"},
+	}, nil)
+
+	res, err := usecase.New(&dependency.Repositories{Session: sessionRepoMock}).GetAll(ctx, &payload.GetSessionsRequest{
+		UserID:           userId,
+		CurrentSessionID: "s2",
+	})
+	if assert.NoError(t, err) && assert.Len(t, res.Sessions, 2) {
+		assert.Equal(t, "Phone", res.Sessions[0].DeviceName)
+		assert.Equal(t, createdAt, res.Sessions[0].CreatedAt)
+		assert.False(t, res.Sessions[0].Current)
+		assert.True(t, res.Sessions[1].Current)
+	}
+}
+
+func Test_SessionUsecase_Revoke(t *testing.T) {
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+
+	tests := []struct {
+		name    string
+		ownerId string
+		wantErr string
+	}{
+		{name: "Own session", ownerId: userId},
+		{name: "Someone else's session", ownerId: "47dsga9t-d76e-401a-a3ba-7a03352812c2", wantErr: "unauthorized"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			sessionRepoMock := mock_session.NewMockRepository(ctrl)
+			sessionRepoMock.EXPECT().GetById(ctx, "s1").Return(&model.Session{Model: model.Model{ID: "s1"}, UserID: tt.ownerId}, nil)
+			if tt.wantErr == "" {
+				sessionRepoMock.EXPECT().Revoke(ctx, "s1", gomock.Any()).Return(nil)
+			}
+
+			_, err := usecase.New(&dependency.Repositories{Session: sessionRepoMock}).Revoke(ctx, &payload.RevokeSessionRequest{
+				UserID:    userId,
+				SessionID: "s1",
+			})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_SessionUsecase_RevokeOthers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+	userId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+
+	sessionRepoMock := mock_session.NewMockRepository(ctrl)
+	sessionRepoMock.EXPECT().RevokeAllExcept(ctx, userId, "s2", gomock.Any()).Return(int64(3), nil)
+
+	res, err := usecase.New(&dependency.Repositories{Session: sessionRepoMock}).RevokeOthers(ctx, &payload.RevokeOtherSessionsRequest{
+		UserID:           userId,
+		CurrentSessionID: "s2",
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(3), res.Revoked)
+	}
+}
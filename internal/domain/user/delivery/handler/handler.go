@@ -10,6 +10,12 @@ import (
 	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+const (
+	RefreshTokenCookie     = "refresh_token"
+	RefreshTokenCookiePath = "/api/v1/auth"
 )
 
 type UserHandler struct {
@@ -62,10 +68,9 @@ func (h UserHandler) Create(ctx echo.Context) error {
 
 // UpdateUser godoc
 // @Summary Update User
-// @Description update user from request body
+// @Description update user from request body, users can only update themselves unless they are an admin
 // @Tags User
 // @Accept application/json
-// @Param Authorization header string true "Token"
 // @Param id path string true "User ID"
 // @Param body body payload.UpdateRequest true "Update User"
 // @Produce json
@@ -86,11 +91,82 @@ func (h UserHandler) Update(ctx echo.Context) error {
 	}
 
 	// Pass body to usecase
+	body.Requester, _ = ctx.Get("user").(*model.User)
+	body.CurrentSessionID, _ = ctx.Get("session_id").(string)
 	data, err := h.usecases.User.Update(ctx.Request().Context(), &body)
 	if err != nil {
 		httpErr, ok := err.(*http_error.Error)
 		if !ok {
-			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to update user: %s", httpErr.Error())))
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to update user: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// UpdateUserRole godoc
+// @Summary Update User Role
+// @Description change the role of another user, requires the admin role. Admins can't change their own role
+// @Tags User
+// @Accept application/json
+// @Param id path string true "User ID"
+// @Param body body payload.UpdateRoleRequest true "Role"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.UpdateRoleResponse}
+// @Router /api/v1/user/{id}/role [put]
+func (h UserHandler) UpdateRole(ctx echo.Context) error {
+	var body payload.UpdateRoleRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	body.Requester, _ = ctx.Get("user").(*model.User)
+	data, err := h.usecases.User.UpdateRole(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to update user role: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RevokeUserSessions godoc
+// @Summary Revoke User Sessions
+// @Description sign a user out on every device, requires the moderator role. Only admins can sign out an admin
+// @Tags User
+// @Param id path string true "User ID"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RevokeSessionsResponse}
+// @Router /api/v1/user/{id}/sessions [delete]
+func (h UserHandler) RevokeSessions(ctx echo.Context) error {
+	var req payload.RevokeSessionsRequest
+
+	if err := ctx.Bind(&req); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	req.Requester, _ = ctx.Get("user").(*model.User)
+	data, err := h.usecases.User.RevokeSessions(ctx.Request().Context(), &req)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to revoke user sessions: %s", err.Error())))
 		}
 		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
 	}
@@ -102,11 +178,11 @@ func (h UserHandler) Update(ctx echo.Context) error {
 
 // DeleteUser godoc
 // @Summary Delete User
-// @Description delete user from request body
+// @Description delete a user account, the profile is anonymized and conversations show it as a deleted account. Users can only delete themselves unless they are an admin
 // @Tags User
 // @Accept application/json
-// @Param Authorization header string true "Token"
 // @Param id path string true "User ID"
+// @Param purge_messages query bool false "Also erase the user's messages after the grace period"
 // @Produce json
 // @Success 200 {object} object{status=string,data=payload.DeleteResponse}
 // @Router /api/v1/user/delete/{id} [delete]
@@ -125,11 +201,12 @@ func (h UserHandler) Delete(ctx echo.Context) error {
 	}
 
 	// Pass body to usecase
+	body.Requester, _ = ctx.Get("user").(*model.User)
 	data, err := h.usecases.User.Delete(ctx.Request().Context(), &body)
 	if err != nil {
 		httpErr, ok := err.(*http_error.Error)
 		if !ok {
-			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to update user: %s", httpErr.Error())))
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to delete user: %s", err.Error())))
 		}
 		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
 	}
@@ -202,6 +279,10 @@ func (h UserHandler) GetAll(ctx echo.Context) error {
 	}
 
 	// Pass body to usecase
+	if user, ok := ctx.Get("user").(*model.User); ok {
+		body.UserID = user.ID
+		body.CallerIsBot = user.IsBot
+	}
 	data, err := h.usecases.User.GetAll(ctx.Request().Context(), &body)
 	if err != nil {
 		httpErr, ok := err.(*http_error.Error)
@@ -238,8 +319,19 @@ func (h UserHandler) GetByToken(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, errCustom)
 	}
 
+	// Bots authenticate with an API key instead of a token, the middleware already loaded them
+	token, ok := ctx.Get("token").(string)
+	if !ok {
+		res := new(apiPayload.BaseResponse)
+		res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(&payload.GetByTokenResponse{
+			User:    ctx.Get("user").(*model.User),
+			Message: "Successfully get user by token",
+		})
+		return ctx.JSON(res.HTTPCode, res)
+	}
+
 	// Pass body to usecase
-	body.Token = ctx.Get("token").(string)
+	body.Token = token
 	data, err := h.usecases.User.GetByToken(ctx.Request().Context(), &body)
 	if err != nil {
 		httpErr, ok := err.(*http_error.Error)
@@ -278,6 +370,8 @@ func (h UserHandler) Login(ctx echo.Context) error {
 	}
 
 	// Pass body to usecase
+	body.UserAgent = ctx.Request().UserAgent()
+	body.IP = ctx.RealIP()
 	data, err := h.usecases.User.Login(ctx.Request().Context(), &body)
 	if err != nil {
 		httpErr, ok := err.(*http_error.Error)
@@ -290,11 +384,167 @@ func (h UserHandler) Login(ctx echo.Context) error {
 	res := new(apiPayload.BaseResponse)
 	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
 
+	setTokenCookies(ctx, &data.Tokens)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// RefreshToken godoc
+// @Summary Refresh Token
+// @Description exchange a refresh token for a new access token and refresh token, the refresh token can only be used once
+// @Tags User
+// @Accept application/json
+// @Param body body payload.RefreshRequest false "Refresh token, read from the refresh_token cookie when left out"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.RefreshResponse}
+// @Router /api/v1/auth/refresh [post]
+func (h UserHandler) Refresh(ctx echo.Context) error {
+	var body payload.RefreshRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	if body.RefreshToken == "" {
+		if cookie, err := ctx.Cookie(RefreshTokenCookie); err == nil {
+			body.RefreshToken = cookie.Value
+		}
+	}
+	data, err := h.usecases.User.Refresh(ctx.Request().Context(), &body)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to refresh token: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+
+	setTokenCookies(ctx, &data.Tokens)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description revoke the access token of the request and end its session, the token and refresh_token cookies are cleared. When the access token expired the session of the refresh_token cookie is ended instead, logging out without either succeeds and only clears the cookies
+// @Tags User
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.LogoutResponse}
+// @Router /api/v1/auth/logout [post]
+func (h UserHandler) Logout(ctx echo.Context) error {
+	res := new(apiPayload.BaseResponse)
+
+	// Requests authenticated with an API key have no token
+	req := &payload.LogoutRequest{}
+	req.Token, _ = ctx.Get("token").(string)
+	if _, ok := ctx.Get("user").(*model.User); !ok {
+		// Missing, expired or already revoked access token, the refresh token may still be valid
+		if cookie, err := ctx.Cookie(RefreshTokenCookie); err == nil {
+			req.RefreshToken = cookie.Value
+		}
+		if req.RefreshToken == "" {
+			res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(&payload.LogoutResponse{
+				Message: "Already logged out",
+			})
+			clearTokenCookies(ctx)
+			return ctx.JSON(res.HTTPCode, res)
+		}
+	}
+
+	data, err := h.usecases.User.Logout(ctx.Request().Context(), req)
+	if err != nil {
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to logout: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
+
+	clearTokenCookies(ctx)
+	return ctx.JSON(res.HTTPCode, res)
+}
+
+// The refresh token cookie is only sent to the auth endpoints
+func setTokenCookies(ctx echo.Context, tokens *payload.Tokens) {
 	ctx.SetCookie(&http.Cookie{
 		Name:    "token",
-		Value:   data.Token,
-		Expires: data.Exp,
+		Value:   tokens.Token,
+		Expires: tokens.Exp,
 		Path:    "/",
 	})
+	ctx.SetCookie(&http.Cookie{
+		Name:     RefreshTokenCookie,
+		Value:    tokens.RefreshToken,
+		Expires:  tokens.RefreshExp,
+		Path:     RefreshTokenCookiePath,
+		HttpOnly: true,
+	})
+}
+
+// Expire both cookies, paths have to match the ones they were set with
+func clearTokenCookies(ctx echo.Context) {
+	ctx.SetCookie(&http.Cookie{
+		Name:   "token",
+		Path:   "/",
+		MaxAge: -1,
+	})
+	ctx.SetCookie(&http.Cookie{
+		Name:     RefreshTokenCookie,
+		Path:     RefreshTokenCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+// CancelMessagePurge godoc
+// @Summary Cancel Message Purge
+// @Description keep the messages of a deleted account, using the cancel token returned when it was deleted
+// @Tags User
+// @Accept application/json
+// @Param id path string true "Deletion ID"
+// @Param body body payload.CancelPurgeRequest true "Cancel Token"
+// @Produce json
+// @Success 200 {object} object{status=string,data=payload.CancelPurgeResponse}
+// @Router /api/v1/user/deletions/{id}/cancel [post]
+func (h UserHandler) CancelPurge(ctx echo.Context) error {
+	var body payload.CancelPurgeRequest
+
+	if err := ctx.Bind(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(errCustom.HTTPCode, errCustom.HttpResponseError())
+	}
+
+	// Validate incoming data
+	if err := ctx.Validate(&body); err != nil {
+		errCustom := http_error.BadRequest(err)
+		return ctx.JSON(http.StatusBadRequest, errCustom)
+	}
+
+	// Pass body to usecase
+	data, err := h.usecases.User.CancelPurge(ctx.Request().Context(), &body)
+	if err != nil {
+		if err.Error() == "not found" {
+			return ctx.JSON(http.StatusNotFound, "not found")
+		}
+		httpErr, ok := err.(*http_error.Error)
+		if !ok {
+			return ctx.JSON(http.StatusInternalServerError, http_error.InternalServerError(fmt.Sprintf("Failed to cancel message purge: %s", err.Error())))
+		}
+		return ctx.JSON(httpErr.HTTPCode, httpErr.HttpResponseError())
+	}
+
+	res := new(apiPayload.BaseResponse)
+	res.AddHTTPCode(http.StatusOK).AddStatus(apiPayload.StatusOK).AddData(data)
 	return ctx.JSON(res.HTTPCode, res)
 }
@@ -386,3 +386,68 @@ func Test_UserHandler_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func Test_UserHandler_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testUser := &model.User{Model: model.Model{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2"}}
+
+	tests := []struct {
+		name         string
+		user         *model.User
+		token        string
+		refreshToken string
+		want         string
+	}{
+		{
+			name:  "Logout Handler Success",
+			user:  testUser,
+			token: "access-token",
+			want:  `{"status":"OK","data":{"message":"Logout success"}}`,
+		},
+		{
+			// The middleware sets no user for an expired access token
+			name:         "Logout With Expired Access Token",
+			refreshToken: "refresh-token",
+			want:         `{"status":"OK","data":{"message":"Logout success"}}`,
+		},
+		{
+			name: "Logout Without Session",
+			want: `{"status":"OK","data":{"message":"Already logged out"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", nil)
+			if tt.refreshToken != "" {
+				req.AddCookie(&http.Cookie{Name: handler.RefreshTokenCookie, Value: tt.refreshToken})
+			}
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+
+			userUsecaseMock := mock_user.NewMockUsecase(ctrl)
+			if tt.user != nil {
+				ctx.Set("user", tt.user)
+				ctx.Set("token", tt.token)
+			}
+			if tt.token != "" || tt.refreshToken != "" {
+				userUsecaseMock.EXPECT().Logout(gomock.Any(), &payload.LogoutRequest{Token: tt.token, RefreshToken: tt.refreshToken}).
+					Return(&payload.LogoutResponse{Message: "Logout success"}, nil)
+			}
+
+			userHandler := handler.New(&dependency.Usecases{
+				User: userUsecaseMock,
+			})
+			err := userHandler.Logout(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, tt.want, strings.Replace(rec.Body.String(), "\n", "", -1))
+				// Both cookies are expired either way
+				assert.Len(t, rec.Result().Cookies(), 2)
+			}
+		})
+	}
+}
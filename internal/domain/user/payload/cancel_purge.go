@@ -0,0 +1,10 @@
+package payload
+
+type CancelPurgeRequest struct {
+	DeletionID string `param:"id" json:"-"`
+	Token      string `json:"token" validate:"required"`
+}
+
+type CancelPurgeResponse struct {
+	Message string `json:"message"`
+}
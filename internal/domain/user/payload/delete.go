@@ -1,9 +1,23 @@
 package payload
 
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
 type DeleteRequest struct {
 	UserID string `param:"id"`
+	// Also erase every message the user sent once the grace period is over
+	PurgeMessages bool `json:"purge_messages" query:"purge_messages"`
+	// The authenticated user, only admins may delete someone else
+	Requester *model.User `json:"-" swaggerignore:"true"`
 }
 
 type DeleteResponse struct {
 	Message string `json:"message"`
+	// Set when a message purge was scheduled, the token cancels it until purge_at
+	DeletionID  string     `json:"deletion_id,omitempty"`
+	CancelToken string     `json:"cancel_token,omitempty"`
+	PurgeAt     *time.Time `json:"purge_at,omitempty"`
 }
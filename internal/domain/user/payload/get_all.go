@@ -8,6 +8,10 @@ import (
 type GetAllRequest struct {
 	pagination.Pagination
 	Search string `query:"search"`
+	// Set when the caller is logged in, users who blocked them are left out
+	UserID string `json:"-"`
+	// Bots only get the directory when their owner granted it
+	CallerIsBot bool `json:"-"`
 }
 
 type GetAllResponse struct {
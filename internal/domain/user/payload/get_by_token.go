@@ -7,6 +7,7 @@ type GetByTokenRequest struct {
 }
 
 type GetByTokenResponse struct {
-	User    *model.User `json:"user"`
-	Message string      `json:"message"`
+	User      *model.User `json:"user"`
+	SessionID string      `json:"-"`
+	Message   string      `json:"message"`
 }
\ No newline at end of file
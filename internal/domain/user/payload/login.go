@@ -5,10 +5,23 @@ import "time"
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// Shown in the session list, e.g. "Alice's phone"
+	DeviceName string `json:"device_name" validate:"max=100"`
+	UserAgent  string `json:"-"`
+	IP         string `json:"-"`
+}
+
+// Tokens of a session. The access token goes in the token cookie, the refresh token is
+// exchanged for new tokens once it expired.
+type Tokens struct {
+	Token        string    `json:"token"`
+	Exp          time.Time `json:"exp"`
+	RefreshToken string    `json:"refresh_token"`
+	RefreshExp   time.Time `json:"refresh_exp"`
+	SessionID    string    `json:"session_id"`
 }
 
 type LoginResponse struct {
-	Token   string    `json:"token"`
-	Exp     time.Time `json:"exp"`
-	Message string    `json:"message"`
+	Tokens
+	Message string `json:"message"`
 }
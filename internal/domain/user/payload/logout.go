@@ -0,0 +1,12 @@
+package payload
+
+type LogoutRequest struct {
+	// Access token of the request, set from the token cookie
+	Token string `json:"-"`
+	// Refresh token cookie, ends the session when the access token already expired
+	RefreshToken string `json:"-"`
+}
+
+type LogoutResponse struct {
+	Message string `json:"message"`
+}
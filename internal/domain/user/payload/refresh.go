@@ -0,0 +1,11 @@
+package payload
+
+type RefreshRequest struct {
+	// Falls back to the refresh_token cookie
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshResponse struct {
+	Tokens
+	Message string `json:"message"`
+}
@@ -0,0 +1,15 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type RevokeSessionsRequest struct {
+	UserID string `param:"id"`
+	// The authenticated moderator or admin
+	Requester *model.User `json:"-" swaggerignore:"true"`
+}
+
+type RevokeSessionsResponse struct {
+	// Number of sessions that were ended
+	Revoked int64  `json:"revoked"`
+	Message string `json:"message"`
+}
@@ -7,6 +7,10 @@ type UpdateRequest struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	// The authenticated user, only admins may update someone else
+	Requester *model.User `json:"-" swaggerignore:"true"`
+	// Kept signed in when the password changes, the user's other sessions are revoked
+	CurrentSessionID string `json:"-" swaggerignore:"true"`
 }
 
 type UpdateResponse struct {
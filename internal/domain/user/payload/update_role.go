@@ -0,0 +1,15 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type UpdateRoleRequest struct {
+	UserID string `param:"id" json:"-"`
+	Role   string `json:"role" validate:"required,oneof=user moderator admin"`
+	// The authenticated admin
+	Requester *model.User `json:"-" swaggerignore:"true"`
+}
+
+type UpdateRoleResponse struct {
+	User    *model.User `json:"user"`
+	Message string      `json:"message"`
+}
@@ -2,13 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"time"
 
 	"gitlab.com/raihanlh/messenger-api/internal/constant"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user/payload"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
 	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -24,12 +27,12 @@ func New(gormDB *gorm.DB) user.Repository {
 }
 
 func (r UserRepository) Create(ctx context.Context, user *model.User) (*model.User, error) {
-	result := r.DB.WithContext(ctx).Model(user).Clauses(clause.OnConflict{DoNothing: true}).Create(user)
+	result := postgres.Conn(ctx, r.DB).Model(user).Clauses(clause.OnConflict{DoNothing: true}).Create(user)
 	return user, result.Error
 }
 
 func (r UserRepository) Update(ctx context.Context, user *model.User) (*model.User, error) {
-	result := r.DB.WithContext(ctx).Model(user).Where("id = ?", user.ID).Updates(user)
+	result := postgres.Conn(ctx, r.DB).Model(user).Where("id = ?", user.ID).Updates(user)
 	return user, result.Error
 }
 
@@ -51,6 +54,19 @@ func (r UserRepository) GetByEmail(ctx context.Context, email string) (*model.Us
 	return user, result.Error
 }
 
+func (r UserRepository) GetByEmails(ctx context.Context, emails []string) ([]*model.User, error) {
+	var users []*model.User
+	if len(emails) == 0 {
+		return users, nil
+	}
+	lowered := make([]string, 0, len(emails))
+	for _, email := range emails {
+		lowered = append(lowered, strings.ToLower(email))
+	}
+	result := r.DB.WithContext(ctx).Table(constant.UserTable).Where("LOWER(email) IN ?", lowered).Find(&users)
+	return users, result.Error
+}
+
 func (r UserRepository) GetAll(ctx context.Context, pgn *pagination.Pagination, req *payload.GetAllRequest) ([]*model.User, error) {
 	var users []*model.User
 	result := r.DB.WithContext(ctx).Table(constant.UserTable)
@@ -59,7 +75,101 @@ func (r UserRepository) GetAll(ctx context.Context, pgn *pagination.Pagination,
 		escapedSearchTerm := strings.Replace(req.Search, "%", "\\%", -1)
 		result = result.Where("name ILIKE ?", "%"+escapedSearchTerm+"%")
 	}
+	if req.UserID != "" {
+		result = result.Where("id::text NOT IN (?)", r.DB.Table(constant.BlockTable).Select("blocker_id").
+			Where("blocked_id = ? AND deleted_at IS NULL", req.UserID))
+	}
 
-	result.Scopes(pgn.Paginate(result)).Find(&users)
+	scopes := []func(*gorm.DB) *gorm.DB{}
+	if req.UserID != "" {
+		scopes = append(scopes, contactsFirst(req.UserID))
+	}
+	result.Scopes(append(scopes, pgn.Paginate(result))...).Find(&users)
 	return users, result.Error
 }
+
+// Deleted accounts are still returned, so conversations with them can be rendered
+func (r UserRepository) GetByIdWithDeleted(ctx context.Context, id string) (*model.User, error) {
+	var user *model.User
+	result := r.DB.WithContext(ctx).Unscoped().Table(constant.UserTable).Where("id = ?", id).First(&user)
+	return user, result.Error
+}
+
+// Anonymize wipes the profile and credentials of a user, deletes the rows that only
+// matter to them and soft-deletes the account, all in one transaction
+func (r UserRepository) Anonymize(ctx context.Context, id string) error {
+	return postgres.Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table(constant.UserTable).Where("id = ? AND deleted_at IS NULL", id).Updates(map[string]interface{}{
+			"name":       model.DeletedAccountName,
+			"email":      "",
+			"password":   "",
+			"photo_url":  "",
+			"updated_at": time.Now(),
+			"deleted_at": time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("not found")
+		}
+		if err := tx.Unscoped().Where("user_id = ?", id).Delete(&model.Draft{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", id).Delete(&model.Contact{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("blocker_id = ?", id).Delete(&model.Block{}).Error; err != nil {
+			return err
+		}
+		// Nobody can start an e2e session with a deleted account anymore
+		devices := tx.Unscoped().Model(&model.Device{}).Select("id::text").Where("user_id = ?", id)
+		if err := tx.Unscoped().Where("device_id IN (?)", devices).Delete(&model.OneTimePrekey{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", id).Delete(&model.Device{}).Error
+	})
+}
+
+func (r UserRepository) CreateDeletion(ctx context.Context, deletion *model.AccountDeletion) (*model.AccountDeletion, error) {
+	result := postgres.Conn(ctx, r.DB).Create(deletion)
+	return deletion, result.Error
+}
+
+func (r UserRepository) UpdateDeletion(ctx context.Context, deletion *model.AccountDeletion) (*model.AccountDeletion, error) {
+	result := postgres.Conn(ctx, r.DB).Save(deletion)
+	return deletion, result.Error
+}
+
+func (r UserRepository) GetDeletionById(ctx context.Context, id string) (*model.AccountDeletion, error) {
+	var deletion *model.AccountDeletion
+	result := r.DB.WithContext(ctx).Table(constant.AccountDeletionTable).Where("id = ?", id).Limit(1).Find(&deletion)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return deletion, nil
+}
+
+// Deletions whose message purge is due at the given time and hasn't been cancelled
+func (r UserRepository) GetDueDeletions(ctx context.Context, at time.Time) ([]*model.AccountDeletion, error) {
+	var deletions []*model.AccountDeletion
+	result := r.DB.WithContext(ctx).Table(constant.AccountDeletionTable).
+		Where("purge_at <= ? AND purged_at IS NULL AND cancelled_at IS NULL", at).Order("purge_at ASC").Find(&deletions)
+	return deletions, result.Error
+}
+
+// Order the viewer's contacts before everyone else, the pagination sort applies within each group
+func contactsFirst(userId string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Select("*, id::text IN (SELECT contact_id FROM "+constant.ContactTable+" WHERE user_id = ? AND deleted_at IS NULL) AS is_contact", userId).
+			Order("is_contact DESC")
+	}
+}
+
+func (r UserRepository) UpdateLastSeen(ctx context.Context, id string, at time.Time) error {
+	result := r.DB.WithContext(ctx).Table(constant.UserTable).Where("id = ?", id).UpdateColumn("last_seen_at", at)
+	return result.Error
+}
@@ -531,3 +531,59 @@ func Test_UserRepository_GetAll(t *testing.T) {
 		assert.Equalf(t, tt.want, res, "Error on: GetAll(%v, %+v)", tt.args.ctx, tt.args.req)
 	}
 }
+
+func Test_UserRepository_Anonymize(t *testing.T) {
+	id := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	updateQuery := `UPDATE "users" SET "deleted_at"=$1,"email"=$2,"name"=$3,"password"=$4,"photo_url"=$5,"updated_at"=$6 WHERE id = $7 AND deleted_at IS NULL`
+
+	tests := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "Anonymize User Success",
+			rowsAffected: 1,
+			wantErr:      assert.NoError,
+		},
+		{
+			name:         "Anonymize Deleted User",
+			rowsAffected: 0,
+			wantErr:      assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := Setup()
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(updateQuery)).
+				WithArgs(AnyTime{}, "", model.DeletedAccountName, "", "", AnyTime{}, id).
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsAffected))
+			if tt.rowsAffected > 0 {
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "drafts" WHERE user_id = $1`)).
+					WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "contacts" WHERE user_id = $1`)).
+					WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "blocks" WHERE blocker_id = $1`)).
+					WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "one_time_prekeys" WHERE device_id IN (SELECT id::text FROM "devices" WHERE user_id = $1)`)).
+					WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 5))
+				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "devices" WHERE user_id = $1`)).
+					WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			r := &repo.UserRepository{
+				DB: db,
+			}
+
+			err := r.Anonymize(context.TODO(), id)
+			tt.wantErr(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,11 +2,16 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
 	"gitlab.com/raihanlh/messenger-api/config"
 	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user"
@@ -16,6 +21,23 @@ import (
 	"gitlab.com/raihanlh/messenger-api/pkg/logger"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// How long messages of a deleted account are kept when ACCOUNT_PURGE_GRACE_PERIOD isn't set
+const DefaultPurgeGracePeriod = 30 * 24 * time.Hour
+
+// Last seen is written at most this often per user, not on every request
+const LastSeenResolution = time.Minute
+
+// Last use of a session is written at most this often
+const SessionUsedResolution = time.Minute
+
+// Token lifetimes used when ACCESS_TOKEN_TTL, REFRESH_TOKEN_TTL or SESSION_IDLE_TIMEOUT isn't set
+const (
+	DefaultAccessTokenTTL     = 15 * time.Minute
+	DefaultRefreshTokenTTL    = 30 * 24 * time.Hour
+	DefaultSessionIdleTimeout = 7 * 24 * time.Hour
 )
 
 type UserUsecase struct {
@@ -36,10 +58,19 @@ func (u UserUsecase) Create(ctx context.Context, req *payload.CreateRequest) (*p
 		log.Error("Failed to hash password: ", zap.Error(err))
 		return nil, err
 	}
-	result, err := u.repositories.User.Create(ctx, &model.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: string(hashedPassword),
+	// Integrations learn about the account from the event, it's stored with the user
+	var result *model.User
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = u.repositories.User.Create(ctx, &model.User{
+			Name:     req.Name,
+			Email:    req.Email,
+			Password: string(hashedPassword),
+		})
+		if err != nil {
+			return err
+		}
+		return u.repositories.Outbox.Append(ctx, model.EventUserRegistered, &model.UserRegisteredEvent{UserID: result.ID, Name: result.Name})
 	})
 	if err != nil {
 		log.Error("Failed to create user: ", zap.Error(err))
@@ -55,13 +86,37 @@ func (u UserUsecase) Create(ctx context.Context, req *payload.CreateRequest) (*p
 func (u UserUsecase) Update(ctx context.Context, req *payload.UpdateRequest) (*payload.UpdateResponse, error) {
 	log := logger.GetLogger(ctx)
 
-	result, err := u.repositories.User.Update(ctx, &model.User{
+	if err := authorizeAccountChange(req.Requester, req.UserID, "update"); err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
 		Model: model.Model{
 			ID: req.UserID,
 		},
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
+		Name:  req.Name,
+		Email: req.Email,
+	}
+	// An empty password is left unchanged, Updates skips zero values
+	if req.Password != "" {
+		hashedPassword, err := utils.HashPassword(req.Password)
+		if err != nil {
+			log.Error("Failed to hash password: ", zap.Error(err))
+			return nil, err
+		}
+		user.Password = string(hashedPassword)
+	}
+
+	// A new password signs out every other session, whoever held the old one
+	var result *model.User
+	err := u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = u.repositories.User.Update(ctx, user)
+		if err != nil || req.Password == "" {
+			return err
+		}
+		_, err = u.repositories.Session.RevokeAllExcept(ctx, req.UserID, req.CurrentSessionID, time.Now())
+		return err
 	})
 	if err != nil {
 		log.Error("Failed to update user: ", zap.Error(err))
@@ -74,20 +129,248 @@ func (u UserUsecase) Update(ctx context.Context, req *payload.UpdateRequest) (*p
 	}, nil
 }
 
+// Delete anonymizes the account right away, which also revokes its credentials.
+// Purging the user's messages is optional and only happens after a grace period.
 func (u UserUsecase) Delete(ctx context.Context, req *payload.DeleteRequest) (*payload.DeleteResponse, error) {
 	log := logger.GetLogger(ctx)
 
-	err := u.repositories.User.Delete(ctx, req.UserID)
+	if err := authorizeAccountChange(req.Requester, req.UserID, "delete"); err != nil {
+		return nil, err
+	}
+
+	// The account, its sessions and the purge schedule change together, a failed purge
+	// schedule mustn't leave an anonymized account behind
+	now := time.Now()
+	var deletion *model.AccountDeletion
+	err := u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repositories.User.Anonymize(ctx, req.UserID); err != nil {
+			return err
+		}
+		if _, err := u.repositories.Session.RevokeAllExcept(ctx, req.UserID, "", now); err != nil {
+			return err
+		}
+		// Data exports of the account are deleted by its subscriber
+		if err := u.repositories.Outbox.Append(ctx, model.EventUserDeleted, &model.UserDeletedEvent{UserID: req.UserID}); err != nil {
+			return err
+		}
+		if !req.PurgeMessages {
+			return nil
+		}
+		purgeAt := now.Add(purgeGracePeriod())
+		var err error
+		deletion, err = u.repositories.User.CreateDeletion(ctx, &model.AccountDeletion{
+			UserID:      req.UserID,
+			PurgeAt:     &purgeAt,
+			CancelToken: newToken(),
+		})
+		return err
+	})
 	if err != nil {
 		log.Error("Failed to delete user: ", zap.Error(err))
 		return nil, err
 	}
 
-	return &payload.DeleteResponse{
+	res := &payload.DeleteResponse{
 		Message: "Delete user success",
+	}
+	if deletion == nil {
+		return res, nil
+	}
+	res.DeletionID = deletion.ID
+	res.CancelToken = deletion.CancelToken
+	res.PurgeAt = deletion.PurgeAt
+	return res, nil
+}
+
+// Users may only change their own account, admins may change any. Bots are managed by
+// their owner through the bot endpoints and may change none.
+func authorizeAccountChange(requester *model.User, userId string, action string) error {
+	if requester == nil {
+		return http_error.Unauthorized("Authentication required")
+	}
+	if requester.IsBot {
+		return http_error.Forbidden(fmt.Sprintf("Bots can't %s user accounts", action))
+	}
+	if requester.ID != userId && !requester.IsAdmin() {
+		return http_error.Forbidden(fmt.Sprintf("Only admins can %s another user", action))
+	}
+	return nil
+}
+
+// UpdateRole changes the role of another user, the route only lets admins through. Admins
+// can't change their own role so there is always one left.
+func (u UserUsecase) UpdateRole(ctx context.Context, req *payload.UpdateRoleRequest) (*payload.UpdateRoleResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if req.Requester != nil && req.Requester.ID == req.UserID {
+		return nil, http_error.Forbidden("Admins can't change their own role")
+	}
+
+	user, err := u.repositories.User.GetById(ctx, req.UserID)
+	if err != nil {
+		log.Error("Failed to get user: ", zap.Error(err))
+		return nil, http_error.RecordNotFound("user")
+	}
+	if user.IsBot {
+		return nil, http_error.BadRequest(errors.New("bots can't be given a role"))
+	}
+
+	_, err = u.repositories.User.Update(ctx, &model.User{
+		Model: model.Model{
+			ID: req.UserID,
+		},
+		Role: req.Role,
+	})
+	if err != nil {
+		log.Error("Failed to update user role: ", zap.Error(err))
+		return nil, err
+	}
+	user.Role = req.Role
+
+	return &payload.UpdateRoleResponse{
+		User:    user,
+		Message: "Update role success",
 	}, nil
 }
 
+// RevokeSessions signs a user out everywhere, for instance when the account looks compromised.
+// The route lets moderators through, only admins may sign out an admin.
+func (u UserUsecase) RevokeSessions(ctx context.Context, req *payload.RevokeSessionsRequest) (*payload.RevokeSessionsResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	user, err := u.repositories.User.GetById(ctx, req.UserID)
+	if err != nil {
+		log.Error("Failed to get user: ", zap.Error(err))
+		return nil, http_error.RecordNotFound("user")
+	}
+	if user.IsAdmin() && (req.Requester == nil || !req.Requester.IsAdmin()) {
+		return nil, http_error.Forbidden("Only admins can revoke the sessions of an admin")
+	}
+
+	revoked, err := u.repositories.Session.RevokeAllExcept(ctx, req.UserID, "", time.Now())
+	if err != nil {
+		log.Error("Failed to revoke sessions: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RevokeSessionsResponse{
+		Revoked: revoked,
+		Message: "Revoke sessions success",
+	}, nil
+}
+
+// CancelPurge keeps the messages of a deleted account, the account itself stays anonymized
+func (u UserUsecase) CancelPurge(ctx context.Context, req *payload.CancelPurgeRequest) (*payload.CancelPurgeResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	deletion, err := u.repositories.User.GetDeletionById(ctx, req.DeletionID)
+	if err != nil {
+		log.Error("Failed to get account deletion: ", zap.Error(err))
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(deletion.CancelToken), []byte(req.Token)) != 1 {
+		return nil, errors.New("not found")
+	}
+	if !deletion.IsPurgePending() {
+		return nil, http_error.Expired("message purge can no longer be cancelled")
+	}
+
+	now := time.Now()
+	deletion.CancelledAt = &now
+	if _, err := u.repositories.User.UpdateDeletion(ctx, deletion); err != nil {
+		log.Error("Failed to cancel message purge: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.CancelPurgeResponse{
+		Message: "Message purge cancelled",
+	}, nil
+}
+
+// MarkSeen records that the user is online
+func (u UserUsecase) MarkSeen(ctx context.Context, user *model.User) error {
+	now := time.Now()
+	if user.LastSeenAt != nil && now.Sub(*user.LastSeenAt) < LastSeenResolution {
+		return nil
+	}
+	if err := u.repositories.User.UpdateLastSeen(ctx, user.ID, now); err != nil {
+		return err
+	}
+	user.LastSeenAt = &now
+	return nil
+}
+
+// PurgeDeletedAccounts erases the messages of deleted accounts whose grace period is over
+func (u UserUsecase) PurgeDeletedAccounts(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+
+	deletions, err := u.repositories.User.GetDueDeletions(ctx, time.Now())
+	if err != nil {
+		log.Error("Failed to get due account deletions: ", zap.Error(err))
+		return err
+	}
+	for _, deletion := range deletions {
+		var purged int64
+		err := u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			purged, err = u.repositories.Message.PurgeBySenderId(ctx, deletion.UserID)
+			if err != nil {
+				return err
+			}
+			if err := u.repositories.Inbox.RefreshByUserId(ctx, deletion.UserID); err != nil {
+				return err
+			}
+			now := time.Now()
+			deletion.PurgedAt = &now
+			_, err = u.repositories.User.UpdateDeletion(ctx, deletion)
+			return err
+		})
+		if err != nil {
+			log.Error("Failed to purge messages: ", zap.Error(err))
+			return err
+		}
+		log.Info("Purged messages of deleted account", zap.String("user_id", deletion.UserID), zap.Int64("messages", purged))
+	}
+	return nil
+}
+
+func purgeGracePeriod() time.Duration {
+	conf := config.New()
+	if conf.AccountPurgeGracePeriod <= 0 {
+		return DefaultPurgeGracePeriod
+	}
+	return conf.AccountPurgeGracePeriod
+}
+
+// Lifetimes of access tokens, of a session and how long it may be idle
+func tokenLifetimes() (time.Duration, time.Duration, time.Duration) {
+	conf := config.New()
+	access, refresh, idle := conf.AccessTokenTTL, conf.RefreshTokenTTL, conf.SessionIdleTimeout
+	if access <= 0 {
+		access = DefaultAccessTokenTTL
+	}
+	if refresh <= 0 {
+		refresh = DefaultRefreshTokenTTL
+	}
+	if idle <= 0 {
+		idle = DefaultSessionIdleTimeout
+	}
+	return access, refresh, idle
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
 func (u UserUsecase) GetById(ctx context.Context, req *payload.GetByIdRequest) (*payload.GetByIdResponse, error) {
 	log := logger.GetLogger(ctx)
 
@@ -106,6 +389,17 @@ func (u UserUsecase) GetById(ctx context.Context, req *payload.GetByIdRequest) (
 func (u UserUsecase) GetAll(ctx context.Context, req *payload.GetAllRequest) (*payload.GetAllResponse, error) {
 	log := logger.GetLogger(ctx)
 
+	if req.CallerIsBot {
+		bot, err := u.repositories.Bot.GetByUserId(ctx, req.UserID)
+		if err != nil && err.Error() != "not found" {
+			log.Error("Failed to get bot: ", zap.Error(err))
+			return nil, err
+		}
+		if bot == nil || !bot.CanSearchDirectory {
+			return nil, http_error.Forbidden("Bot isn't allowed to search the user directory")
+		}
+	}
+
 	pgn := &req.Pagination
 	users, err := u.repositories.User.GetAll(ctx, pgn, req)
 	if err != nil {
@@ -122,30 +416,60 @@ func (u UserUsecase) GetAll(ctx context.Context, req *payload.GetAllRequest) (*p
 
 func (u UserUsecase) GetByToken(ctx context.Context, req *payload.GetByTokenRequest) (*payload.GetByTokenResponse, error) {
 	log := logger.GetLogger(ctx)
-	conf := config.New()
 
-	tokenStr := req.Token
-	claims := &utils.Claims{}
-	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(conf.Secret), nil
-	})
+	claims, err := parseToken(ctx, req.Token)
 	if err != nil {
-		log.Error("Failed to parse jwt token: ", zap.Error(err))
 		return nil, err
 	}
-	if !token.Valid {
-		return nil, errors.New("Unauthorized")
+
+	// Logged out tokens are rejected until they expire
+	revoked, err := u.repositories.Revocation.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		log.Error("Failed to check token revocation: ", zap.Error(err))
+		return nil, err
+	}
+	if revoked {
+		return nil, http_error.Unauthorized("Token revoked")
+	}
+
+	// Tokens are only valid while their session is, so signing a device out takes effect immediately
+	if claims.SessionID == "" {
+		return nil, http_error.Unauthorized("Session expired")
+	}
+	session, err := u.repositories.Session.GetById(ctx, claims.SessionID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, http_error.Unauthorized("Session expired")
+		}
+		log.Error("Failed to get session: ", zap.Error(err))
+		return nil, err
+	}
+	now := time.Now()
+	if session.UserID != claims.UserID || !session.IsActive(now) {
+		return nil, http_error.Unauthorized("Session expired")
 	}
 
 	user, err := u.repositories.User.GetById(ctx, claims.UserID)
 	if err != nil {
+		// The account was deleted while the token was still valid
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, http_error.Unauthorized("Session expired")
+		}
 		log.Error("Failed to get user by id: ", zap.Error(err))
 		return nil, err
 	}
 
+	// Only bookkeeping, it mustn't fail the request
+	if now.Sub(session.LastUsedAt) >= SessionUsedResolution {
+		if err := u.repositories.Session.Touch(ctx, session.ID, now); err != nil {
+			log.Error("Failed to record session use: ", zap.Error(err))
+		}
+	}
+
 	return &payload.GetByTokenResponse{
-		User:    user,
-		Message: "Successfully get user by token",
+		User:      user,
+		SessionID: session.ID,
+		Message:   "Successfully get user by token",
 	}, nil
 }
 
@@ -157,6 +481,9 @@ func (u UserUsecase) Login(ctx context.Context, req *payload.LoginRequest) (*pay
 		log.Error("Failed to log in: ", zap.Error(err))
 		return nil, err
 	}
+	if user.IsBot {
+		return nil, http_error.Forbidden("Bots authenticate with API keys")
+	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
@@ -164,15 +491,217 @@ func (u UserUsecase) Login(ctx context.Context, req *payload.LoginRequest) (*pay
 		return nil, err
 	}
 
-	exp := time.Now().Add(time.Hour * 72)
-	token, err := utils.GenerateToken(req.Email, user.ID, exp)
+	now := time.Now()
+	_, refreshTTL, _ := tokenLifetimes()
+	session, err := u.repositories.Session.Create(ctx, &model.Session{
+		UserID:     user.ID,
+		DeviceName: req.DeviceName,
+		UserAgent:  req.UserAgent,
+		IP:         req.IP,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(refreshTTL),
+	})
+	if err != nil {
+		log.Error("Failed to create session: ", zap.Error(err))
+		return nil, err
+	}
+	tokens, err := u.issueTokens(ctx, user, session, now)
 	if err != nil {
-		log.Error("Failed to generate token: ", zap.Error(err))
 		return nil, err
 	}
 	return &payload.LoginResponse{
-		Token:   token,
+		Tokens:  *tokens,
 		Message: "Login success",
-		Exp:     exp,
 	}, nil
 }
+
+// Refresh exchanges a refresh token for new tokens. Each refresh token works once, using one
+// again means it was stolen, so the session it belongs to is revoked with all its tokens.
+func (u UserUsecase) Refresh(ctx context.Context, req *payload.RefreshRequest) (*payload.RefreshResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if req.RefreshToken == "" {
+		return nil, http_error.Unauthorized("Invalid refresh token")
+	}
+	refreshToken, err := u.repositories.Session.GetRefreshTokenByHash(ctx, hashToken(req.RefreshToken))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, http_error.Unauthorized("Invalid refresh token")
+		}
+		log.Error("Failed to get refresh token: ", zap.Error(err))
+		return nil, err
+	}
+	session, err := u.repositories.Session.GetById(ctx, refreshToken.SessionID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, http_error.Unauthorized("Session expired")
+		}
+		log.Error("Failed to get session: ", zap.Error(err))
+		return nil, err
+	}
+	now := time.Now()
+	if refreshToken.UsedAt != nil {
+		return nil, u.revokeReusedSession(ctx, session, now)
+	}
+	_, _, idleTimeout := tokenLifetimes()
+	if !session.IsActive(now) || !now.Before(refreshToken.ExpiresAt) || now.Sub(session.LastUsedAt) > idleTimeout {
+		return nil, http_error.Unauthorized("Session expired")
+	}
+	user, err := u.repositories.User.GetById(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, http_error.Unauthorized("Session expired")
+		}
+		log.Error("Failed to get user by id: ", zap.Error(err))
+		return nil, err
+	}
+
+	var tokens *payload.Tokens
+	reused := false
+	err = u.repositories.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		used, err := u.repositories.Session.UseRefreshToken(ctx, refreshToken.ID, now)
+		if err != nil {
+			return err
+		}
+		// A concurrent refresh with the same token got there first
+		if !used {
+			reused = true
+			return nil
+		}
+		if err := u.repositories.Session.Touch(ctx, session.ID, now); err != nil {
+			return err
+		}
+		tokens, err = u.issueTokens(ctx, user, session, now)
+		return err
+	})
+	if err != nil {
+		log.Error("Failed to refresh tokens: ", zap.Error(err))
+		return nil, err
+	}
+	if reused {
+		return nil, u.revokeReusedSession(ctx, session, now)
+	}
+
+	return &payload.RefreshResponse{
+		Tokens:  *tokens,
+		Message: "Refresh token success",
+	}, nil
+}
+
+// Logout revokes the access token and ends its session, so the refresh token stops working too
+func (u UserUsecase) Logout(ctx context.Context, req *payload.LogoutRequest) (*payload.LogoutResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if req.Token == "" && req.RefreshToken == "" {
+		return nil, http_error.Unauthorized("No session to log out of")
+	}
+	if req.Token == "" {
+		return u.logoutByRefreshToken(ctx, req.RefreshToken)
+	}
+	claims, err := parseToken(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repositories.Revocation.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		log.Error("Failed to revoke token: ", zap.Error(err))
+		return nil, err
+	}
+	if err := u.repositories.Session.Revoke(ctx, claims.SessionID, time.Now()); err != nil {
+		log.Error("Failed to revoke session: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.LogoutResponse{
+		Message: "Logout success",
+	}, nil
+}
+
+// Without a valid access token the session is found from its refresh token, otherwise the
+// refresh token would keep working after logging out
+func (u UserUsecase) logoutByRefreshToken(ctx context.Context, token string) (*payload.LogoutResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	refreshToken, err := u.repositories.Session.GetRefreshTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		if err.Error() == "not found" {
+			return &payload.LogoutResponse{
+				Message: "Already logged out",
+			}, nil
+		}
+		log.Error("Failed to get refresh token: ", zap.Error(err))
+		return nil, err
+	}
+	if err := u.repositories.Session.Revoke(ctx, refreshToken.SessionID, time.Now()); err != nil {
+		log.Error("Failed to revoke session: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.LogoutResponse{
+		Message: "Logout success",
+	}, nil
+}
+
+func (u UserUsecase) revokeReusedSession(ctx context.Context, session *model.Session, at time.Time) error {
+	log := logger.GetLogger(ctx)
+
+	log.Warn("Refresh token reused, revoking session", zap.String("session_id", session.ID), zap.String("user_id", session.UserID))
+	if err := u.repositories.Session.Revoke(ctx, session.ID, at); err != nil {
+		log.Error("Failed to revoke session: ", zap.Error(err))
+		return err
+	}
+	return http_error.Unauthorized("Refresh token was already used, the session has been revoked")
+}
+
+// Store a new refresh token of the session and sign an access token for it. Neither outlives
+// the session.
+func (u UserUsecase) issueTokens(ctx context.Context, user *model.User, session *model.Session, now time.Time) (*payload.Tokens, error) {
+	log := logger.GetLogger(ctx)
+
+	refreshToken := newToken()
+	_, err := u.repositories.Session.CreateRefreshToken(ctx, &model.RefreshToken{
+		SessionID: session.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		log.Error("Failed to create refresh token: ", zap.Error(err))
+		return nil, err
+	}
+	accessTTL, _, _ := tokenLifetimes()
+	exp := now.Add(accessTTL)
+	if exp.After(session.ExpiresAt) {
+		exp = session.ExpiresAt
+	}
+	token, err := utils.GenerateToken(user.Email, user.ID, user.Role, session.ID, exp)
+	if err != nil {
+		log.Error("Failed to generate token: ", zap.Error(err))
+		return nil, err
+	}
+	return &payload.Tokens{
+		Token:        token,
+		Exp:          exp,
+		RefreshToken: refreshToken,
+		RefreshExp:   session.ExpiresAt,
+		SessionID:    session.ID,
+	}, nil
+}
+
+// Check the signature and registered claims of an access token. Expired or malformed tokens are
+// unauthorized, so clients know to refresh.
+func parseToken(ctx context.Context, tokenStr string) (*utils.Claims, error) {
+	log := logger.GetLogger(ctx)
+	conf := config.New()
+
+	claims := &utils.Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(conf.Secret), nil
+	})
+	if err != nil {
+		log.Error("Failed to parse jwt token: ", zap.Error(err))
+		return nil, http_error.Unauthorized("Invalid token")
+	}
+	if !token.Valid || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, http_error.Unauthorized("Invalid token")
+	}
+	return claims, nil
+}
@@ -2,16 +2,25 @@ package usecase_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
-	"gitlab.com/raihanlh/messenger-api/config"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
 	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
 	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_bot "gitlab.com/raihanlh/messenger-api/testing/mocks/bot"
+	mock_inbox "gitlab.com/raihanlh/messenger-api/testing/mocks/inbox"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	mock_outbox "gitlab.com/raihanlh/messenger-api/testing/mocks/outbox"
+	mock_revocation "gitlab.com/raihanlh/messenger-api/testing/mocks/revocation"
+	mock_session "gitlab.com/raihanlh/messenger-api/testing/mocks/session"
 	mock_user "gitlab.com/raihanlh/messenger-api/testing/mocks/user"
 
-	"github.com/golang-jwt/jwt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
@@ -19,6 +28,8 @@ import (
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user/usecase"
 	"gitlab.com/raihanlh/messenger-api/internal/model"
 	"gitlab.com/raihanlh/messenger-api/internal/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 func Test_UserUsecase_Create(t *testing.T) {
@@ -69,9 +80,13 @@ func Test_UserUsecase_Create(t *testing.T) {
 				Email:    tt.args.req.Email,
 				Password: tt.args.req.Password,
 			})).Return(tt.wantRepoResp, tt.wantErrRepoResp).AnyTimes()
+			outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+			outboxRepoMock.EXPECT().Append(ctx, model.EventUserRegistered, &model.UserRegisteredEvent{UserID: testUser.ID, Name: testUser.Name}).Return(nil)
 
 			userUsecase := usecase.New(&dependency.Repositories{
-				User: userRepoMock,
+				Transactor: helper.NoTransaction{},
+				User:       userRepoMock,
+				Outbox:     outboxRepoMock,
 			})
 
 			res, err := userUsecase.Create(ctx, tt.args.req)
@@ -97,6 +112,10 @@ func Test_UserUsecase_Update(t *testing.T) {
 	type args struct {
 		req *payload.UpdateRequest
 	}
+	admin := &model.User{Model: model.Model{ID: "0b8a1f4e-d76e-401a-a3ba-7a03352812c2"}, Role: model.RoleAdmin}
+	other := &model.User{Model: model.Model{ID: "c4e7a2d9-d76e-401a-a3ba-7a03352812c2"}, Role: model.RoleModerator}
+	bot := &model.User{Model: model.Model{ID: testUser.ID}, IsBot: true}
+
 	tests := []struct {
 		name            string
 		args            args
@@ -104,14 +123,18 @@ func Test_UserUsecase_Update(t *testing.T) {
 		wantErrRepoResp error
 		want            *payload.UpdateResponse
 		wantErr         assert.ErrorAssertionFunc
+		wantHTTPCode    int
 	}{{
 		name: "Update User Usecase Success",
 		args: args{
 			req: &payload.UpdateRequest{
-				UserID:   testUser.ID,
-				Name:     testUser.Name,
-				Email:    testUser.Email,
-				Password: testUser.Password,
+				UserID:    testUser.ID,
+				Name:      testUser.Name,
+				Email:     testUser.Email,
+				Password:  testUser.Password,
+				Requester: testUser,
+				// The other sessions are revoked once the password changes
+				CurrentSessionID: "b2f1c0de-d76e-401a-a3ba-7a03352812c2",
 			},
 		},
 		wantRepoResp:    testUser,
@@ -121,23 +144,80 @@ func Test_UserUsecase_Update(t *testing.T) {
 			Message: "Update user success",
 		},
 		wantErr: assert.NoError,
+	}, {
+		name: "Admin updates another user",
+		args: args{
+			req: &payload.UpdateRequest{
+				UserID:    testUser.ID,
+				Name:      testUser.Name,
+				Requester: admin,
+			},
+		},
+		wantRepoResp: testUser,
+		want: &payload.UpdateResponse{
+			User:    testUser,
+			Message: "Update user success",
+		},
+		wantErr: assert.NoError,
+	}, {
+		name: "Non-admin updates another user",
+		args: args{
+			req: &payload.UpdateRequest{
+				UserID:    testUser.ID,
+				Name:      testUser.Name,
+				Requester: other,
+			},
+		},
+		wantErr:      assert.Error,
+		wantHTTPCode: http.StatusForbidden,
+	}, {
+		name: "Bot updates its own account",
+		args: args{
+			req: &payload.UpdateRequest{
+				UserID:    testUser.ID,
+				Name:      testUser.Name,
+				Requester: bot,
+			},
+		},
+		wantErr:      assert.Error,
+		wantHTTPCode: http.StatusForbidden,
+	}, {
+		name: "Unauthenticated",
+		args: args{
+			req: &payload.UpdateRequest{
+				UserID: testUser.ID,
+				Name:   testUser.Name,
+			},
+		},
+		wantErr:      assert.Error,
+		wantHTTPCode: http.StatusUnauthorized,
 	}}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepoMock := mock_user.NewMockRepository(ctrl)
 			ctx := context.TODO()
-			userRepoMock.EXPECT().Update(ctx, &model.User{
-				Model: model.Model{
-					ID: tt.args.req.UserID,
-				},
-				Name:     tt.args.req.Name,
-				Email:    tt.args.req.Email,
-				Password: tt.args.req.Password,
-			}).Return(tt.wantRepoResp, tt.wantErrRepoResp).AnyTimes()
+			userRepoMock.EXPECT().Update(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, user *model.User) (*model.User, error) {
+					assert.Equal(t, tt.args.req.UserID, user.ID)
+					assert.Equal(t, tt.args.req.Name, user.Name)
+					assert.Equal(t, tt.args.req.Email, user.Email)
+					if tt.args.req.Password == "" {
+						assert.Empty(t, user.Password, "an empty password is left unchanged")
+					} else {
+						assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(tt.args.req.Password)), "the password is stored hashed")
+					}
+					return tt.wantRepoResp, tt.wantErrRepoResp
+				}).AnyTimes()
+			sessionRepoMock := mock_session.NewMockRepository(ctrl)
+			if tt.args.req.Password != "" {
+				sessionRepoMock.EXPECT().RevokeAllExcept(ctx, tt.args.req.UserID, tt.args.req.CurrentSessionID, gomock.Any()).Return(int64(2), nil)
+			}
 
 			userUsecase := usecase.New(&dependency.Repositories{
-				User: userRepoMock,
+				Transactor: helper.NoTransaction{},
+				User:       userRepoMock,
+				Session:    sessionRepoMock,
 			})
 
 			res, err := userUsecase.Update(ctx, tt.args.req)
@@ -145,6 +225,11 @@ func Test_UserUsecase_Update(t *testing.T) {
 			if err == nil {
 				assert.Equalf(t, res, tt.want, "Update User")
 			}
+			if tt.wantHTTPCode != 0 {
+				if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+					assert.Equal(t, tt.wantHTTPCode, httpErr.HTTPCode)
+				}
+			}
 		})
 	}
 }
@@ -156,6 +241,12 @@ func Test_UserUsecase_Delete(t *testing.T) {
 	type args struct {
 		req *payload.DeleteRequest
 	}
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	self := &model.User{Model: model.Model{ID: userId}}
+	admin := &model.User{Model: model.Model{ID: "0b8a1f4e-d76e-401a-a3ba-7a03352812c2"}, Role: model.RoleAdmin}
+	other := &model.User{Model: model.Model{ID: "c4e7a2d9-d76e-401a-a3ba-7a03352812c2"}, Role: model.RoleUser}
+	bot := &model.User{Model: model.Model{ID: userId}, IsBot: true}
+
 	tests := []struct {
 		name            string
 		args            args
@@ -163,11 +254,13 @@ func Test_UserUsecase_Delete(t *testing.T) {
 		wantErrRepoResp error
 		want            *payload.DeleteResponse
 		wantErr         assert.ErrorAssertionFunc
+		wantHTTPCode    int
 	}{{
 		name: "Delete User Usecase Success",
 		args: args{
 			req: &payload.DeleteRequest{
-				UserID: "6fd33930-d76e-401a-a3ba-7a03352812c2",
+				UserID:    userId,
+				Requester: self,
 			},
 		},
 		wantErrRepoResp: nil,
@@ -175,16 +268,67 @@ func Test_UserUsecase_Delete(t *testing.T) {
 			Message: "Delete user success",
 		},
 		wantErr: assert.NoError,
+	}, {
+		name: "Admin deletes another user",
+		args: args{
+			req: &payload.DeleteRequest{
+				UserID:    userId,
+				Requester: admin,
+			},
+		},
+		want: &payload.DeleteResponse{
+			Message: "Delete user success",
+		},
+		wantErr: assert.NoError,
+	}, {
+		name: "Non-admin deletes another user",
+		args: args{
+			req: &payload.DeleteRequest{
+				UserID:    userId,
+				Requester: other,
+			},
+		},
+		wantErr:      assert.Error,
+		wantHTTPCode: http.StatusForbidden,
+	}, {
+		name: "Bot deletes its own account",
+		args: args{
+			req: &payload.DeleteRequest{
+				UserID:    userId,
+				Requester: bot,
+			},
+		},
+		wantErr:      assert.Error,
+		wantHTTPCode: http.StatusForbidden,
+	}, {
+		name: "Unauthenticated",
+		args: args{
+			req: &payload.DeleteRequest{
+				UserID: userId,
+			},
+		},
+		wantErr:      assert.Error,
+		wantHTTPCode: http.StatusUnauthorized,
 	}}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepoMock := mock_user.NewMockRepository(ctrl)
+			sessionRepoMock := mock_session.NewMockRepository(ctrl)
+			outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
 			ctx := context.TODO()
-			userRepoMock.EXPECT().Delete(ctx, tt.args.req.UserID).Return(tt.wantErrRepoResp).AnyTimes()
+			if tt.wantHTTPCode == 0 {
+				userRepoMock.EXPECT().Anonymize(ctx, tt.args.req.UserID).Return(tt.wantErrRepoResp)
+				// Deleting the account signs it out everywhere
+				sessionRepoMock.EXPECT().RevokeAllExcept(ctx, tt.args.req.UserID, "", gomock.Any()).Return(int64(1), nil)
+				outboxRepoMock.EXPECT().Append(ctx, model.EventUserDeleted, &model.UserDeletedEvent{UserID: tt.args.req.UserID}).Return(nil)
+			}
 
 			userUsecase := usecase.New(&dependency.Repositories{
-				User: userRepoMock,
+				Transactor: helper.NoTransaction{},
+				User:       userRepoMock,
+				Session:    sessionRepoMock,
+				Outbox:     outboxRepoMock,
 			})
 
 			res, err := userUsecase.Delete(ctx, tt.args.req)
@@ -192,10 +336,311 @@ func Test_UserUsecase_Delete(t *testing.T) {
 			if err == nil {
 				assert.Equalf(t, res, tt.want, "Delete User")
 			}
+			if tt.wantHTTPCode != 0 {
+				if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+					assert.Equal(t, tt.wantHTTPCode, httpErr.HTTPCode)
+				}
+			}
+		})
+	}
+}
+
+func Test_UserUsecase_UpdateRole(t *testing.T) {
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	admin := &model.User{Model: model.Model{ID: "0b8a1f4e-d76e-401a-a3ba-7a03352812c2"}, Role: model.RoleAdmin}
+
+	tests := []struct {
+		name         string
+		userId       string
+		target       *model.User
+		wantHTTPCode int
+	}{
+		{
+			name:   "Admin promotes a user",
+			userId: userId,
+			target: &model.User{Model: model.Model{ID: userId}, Role: model.RoleUser},
+		},
+		{
+			name:         "Admin changes their own role",
+			userId:       admin.ID,
+			wantHTTPCode: http.StatusForbidden,
+		},
+		{
+			name:         "Unknown user",
+			userId:       userId,
+			wantHTTPCode: http.StatusNotFound,
+		},
+		{
+			name:         "Bot",
+			userId:       userId,
+			target:       &model.User{Model: model.Model{ID: userId}, IsBot: true},
+			wantHTTPCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			if tt.userId != admin.ID {
+				if tt.target == nil {
+					userRepoMock.EXPECT().GetById(ctx, tt.userId).Return(nil, errors.New("record not found"))
+				} else {
+					userRepoMock.EXPECT().GetById(ctx, tt.userId).Return(tt.target, nil)
+				}
+			}
+			if tt.wantHTTPCode == 0 {
+				update := &model.User{Model: model.Model{ID: tt.userId}, Role: model.RoleModerator}
+				userRepoMock.EXPECT().Update(ctx, update).Return(update, nil)
+			}
+
+			userUsecase := usecase.New(&dependency.Repositories{
+				User: userRepoMock,
+			})
+			res, err := userUsecase.UpdateRole(ctx, &payload.UpdateRoleRequest{
+				UserID:    tt.userId,
+				Role:      model.RoleModerator,
+				Requester: admin,
+			})
+			if tt.wantHTTPCode == 0 {
+				if assert.NoError(t, err) {
+					assert.Equal(t, model.RoleModerator, res.User.Role)
+				}
+				return
+			}
+			if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+				assert.Equal(t, tt.wantHTTPCode, httpErr.HTTPCode)
+			}
+		})
+	}
+}
+
+func Test_UserUsecase_RevokeSessions(t *testing.T) {
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	admin := &model.User{Model: model.Model{ID: "0b8a1f4e-d76e-401a-a3ba-7a03352812c2"}, Role: model.RoleAdmin}
+	moderator := &model.User{Model: model.Model{ID: "c4e7a2d9-d76e-401a-a3ba-7a03352812c2"}, Role: model.RoleModerator}
+
+	tests := []struct {
+		name         string
+		target       *model.User
+		requester    *model.User
+		wantHTTPCode int
+	}{
+		{
+			name:      "Moderator signs out a user",
+			target:    &model.User{Model: model.Model{ID: userId}},
+			requester: moderator,
+		},
+		{
+			name:      "Admin signs out an admin",
+			target:    &model.User{Model: model.Model{ID: userId}, Role: model.RoleAdmin},
+			requester: admin,
+		},
+		{
+			name:         "Moderator signs out an admin",
+			target:       &model.User{Model: model.Model{ID: userId}, Role: model.RoleAdmin},
+			requester:    moderator,
+			wantHTTPCode: http.StatusForbidden,
+		},
+		{
+			name:         "Unknown user",
+			requester:    moderator,
+			wantHTTPCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			sessionRepoMock := mock_session.NewMockRepository(ctrl)
+			if tt.target == nil {
+				userRepoMock.EXPECT().GetById(ctx, userId).Return(nil, errors.New("record not found"))
+			} else {
+				userRepoMock.EXPECT().GetById(ctx, userId).Return(tt.target, nil)
+			}
+			if tt.wantHTTPCode == 0 {
+				sessionRepoMock.EXPECT().RevokeAllExcept(ctx, userId, "", gomock.Any()).Return(int64(2), nil)
+			}
+
+			userUsecase := usecase.New(&dependency.Repositories{
+				User:    userRepoMock,
+				Session: sessionRepoMock,
+			})
+			res, err := userUsecase.RevokeSessions(ctx, &payload.RevokeSessionsRequest{
+				UserID:    userId,
+				Requester: tt.requester,
+			})
+			if tt.wantHTTPCode == 0 {
+				if assert.NoError(t, err) {
+					assert.Equal(t, int64(2), res.Revoked)
+				}
+				return
+			}
+			if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+				assert.Equal(t, tt.wantHTTPCode, httpErr.HTTPCode)
+			}
+		})
+	}
+}
+
+func Test_UserUsecase_Delete_PurgeMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().Anonymize(ctx, userId).Return(nil)
+	userRepoMock.EXPECT().CreateDeletion(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, deletion *model.AccountDeletion) (*model.AccountDeletion, error) {
+			deletion.ID = "d1"
+			return deletion, nil
+		})
+	sessionRepoMock := mock_session.NewMockRepository(ctrl)
+	sessionRepoMock.EXPECT().RevokeAllExcept(ctx, userId, "", gomock.Any()).Return(int64(2), nil)
+	outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+	outboxRepoMock.EXPECT().Append(ctx, model.EventUserDeleted, gomock.Any()).Return(nil)
+
+	userUsecase := usecase.New(&dependency.Repositories{
+		Transactor: helper.NoTransaction{},
+		User:       userRepoMock,
+		Session:    sessionRepoMock,
+		Outbox:     outboxRepoMock,
+	})
+
+	res, err := userUsecase.Delete(ctx, &payload.DeleteRequest{
+		UserID:        userId,
+		PurgeMessages: true,
+		Requester:     &model.User{Model: model.Model{ID: userId}},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "d1", res.DeletionID)
+		assert.NotEmpty(t, res.CancelToken)
+		if assert.NotNil(t, res.PurgeAt) {
+			assert.WithinDuration(t, time.Now().Add(usecase.DefaultPurgeGracePeriod), *res.PurgeAt, time.Minute)
+		}
+	}
+}
+
+// The whole deletion is one transaction, a purge that can't be scheduled fails it
+func Test_UserUsecase_Delete_PurgeScheduleFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	userId := "6fd33930-d76e-401a-a3ba-7a03352812c2"
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().Anonymize(ctx, userId).Return(nil)
+	userRepoMock.EXPECT().CreateDeletion(ctx, gomock.Any()).Return(nil, errors.New("insert failed"))
+	sessionRepoMock := mock_session.NewMockRepository(ctrl)
+	sessionRepoMock.EXPECT().RevokeAllExcept(ctx, userId, "", gomock.Any()).Return(int64(1), nil)
+	outboxRepoMock := mock_outbox.NewMockRepository(ctrl)
+	outboxRepoMock.EXPECT().Append(ctx, model.EventUserDeleted, gomock.Any()).Return(nil)
+
+	userUsecase := usecase.New(&dependency.Repositories{
+		Transactor: helper.NoTransaction{},
+		User:       userRepoMock,
+		Session:    sessionRepoMock,
+		Outbox:     outboxRepoMock,
+	})
+
+	res, err := userUsecase.Delete(ctx, &payload.DeleteRequest{
+		UserID:        userId,
+		PurgeMessages: true,
+		Requester:     &model.User{Model: model.Model{ID: userId}},
+	})
+	assert.EqualError(t, err, "insert failed")
+	assert.Nil(t, res)
+}
+
+func Test_UserUsecase_CancelPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	purgeAt := time.Now().Add(time.Hour)
+	purgedAt := time.Now()
+	tests := []struct {
+		name     string
+		deletion *model.AccountDeletion
+		token    string
+		wantErr  string
+	}{
+		{
+			name:     "Cancel pending purge",
+			deletion: &model.AccountDeletion{Model: model.Model{ID: "d1"}, PurgeAt: &purgeAt, CancelToken: "secret"},
+			token:    "secret",
+		},
+		{
+			name:     "Wrong token",
+			deletion: &model.AccountDeletion{Model: model.Model{ID: "d1"}, PurgeAt: &purgeAt, CancelToken: "secret"},
+			token:    "guess",
+			wantErr:  "not found",
+		},
+		{
+			name:     "Already purged",
+			deletion: &model.AccountDeletion{Model: model.Model{ID: "d1"}, PurgeAt: &purgeAt, PurgedAt: &purgedAt, CancelToken: "secret"},
+			token:    "secret",
+			wantErr:  "message purge can no longer be cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			userRepoMock.EXPECT().GetDeletionById(ctx, "d1").Return(tt.deletion, nil)
+			if tt.wantErr == "" {
+				userRepoMock.EXPECT().UpdateDeletion(ctx, tt.deletion).Return(tt.deletion, nil)
+			}
+
+			userUsecase := usecase.New(&dependency.Repositories{
+				User: userRepoMock,
+			})
+
+			_, err := userUsecase.CancelPurge(ctx, &payload.CancelPurgeRequest{DeletionID: "d1", Token: tt.token})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.NotNil(t, tt.deletion.CancelledAt)
+			}
 		})
 	}
 }
 
+func Test_UserUsecase_PurgeDeletedAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.TODO()
+	purgeAt := time.Now().Add(-time.Minute)
+	deletion := &model.AccountDeletion{Model: model.Model{ID: "d1"}, UserID: "u1", PurgeAt: &purgeAt}
+
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetDueDeletions(ctx, gomock.Any()).Return([]*model.AccountDeletion{deletion}, nil)
+	userRepoMock.EXPECT().UpdateDeletion(ctx, deletion).Return(deletion, nil)
+	msgRepoMock := mock_message.NewMockRepository(ctrl)
+	msgRepoMock.EXPECT().PurgeBySenderId(ctx, "u1").Return(int64(3), nil)
+	inboxRepoMock := mock_inbox.NewMockRepository(ctrl)
+	inboxRepoMock.EXPECT().RefreshByUserId(ctx, "u1").Return(nil)
+
+	userUsecase := usecase.New(&dependency.Repositories{
+		Transactor: helper.NoTransaction{},
+		User:       userRepoMock,
+		Message:    msgRepoMock,
+		Inbox:      inboxRepoMock,
+	})
+
+	assert.NoError(t, userUsecase.PurgeDeletedAccounts(ctx))
+	assert.NotNil(t, deletion.PurgedAt)
+}
+
 func Test_UserUsecase_GetById(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -253,70 +698,184 @@ func Test_UserUsecase_GetById(t *testing.T) {
 }
 
 func Test_UserUsecase_GetByToken(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
 	testUser := &model.User{
 		Model:    model.Model{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2"},
 		Name:     "Example User",
 		Email:    "test@example.id",
 		Password: "password",
 	}
+	sessionId := "9b2c1d5e-d76e-401a-a3ba-7a03352812c2"
+	tokenStr, _ := utils.GenerateToken(testUser.Email, testUser.ID, model.RoleUser, sessionId, time.Now().Add(time.Hour*72))
+	revokedAt := time.Now().Add(-time.Minute)
 
-	tokenStr, _ := utils.GenerateToken(testUser.Email, testUser.ID, time.Now().Add(time.Hour*72))
-
-	type args struct {
-		req *payload.GetByTokenRequest
-	}
 	tests := []struct {
-		name            string
-		args            args
-		wantRepoResp    *model.User
-		wantErrRepoResp error
-		want            *payload.GetByTokenResponse
-		wantErr         assert.ErrorAssertionFunc
-	}{{
-		name: "Get User By Id Usecase Success",
-		args: args{
-			req: &payload.GetByTokenRequest{
-				Token: tokenStr,
-			},
+		name        string
+		revoked     bool
+		session     *model.Session
+		userDeleted bool
+		wantTouch   bool
+		wantErr     bool
+	}{
+		{
+			name:      "Get User By Token Usecase Success",
+			session:   &model.Session{Model: model.Model{ID: sessionId}, UserID: testUser.ID, LastUsedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(time.Hour)},
+			wantTouch: true,
 		},
-		wantRepoResp:    testUser,
-		wantErrRepoResp: nil,
-		want: &payload.GetByTokenResponse{
-			User:    testUser,
-			Message: "Successfully get user by token",
+		{
+			name:    "Recently used session isn't touched",
+			session: &model.Session{Model: model.Model{ID: sessionId}, UserID: testUser.ID, LastUsedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
 		},
-		wantErr: assert.NoError,
-	}}
+		{
+			name:    "Revoked session",
+			session: &model.Session{Model: model.Model{ID: sessionId}, UserID: testUser.ID, RevokedAt: &revokedAt, ExpiresAt: time.Now().Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "Expired session",
+			session: &model.Session{Model: model.Model{ID: sessionId}, UserID: testUser.ID, ExpiresAt: time.Now().Add(-time.Minute)},
+			wantErr: true,
+		},
+		{
+			name:    "Logged out token",
+			revoked: true,
+			wantErr: true,
+		},
+		{
+			name:        "Deleted account",
+			session:     &model.Session{Model: model.Model{ID: sessionId}, UserID: testUser.ID, LastUsedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
+			userDeleted: true,
+			wantErr:     true,
+		},
+	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			userRepoMock := mock_user.NewMockRepository(ctrl)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 			ctx := context.TODO()
-			conf := config.New()
 
-			claims := &utils.Claims{}
-			jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-				return []byte(conf.Secret), nil
-			})
-			userRepoMock.EXPECT().GetByEmail(ctx, testUser.Email).Return(tt.wantRepoResp, tt.wantErrRepoResp).AnyTimes()
-			userRepoMock.EXPECT().GetById(ctx, claims.UserID).Return(tt.wantRepoResp, tt.wantErrRepoResp).AnyTimes()
+			revocationRepoMock := mock_revocation.NewMockRepository(ctrl)
+			revocationRepoMock.EXPECT().IsRevoked(ctx, gomock.Any()).Return(tt.revoked, nil)
+			sessionRepoMock := mock_session.NewMockRepository(ctrl)
+			if !tt.revoked {
+				sessionRepoMock.EXPECT().GetById(ctx, sessionId).Return(tt.session, nil)
+			}
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			if tt.userDeleted {
+				userRepoMock.EXPECT().GetById(ctx, testUser.ID).Return(nil, gorm.ErrRecordNotFound)
+			} else if !tt.wantErr {
+				userRepoMock.EXPECT().GetById(ctx, testUser.ID).Return(testUser, nil)
+			}
+			if tt.wantTouch {
+				sessionRepoMock.EXPECT().Touch(ctx, sessionId, gomock.Any()).Return(nil)
+			}
 
 			userUsecase := usecase.New(&dependency.Repositories{
-				User: userRepoMock,
+				User:       userRepoMock,
+				Session:    sessionRepoMock,
+				Revocation: revocationRepoMock,
 			})
 
-			res, err := userUsecase.GetByToken(ctx, tt.args.req)
-			tt.wantErr(t, err)
-			if err == nil {
-				assert.Equalf(t, res, tt.want, "Get User By Id")
+			res, err := userUsecase.GetByToken(ctx, &payload.GetByTokenRequest{Token: tokenStr})
+			if tt.wantErr {
+				if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+					assert.Equal(t, http.StatusUnauthorized, httpErr.HTTPCode)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, testUser, res.User)
+				assert.Equal(t, sessionId, res.SessionID)
 			}
 		})
 	}
 }
 
+func Test_UserUsecase_GetByToken_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Expiry is checked before any lookup, the mocks expect no calls
+	tokenStr, _ := utils.GenerateToken("test@example.id", "6fd33930-d76e-401a-a3ba-7a03352812c2", model.RoleUser, "9b2c1d5e-d76e-401a-a3ba-7a03352812c2", time.Now().Add(-time.Minute))
+	userUsecase := usecase.New(&dependency.Repositories{
+		User:       mock_user.NewMockRepository(ctrl),
+		Session:    mock_session.NewMockRepository(ctrl),
+		Revocation: mock_revocation.NewMockRepository(ctrl),
+	})
+
+	_, err := userUsecase.GetByToken(context.TODO(), &payload.GetByTokenRequest{Token: tokenStr})
+	if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+		assert.Equal(t, http.StatusUnauthorized, httpErr.HTTPCode)
+	}
+}
+
+func Test_UserUsecase_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	sessionId := "9b2c1d5e-d76e-401a-a3ba-7a03352812c2"
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	tokenStr, _ := utils.GenerateToken("test@example.id", "6fd33930-d76e-401a-a3ba-7a03352812c2", model.RoleUser, sessionId, exp)
+
+	revocationRepoMock := mock_revocation.NewMockRepository(ctrl)
+	sessionRepoMock := mock_session.NewMockRepository(ctrl)
+	// The token stays on the list until it would have expired
+	revocationRepoMock.EXPECT().Revoke(ctx, gomock.Any(), exp).DoAndReturn(func(ctx context.Context, jti string, expiresAt time.Time) error {
+		assert.NotEmpty(t, jti)
+		return nil
+	})
+	sessionRepoMock.EXPECT().Revoke(ctx, sessionId, gomock.Any()).Return(nil)
+
+	userUsecase := usecase.New(&dependency.Repositories{
+		Session:    sessionRepoMock,
+		Revocation: revocationRepoMock,
+	})
+
+	res, err := userUsecase.Logout(ctx, &payload.LogoutRequest{Token: tokenStr})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Logout success", res.Message)
+	}
+
+	// Requests authenticated with an API key have no token to revoke
+	_, err = userUsecase.Logout(ctx, &payload.LogoutRequest{})
+	if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+		assert.Equal(t, http.StatusUnauthorized, httpErr.HTTPCode)
+	}
+}
+
+func Test_UserUsecase_Logout_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	sessionId := "9b2c1d5e-d76e-401a-a3ba-7a03352812c2"
+	refreshToken := "c2f1e0d9b8a7"
+
+	sessionRepoMock := mock_session.NewMockRepository(ctrl)
+	hash := sha256.Sum256([]byte(refreshToken))
+	sessionRepoMock.EXPECT().GetRefreshTokenByHash(ctx, hex.EncodeToString(hash[:])).
+		Return(&model.RefreshToken{SessionID: sessionId}, nil)
+	sessionRepoMock.EXPECT().Revoke(ctx, sessionId, gomock.Any()).Return(nil)
+
+	userUsecase := usecase.New(&dependency.Repositories{
+		Session: sessionRepoMock,
+	})
+
+	// The access token expired, the session is ended from the refresh token
+	res, err := userUsecase.Logout(ctx, &payload.LogoutRequest{RefreshToken: refreshToken})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Logout success", res.Message)
+	}
+
+	// An unknown refresh token has nothing left to end
+	sessionRepoMock.EXPECT().GetRefreshTokenByHash(ctx, gomock.Any()).Return(nil, errors.New("not found"))
+	res, err = userUsecase.Logout(ctx, &payload.LogoutRequest{RefreshToken: "unknown"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Already logged out", res.Message)
+	}
+}
+
 func Test_UserUsecase_Login(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -344,8 +903,9 @@ func Test_UserUsecase_Login(t *testing.T) {
 		name: "Create User Usecase Success",
 		args: args{
 			req: &payload.LoginRequest{
-				Email:    testUser.Email,
-				Password: testUser.Password,
+				Email:      testUser.Email,
+				Password:   testUser.Password,
+				DeviceName: "Work laptop",
 			},
 		},
 		wantRepoResp: &model.User{
@@ -356,9 +916,7 @@ func Test_UserUsecase_Login(t *testing.T) {
 		},
 		wantErrRepoResp: nil,
 		want: &payload.LoginResponse{
-			Token:   "string",
 			Message: "Login success",
-			Exp:     time.Now().Add(time.Hour * 72),
 		},
 		wantErr: assert.NoError,
 	}}
@@ -368,15 +926,33 @@ func Test_UserUsecase_Login(t *testing.T) {
 			userRepoMock := mock_user.NewMockRepository(ctrl)
 			ctx := context.TODO()
 			userRepoMock.EXPECT().GetByEmail(ctx, tt.args.req.Email).Return(tt.wantRepoResp, tt.wantErrRepoResp).AnyTimes()
+			sessionRepoMock := mock_session.NewMockRepository(ctrl)
+			sessionRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, session *model.Session) (*model.Session, error) {
+				assert.Equal(t, testUser.ID, session.UserID)
+				assert.Equal(t, "Work laptop", session.DeviceName)
+				session.ID = "9b2c1d5e-d76e-401a-a3ba-7a03352812c2"
+				return session, nil
+			})
+			var refreshHash string
+			sessionRepoMock.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *model.RefreshToken) (*model.RefreshToken, error) {
+				assert.Equal(t, "9b2c1d5e-d76e-401a-a3ba-7a03352812c2", token.SessionID)
+				refreshHash = token.TokenHash
+				return token, nil
+			})
 
 			userUsecase := usecase.New(&dependency.Repositories{
-				User: userRepoMock,
+				User:    userRepoMock,
+				Session: sessionRepoMock,
 			})
 
 			res, err := userUsecase.Login(ctx, tt.args.req)
 			tt.wantErr(t, err)
 			if err == nil {
 				assert.Equalf(t, res.Message, tt.want.Message, "Login message")
+				assert.Equal(t, "9b2c1d5e-d76e-401a-a3ba-7a03352812c2", res.SessionID)
+				assert.WithinDuration(t, time.Now().Add(usecase.DefaultAccessTokenTTL), res.Exp, time.Second)
+				assert.WithinDuration(t, time.Now().Add(usecase.DefaultRefreshTokenTTL), res.RefreshExp, time.Second)
+				assert.NotEqual(t, res.RefreshToken, refreshHash, "only a hash of the refresh token is stored")
 			}
 		})
 	}
@@ -455,3 +1031,140 @@ func Test_UserUsecase_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func Test_UserUsecase_Login_Bot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.TODO()
+
+	userRepoMock := mock_user.NewMockRepository(ctrl)
+	userRepoMock.EXPECT().GetByEmail(ctx, "bot@example.id").Return(&model.User{
+		Model: model.Model{ID: "b1"},
+		Email: "bot@example.id",
+		IsBot: true,
+	}, nil)
+
+	userUsecase := usecase.New(&dependency.Repositories{
+		User: userRepoMock,
+	})
+
+	_, err := userUsecase.Login(ctx, &payload.LoginRequest{Email: "bot@example.id", Password: ""})
+	if assert.Error(t, err) {
+		assert.Equal(t, "Bots authenticate with API keys", err.Error())
+	}
+}
+
+func Test_UserUsecase_GetAll_Bot(t *testing.T) {
+	tests := []struct {
+		name    string
+		bot     *model.Bot
+		wantErr bool
+	}{
+		{name: "Not granted", bot: &model.Bot{UserID: "b1"}, wantErr: true},
+		{name: "Granted", bot: &model.Bot{UserID: "b1", CanSearchDirectory: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			req := &payload.GetAllRequest{UserID: "b1", CallerIsBot: true}
+			botRepoMock := mock_bot.NewMockRepository(ctrl)
+			botRepoMock.EXPECT().GetByUserId(ctx, "b1").Return(tt.bot, nil)
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			if !tt.wantErr {
+				userRepoMock.EXPECT().GetAll(ctx, &req.Pagination, req).Return([]*model.User{}, nil)
+			}
+
+			userUsecase := usecase.New(&dependency.Repositories{
+				User: userRepoMock,
+				Bot:  botRepoMock,
+			})
+
+			_, err := userUsecase.GetAll(ctx, req)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func Test_UserUsecase_Refresh(t *testing.T) {
+	const refreshToken = "r3fresh"
+	sum := sha256.Sum256([]byte(refreshToken))
+	hash := hex.EncodeToString(sum[:])
+	testUser := &model.User{Model: model.Model{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2"}, Email: "test@example.id"}
+	sessionId := "9b2c1d5e-d76e-401a-a3ba-7a03352812c2"
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		usedAt      *time.Time
+		lastUsedAt  time.Time
+		lostRace    bool
+		userDeleted bool
+		wantRevoke  bool
+		wantErr     bool
+		wantRotated bool
+	}{
+		{name: "Rotated", lastUsedAt: time.Now().Add(-time.Hour), wantRotated: true},
+		{name: "Reused token revokes the session", usedAt: &usedAt, lastUsedAt: time.Now(), wantRevoke: true, wantErr: true},
+		{name: "Concurrent refresh with the same token", lastUsedAt: time.Now(), lostRace: true, wantRevoke: true, wantErr: true},
+		{name: "Idle session", lastUsedAt: time.Now().Add(-usecase.DefaultSessionIdleTimeout - time.Minute), wantErr: true},
+		{name: "Deleted account", lastUsedAt: time.Now().Add(-time.Hour), userDeleted: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			session := &model.Session{Model: model.Model{ID: sessionId}, UserID: testUser.ID, LastUsedAt: tt.lastUsedAt, ExpiresAt: time.Now().Add(time.Hour)}
+			sessionRepoMock := mock_session.NewMockRepository(ctrl)
+			sessionRepoMock.EXPECT().GetRefreshTokenByHash(ctx, hash).Return(&model.RefreshToken{
+				Model:     model.Model{ID: "rt1"},
+				SessionID: sessionId,
+				ExpiresAt: session.ExpiresAt,
+				UsedAt:    tt.usedAt,
+			}, nil)
+			sessionRepoMock.EXPECT().GetById(ctx, sessionId).Return(session, nil)
+			userRepoMock := mock_user.NewMockRepository(ctrl)
+			if tt.userDeleted {
+				userRepoMock.EXPECT().GetById(ctx, testUser.ID).Return(nil, gorm.ErrRecordNotFound)
+			}
+			if tt.wantRotated || tt.lostRace {
+				userRepoMock.EXPECT().GetById(ctx, testUser.ID).Return(testUser, nil)
+				sessionRepoMock.EXPECT().UseRefreshToken(ctx, "rt1", gomock.Any()).Return(!tt.lostRace, nil)
+			}
+			if tt.wantRotated {
+				sessionRepoMock.EXPECT().Touch(ctx, sessionId, gomock.Any()).Return(nil)
+				sessionRepoMock.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, token *model.RefreshToken) (*model.RefreshToken, error) {
+					assert.Equal(t, sessionId, token.SessionID)
+					assert.NotEqual(t, hash, token.TokenHash)
+					assert.Equal(t, session.ExpiresAt, token.ExpiresAt, "refreshing doesn't extend the session")
+					return token, nil
+				})
+			}
+			if tt.wantRevoke {
+				sessionRepoMock.EXPECT().Revoke(ctx, sessionId, gomock.Any()).Return(nil)
+			}
+
+			userUsecase := usecase.New(&dependency.Repositories{
+				Transactor: helper.NoTransaction{},
+				User:       userRepoMock,
+				Session:    sessionRepoMock,
+			})
+			res, err := userUsecase.Refresh(ctx, &payload.RefreshRequest{RefreshToken: refreshToken})
+			if tt.wantErr {
+				if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+					assert.Equal(t, http.StatusUnauthorized, httpErr.HTTPCode)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.NotEmpty(t, res.Token)
+				assert.NotEqual(t, refreshToken, res.RefreshToken)
+				assert.Equal(t, sessionId, res.SessionID)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"gitlab.com/raihanlh/messenger-api/internal/domain/user/payload"
@@ -15,25 +16,45 @@ type Repository interface {
 	Delete(ctx context.Context, id string) error
 	GetById(ctx context.Context, id string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	GetByEmails(ctx context.Context, emails []string) ([]*model.User, error)
 	GetAll(ctx context.Context, pgn *pagination.Pagination, req *payload.GetAllRequest) ([]*model.User, error)
+	GetByIdWithDeleted(ctx context.Context, id string) (*model.User, error)
+	Anonymize(ctx context.Context, id string) error
+	CreateDeletion(ctx context.Context, deletion *model.AccountDeletion) (*model.AccountDeletion, error)
+	UpdateDeletion(ctx context.Context, deletion *model.AccountDeletion) (*model.AccountDeletion, error)
+	GetDeletionById(ctx context.Context, id string) (*model.AccountDeletion, error)
+	GetDueDeletions(ctx context.Context, at time.Time) ([]*model.AccountDeletion, error)
+	UpdateLastSeen(ctx context.Context, id string, at time.Time) error
 }
 
 type Usecase interface {
 	Create(ctx context.Context, req *payload.CreateRequest) (*payload.CreateResponse, error)
 	Update(ctx context.Context, req *payload.UpdateRequest) (*payload.UpdateResponse, error)
 	Delete(ctx context.Context, req *payload.DeleteRequest) (*payload.DeleteResponse, error)
+	UpdateRole(ctx context.Context, req *payload.UpdateRoleRequest) (*payload.UpdateRoleResponse, error)
+	RevokeSessions(ctx context.Context, req *payload.RevokeSessionsRequest) (*payload.RevokeSessionsResponse, error)
 	GetById(ctx context.Context, req *payload.GetByIdRequest) (*payload.GetByIdResponse, error)
 	GetAll(ctx context.Context, req *payload.GetAllRequest) (*payload.GetAllResponse, error)
 	GetByToken(ctx context.Context, req *payload.GetByTokenRequest) (*payload.GetByTokenResponse, error)
 	Login(ctx context.Context, req *payload.LoginRequest) (*payload.LoginResponse, error)
+	Refresh(ctx context.Context, req *payload.RefreshRequest) (*payload.RefreshResponse, error)
+	Logout(ctx context.Context, req *payload.LogoutRequest) (*payload.LogoutResponse, error)
+	CancelPurge(ctx context.Context, req *payload.CancelPurgeRequest) (*payload.CancelPurgeResponse, error)
+	PurgeDeletedAccounts(ctx context.Context) error
+	MarkSeen(ctx context.Context, user *model.User) error
 }
 
 type Handler interface {
 	Create(ctx echo.Context) error
 	Update(ctx echo.Context) error
 	Delete(ctx echo.Context) error
+	UpdateRole(ctx echo.Context) error
+	RevokeSessions(ctx echo.Context) error
 	GetById(ctx echo.Context) error
 	GetAll(ctx echo.Context) error
 	GetByToken(ctx echo.Context) error
 	Login(ctx echo.Context) error
+	Refresh(ctx echo.Context) error
+	Logout(ctx echo.Context) error
+	CancelPurge(ctx echo.Context) error
 }
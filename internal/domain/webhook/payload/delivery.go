@@ -0,0 +1,33 @@
+package payload
+
+import (
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
+)
+
+type GetDeliveriesRequest struct {
+	// Newest first, sort is ignored
+	pagination.Pagination
+	UserID    string `json:"-"`
+	WebhookID string `param:"id"`
+	// Only deliveries in this status, e.g. dead ones to redeliver
+	Status string `query:"status" validate:"omitempty,oneof=pending delivering succeeded dead"`
+}
+
+type GetDeliveriesResponse struct {
+	*pagination.Pagination
+	PaginatedData []*model.WebhookDelivery `json:"paginatedData"`
+	Message       string                   `json:"message"`
+}
+
+type RedeliverRequest struct {
+	UserID     string `json:"-"`
+	WebhookID  string `param:"id"`
+	DeliveryID string `param:"delivery_id"`
+}
+
+// Delivery is a new entry in the log, the original keeps its attempts
+type RedeliverResponse struct {
+	Delivery *model.WebhookDelivery `json:"delivery"`
+	Message  string                 `json:"message"`
+}
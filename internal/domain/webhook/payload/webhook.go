@@ -0,0 +1,34 @@
+package payload
+
+import "gitlab.com/raihanlh/messenger-api/internal/model"
+
+type CreateWebhookRequest struct {
+	UserID string   `json:"-"`
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=message.created message.received conversation.created user.created"`
+}
+
+// Secret signs every request to the webhook, it isn't shown again
+type CreateWebhookResponse struct {
+	Webhook *model.Webhook `json:"webhook"`
+	Secret  string         `json:"secret"`
+	Message string         `json:"message"`
+}
+
+type GetAllWebhooksRequest struct {
+	UserID string `json:"-"`
+}
+
+type GetAllWebhooksResponse struct {
+	Webhooks []*model.Webhook `json:"webhooks"`
+	Message  string           `json:"message"`
+}
+
+type RemoveWebhookRequest struct {
+	UserID    string `json:"-"`
+	WebhookID string `param:"id"`
+}
+
+type RemoveWebhookResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
+	"gitlab.com/raihanlh/messenger-api/pkg/postgres"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+func New(gormDB *gorm.DB) webhook.Repository {
+	return &WebhookRepository{
+		DB: gormDB,
+	}
+}
+
+func (r WebhookRepository) Create(ctx context.Context, webhook *model.Webhook) (*model.Webhook, error) {
+	result := postgres.Conn(ctx, r.DB).Create(webhook)
+	return webhook, result.Error
+}
+
+func (r WebhookRepository) GetById(ctx context.Context, id string) (*model.Webhook, error) {
+	var webhook *model.Webhook
+	result := r.DB.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&webhook)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return webhook, nil
+}
+
+// Removed webhooks are left out
+func (r WebhookRepository) GetByIds(ctx context.Context, ids []string) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	if len(ids) == 0 {
+		return webhooks, nil
+	}
+	result := r.DB.WithContext(ctx).Where("id IN ?", ids).Find(&webhooks)
+	return webhooks, result.Error
+}
+
+func (r WebhookRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	result := r.DB.WithContext(ctx).Where("user_id = ?", userId).Order("created_at ASC").Find(&webhooks)
+	return webhooks, result.Error
+}
+
+func (r WebhookRepository) Delete(ctx context.Context, id string) error {
+	result := postgres.Conn(ctx, r.DB).Where("id = ?", id).Delete(&model.Webhook{})
+	return result.Error
+}
+
+// Enqueue queues a delivery of the event to every webhook subscribed to it, or only to
+// those owned by ownerIds when given. It joins the caller's transaction, so an event is
+// only delivered when whatever it reports was stored.
+func (r WebhookRepository) Enqueue(ctx context.Context, event *model.WebhookEvent, ownerIds []string) error {
+	if ownerIds != nil && len(ownerIds) == 0 {
+		return nil
+	}
+	db := postgres.Conn(ctx, r.DB)
+	eventType, err := json.Marshal([]string{event.Type})
+	if err != nil {
+		return err
+	}
+	var webhooks []*model.Webhook
+	query := db.Select("id").Where("events @> ?", string(eventType))
+	if ownerIds != nil {
+		query = query.Where("user_id IN ?", ownerIds)
+	}
+	// Webhooks that already have a delivery of the event got it before
+	query = query.Where("id NOT IN (SELECT webhook_id FROM "+constant.WebhookDeliveryTable+" WHERE event_id = ?)", event.ID)
+	if err := query.Find(&webhooks).Error; err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	deliveries := make([]*model.WebhookDelivery, 0, len(webhooks))
+	for _, w := range webhooks {
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			WebhookID:     w.ID,
+			EventID:       event.ID,
+			Event:         event.Type,
+			Payload:       string(body),
+			Status:        model.WebhookDeliveryStatusPending,
+			NextAttemptAt: event.CreatedAt,
+			OccurredAt:    event.CreatedAt,
+		})
+	}
+	return db.Create(&deliveries).Error
+}
+
+func (r WebhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	result := r.DB.WithContext(ctx).Create(delivery)
+	return delivery, result.Error
+}
+
+func (r WebhookRepository) GetDeliveryById(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	var delivery *model.WebhookDelivery
+	result := r.DB.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&delivery)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("not found")
+	}
+	return delivery, nil
+}
+
+func (r WebhookRepository) GetDeliveries(ctx context.Context, pgn *pagination.Pagination, webhookId string, status string) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	query := r.DB.WithContext(ctx).Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	if result := query.Count(&pgn.TotalItems); result.Error != nil {
+		return nil, result.Error
+	}
+	pgn.TotalPages = int(math.Ceil(float64(pgn.TotalItems) / float64(pgn.GetLimit())))
+
+	result := query.Order("created_at DESC, id DESC").Offset(pgn.GetOffset()).Limit(pgn.GetLimit()).Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// ClaimDue marks up to limit deliveries due at the given time as delivering, counting the
+// attempt, and returns them. Deliveries left delivering since before leaseExpiredBefore
+// belonged to a worker that died and are claimed again. Rows locked by another worker
+// are skipped.
+func (r WebhookRepository) ClaimDue(ctx context.Context, at time.Time, leaseExpiredBefore time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	result := r.DB.WithContext(ctx).Raw(`UPDATE `+constant.WebhookDeliveryTable+` SET status = ?, attempts = attempts + 1, updated_at = ?
+		WHERE id IN (SELECT id FROM `+constant.WebhookDeliveryTable+` WHERE deleted_at IS NULL
+		AND ((status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?))
+		ORDER BY next_attempt_at ASC LIMIT ? FOR UPDATE SKIP LOCKED) RETURNING *`,
+		model.WebhookDeliveryStatusDelivering, at,
+		model.WebhookDeliveryStatusPending, at, model.WebhookDeliveryStatusDelivering, leaseExpiredBefore, limit).Scan(&deliveries)
+	return deliveries, result.Error
+}
+
+func (r WebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	result := r.DB.WithContext(ctx).Table(constant.WebhookDeliveryTable).Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"response_code":   delivery.ResponseCode,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      time.Now(),
+		})
+	return result.Error
+}
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	repo "gitlab.com/raihanlh/messenger-api/internal/domain/webhook/repository"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func Setup() (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, _ := sqlmock.New()
+
+	dialector := postgres.New(postgres.Config{
+		DSN:                  "sqlmock_db_0",
+		PreferSimpleProtocol: true,
+		Conn:                 db,
+		DriverName:           "postgres",
+	})
+
+	gormDB, _ := gorm.Open(dialector, &gorm.Config{})
+
+	return gormDB, mock
+}
+
+func Test_WebhookRepository_Enqueue(t *testing.T) {
+	db, mock := Setup()
+	event := model.NewWebhookEvent(model.WebhookEventMessageCreated, &model.WebhookMessageData{ID: "m1"})
+
+	// Only webhooks of the given owners subscribed to the event type and without a delivery of it get one
+	mock.ExpectQuery(`SELECT "id" FROM "webhooks" WHERE events @> \$1 AND user_id IN \(\$2,\$3\) AND id NOT IN \(SELECT webhook_id FROM webhook_deliveries WHERE event_id = \$4\) AND "webhooks"\."deleted_at" IS NULL`).
+		WithArgs(`["message.created"]`, "u1", "u2", event.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("w1"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "webhook_deliveries"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "w1", event.ID, "message.created", sqlmock.AnyArg(),
+			model.WebhookDeliveryStatusPending, 0, event.CreatedAt, 0, "", event.CreatedAt, nil, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("d1"))
+	mock.ExpectCommit()
+
+	err := repo.New(db).Enqueue(context.TODO(), event, []string{"u1", "u2"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func Test_WebhookRepository_Enqueue_NoSubscribers(t *testing.T) {
+	db, mock := Setup()
+	event := model.NewWebhookEvent(model.WebhookEventUserCreated, &model.WebhookUserData{ID: "u1"})
+
+	mock.ExpectQuery(`SELECT "id" FROM "webhooks" WHERE events @> \$1 AND id NOT IN \(SELECT webhook_id FROM webhook_deliveries WHERE event_id = \$2\) AND "webhooks"\."deleted_at" IS NULL`).
+		WithArgs(`["user.created"]`, event.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	assert.NoError(t, repo.New(db).Enqueue(context.TODO(), event, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
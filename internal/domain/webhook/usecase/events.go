@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+// QueueDeliveries subscribes to the domain events integrations can listen to and queues
+// a delivery for every webhook of the users involved
+func (u WebhookUsecase) QueueDeliveries(ctx context.Context, e *eventbus.Event) error {
+	switch e.Type {
+	case model.EventMessageCreated:
+		var data model.MessageCreatedEvent
+		if err := e.Decode(&data); err != nil {
+			return err
+		}
+		msg := &model.WebhookMessageData{
+			ID:             data.MessageID,
+			ConversationID: data.ConversationID,
+			SenderID:       data.SenderID,
+			ReceiverID:     data.ReceiverID,
+			Mode:           data.Mode,
+			SentAt:         data.SentAt,
+		}
+		// The event doesn't carry the text, e2e messages have none to send anyway
+		if data.Mode != model.MessageModeE2E {
+			m, err := u.repositories.Message.GetById(ctx, data.MessageID)
+			if err != nil {
+				return err
+			}
+			msg.Message = m.MessageText
+		}
+		if err := u.enqueue(ctx, e, model.WebhookEventMessageCreated, msg, data.ParticipantIDs); err != nil {
+			return err
+		}
+		// Bots get their inbound messages through this event
+		return u.enqueue(ctx, e, model.WebhookEventMessageReceived, msg, data.RecipientIDs)
+	case model.EventConversationCreated:
+		var data model.ConversationCreatedEvent
+		if err := e.Decode(&data); err != nil {
+			return err
+		}
+		return u.enqueue(ctx, e, model.WebhookEventConversationCreated, &model.WebhookConversationData{
+			ID:         data.ConversationID,
+			SenderID:   data.SenderID,
+			ReceiverID: data.ReceiverID,
+			Status:     data.Status,
+		}, []string{data.SenderID, data.ReceiverID})
+	case model.EventUserRegistered:
+		var data model.UserRegisteredEvent
+		if err := e.Decode(&data); err != nil {
+			return err
+		}
+		return u.enqueue(ctx, e, model.WebhookEventUserCreated, &model.WebhookUserData{ID: data.UserID, Name: data.Name}, nil)
+	}
+	return fmt.Errorf("no webhook event for %s", e.Type)
+}
+
+// The webhook event id is derived from the domain event, so a redelivered event is
+// recognized and not queued twice
+func (u WebhookUsecase) enqueue(ctx context.Context, e *eventbus.Event, eventType string, data interface{}, ownerIds []string) error {
+	return u.repositories.Webhook.Enqueue(ctx, &model.WebhookEvent{
+		ID:        uuid.NewV5(uuid.FromStringOrNil(e.ID), eventType).String(),
+		Type:      eventType,
+		CreatedAt: e.OccurredAt,
+		Data:      data,
+	}, ownerIds)
+}
@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+	mock_message "gitlab.com/raihanlh/messenger-api/testing/mocks/message"
+	mock_webhook "gitlab.com/raihanlh/messenger-api/testing/mocks/webhook"
+)
+
+func Test_WebhookUsecase_QueueDeliveries(t *testing.T) {
+	occurredAt := time.Now().Add(-time.Minute)
+	messageCreated := `{"message_id":"m1","conversation_id":"c1","sender_id":"u1","receiver_id":"u2","recipient_ids":["u2"],"participant_ids":["u1","u2"],"mode":"%s"}`
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   string
+		mode      string
+		wantTypes []string
+		wantText  string
+	}{
+		{
+			name: "Plain message", eventType: model.EventMessageCreated, mode: model.MessageModePlain, wantText: "hello",
+			wantTypes: []string{model.WebhookEventMessageCreated, model.WebhookEventMessageReceived},
+		},
+		{
+			name: "E2E message", eventType: model.EventMessageCreated, mode: model.MessageModeE2E,
+			wantTypes: []string{model.WebhookEventMessageCreated, model.WebhookEventMessageReceived},
+		},
+		{
+			name: "Conversation created", eventType: model.EventConversationCreated,
+			payload:   `{"conversation_id":"c1","sender_id":"u1","receiver_id":"u2","status":"accepted"}`,
+			wantTypes: []string{model.WebhookEventConversationCreated},
+		},
+		{
+			name: "User registered", eventType: model.EventUserRegistered,
+			payload:   `{"user_id":"u1","name":"Alice"}`,
+			wantTypes: []string{model.WebhookEventUserCreated},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			payload := tt.payload
+			if tt.eventType == model.EventMessageCreated {
+				payload = fmt.Sprintf(messageCreated, tt.mode)
+			}
+			msgRepoMock := mock_message.NewMockRepository(ctrl)
+			if tt.mode == model.MessageModePlain {
+				msgRepoMock.EXPECT().GetById(ctx, "m1").Return(&model.Message{MessageText: "hello"}, nil)
+			}
+			var queued []*model.WebhookEvent
+			owners := map[string][]string{}
+			webhookRepoMock := mock_webhook.NewMockRepository(ctrl)
+			webhookRepoMock.EXPECT().Enqueue(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *model.WebhookEvent, ownerIds []string) error {
+				queued = append(queued, event)
+				owners[event.Type] = ownerIds
+				return nil
+			}).Times(len(tt.wantTypes))
+
+			e := &eventbus.Event{ID: "6fd33930-d76e-401a-a3ba-7a03352812c2", Type: tt.eventType, OccurredAt: occurredAt, Payload: []byte(payload)}
+			webhookUsecase := usecase.New(&dependency.Repositories{Message: msgRepoMock, Webhook: webhookRepoMock}, nil)
+			if !assert.NoError(t, webhookUsecase.QueueDeliveries(ctx, e)) {
+				return
+			}
+
+			var types []string
+			for _, event := range queued {
+				types = append(types, event.Type)
+				assert.Equal(t, occurredAt, event.CreatedAt)
+			}
+			assert.Equal(t, tt.wantTypes, types)
+
+			switch tt.eventType {
+			case model.EventMessageCreated:
+				if data, ok := queued[0].Data.(*model.WebhookMessageData); assert.True(t, ok) {
+					assert.Equal(t, "m1", data.ID)
+					assert.Equal(t, tt.wantText, data.Message)
+				}
+				assert.Equal(t, []string{"u1", "u2"}, owners[model.WebhookEventMessageCreated])
+				assert.Equal(t, []string{"u2"}, owners[model.WebhookEventMessageReceived])
+				assert.NotEqual(t, queued[0].ID, queued[1].ID, "each webhook event has its own id")
+			case model.EventUserRegistered:
+				assert.Nil(t, owners[model.WebhookEventUserCreated], "every subscribed webhook gets user.created")
+			}
+
+			// Redelivering the same event produces the same webhook event ids
+			again := []*model.WebhookEvent{}
+			webhookRepoMock.EXPECT().Enqueue(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, event *model.WebhookEvent, _ []string) error {
+				again = append(again, event)
+				return nil
+			}).Times(len(tt.wantTypes))
+			if tt.mode == model.MessageModePlain {
+				msgRepoMock.EXPECT().GetById(ctx, "m1").Return(&model.Message{MessageText: "hello"}, nil)
+			}
+			assert.NoError(t, webhookUsecase.QueueDeliveries(ctx, e))
+			for i := range again {
+				assert.Equal(t, queued[i].ID, again[i].ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/logger"
+	webhookSender "gitlab.com/raihanlh/messenger-api/pkg/webhook"
+	"go.uber.org/zap"
+)
+
+const (
+	// Deliveries claimed per run of DeliverPending, they're sent one after another
+	DeliveryBatchSize = 20
+	// A delivery still marked delivering after this long is claimed again
+	DeliveryLease = 10 * time.Minute
+	// Attempts before a delivery is dead, the waits in between double from RetryBaseDelay
+	MaxDeliveryAttempts = 8
+	RetryBaseDelay      = 30 * time.Second
+	RetryMaxDelay       = 6 * time.Hour
+	// Longest receiver error kept in the delivery log
+	MaxErrorLength = 500
+)
+
+type WebhookUsecase struct {
+	repositories *dependency.Repositories
+	gateways     *dependency.Gateways
+}
+
+func New(r *dependency.Repositories, g *dependency.Gateways) webhook.Usecase {
+	return &WebhookUsecase{
+		repositories: r,
+		gateways:     g,
+	}
+}
+
+func (u WebhookUsecase) Create(ctx context.Context, req *payload.CreateWebhookRequest) (*payload.CreateWebhookResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if err := u.gateways.URLGuard.CheckURL(ctx, req.URL); err != nil {
+		return nil, http_error.BadRequest(fmt.Errorf("url %w", err))
+	}
+
+	secret := newSecret()
+	result, err := u.repositories.Webhook.Create(ctx, &model.Webhook{
+		UserID: req.UserID,
+		URL:    req.URL,
+		Secret: secret,
+		Events: req.Events,
+	})
+	if err != nil {
+		log.Error("Failed to create webhook: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.CreateWebhookResponse{
+		Webhook: result,
+		Secret:  secret,
+		Message: "Create webhook success",
+	}, nil
+}
+
+func (u WebhookUsecase) GetAll(ctx context.Context, req *payload.GetAllWebhooksRequest) (*payload.GetAllWebhooksResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	webhooks, err := u.repositories.Webhook.GetAllByUserId(ctx, req.UserID)
+	if err != nil {
+		log.Error("Failed to get webhooks: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.GetAllWebhooksResponse{
+		Webhooks: webhooks,
+		Message:  "Successfully get webhooks",
+	}, nil
+}
+
+// Deliveries still queued for the webhook are dropped when they come up
+func (u WebhookUsecase) Remove(ctx context.Context, req *payload.RemoveWebhookRequest) (*payload.RemoveWebhookResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnWebhook(ctx, req.UserID, req.WebhookID); err != nil {
+		return nil, err
+	}
+	if err := u.repositories.Webhook.Delete(ctx, req.WebhookID); err != nil {
+		log.Error("Failed to remove webhook: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RemoveWebhookResponse{
+		Message: "Remove webhook success",
+	}, nil
+}
+
+func (u WebhookUsecase) GetDeliveries(ctx context.Context, req *payload.GetDeliveriesRequest) (*payload.GetDeliveriesResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnWebhook(ctx, req.UserID, req.WebhookID); err != nil {
+		return nil, err
+	}
+	pgn := &req.Pagination
+	deliveries, err := u.repositories.Webhook.GetDeliveries(ctx, pgn, req.WebhookID, req.Status)
+	if err != nil {
+		log.Error("Failed to get webhook deliveries: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.GetDeliveriesResponse{
+		Pagination:    pgn,
+		PaginatedData: deliveries,
+		Message:       "Successfully get webhook deliveries",
+	}, nil
+}
+
+// Redeliver queues the payload of a past delivery again, under the same event id so
+// receivers can tell it apart from a new event
+func (u WebhookUsecase) Redeliver(ctx context.Context, req *payload.RedeliverRequest) (*payload.RedeliverResponse, error) {
+	log := logger.GetLogger(ctx)
+
+	if _, err := u.getOwnWebhook(ctx, req.UserID, req.WebhookID); err != nil {
+		return nil, err
+	}
+	original, err := u.repositories.Webhook.GetDeliveryById(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != req.WebhookID {
+		return nil, errors.New("not found")
+	}
+
+	delivery, err := u.repositories.Webhook.CreateDelivery(ctx, &model.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        model.WebhookDeliveryStatusPending,
+		NextAttemptAt: time.Now(),
+		OccurredAt:    original.OccurredAt,
+	})
+	if err != nil {
+		log.Error("Failed to redeliver webhook: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &payload.RedeliverResponse{
+		Delivery: delivery,
+		Message:  "Redeliver webhook success",
+	}, nil
+}
+
+// DeliverPending sends the deliveries that are due. A failed delivery is retried with
+// exponential backoff until it runs out of attempts and is marked dead.
+func (u WebhookUsecase) DeliverPending(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+	now := time.Now()
+
+	deliveries, err := u.repositories.Webhook.ClaimDue(ctx, now, now.Add(-DeliveryLease), DeliveryBatchSize)
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+	ids := make([]string, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.WebhookID)
+	}
+	webhooks, err := u.repositories.Webhook.GetByIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+	byId := make(map[string]*model.Webhook, len(webhooks))
+	for _, w := range webhooks {
+		byId[w.ID] = w
+	}
+
+	for _, d := range deliveries {
+		u.deliver(ctx, d, byId[d.WebhookID])
+		if err := u.repositories.Webhook.UpdateDelivery(ctx, d); err != nil {
+			log.Error("Failed to update webhook delivery: ", zap.String("delivery_id", d.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// Send one delivery and record the outcome on it
+func (u WebhookUsecase) deliver(ctx context.Context, d *model.WebhookDelivery, hook *model.Webhook) {
+	if hook == nil {
+		d.Status = model.WebhookDeliveryStatusDead
+		d.LastError = "webhook was removed"
+		return
+	}
+
+	code, err := u.gateways.Webhook.Send(ctx, &webhookSender.Request{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		Event:      d.Event,
+		DeliveryID: d.ID,
+		Body:       []byte(d.Payload),
+	})
+	now := time.Now()
+	d.ResponseCode = code
+	if err == nil {
+		d.Status = model.WebhookDeliveryStatusSucceeded
+		d.LastError = ""
+		d.DeliveredAt = &now
+		return
+	}
+
+	d.LastError = err.Error()
+	if len(d.LastError) > MaxErrorLength {
+		d.LastError = d.LastError[:MaxErrorLength]
+	}
+	if d.Attempts >= MaxDeliveryAttempts {
+		d.Status = model.WebhookDeliveryStatusDead
+		return
+	}
+	d.Status = model.WebhookDeliveryStatusPending
+	d.NextAttemptAt = now.Add(RetryDelay(d.Attempts))
+}
+
+// RetryDelay is the wait after the given number of failed attempts
+func RetryDelay(attempts int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempts && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > RetryMaxDelay {
+		return RetryMaxDelay
+	}
+	return delay
+}
+
+func (u WebhookUsecase) getOwnWebhook(ctx context.Context, userId string, webhookId string) (*model.Webhook, error) {
+	hook, err := u.repositories.Webhook.GetById(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	if hook.UserID != userId {
+		return nil, errors.New("unauthorized")
+	}
+	return hook, nil
+}
+
+func newSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,216 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	http_error "gitlab.com/raihanlh/messenger-api/api/payload/http-error"
+	"gitlab.com/raihanlh/messenger-api/internal/app/dependency"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook/usecase"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	webhookSender "gitlab.com/raihanlh/messenger-api/pkg/webhook"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+	mock_webhook "gitlab.com/raihanlh/messenger-api/testing/mocks/webhook"
+)
+
+func Test_WebhookUsecase_Create(t *testing.T) {
+	guard := helper.URLGuard(helper.StaticResolver{
+		"hooks.example.id":    {"93.184.216.34"},
+		"rebind.example.id":   {"127.0.0.1"},
+		"internal.example.id": {"172.16.0.10"},
+	})
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "Public receiver", url: "https://hooks.example.id/events"},
+		{name: "Not http", url: "ftp://hooks.example.id/events", wantErr: true},
+		{name: "Loopback", url: "http://127.0.0.1:8080/events", wantErr: true},
+		{name: "Host resolving to loopback", url: "https://rebind.example.id/events", wantErr: true},
+		{name: "Host resolving to a private address", url: "https://internal.example.id/events", wantErr: true},
+		{name: "Link-local", url: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "Unspecified", url: "http://0.0.0.0/events", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			webhookRepoMock := mock_webhook.NewMockRepository(ctrl)
+			if !tt.wantErr {
+				webhookRepoMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, hook *model.Webhook) (*model.Webhook, error) {
+					return hook, nil
+				})
+			}
+
+			webhookUsecase := usecase.New(&dependency.Repositories{Webhook: webhookRepoMock}, &dependency.Gateways{URLGuard: guard})
+			_, err := webhookUsecase.Create(ctx, &payload.CreateWebhookRequest{
+				UserID: "u1",
+				URL:    tt.url,
+				Events: []string{model.WebhookEventMessageReceived},
+			})
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			if httpErr, ok := err.(*http_error.Error); assert.True(t, ok) {
+				assert.Equal(t, http.StatusBadRequest, httpErr.HTTPCode)
+			}
+		})
+	}
+}
+
+func Test_WebhookUsecase_DeliverPending(t *testing.T) {
+	body := `{"id":"e1","type":"message.created","data":{}}`
+
+	tests := []struct {
+		name       string
+		status     int
+		attempts   int
+		removed    bool
+		wantStatus string
+		wantRetry  time.Duration
+	}{
+		{name: "Accepted", status: http.StatusOK, attempts: 1, wantStatus: model.WebhookDeliveryStatusSucceeded},
+		{name: "First failure", status: http.StatusBadGateway, attempts: 1, wantStatus: model.WebhookDeliveryStatusPending, wantRetry: 30 * time.Second},
+		{name: "Backoff doubles", status: http.StatusBadGateway, attempts: 4, wantStatus: model.WebhookDeliveryStatusPending, wantRetry: 4 * time.Minute},
+		{name: "Out of attempts", status: http.StatusBadGateway, attempts: usecase.MaxDeliveryAttempts, wantStatus: model.WebhookDeliveryStatusDead},
+		{name: "Webhook removed", removed: true, attempts: 1, wantStatus: model.WebhookDeliveryStatusDead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				received, _ := io.ReadAll(r.Body)
+				assert.Equal(t, body, string(received))
+				assert.Equal(t, "d1", r.Header.Get(webhookSender.DeliveryHeader))
+				timestamp, _ := strconv.ParseInt(r.Header.Get(webhookSender.TimestampHeader), 10, 64)
+				assert.True(t, webhookSender.Verify("s3cret", r.Header.Get(webhookSender.SignatureHeader), timestamp, received))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			delivery := &model.WebhookDelivery{
+				Model:     model.Model{ID: "d1"},
+				WebhookID: "w1",
+				EventID:   "e1",
+				Event:     model.WebhookEventMessageCreated,
+				Payload:   body,
+				Status:    model.WebhookDeliveryStatusDelivering,
+				Attempts:  tt.attempts,
+			}
+			var webhooks []*model.Webhook
+			if !tt.removed {
+				webhooks = append(webhooks, &model.Webhook{Model: model.Model{ID: "w1"}, URL: server.URL, Secret: "s3cret"})
+			}
+
+			webhookRepoMock := mock_webhook.NewMockRepository(ctrl)
+			webhookRepoMock.EXPECT().ClaimDue(ctx, gomock.Any(), gomock.Any(), usecase.DeliveryBatchSize).
+				DoAndReturn(func(_ context.Context, at time.Time, leaseExpiredBefore time.Time, _ int) ([]*model.WebhookDelivery, error) {
+					assert.Equal(t, usecase.DeliveryLease, at.Sub(leaseExpiredBefore))
+					return []*model.WebhookDelivery{delivery}, nil
+				})
+			webhookRepoMock.EXPECT().GetByIds(ctx, []string{"w1"}).Return(webhooks, nil)
+			webhookRepoMock.EXPECT().UpdateDelivery(ctx, delivery).Return(nil)
+
+			webhookUsecase := usecase.New(&dependency.Repositories{
+				Webhook: webhookRepoMock,
+			}, &dependency.Gateways{
+				Webhook: webhookSender.NewClient(time.Second, nil),
+			})
+			start := time.Now()
+			assert.NoError(t, webhookUsecase.DeliverPending(ctx))
+
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			if tt.removed {
+				assert.Equal(t, 0, requests)
+				return
+			}
+			assert.Equal(t, 1, requests)
+			assert.Equal(t, tt.status, delivery.ResponseCode)
+			switch tt.wantStatus {
+			case model.WebhookDeliveryStatusSucceeded:
+				assert.NotNil(t, delivery.DeliveredAt)
+				assert.Empty(t, delivery.LastError)
+			case model.WebhookDeliveryStatusPending:
+				assert.WithinDuration(t, start.Add(tt.wantRetry), delivery.NextAttemptAt, time.Second)
+				assert.Contains(t, delivery.LastError, "502")
+			}
+		})
+	}
+}
+
+func Test_RetryDelay(t *testing.T) {
+	assert.Equal(t, usecase.RetryBaseDelay, usecase.RetryDelay(1))
+	assert.Equal(t, 2*usecase.RetryBaseDelay, usecase.RetryDelay(2))
+	assert.Equal(t, usecase.RetryMaxDelay, usecase.RetryDelay(100))
+}
+
+func Test_WebhookUsecase_Redeliver(t *testing.T) {
+	ownerId := "34251esd-d76e-401a-a3ba-7a03352812c2"
+	occurredAt := time.Now().Add(-time.Hour)
+	original := &model.WebhookDelivery{
+		Model: model.Model{ID: "d1"}, WebhookID: "w1", EventID: "e1", Event: model.WebhookEventUserCreated,
+		Payload: `{"id":"e1"}`, Status: model.WebhookDeliveryStatusDead, Attempts: usecase.MaxDeliveryAttempts, OccurredAt: occurredAt,
+	}
+
+	tests := []struct {
+		name      string
+		userId    string
+		webhookId string
+		wantErr   string
+	}{
+		{name: "Queued again as a new delivery", userId: ownerId, webhookId: "w1"},
+		{name: "Someone else's webhook", userId: "47dsga9t-d76e-401a-a3ba-7a03352812c2", webhookId: "w1", wantErr: "unauthorized"},
+		{name: "Delivery of another webhook", userId: ownerId, webhookId: "w2", wantErr: "not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			ctx := context.TODO()
+
+			webhookRepoMock := mock_webhook.NewMockRepository(ctrl)
+			webhookRepoMock.EXPECT().GetById(ctx, tt.webhookId).Return(&model.Webhook{Model: model.Model{ID: tt.webhookId}, UserID: ownerId}, nil)
+			if tt.wantErr != "unauthorized" {
+				webhookRepoMock.EXPECT().GetDeliveryById(ctx, "d1").Return(original, nil)
+			}
+			if tt.wantErr == "" {
+				webhookRepoMock.EXPECT().CreateDelivery(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, d *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+					assert.Equal(t, "e1", d.EventID)
+					assert.Equal(t, original.Payload, d.Payload)
+					assert.Equal(t, model.WebhookDeliveryStatusPending, d.Status)
+					assert.Zero(t, d.Attempts)
+					assert.Equal(t, occurredAt, d.OccurredAt)
+					d.ID = "d2"
+					return d, nil
+				})
+			}
+
+			webhookUsecase := usecase.New(&dependency.Repositories{Webhook: webhookRepoMock}, &dependency.Gateways{})
+			res, err := webhookUsecase.Redeliver(ctx, &payload.RedeliverRequest{UserID: tt.userId, WebhookID: tt.webhookId, DeliveryID: "d1"})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "d2", res.Delivery.ID)
+		})
+	}
+}
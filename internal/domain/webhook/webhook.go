@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/raihanlh/messenger-api/internal/domain/webhook/payload"
+	"gitlab.com/raihanlh/messenger-api/internal/model"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+	"gitlab.com/raihanlh/messenger-api/pkg/pagination"
+)
+
+type Repository interface {
+	Create(ctx context.Context, webhook *model.Webhook) (*model.Webhook, error)
+	GetById(ctx context.Context, id string) (*model.Webhook, error)
+	GetByIds(ctx context.Context, ids []string) ([]*model.Webhook, error)
+	GetAllByUserId(ctx context.Context, userId string) ([]*model.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	Enqueue(ctx context.Context, event *model.WebhookEvent, ownerIds []string) error
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error)
+	GetDeliveryById(ctx context.Context, id string) (*model.WebhookDelivery, error)
+	GetDeliveries(ctx context.Context, pgn *pagination.Pagination, webhookId string, status string) ([]*model.WebhookDelivery, error)
+	ClaimDue(ctx context.Context, at time.Time, leaseExpiredBefore time.Time, limit int) ([]*model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+}
+
+type Usecase interface {
+	Create(ctx context.Context, req *payload.CreateWebhookRequest) (*payload.CreateWebhookResponse, error)
+	GetAll(ctx context.Context, req *payload.GetAllWebhooksRequest) (*payload.GetAllWebhooksResponse, error)
+	Remove(ctx context.Context, req *payload.RemoveWebhookRequest) (*payload.RemoveWebhookResponse, error)
+	GetDeliveries(ctx context.Context, req *payload.GetDeliveriesRequest) (*payload.GetDeliveriesResponse, error)
+	Redeliver(ctx context.Context, req *payload.RedeliverRequest) (*payload.RedeliverResponse, error)
+	DeliverPending(ctx context.Context) error
+	QueueDeliveries(ctx context.Context, e *eventbus.Event) error
+}
+
+type Handler interface {
+	Create(ctx echo.Context) error
+	GetAll(ctx echo.Context) error
+	Remove(ctx echo.Context) error
+	GetDeliveries(ctx echo.Context) error
+	Redeliver(ctx echo.Context) error
+}
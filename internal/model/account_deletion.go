@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// AccountDeletion records a deleted account and, when asked for, when its messages get purged
+type AccountDeletion struct {
+	Model  `swaggerignore:"true"`
+	UserID string `gorm:"index" json:"-"`
+	// Nil when the user chose to keep their messages
+	PurgeAt     *time.Time `json:"purge_at,omitempty"`
+	PurgedAt    *time.Time `json:"purged_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	CancelToken string     `json:"-"`
+	User        *User      `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// Table name for gorm
+func (u *AccountDeletion) Table() string {
+	return constant.AccountDeletionTable
+}
+
+// A purge can be cancelled until it has run
+func (u *AccountDeletion) IsPurgePending() bool {
+	return u.PurgeAt != nil && u.PurgedAt == nil && u.CancelledAt == nil
+}
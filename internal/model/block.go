@@ -0,0 +1,17 @@
+package model
+
+import "gitlab.com/raihanlh/messenger-api/internal/constant"
+
+// Block means BlockerID doesn't want to hear from BlockedID anymore
+type Block struct {
+	Model     `swaggerignore:"true"`
+	BlockerID string `gorm:"uniqueIndex:idx_blocks_blocker_blocked" json:"-"`
+	BlockedID string `gorm:"uniqueIndex:idx_blocks_blocker_blocked;index" json:"-"`
+	Blocker   *User  `gorm:"foreignKey:BlockerID" json:"-"`
+	Blocked   *User  `gorm:"foreignKey:BlockedID" json:"user"`
+}
+
+// Table name for gorm
+func (u *Block) Table() string {
+	return constant.BlockTable
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// Bot is an account run by a program on behalf of its owner. It has no password and
+// authenticates with API keys.
+type Bot struct {
+	Model   `swaggerignore:"true"`
+	UserID  string `gorm:"uniqueIndex" json:"-"`
+	OwnerID string `gorm:"index" json:"-"`
+	// Webhook of the bot's account that POSTs inbound messages to the callback URL
+	CallbackWebhookID string `json:"-"`
+	// Bots can't search the user directory unless the owner grants it
+	CanSearchDirectory bool   `gorm:"default:false" json:"can_search_directory"`
+	CallbackURL        string `gorm:"-" json:"callback_url,omitempty"`
+	User               *User  `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// Table name for gorm
+func (u *Bot) Table() string {
+	return constant.BotTable
+}
+
+// APIKey authenticates a bot. Only a hash of the key is stored, the key itself is shown once.
+type APIKey struct {
+	Model  `swaggerignore:"true"`
+	UserID string `gorm:"index" json:"-"`
+	Name   string `json:"name"`
+	// Start of the key, to tell keys apart
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `gorm:"uniqueIndex" json:"-"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Table name for gorm
+func (u *APIKey) Table() string {
+	return constant.APIKeyTable
+}
+
+// Every API key starts with this, so it can be told apart from other bearer tokens
+const APIKeyPrefix = "mbk_"
+
+// IsAPIKey reports whether a bearer token looks like a bot API key
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
@@ -0,0 +1,18 @@
+package model
+
+import "gitlab.com/raihanlh/messenger-api/internal/constant"
+
+type Contact struct {
+	Model     `swaggerignore:"true"`
+	UserID    string `gorm:"uniqueIndex:idx_contacts_user_contact" json:"-"`
+	ContactID string `gorm:"uniqueIndex:idx_contacts_user_contact" json:"-"`
+	// Private name UserID gave to ContactID
+	Nickname string `json:"nickname,omitempty"`
+	User     *User  `gorm:"foreignKey:UserID" json:"-"`
+	Contact  *User  `gorm:"foreignKey:ContactID" json:"user"`
+}
+
+// Table name for gorm
+func (u *Contact) Table() string {
+	return constant.ContactTable
+}
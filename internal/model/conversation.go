@@ -2,15 +2,27 @@ package model
 
 import "gitlab.com/raihanlh/messenger-api/internal/constant"
 
+const (
+	// Started by a stranger, waiting for the receiver to accept it
+	ConversationStatusRequest  = "request"
+	ConversationStatusAccepted = "accepted"
+	ConversationStatusDeclined = "declined"
+)
+
 type Conversation struct {
 	Model       `swaggerignore:"true"`
 	SenderID    string   `json:"sender_id"`
 	ReceiverID  string   `json:"receiver_id"`
+	Status      string   `gorm:"default:accepted" json:"status"`
 	Sender      *User    `gorm:"foreignKey:SenderID" json:"-"`
 	Receiver    *User    `gorm:"foreignKey:ReceiverID" json:"-"`
 	LastMessage *Message `json:"last_message,omitempty"`
 }
 
+func (c *Conversation) IsRequest() bool {
+	return c.Status == ConversationStatusRequest
+}
+
 // Table name for gorm
 func (u *Conversation) Table() string {
 	return constant.ConversationTable
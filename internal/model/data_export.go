@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+)
+
+// DataExport is an archive of everything a user stored with us, built in the background.
+// Only the hash of the download token is stored, a link is shown once when it's issued.
+type DataExport struct {
+	Model             `swaggerignore:"true"`
+	UserID            string     `gorm:"index" json:"-"`
+	Status            string     `gorm:"default:pending" json:"status"`
+	FileKey           string     `json:"-"`
+	DownloadTokenHash string     `json:"-"`
+	Error             string     `json:"error,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	User              *User      `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// Table name for gorm
+func (u *DataExport) Table() string {
+	return constant.DataExportTable
+}
+
+func (u *DataExport) IsExpired(at time.Time) bool {
+	return u.ExpiresAt != nil && !at.Before(*u.ExpiresAt)
+}
+
+// The archive is ready, still stored and its link hasn't expired
+func (u *DataExport) IsDownloadable(at time.Time) bool {
+	return u.Status == DataExportStatusReady && u.FileKey != "" && !u.IsExpired(at)
+}
@@ -0,0 +1,37 @@
+package model
+
+import "gitlab.com/raihanlh/messenger-api/internal/constant"
+
+// Device is one client install of a user, with the public keys other clients need to
+// start an end-to-end encrypted session with it. Private keys never leave the device,
+// the server only relays public keys and ciphertext it can't read.
+type Device struct {
+	Model                 `swaggerignore:"true"`
+	UserID                string `gorm:"index" json:"user_id"`
+	Name                  string `json:"name"`
+	IdentityKey           string `json:"identity_key"`
+	SignedPrekeyID        int64  `json:"signed_prekey_id"`
+	SignedPrekey          string `json:"signed_prekey"`
+	SignedPrekeySignature string `json:"signed_prekey_signature"`
+	// One-time prekeys not claimed yet, clients upload more when it runs low
+	PrekeysLeft int64 `gorm:"->;-:migration" json:"prekeys_left"`
+	User        *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// Table name for gorm
+func (u *Device) Table() string {
+	return constant.DeviceTable
+}
+
+// OneTimePrekey is handed out once to whoever starts a session with the device, then deleted
+type OneTimePrekey struct {
+	Model     `swaggerignore:"true"`
+	DeviceID  string `gorm:"uniqueIndex:idx_one_time_prekeys_device_key" json:"-"`
+	KeyID     int64  `gorm:"uniqueIndex:idx_one_time_prekeys_device_key" json:"key_id"`
+	PublicKey string `json:"public_key"`
+}
+
+// Table name for gorm
+func (u *OneTimePrekey) Table() string {
+	return constant.OneTimePrekeyTable
+}
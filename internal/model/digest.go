@@ -0,0 +1,16 @@
+package model
+
+import "gitlab.com/raihanlh/messenger-api/internal/constant"
+
+// DigestedMessage records that a user was emailed about an unread message, so it's never in another digest
+type DigestedMessage struct {
+	Model          `swaggerignore:"true"`
+	UserID         string `gorm:"uniqueIndex:idx_digested_messages_user_message"`
+	MessageID      string `gorm:"uniqueIndex:idx_digested_messages_user_message"`
+	ConversationID string
+}
+
+// Table name for gorm
+func (u *DigestedMessage) Table() string {
+	return constant.DigestedMessageTable
+}
@@ -0,0 +1,17 @@
+package model
+
+import "gitlab.com/raihanlh/messenger-api/internal/constant"
+
+type Draft struct {
+	Model          `swaggerignore:"true"`
+	UserID         string        `gorm:"uniqueIndex:idx_drafts_user_conversation" json:"-"`
+	ConversationID string        `gorm:"uniqueIndex:idx_drafts_user_conversation" json:"conversation_id"`
+	Text           string        `json:"text"`
+	User           *User         `gorm:"foreignKey:UserID" json:"-"`
+	Conversation   *Conversation `gorm:"foreignKey:ConversationID" json:"-"`
+}
+
+// Table name for gorm
+func (u *Draft) Table() string {
+	return constant.DraftTable
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// Number of characters of the last message kept in the inbox
+const InboxPreviewLength = 100
+
+// Inbox is the conversation list projection, one row per user and conversation.
+// It is kept up to date whenever messages are sent, read or deleted.
+type Inbox struct {
+	Model               `swaggerignore:"true"`
+	UserID              string        `gorm:"uniqueIndex:idx_inboxes_user_conversation;index:idx_inboxes_user_activity,priority:1" json:"-"`
+	ConversationID      string        `gorm:"uniqueIndex:idx_inboxes_user_conversation" json:"conversation_id"`
+	LastMessageID       string        `json:"last_message_id,omitempty"`
+	LastMessageSenderID string        `json:"-"`
+	LastMessagePreview  string        `json:"last_message_preview,omitempty"`
+	LastActivityAt      time.Time     `gorm:"index:idx_inboxes_user_activity,priority:2,sort:desc" json:"last_activity_at"`
+	UnreadCount         int64         `gorm:"default:0" json:"unread_count"`
+	User                *User         `gorm:"foreignKey:UserID" json:"-"`
+	Conversation        *Conversation `gorm:"foreignKey:ConversationID" json:"-"`
+}
+
+// Table name for gorm
+func (u *Inbox) Table() string {
+	return constant.InboxTable
+}
+
+// Preview of a message as stored in the inbox
+func InboxPreview(text string) string {
+	runes := []rune(text)
+	if len(runes) <= InboxPreviewLength {
+		return text
+	}
+	return string(runes[:InboxPreviewLength])
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// IncomingWebhook is a secret URL that posts into a conversation. Messages it posts are
+// sent by its integration identity, a bot user named after the hook.
+type IncomingWebhook struct {
+	Model          `swaggerignore:"true"`
+	ConversationID string `gorm:"index" json:"conversation_id"`
+	CreatorID      string `json:"creator_id"`
+	UserID         string `json:"-"`
+	// Only a hash of the token in the URL is stored, the URL is shown once
+	TokenHash string `gorm:"uniqueIndex" json:"-"`
+	// Posts allowed per minute
+	RateLimit int `json:"rate_limit"`
+	// Fixed window the rate limit counts posts in
+	WindowStartedAt time.Time  `json:"-"`
+	WindowCount     int        `gorm:"default:0" json:"-"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+	Integration     *User      `gorm:"foreignKey:UserID" json:"integration"`
+}
+
+// Table name for gorm
+func (u *IncomingWebhook) Table() string {
+	return constant.IncomingWebhookTable
+}
@@ -4,6 +4,13 @@ import (
 	"time"
 
 	"gitlab.com/raihanlh/messenger-api/internal/constant"
+	"gitlab.com/raihanlh/messenger-api/pkg/cipher"
+)
+
+const (
+	MessageModePlain = "plain"
+	// The server only relays per device ciphertext, MessageText stays empty
+	MessageModeE2E = "e2e"
 )
 
 type Message struct {
@@ -15,6 +22,65 @@ type Message struct {
 	Conversation   *Conversation `gorm:"foreignKey:ConversationID" json:"-"`
 	Sender         *User         `gorm:"foreignKey:SenderID" json:"sender"`
 	IsRead         bool          `gorm:"default:false" json:"-"`
+	EditedAt       *time.Time    `json:"edited_at,omitempty"`
+	Mode           string        `gorm:"default:plain" json:"mode"`
+	// Read receipt, only shown to the sender and not when the receiver blocked them
+	Read *bool `gorm:"-" json:"read,omitempty"`
+	// Set when MessageText holds ciphertext, see Seal
+	KeyID   string `gorm:"default:''" json:"-"`
+	DataKey string `gorm:"default:''" json:"-"`
+	// Ciphertext of an e2e message, one per recipient device. Reads only carry the
+	// caller's devices.
+	Contents []*MessageDeviceContent `gorm:"foreignKey:MessageID" json:"content,omitempty"`
+}
+
+// MessageDeviceContent is an e2e message encrypted for one device. The server stores
+// and delivers it as is, it is never decrypted, searched or previewed.
+type MessageDeviceContent struct {
+	Model      `swaggerignore:"true"`
+	MessageID  string `gorm:"uniqueIndex:idx_message_device_contents_message_device" json:"-"`
+	DeviceID   string `gorm:"uniqueIndex:idx_message_device_contents_message_device;index" json:"device_id"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// Table name for gorm
+func (u *MessageDeviceContent) Table() string {
+	return constant.MessageDeviceContentTable
+}
+
+func (m *Message) IsE2E() bool {
+	return m.Mode == MessageModeE2E
+}
+
+func (m *Message) IsDeleted() bool {
+	return m.DeletedAt.Valid
+}
+
+// Seal replaces MessageText with its ciphertext, it stays in plaintext when the keyring is disabled
+func (m *Message) Seal(keys *cipher.Keyring) error {
+	envelope, err := keys.Seal(m.MessageText)
+	if err != nil {
+		return err
+	}
+	m.MessageText, m.KeyID, m.DataKey = envelope.Ciphertext, envelope.KeyID, envelope.DataKey
+	return nil
+}
+
+// Open replaces the ciphertext in MessageText with the plaintext
+func (m *Message) Open(keys *cipher.Keyring) error {
+	if m.KeyID == "" {
+		return nil
+	}
+	text, err := keys.Open(m.Envelope())
+	if err != nil {
+		return err
+	}
+	m.MessageText, m.KeyID, m.DataKey = text, "", ""
+	return nil
+}
+
+func (m *Message) Envelope() *cipher.Envelope {
+	return &cipher.Envelope{KeyID: m.KeyID, DataKey: m.DataKey, Ciphertext: m.MessageText}
 }
 
 // Table name for gorm
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// MessageSearchEntry is the plaintext copy of a message used for search while the
+// message itself is encrypted. Entries are only written when the search index policy
+// allows keeping message text in plaintext.
+type MessageSearchEntry struct {
+	Model          `swaggerignore:"true"`
+	MessageID      string `gorm:"uniqueIndex"`
+	ConversationID string `gorm:"index"`
+	SenderID       string `gorm:"index"`
+	Body           string
+	SentAt         time.Time
+}
+
+// Table name for gorm
+func (u *MessageSearchEntry) Table() string {
+	return constant.MessageSearchEntryTable
+}
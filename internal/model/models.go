@@ -4,4 +4,28 @@ var Models = []interface{}{
 	&User{},
 	&Conversation{},
 	&Message{},
+	&Draft{},
+	&UserParticipant{},
+	&Block{},
+	&Contact{},
+	&DataExport{},
+	&AccountDeletion{},
+	&Inbox{},
+	&MessageSearchEntry{},
+	&Device{},
+	&OneTimePrekey{},
+	&MessageDeviceContent{},
+	&PushToken{},
+	&PushJob{},
+	&DigestedMessage{},
+	&Webhook{},
+	&WebhookDelivery{},
+	&Bot{},
+	&APIKey{},
+	&IncomingWebhook{},
+	&SlashCommand{},
+	&OutboxEvent{},
+	&Session{},
+	&RefreshToken{},
+	&RevokedToken{},
 }
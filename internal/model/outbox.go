@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// Domain events, written to the outbox with the change they report
+const (
+	EventMessageCreated      = "message.created"
+	EventConversationCreated = "conversation.created"
+	EventUserRegistered      = "user.registered"
+	EventUserDeleted         = "user.deleted"
+)
+
+const (
+	OutboxStatusPending     = "pending"
+	OutboxStatusDispatching = "dispatching"
+	OutboxStatusDispatched  = "dispatched"
+	// Out of attempts, some subscribers never handled it
+	OutboxStatusDead = "dead"
+)
+
+// OutboxEvent is a domain event stored in the transaction of the change it reports and
+// handed to the in-process subscribers afterwards. Dispatched events are kept as a trail.
+type OutboxEvent struct {
+	Model   `swaggerignore:"true"`
+	Type    string `gorm:"index"`
+	Payload string `gorm:"type:jsonb"`
+	Status  string `gorm:"index;default:pending"`
+	// Subscribers that still have to handle the event, nil until the first attempt
+	Pending       []string  `gorm:"type:jsonb;serializer:json"`
+	Attempts      int       `gorm:"default:0"`
+	NextAttemptAt time.Time `gorm:"index"`
+	LastError     string
+	OccurredAt    time.Time
+	DispatchedAt  *time.Time
+}
+
+// Table name for gorm
+func (u *OutboxEvent) Table() string {
+	return constant.OutboxEventTable
+}
+
+// Payload of message.created. RecipientIDs get the message as unread, ParticipantIDs
+// are everyone in the conversation.
+type MessageCreatedEvent struct {
+	MessageID      string    `json:"message_id"`
+	ConversationID string    `json:"conversation_id"`
+	SenderID       string    `json:"sender_id"`
+	ReceiverID     string    `json:"receiver_id,omitempty"`
+	RecipientIDs   []string  `json:"recipient_ids"`
+	ParticipantIDs []string  `json:"participant_ids"`
+	Mode           string    `json:"mode"`
+	SentAt         time.Time `json:"sent_at"`
+}
+
+// Payload of conversation.created
+type ConversationCreatedEvent struct {
+	ConversationID string `json:"conversation_id"`
+	SenderID       string `json:"sender_id"`
+	ReceiverID     string `json:"receiver_id"`
+	Status         string `json:"status"`
+}
+
+// Payload of user.registered
+type UserRegisteredEvent struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// Payload of user.deleted
+type UserDeletedEvent struct {
+	UserID string `json:"user_id"`
+}
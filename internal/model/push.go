@@ -0,0 +1,42 @@
+package model
+
+import "gitlab.com/raihanlh/messenger-api/internal/constant"
+
+// PushToken is where a push provider delivers notifications for one app install
+type PushToken struct {
+	Model    `swaggerignore:"true"`
+	UserID   string `gorm:"index" json:"-"`
+	Platform string `json:"platform"`
+	Token    string `gorm:"uniqueIndex" json:"token"`
+}
+
+// Table name for gorm
+func (u *PushToken) Table() string {
+	return constant.PushTokenTable
+}
+
+const (
+	PushJobStatusPending    = "pending"
+	PushJobStatusProcessing = "processing"
+	PushJobStatusSent       = "sent"
+	// Nothing to send, e.g. the receiver was online or muted the conversation
+	PushJobStatusSkipped = "skipped"
+	PushJobStatusFailed  = "failed"
+)
+
+// PushJob notifies one receiver of a new message, it's queued from the message.created event and sent in the background
+type PushJob struct {
+	Model `swaggerignore:"true"`
+	// One job per receiver and message, so a redelivered event doesn't push twice
+	UserID         string `gorm:"index;uniqueIndex:idx_push_jobs_user_message"`
+	MessageID      string `gorm:"uniqueIndex:idx_push_jobs_user_message"`
+	ConversationID string
+	SenderID       string
+	Status         string `gorm:"index;default:pending"`
+	Attempts       int    `gorm:"default:0"`
+}
+
+// Table name for gorm
+func (u *PushJob) Table() string {
+	return constant.PushJobTable
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// RevokedToken is an access token that was logged out before it expired. It's only kept
+// until then, an expired token is rejected anyway.
+type RevokedToken struct {
+	Model     `swaggerignore:"true"`
+	JTI       string    `gorm:"uniqueIndex"`
+	ExpiresAt time.Time `gorm:"index"`
+}
+
+// Table name for gorm
+func (u *RevokedToken) Table() string {
+	return constant.RevokedTokenTable
+}
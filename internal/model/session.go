@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// Session is one login of a user, the token issued for it carries its id. A revoked
+// session's token is rejected even though it hasn't expired.
+type Session struct {
+	Model      `swaggerignore:"true"`
+	UserID     string `gorm:"index"`
+	DeviceName string
+	UserAgent  string
+	IP         string
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time `gorm:"index"`
+}
+
+// Table name for gorm
+func (u *Session) Table() string {
+	return constant.SessionTable
+}
+
+// Active sessions can authenticate requests
+func (u *Session) IsActive(at time.Time) bool {
+	return u.RevokedAt == nil && at.Before(u.ExpiresAt)
+}
+
+// RefreshToken renews the access token of a session. Every refresh replaces it, a token
+// used a second time means it leaked and the whole session is revoked.
+type RefreshToken struct {
+	Model     `swaggerignore:"true"`
+	SessionID string `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// Table name for gorm
+func (u *RefreshToken) Table() string {
+	return constant.RefreshTokenTable
+}
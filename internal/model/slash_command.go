@@ -0,0 +1,22 @@
+package model
+
+import "gitlab.com/raihanlh/messenger-api/internal/constant"
+
+// SlashCommand is a custom command of a conversation, run by POSTing the invocation to
+// URL signed with Secret
+type SlashCommand struct {
+	Model          `swaggerignore:"true"`
+	ConversationID string `gorm:"uniqueIndex:idx_slash_commands_conversation_name" json:"conversation_id"`
+	CreatorID      string `json:"creator_id"`
+	// Without the slash, lowercase
+	Name        string `gorm:"uniqueIndex:idx_slash_commands_conversation_name" json:"name"`
+	Description string `json:"description"`
+	Usage       string `json:"usage"`
+	URL         string `json:"url"`
+	Secret      string `json:"-"`
+}
+
+// Table name for gorm
+func (u *SlashCommand) Table() string {
+	return constant.SlashCommandTable
+}
@@ -1,6 +1,10 @@
 package model
 
-import "gitlab.com/raihanlh/messenger-api/internal/constant"
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
 
 type User struct {
 	Model    `swaggerignore:"true"`
@@ -8,9 +12,55 @@ type User struct {
 	Email    string `json:"email,omitempty"`
 	Password string `json:"-" swaggerignore:"true"`
 	PhotoURL string `json:"photo_url,omitempty"`
+	IsBot    bool   `gorm:"default:false" json:"is_bot"`
+	// One of RoleUser, RoleModerator or RoleAdmin
+	Role string `gorm:"default:user" json:"role"`
+	// Last authenticated request, used to tell whether the user is online
+	LastSeenAt *time.Time `json:"-" swaggerignore:"true"`
+	// Stops the email digest of unread messages
+	DigestOptOut bool `gorm:"default:false" json:"-" swaggerignore:"true"`
+}
+
+// Roles from least to most privileged, each role has the permissions of the ones before it
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
 }
 
+// Shown in place of the name of a user who deleted their account
+const DeletedAccountName = "Deleted account"
+
 // Table name for gorm
 func (u *User) Table() string {
 	return constant.UserTable
 }
+
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt.Valid
+}
+
+// Online when the user made a request within the window before at
+func (u *User) IsOnline(at time.Time, window time.Duration) bool {
+	return u.LastSeenAt != nil && u.LastSeenAt.After(at.Add(-window))
+}
+
+// HasRole reports whether the user has the role or a more privileged one. Users created
+// before roles existed have none and count as RoleUser.
+func (u *User) HasRole(role string) bool {
+	current := u.Role
+	if current == "" {
+		current = RoleUser
+	}
+	return roleRanks[current] >= roleRanks[role] && roleRanks[role] > 0
+}
+
+func (u *User) IsAdmin() bool {
+	return u.HasRole(RoleAdmin)
+}
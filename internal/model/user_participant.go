@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+// UserParticipant holds the settings a single participant has for a conversation
+type UserParticipant struct {
+	Model          `swaggerignore:"true"`
+	UserID         string        `gorm:"uniqueIndex:idx_user_participants_user_conversation" json:"-"`
+	ConversationID string        `gorm:"uniqueIndex:idx_user_participants_user_conversation" json:"conversation_id"`
+	IsArchived     bool          `gorm:"default:false" json:"archived"`
+	MutedUntil     *time.Time    `json:"muted_until,omitempty"`
+	PinnedOrder    *int          `json:"pinned_order,omitempty"`
+	ClearedAt      *time.Time    `json:"cleared_at,omitempty"`
+	User           *User         `gorm:"foreignKey:UserID" json:"-"`
+	Conversation   *Conversation `gorm:"foreignKey:ConversationID" json:"-"`
+}
+
+// Table name for gorm
+func (u *UserParticipant) Table() string {
+	return constant.UserParticipantTable
+}
+
+func (u *UserParticipant) IsMuted(at time.Time) bool {
+	return u.MutedUntil != nil && u.MutedUntil.After(at)
+}
+
+func (u *UserParticipant) IsPinned() bool {
+	return u.PinnedOrder != nil
+}
+
+// Messages sent at or before the cutoff are hidden from this participant
+func (u *UserParticipant) IsCleared(sentAt time.Time) bool {
+	return u.ClearedAt != nil && !sentAt.After(*u.ClearedAt)
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gitlab.com/raihanlh/messenger-api/internal/constant"
+)
+
+const (
+	WebhookEventMessageCreated      = "message.created"
+	WebhookEventMessageReceived     = "message.received" // only sent to the receiver
+	WebhookEventConversationCreated = "conversation.created"
+	WebhookEventUserCreated         = "user.created"
+)
+
+// Webhook subscribes a URL to events in its owner's conversations. Requests are signed
+// with Secret, which is only shown when the webhook is created.
+type Webhook struct {
+	Model  `swaggerignore:"true"`
+	UserID string   `gorm:"index" json:"-"`
+	URL    string   `json:"url"`
+	Secret string   `json:"-"`
+	Events []string `gorm:"type:jsonb;serializer:json" json:"events"`
+}
+
+// Table name for gorm
+func (u *Webhook) Table() string {
+	return constant.WebhookTable
+}
+
+const (
+	WebhookDeliveryStatusPending    = "pending"
+	WebhookDeliveryStatusDelivering = "delivering"
+	WebhookDeliveryStatusSucceeded  = "succeeded"
+	// Out of attempts, only sent again when redelivered by hand
+	WebhookDeliveryStatusDead = "dead"
+)
+
+// WebhookDelivery is one event sent to one webhook, kept as a log of its attempts
+type WebhookDelivery struct {
+	Model     `swaggerignore:"true"`
+	WebhookID string `gorm:"index" json:"webhook_id"`
+	EventID   string `json:"event_id"`
+	Event     string `json:"event"`
+	// Body as it's sent, redeliveries send it again unchanged
+	Payload       string     `json:"payload"`
+	Status        string     `gorm:"index;default:pending" json:"status"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+// Table name for gorm
+func (u *WebhookDelivery) Table() string {
+	return constant.WebhookDeliveryTable
+}
+
+// WebhookEvent is the JSON body of a delivery
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+func NewWebhookEvent(eventType string, data interface{}) *WebhookEvent {
+	return &WebhookEvent{
+		ID:        uuid.NewV4().String(),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+}
+
+// Data of message.created, the text is left out of e2e messages
+type WebhookMessageData struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	SenderID       string    `json:"sender_id"`
+	ReceiverID     string    `json:"receiver_id"`
+	Mode           string    `json:"mode"`
+	Message        string    `json:"message,omitempty"`
+	SentAt         time.Time `json:"sent_at"`
+}
+
+// Data of conversation.created
+type WebhookConversationData struct {
+	ID         string `json:"id"`
+	SenderID   string `json:"sender_id"`
+	ReceiverID string `json:"receiver_id"`
+	Status     string `json:"status"`
+}
+
+// Data of user.created, only what the user directory shows anyway
+type WebhookUserData struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
@@ -5,15 +5,21 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	uuid "github.com/satori/go.uuid"
 	"gitlab.com/raihanlh/messenger-api/config"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Claims of an access token. The registered ones carry its id (jti), which is what a
+// logout revokes, and its expiry, which is checked when the token is parsed.
 type Claims struct {
 	jwt.RegisteredClaims
 	Email  string `json:"email"`
 	UserID string `json:"id"`
-	Exp    int64  `json:"exp"`
+	// Role when the token was issued, authorization reads the current one from the user
+	Role string `json:"role"`
+	// Session the token was issued for, it stops working when the session is revoked
+	SessionID string `json:"sid"`
 }
 
 func HashPassword(password string) ([]byte, error) {
@@ -21,15 +27,25 @@ func HashPassword(password string) ([]byte, error) {
 	return bytes, err
 }
 
-func GenerateToken(email string, id string, exp time.Time) (string, error) {
+func GenerateToken(email string, id string, role string, sessionId string, exp time.Time) (string, error) {
+	conf := config.New()
+	now := time.Now()
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-		Email:  email,
-		UserID: id,
-		Exp:    exp.Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewV4().String(),
+			Issuer:    conf.AppName,
+			Subject:   id,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+		Email:     email,
+		UserID:    id,
+		Role:      role,
+		SessionID: sessionId,
 	})
 
-	conf := config.New()
-
 	t, err := token.SignedString([]byte(conf.Secret))
 	if err != nil {
 		log.Println("failed to create token", err.Error())
@@ -0,0 +1,199 @@
+package cipher
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrUnknownKey = errors.New("cipher: unknown key id")
+	ErrMalformed  = errors.New("cipher: malformed ciphertext")
+)
+
+// Envelope is a value sealed with its own random data key. Only the data key is
+// encrypted with a master key, so rotating the master key rewraps the data key
+// without touching the ciphertext.
+type Envelope struct {
+	KeyID      string
+	DataKey    string
+	Ciphertext string
+}
+
+// Keyring holds the master keys by id. New values are sealed with the active key,
+// older keys stay around until every envelope using them has been rewrapped.
+// A nil or empty keyring leaves values in plaintext.
+type Keyring struct {
+	activeId string
+	keys     map[string][]byte
+}
+
+func NewKeyring(activeId string, keys map[string][]byte) (*Keyring, error) {
+	for id, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("cipher: key %q must be 32 bytes, got %d", id, len(key))
+		}
+	}
+	if activeId != "" {
+		if _, ok := keys[activeId]; !ok {
+			return nil, fmt.Errorf("cipher: active key %q is not configured", activeId)
+		}
+	}
+	return &Keyring{activeId: activeId, keys: keys}, nil
+}
+
+// ParseKeys reads a comma separated list of id:base64 pairs, e.g. "2024a:AAEC...,2024b:BAUG..."
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("cipher: key %q must look like id:base64", pair)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("cipher: key %q is not valid base64: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.activeId != ""
+}
+
+func (k *Keyring) ActiveKeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.activeId
+}
+
+// Seal encrypts plaintext under a fresh data key wrapped with the active key
+func (k *Keyring) Seal(plaintext string) (*Envelope, error) {
+	if !k.Enabled() {
+		return &Envelope{Ciphertext: plaintext}, nil
+	}
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := seal(k.keys[k.activeId], dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		KeyID:      k.activeId,
+		DataKey:    base64.StdEncoding.EncodeToString(wrapped),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+// Open returns the plaintext of an envelope, envelopes without a key id were never encrypted
+func (k *Keyring) Open(e *Envelope) (string, error) {
+	if e.KeyID == "" {
+		return e.Ciphertext, nil
+	}
+	dataKey, err := k.unwrap(e)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(e.Ciphertext)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap moves an envelope to the active key. Only the data key is re-encrypted,
+// plaintext envelopes are sealed for the first time.
+func (k *Keyring) Rewrap(e *Envelope) (*Envelope, error) {
+	if !k.Enabled() {
+		return nil, errors.New("cipher: no active key to rewrap with")
+	}
+	if e.KeyID == "" {
+		return k.Seal(e.Ciphertext)
+	}
+	if e.KeyID == k.activeId {
+		return e, nil
+	}
+	dataKey, err := k.unwrap(e)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := seal(k.keys[k.activeId], dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		KeyID:      k.activeId,
+		DataKey:    base64.StdEncoding.EncodeToString(wrapped),
+		Ciphertext: e.Ciphertext,
+	}, nil
+}
+
+func (k *Keyring) unwrap(e *Envelope) ([]byte, error) {
+	if k == nil {
+		return nil, ErrUnknownKey
+	}
+	key, ok := k.keys[e.KeyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(e.DataKey)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return open(key, wrapped)
+}
+
+// The nonce is prepended to the AES-GCM output
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key []byte, sealed []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (gocipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return gocipher.NewGCM(block)
+}
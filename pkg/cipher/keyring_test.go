@@ -0,0 +1,101 @@
+package cipher_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/cipher"
+)
+
+func testKeys() map[string][]byte {
+	return map[string][]byte{
+		"old": bytes.Repeat([]byte{1}, 32),
+		"new": bytes.Repeat([]byte{2}, 32),
+	}
+}
+
+func Test_Keyring_SealOpen(t *testing.T) {
+	keys, err := cipher.NewKeyring("old", testKeys())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	envelope, err := keys.Seal("hello there")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "old", envelope.KeyID)
+	assert.NotContains(t, envelope.Ciphertext, "hello")
+
+	text, err := keys.Open(envelope)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello there", text)
+
+	// Every value gets its own data key
+	other, _ := keys.Seal("hello there")
+	assert.NotEqual(t, envelope.DataKey, other.DataKey)
+	assert.NotEqual(t, envelope.Ciphertext, other.Ciphertext)
+}
+
+func Test_Keyring_Rewrap(t *testing.T) {
+	oldKeys, _ := cipher.NewKeyring("old", testKeys())
+	newKeys, _ := cipher.NewKeyring("new", testKeys())
+
+	envelope, _ := oldKeys.Seal("hello there")
+	rewrapped, err := newKeys.Rewrap(envelope)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "new", rewrapped.KeyID)
+	assert.Equal(t, envelope.Ciphertext, rewrapped.Ciphertext, "only the data key is re-encrypted")
+
+	text, err := newKeys.Open(rewrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello there", text)
+
+	// Plaintext values are sealed for the first time
+	sealed, err := newKeys.Rewrap(&cipher.Envelope{Ciphertext: "legacy"})
+	assert.NoError(t, err)
+	assert.Equal(t, "new", sealed.KeyID)
+	text, _ = newKeys.Open(sealed)
+	assert.Equal(t, "legacy", text)
+}
+
+func Test_Keyring_Open_Errors(t *testing.T) {
+	keys, _ := cipher.NewKeyring("old", testKeys())
+	envelope, _ := keys.Seal("hello there")
+
+	onlyNew, _ := cipher.NewKeyring("new", map[string][]byte{"new": testKeys()["new"]})
+	_, err := onlyNew.Open(envelope)
+	assert.ErrorIs(t, err, cipher.ErrUnknownKey)
+
+	ciphertext, _ := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	ciphertext[len(ciphertext)-1] ^= 1
+	tampered := *envelope
+	tampered.Ciphertext = base64.StdEncoding.EncodeToString(ciphertext)
+	_, err = keys.Open(&tampered)
+	assert.Error(t, err)
+
+	// Values that were never encrypted need no key
+	var disabled *cipher.Keyring
+	text, err := disabled.Open(&cipher.Envelope{Ciphertext: "plain"})
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", text)
+}
+
+func Test_ParseKeys(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{3}, 32))
+	keys, err := cipher.ParseKeys("a:" + encoded + ", b:" + encoded)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	_, err = cipher.ParseKeys("missing-separator")
+	assert.Error(t, err)
+
+	_, err = cipher.NewKeyring("a", map[string][]byte{"a": []byte("short")})
+	assert.Error(t, err)
+	_, err = cipher.NewKeyring("c", keys)
+	assert.Error(t, err)
+}
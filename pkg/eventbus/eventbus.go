@@ -0,0 +1,84 @@
+// Package eventbus hands domain events to the in-process subscribers of their type.
+// Events are read from the outbox, so a subscriber can see one more than once and has
+// to be idempotent.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Event struct {
+	ID         string
+	Type       string
+	OccurredAt time.Time
+	Payload    []byte
+}
+
+// Decode unmarshals the payload into v
+func (e *Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+type HandlerFunc func(ctx context.Context, e *Event) error
+
+type subscriber struct {
+	name   string
+	handle HandlerFunc
+}
+
+type Bus struct {
+	subscribers map[string][]subscriber
+}
+
+func New() *Bus {
+	return &Bus{subscribers: map[string][]subscriber{}}
+}
+
+// Subscribe runs handle for every event of the type. The name identifies the subscriber
+// when a delivery is retried, so it has to be unique per type and stable across releases.
+func (b *Bus) Subscribe(eventType string, name string, handle HandlerFunc) {
+	b.subscribers[eventType] = append(b.subscribers[eventType], subscriber{name: name, handle: handle})
+}
+
+// Publish runs the subscribers of the event's type, only those named in only when it
+// isn't nil. It returns the names of the subscribers that failed, to retry them alone.
+func (b *Bus) Publish(ctx context.Context, e *Event, only []string) ([]string, error) {
+	var failed []string
+	var errs []string
+	for _, s := range b.subscribers[e.Type] {
+		if only != nil && !contains(only, s.name) {
+			continue
+		}
+		if err := s.run(ctx, e); err != nil {
+			failed = append(failed, s.name)
+			errs = append(errs, s.name+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return failed, fmt.Errorf("eventbus: %s", strings.Join(errs, "; "))
+	}
+	return nil, nil
+}
+
+// A panicking subscriber fails like one returning an error, the others still run
+func (s subscriber) run(ctx context.Context, e *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.handle(ctx, e)
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
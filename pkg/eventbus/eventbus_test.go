@@ -0,0 +1,48 @@
+package eventbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+func Test_Bus_Publish(t *testing.T) {
+	bus := eventbus.New()
+	var ran []string
+	bus.Subscribe("message.created", "push", func(ctx context.Context, e *eventbus.Event) error {
+		ran = append(ran, "push")
+		return nil
+	})
+	bus.Subscribe("message.created", "webhooks", func(ctx context.Context, e *eventbus.Event) error {
+		ran = append(ran, "webhooks")
+		return errors.New("database is down")
+	})
+	bus.Subscribe("message.created", "search", func(ctx context.Context, e *eventbus.Event) error {
+		ran = append(ran, "search")
+		panic("oops")
+	})
+	bus.Subscribe("user.registered", "welcome", func(ctx context.Context, e *eventbus.Event) error {
+		ran = append(ran, "welcome")
+		return nil
+	})
+	e := &eventbus.Event{ID: "e1", Type: "message.created", Payload: []byte(`{"message_id":"m1"}`)}
+
+	failed, err := bus.Publish(context.TODO(), e, nil)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"webhooks", "search"}, failed)
+	assert.Equal(t, []string{"push", "webhooks", "search"}, ran, "a failing subscriber doesn't stop the others")
+
+	// A retry only runs the subscribers that failed
+	ran = nil
+	_, _ = bus.Publish(context.TODO(), e, []string{"webhooks"})
+	assert.Equal(t, []string{"webhooks"}, ran)
+
+	var payload struct {
+		MessageID string `json:"message_id"`
+	}
+	assert.NoError(t, e.Decode(&payload))
+	assert.Equal(t, "m1", payload.MessageID)
+}
@@ -0,0 +1,16 @@
+package mail
+
+import "context"
+
+// Message is an email with a plain text body and an HTML alternative
+type Message struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers emails, it returns once the server accepted the message
+type Mailer interface {
+	Send(ctx context.Context, m *Message) error
+}
@@ -0,0 +1,75 @@
+package smtp_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/mail"
+	"gitlab.com/raihanlh/messenger-api/pkg/mail/smtp"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+)
+
+func Test_Mailer_Send(t *testing.T) {
+	server := helper.NewSMTPServer(t)
+	mailer := smtp.New(server.Host, server.Port, "", "", "Messenger <no-reply@example.com>")
+
+	err := mailer.Send(context.TODO(), &mail.Message{
+		To:      []string{"alice@example.com"},
+		Subject: "2 unread messages — Messenger",
+		Text:    "Hi Alice",
+		HTML:    "<p>Hi Alice</p>",
+	})
+	assert.NoError(t, err)
+
+	mails := server.Mails()
+	if !assert.Len(t, mails, 1) {
+		return
+	}
+	assert.Equal(t, "no-reply@example.com", mails[0].From)
+	assert.Equal(t, []string{"alice@example.com"}, mails[0].To)
+
+	msg, err := netmail.ReadMessage(strings.NewReader(mails[0].Data))
+	if !assert.NoError(t, err) {
+		return
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	assert.Equal(t, "2 unread messages — Messenger", subject)
+	assert.Equal(t, "alice@example.com", msg.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		body, _ := io.ReadAll(part)
+		bodies = append(bodies, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	assert.Equal(t, []string{
+		"text/plain; charset=utf-8: Hi Alice",
+		"text/html; charset=utf-8: <p>Hi Alice</p>",
+	}, bodies)
+}
+
+func Test_Mailer_Send_Rejected(t *testing.T) {
+	server := helper.NewSMTPServer(t)
+	server.RejectRcpt = "550"
+	mailer := smtp.New(server.Host, server.Port, "", "", "no-reply@example.com")
+
+	err := mailer.Send(context.TODO(), &mail.Message{To: []string{"nobody@example.com"}, Subject: "Hi", Text: "Hi"})
+	assert.Error(t, err)
+	assert.Empty(t, server.Mails())
+}
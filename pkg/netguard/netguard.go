@@ -0,0 +1,109 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidURL = errors.New("must be an absolute http or https url")
+	ErrUnresolved = errors.New("must have a host that resolves")
+	ErrNotPublic  = errors.New("must not point to a loopback, private, link-local or unspecified address")
+	errDialedHost = errors.New("netguard: dialed address is not an ip")
+)
+
+// Resolver looks up the addresses of a host, *net.Resolver satisfies it
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// Guard keeps requests to URLs users registered, webhooks, bot callbacks and slash commands,
+// away from the server's own network. URLs are checked when they are registered and
+// every connection is checked again when it is dialed, so a host that resolves to another
+// address later (DNS rebinding) is still refused.
+type Guard struct {
+	Resolver Resolver
+	// Lets local development reach services on the same machine or network
+	AllowPrivate bool
+}
+
+func New(allowPrivate bool) *Guard {
+	return &Guard{
+		Resolver:     net.DefaultResolver,
+		AllowPrivate: allowPrivate,
+	}
+}
+
+// IsPublic reports whether ip may be reached, loopback, private, link-local, multicast
+// and unspecified addresses may not
+func IsPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// CheckURL accepts absolute http and https URLs whose host only resolves to public addresses
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Hostname() == "" {
+		return ErrInvalidURL
+	}
+	if g.AllowPrivate {
+		return nil
+	}
+
+	host := target.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublic(ip) {
+			return ErrNotPublic
+		}
+		return nil
+	}
+	addrs, err := g.Resolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return ErrUnresolved
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr.IP) {
+			return ErrNotPublic
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control func, it sees the address actually being connected to
+func (g *Guard) Control(network string, address string, _ syscall.RawConn) error {
+	if g.AllowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errDialedHost
+	}
+	if !IsPublic(ip) {
+		return ErrNotPublic
+	}
+	return nil
+}
+
+// Transport is an http.Transport that only connects to addresses the guard accepts.
+// Proxies from the environment are not used, they would be checked instead of the target.
+func (g *Guard) Transport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   g.Control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
@@ -0,0 +1,65 @@
+package netguard_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/netguard"
+	"gitlab.com/raihanlh/messenger-api/testing/helper"
+)
+
+func Test_Guard_CheckURL(t *testing.T) {
+	guard := helper.URLGuard(helper.StaticResolver{
+		"hooks.example.id":    {"93.184.216.34"},
+		"internal.example.id": {"10.0.0.5"},
+		// One private answer is enough to refuse the host
+		"mixed.example.id": {"93.184.216.34", "127.0.0.1"},
+		"v6.example.id":    {"2606:2800:220:1:248:1893:25c8:1946"},
+	})
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "Public host", url: "https://hooks.example.id/events"},
+		{name: "Public IPv6 host", url: "http://v6.example.id:8080/events"},
+		{name: "Public address", url: "http://93.184.216.34/events"},
+		{name: "Not http", url: "ftp://hooks.example.id/events", wantErr: netguard.ErrInvalidURL},
+		{name: "Relative", url: "/events", wantErr: netguard.ErrInvalidURL},
+		{name: "Unknown host", url: "https://nowhere.example.id", wantErr: netguard.ErrUnresolved},
+		{name: "Host resolving to a private address", url: "https://internal.example.id", wantErr: netguard.ErrNotPublic},
+		{name: "Host with a private address among others", url: "https://mixed.example.id", wantErr: netguard.ErrNotPublic},
+		{name: "Loopback", url: "http://127.0.0.1:8080", wantErr: netguard.ErrNotPublic},
+		{name: "IPv6 loopback", url: "http://[::1]/", wantErr: netguard.ErrNotPublic},
+		{name: "Private", url: "http://192.168.1.10/", wantErr: netguard.ErrNotPublic},
+		{name: "Link-local metadata service", url: "http://169.254.169.254/latest/meta-data", wantErr: netguard.ErrNotPublic},
+		{name: "Unspecified", url: "http://0.0.0.0/", wantErr: netguard.ErrNotPublic},
+		{name: "IPv4-mapped loopback", url: "http://[::ffff:127.0.0.1]/", wantErr: netguard.ErrNotPublic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := guard.CheckURL(context.TODO(), tt.url)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_Guard_CheckURL_AllowPrivate(t *testing.T) {
+	guard := netguard.New(true)
+	assert.NoError(t, guard.CheckURL(context.TODO(), "http://127.0.0.1:8080/events"))
+	assert.ErrorIs(t, guard.CheckURL(context.TODO(), "ftp://127.0.0.1/events"), netguard.ErrInvalidURL)
+}
+
+func Test_Guard_Control(t *testing.T) {
+	guard := netguard.New(false)
+	assert.NoError(t, guard.Control("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, guard.Control("tcp4", "127.0.0.1:443", nil), netguard.ErrNotPublic)
+	assert.ErrorIs(t, guard.Control("tcp6", "[fe80::1]:443", nil), netguard.ErrNotPublic)
+	assert.ErrorIs(t, guard.Control("tcp4", "10.1.2.3:80", nil), netguard.ErrNotPublic)
+}
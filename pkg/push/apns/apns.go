@@ -0,0 +1,95 @@
+package apns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+)
+
+const DefaultEndpoint = "https://api.push.apple.com"
+
+// Provider sends through the APNs HTTP/2 API with token based authentication.
+// AuthToken is the signed provider JWT, Topic the app's bundle id.
+type Provider struct {
+	Endpoint  string
+	Topic     string
+	AuthToken string
+	Client    *http.Client
+}
+
+func New(endpoint string, topic string, authToken string) *Provider {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Provider{
+		Endpoint:  strings.TrimRight(endpoint, "/"),
+		Topic:     topic,
+		AuthToken: authToken,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type alert struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type aps struct {
+	Alert alert  `json:"alert"`
+	Sound string `json:"sound"`
+}
+
+type errorResponse struct {
+	Reason string `json:"reason"`
+}
+
+func (p *Provider) Send(ctx context.Context, n *push.Notification) error {
+	// Custom data sits next to the aps dictionary
+	body := map[string]interface{}{
+		"aps": aps{Alert: alert{Title: n.Title, Body: n.Body}, Sound: "default"},
+	}
+	for k, v := range n.Data {
+		body[k] = v
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Endpoint+"/3/device/"+n.Token, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+p.AuthToken)
+	req.Header.Set("apns-topic", p.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	if n.CollapseKey != "" {
+		req.Header.Set("apns-collapse-id", n.CollapseKey)
+	}
+
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var errRes errorResponse
+	raw, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	_ = json.Unmarshal(raw, &errRes)
+	// 410 means the token is no longer active, 400 BadDeviceToken that it never was
+	if res.StatusCode == http.StatusGone || errRes.Reason == "BadDeviceToken" || errRes.Reason == "Unregistered" {
+		return push.ErrInvalidToken
+	}
+	return fmt.Errorf("apns: %s %s", res.Status, errRes.Reason)
+}
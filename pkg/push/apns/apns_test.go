@@ -0,0 +1,62 @@
+package apns_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+	"gitlab.com/raihanlh/messenger-api/pkg/push/apns"
+)
+
+func Test_Provider_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "Delivered", token: "good", status: http.StatusOK},
+		{name: "Token no longer active", token: "gone", status: http.StatusGone, body: `{"reason":"Unregistered"}`, wantErr: push.ErrInvalidToken},
+		{name: "Bad token", token: "bad", status: http.StatusBadRequest, body: `{"reason":"BadDeviceToken"}`, wantErr: push.ErrInvalidToken},
+		{name: "Throttled", token: "good", status: http.StatusTooManyRequests, body: `{"reason":"TooManyRequests"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/3/device/"+tt.token, r.URL.Path)
+				assert.Equal(t, "bearer jwt", r.Header.Get("Authorization"))
+				assert.Equal(t, "com.example.messenger", r.Header.Get("apns-topic"))
+				assert.Equal(t, "c1", r.Header.Get("apns-collapse-id"))
+				var req map[string]interface{}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, "c1", req["conversation_id"])
+				if aps, ok := req["aps"].(map[string]interface{}); assert.True(t, ok) {
+					assert.Equal(t, map[string]interface{}{"title": "Alice", "body": "New message"}, aps["alert"])
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := apns.New(server.URL, "com.example.messenger", "jwt")
+			err := provider.Send(context.TODO(), &push.Notification{
+				Token: tt.token, Title: "Alice", Body: "New message", CollapseKey: "c1",
+				Data: map[string]string{"conversation_id": "c1"},
+			})
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.status != http.StatusOK:
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, push.ErrInvalidToken)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package fcm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+)
+
+const DefaultEndpoint = "https://fcm.googleapis.com"
+
+// Provider sends through the FCM HTTP v1 API. AccessToken is an OAuth2 token for the
+// project's service account, Endpoint can point to a local stub in tests.
+type Provider struct {
+	Endpoint    string
+	ProjectID   string
+	AccessToken string
+	Client      *http.Client
+}
+
+func New(endpoint string, projectId string, accessToken string) *Provider {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Provider{
+		Endpoint:    strings.TrimRight(endpoint, "/"),
+		ProjectID:   projectId,
+		AccessToken: accessToken,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type message struct {
+	Message struct {
+		Token        string            `json:"token"`
+		Notification notification      `json:"notification"`
+		Data         map[string]string `json:"data,omitempty"`
+		Android      *android          `json:"android,omitempty"`
+	} `json:"message"`
+}
+
+type notification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type android struct {
+	CollapseKey string `json:"collapse_key,omitempty"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (p *Provider) Send(ctx context.Context, n *push.Notification) error {
+	var body message
+	body.Message.Token = n.Token
+	body.Message.Notification = notification{Title: n.Title, Body: n.Body}
+	body.Message.Data = n.Data
+	if n.CollapseKey != "" {
+		body.Message.Android = &android{CollapseKey: n.CollapseKey}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", p.Endpoint, p.ProjectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.AccessToken)
+
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var errRes errorResponse
+	raw, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	_ = json.Unmarshal(raw, &errRes)
+	// Unregistered tokens come back as 404 NOT_FOUND, malformed ones as INVALID_ARGUMENT
+	for _, d := range errRes.Error.Details {
+		if d.ErrorCode == "UNREGISTERED" || d.ErrorCode == "INVALID_ARGUMENT" {
+			return push.ErrInvalidToken
+		}
+	}
+	if res.StatusCode == http.StatusNotFound {
+		return push.ErrInvalidToken
+	}
+	return fmt.Errorf("fcm: %s %s", res.Status, errRes.Error.Message)
+}
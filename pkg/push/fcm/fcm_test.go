@@ -0,0 +1,78 @@
+package fcm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/push"
+	"gitlab.com/raihanlh/messenger-api/pkg/push/fcm"
+)
+
+func Test_Provider_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "Delivered", token: "good", status: http.StatusOK, body: `{"name":"projects/p1/messages/1"}`},
+		{
+			name: "Unregistered token", token: "gone", status: http.StatusNotFound,
+			body:    `{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`,
+			wantErr: push.ErrInvalidToken,
+		},
+		{
+			name: "Malformed token", token: "bad", status: http.StatusBadRequest,
+			body:    `{"error":{"status":"INVALID_ARGUMENT","details":[{"errorCode":"INVALID_ARGUMENT"}]}}`,
+			wantErr: push.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/projects/p1/messages:send", r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				var req struct {
+					Message struct {
+						Token        string            `json:"token"`
+						Notification map[string]string `json:"notification"`
+						Data         map[string]string `json:"data"`
+					} `json:"message"`
+				}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, tt.token, req.Message.Token)
+				assert.Equal(t, "Alice", req.Message.Notification["title"])
+				assert.Equal(t, "c1", req.Message.Data["conversation_id"])
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := fcm.New(server.URL, "p1", "secret")
+			err := provider.Send(context.TODO(), &push.Notification{
+				Token: tt.token, Title: "Alice", Body: "New message", Data: map[string]string{"conversation_id": "c1"},
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_Provider_Send_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := fcm.New(server.URL, "p1", "secret").Send(context.TODO(), &push.Notification{Token: "good"})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, push.ErrInvalidToken, "transient errors keep the token")
+}
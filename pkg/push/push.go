@@ -0,0 +1,59 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+)
+
+// ErrInvalidToken means the provider will never deliver to the token again,
+// e.g. the app was uninstalled, so the token should be forgotten
+var ErrInvalidToken = errors.New("push: invalid token")
+
+type Notification struct {
+	Token string
+	Title string
+	Body  string
+	// Delivered to the app alongside the alert, e.g. the conversation to open
+	Data map[string]string
+	// Notifications with the same collapse key replace each other on the device
+	CollapseKey string
+}
+
+// Provider delivers notifications through one push service
+type Provider interface {
+	Send(ctx context.Context, n *Notification) error
+}
+
+// Gateway sends a notification through the provider of the token's platform
+type Gateway interface {
+	Send(ctx context.Context, platform string, n *Notification) error
+	Supports(platform string) bool
+}
+
+type gateway struct {
+	providers map[string]Provider
+}
+
+// NewGateway routes each platform to its provider, platforms without one are unsupported
+func NewGateway(providers map[string]Provider) Gateway {
+	return &gateway{providers: providers}
+}
+
+func (g *gateway) Send(ctx context.Context, platform string, n *Notification) error {
+	provider, ok := g.providers[platform]
+	if !ok {
+		return fmt.Errorf("push: no provider for platform %q", platform)
+	}
+	return provider.Send(ctx, n)
+}
+
+func (g *gateway) Supports(platform string) bool {
+	_, ok := g.providers[platform]
+	return ok
+}
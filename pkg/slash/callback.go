@@ -0,0 +1,91 @@
+package slash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/pkg/netguard"
+	"gitlab.com/raihanlh/messenger-api/pkg/webhook"
+)
+
+// Event header value of command callbacks, the request is signed like a webhook delivery
+const CallbackEvent = "slash_command"
+
+// Used when no timeout is given, the sender is waiting for the reply
+const DefaultTimeout = 3 * time.Second
+
+// Longest callback response read
+const maxReplySize = 64 << 10
+
+// Caller forwards an invocation to an external command and returns its reply
+type Caller interface {
+	Call(ctx context.Context, url string, secret string, inv *Invocation) (*Reply, error)
+}
+
+type Client struct {
+	HTTP *http.Client
+}
+
+// NewClient only connects to addresses the guard accepts, a nil guard allows any address
+func NewClient(timeout time.Duration, guard *netguard.Guard) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	client := &Client{
+		HTTP: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	if guard != nil {
+		client.HTTP.Transport = guard.Transport()
+	}
+	return client
+}
+
+// Call POSTs the invocation as JSON. An empty 2xx response means no reply.
+func (c *Client) Call(ctx context.Context, url string, secret string, inv *Invocation) (*Reply, error) {
+	body, err := json.Marshal(inv)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "messenger-api-webhook")
+	req.Header.Set(webhook.EventHeader, CallbackEvent)
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, timestamp, body))
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("slash: command responded %s", res.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxReplySize))
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var reply Reply
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("slash: invalid reply: %w", err)
+	}
+	return &reply, nil
+}
@@ -0,0 +1,121 @@
+// Package slash runs chat commands like "/shrug" typed as a message. Commands run in
+// process or are forwarded to an HTTP callback.
+package slash
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+const (
+	// Only the sender sees the reply, nothing is stored
+	ResponseEphemeral = "ephemeral"
+	// The reply is posted to the conversation as the sender's message
+	ResponseInChannel = "in_channel"
+)
+
+// Invocation is one use of a command
+type Invocation struct {
+	// Name without the slash
+	Command string `json:"command"`
+	// Everything after the name, trimmed
+	Text           string `json:"text"`
+	UserID         string `json:"user_id"`
+	UserName       string `json:"user_name"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	// Commands the sender can use here, for help
+	Available []*Command `json:"-"`
+}
+
+type Reply struct {
+	Text string `json:"text"`
+	// ResponseEphemeral or ResponseInChannel, empty is ephemeral
+	ResponseType string `json:"response_type"`
+}
+
+func (r *Reply) IsEphemeral() bool {
+	return r.ResponseType != ResponseInChannel
+}
+
+// Ephemeral is a reply only the sender sees
+func Ephemeral(text string) *Reply {
+	return &Reply{Text: text, ResponseType: ResponseEphemeral}
+}
+
+// Post is a reply posted to the conversation
+func Post(text string) *Reply {
+	return &Reply{Text: text, ResponseType: ResponseInChannel}
+}
+
+type Handler interface {
+	Run(ctx context.Context, inv *Invocation) (*Reply, error)
+}
+
+type HandlerFunc func(ctx context.Context, inv *Invocation) (*Reply, error)
+
+func (f HandlerFunc) Run(ctx context.Context, inv *Invocation) (*Reply, error) {
+	return f(ctx, inv)
+}
+
+type Command struct {
+	Name        string
+	Description string
+	// Arguments, shown after the name in help
+	Usage   string
+	Handler Handler
+}
+
+// Registry holds the in-process commands
+type Registry struct {
+	commands map[string]*Command
+}
+
+func NewRegistry(commands ...*Command) *Registry {
+	r := &Registry{commands: map[string]*Command{}}
+	for _, c := range commands {
+		r.Register(c)
+	}
+	return r
+}
+
+// Register adds a command, replacing one with the same name
+func (r *Registry) Register(c *Command) {
+	r.commands[c.Name] = c
+}
+
+func (r *Registry) Get(name string) (*Command, bool) {
+	c, ok := r.commands[name]
+	return c, ok
+}
+
+// All returns the commands sorted by name
+func (r *Registry) All() []*Command {
+	commands := make([]*Command, 0, len(r.commands))
+	for _, c := range r.commands {
+		commands = append(commands, c)
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands
+}
+
+// Parse splits "/name args" into the lowercased name and the arguments. Text that doesn't
+// start with a single slash followed by a name isn't a command, so "//" escapes one.
+func Parse(text string) (string, string, bool) {
+	if !strings.HasPrefix(text, "/") || strings.HasPrefix(text, "//") {
+		return "", "", false
+	}
+	name, args, _ := strings.Cut(text[1:], " ")
+	if name == "" || strings.ContainsAny(name, "/\n\t") {
+		return "", "", false
+	}
+	return strings.ToLower(name), strings.TrimSpace(args), true
+}
+
+// Unescape drops the first slash of text escaped with "//", to send it as is
+func Unescape(text string) string {
+	if strings.HasPrefix(text, "//") {
+		return text[1:]
+	}
+	return text
+}
@@ -0,0 +1,74 @@
+package slash_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/slash"
+	"gitlab.com/raihanlh/messenger-api/pkg/webhook"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantName string
+		wantArgs string
+		wantOk   bool
+	}{
+		{text: "/shrug", wantName: "shrug", wantOk: true},
+		{text: "/Poll Lunch? | pizza | sushi ", wantName: "poll", wantArgs: "Lunch? | pizza | sushi", wantOk: true},
+		{text: "hello /shrug"},
+		{text: "//shrug"},
+		{text: "/usr/bin is a path"},
+		{text: "/ nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			name, args, ok := slash.Parse(tt.text)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func Test_Client_Call(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+		assert.True(t, webhook.Verify("s3cret", r.Header.Get(webhook.SignatureHeader), timestamp, body))
+		assert.Equal(t, slash.CallbackEvent, r.Header.Get(webhook.EventHeader))
+
+		var inv slash.Invocation
+		assert.NoError(t, json.Unmarshal(body, &inv))
+		switch inv.Text {
+		case "fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "quiet":
+			w.WriteHeader(http.StatusOK)
+		default:
+			_ = json.NewEncoder(w).Encode(slash.Post("deployed " + inv.Text))
+		}
+	}))
+	defer server.Close()
+	client := slash.NewClient(0, nil)
+
+	reply, err := client.Call(context.TODO(), server.URL, "s3cret", &slash.Invocation{Command: "deploy", Text: "api"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "deployed api", reply.Text)
+		assert.False(t, reply.IsEphemeral())
+	}
+
+	reply, err = client.Call(context.TODO(), server.URL, "s3cret", &slash.Invocation{Command: "deploy", Text: "quiet"})
+	assert.NoError(t, err)
+	assert.Nil(t, reply)
+
+	_, err = client.Call(context.TODO(), server.URL, "s3cret", &slash.Invocation{Command: "deploy", Text: "fail"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitlab.com/raihanlh/messenger-api/pkg/netguard"
+)
+
+const (
+	EventHeader    = "X-Webhook-Event"
+	DeliveryHeader = "X-Webhook-Delivery"
+	// Unix seconds the request was signed at, receivers should reject old ones to stop replays
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Sign returns the signature header value, an HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the subscription secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature in constant time, for receivers written in Go
+func Verify(secret string, signature string, timestamp int64, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// Sender POSTs signed events. It returns the status code whenever the receiver answered,
+// any status outside 2xx is an error.
+type Sender interface {
+	Send(ctx context.Context, r *Request) (int, error)
+}
+
+// Used when no timeout is given
+const DefaultTimeout = 10 * time.Second
+
+type Client struct {
+	HTTP *http.Client
+}
+
+// NewClient only connects to addresses the guard accepts, a nil guard allows any address
+func NewClient(timeout time.Duration, guard *netguard.Guard) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	client := &Client{
+		HTTP: &http.Client{
+			Timeout: timeout,
+			// A redirect could send the event somewhere the subscriber didn't register
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	if guard != nil {
+		client.HTTP.Transport = guard.Transport()
+	}
+	return client
+}
+
+func (c *Client) Send(ctx context.Context, r *Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "messenger-api-webhook")
+	req.Header.Set(EventHeader, r.Event)
+	req.Header.Set(DeliveryHeader, r.DeliveryID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(r.Secret, timestamp, r.Body))
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook: receiver responded %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,90 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/raihanlh/messenger-api/pkg/netguard"
+	"gitlab.com/raihanlh/messenger-api/pkg/webhook"
+)
+
+func Test_Sign(t *testing.T) {
+	body := []byte(`{"a":1}`)
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686", webhook.Sign("secret", 1700000000, body))
+	assert.True(t, webhook.Verify("secret", webhook.Sign("secret", 1700000000, body), 1700000000, body))
+	assert.False(t, webhook.Verify("other", webhook.Sign("secret", 1700000000, body), 1700000000, body))
+	assert.False(t, webhook.Verify("secret", webhook.Sign("secret", 1700000000, body), 1700000001, body))
+}
+
+func Test_Client_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Accepted", status: http.StatusNoContent},
+		{name: "Receiver error", status: http.StatusInternalServerError, wantErr: true},
+		{name: "Redirects aren't followed", status: http.StatusFound, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := []byte(`{"type":"message.created"}`)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received, _ := io.ReadAll(r.Body)
+				assert.Equal(t, body, received)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "message.created", r.Header.Get(webhook.EventHeader))
+				assert.Equal(t, "d1", r.Header.Get(webhook.DeliveryHeader))
+				timestamp, err := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+				assert.NoError(t, err)
+				assert.True(t, webhook.Verify("secret", r.Header.Get(webhook.SignatureHeader), timestamp, received))
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			code, err := webhook.NewClient(0, nil).Send(context.TODO(), &webhook.Request{
+				URL: server.URL, Secret: "secret", Event: "message.created", DeliveryID: "d1", Body: body,
+			})
+			assert.Equal(t, tt.status, code)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_Client_Send_Guarded(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// The address is checked when connecting, a host that now resolves to loopback is refused
+	// even though it passed the check when it was registered
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		_, err := webhook.NewClient(0, netguard.New(false)).Send(context.TODO(), &webhook.Request{
+			URL: url, Secret: "secret", Event: "message.created", DeliveryID: "d1", Body: []byte(`{}`),
+		})
+		assert.ErrorIs(t, err, netguard.ErrNotPublic, url)
+	}
+	assert.False(t, called)
+
+	code, err := webhook.NewClient(0, netguard.New(true)).Send(context.TODO(), &webhook.Request{
+		URL: server.URL, Secret: "secret", Event: "message.created", DeliveryID: "d1", Body: []byte(`{}`),
+	})
+	assert.NoError(t, err, "private addresses are allowed when configured")
+	assert.Equal(t, http.StatusOK, code)
+}
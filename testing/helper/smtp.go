@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ReceivedMail is one message accepted by SMTPServer
+type ReceivedMail struct {
+	From string
+	To   []string
+	// Headers and body as sent after DATA
+	Data string
+}
+
+// SMTPServer is an in-process SMTP stub that accepts every message. It doesn't offer
+// STARTTLS or AUTH, so clients have to send without them.
+type SMTPServer struct {
+	Host string
+	Port string
+	// Replies to RCPT TO with this code when set, e.g. "550"
+	RejectRcpt string
+
+	listener net.Listener
+	mu       sync.Mutex
+	mails    []*ReceivedMail
+}
+
+// NewSMTPServer listens on a random local port until the test ends
+func NewSMTPServer(t *testing.T) *SMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	s := &SMTPServer{Host: host, Port: port, listener: listener}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+// Mails accepted so far
+func (s *SMTPServer) Mails() []*ReceivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*ReceivedMail(nil), s.mails...)
+}
+
+func (s *SMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost ESMTP stub")
+	mail := &ReceivedMail{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			mail = &ReceivedMail{From: address(line)}
+			reply("250 OK")
+		case "RCPT":
+			if s.RejectRcpt != "" {
+				reply(s.RejectRcpt + " mailbox unavailable")
+				continue
+			}
+			mail.To = append(mail.To, address(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RSET", "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// The address in "MAIL FROM:<a@b>" or "RCPT TO:<a@b>"
+func address(line string) string {
+	start, end := strings.Index(line, "<"), strings.LastIndex(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
@@ -0,0 +1,10 @@
+package helper
+
+import "context"
+
+// NoTransaction runs the function straight away, for usecase tests with mocked repositories
+type NoTransaction struct{}
+
+func (NoTransaction) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,302 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/contact/contact.go
+
+// Package mock_contact is a generated GoMock package.
+package mock_contact
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+	payload "gitlab.com/raihanlh/messenger-api/internal/domain/contact/payload"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, contact *model.Contact) (*model.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, contact)
+	ret0, _ := ret[0].(*model.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, contact interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, contact)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, userId, contactId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, contactId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, userId, contactId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, userId, contactId)
+}
+
+// Get mocks base method.
+func (m *MockRepository) Get(ctx context.Context, userId, contactId string) (*model.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userId, contactId)
+	ret0, _ := ret[0].(*model.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRepositoryMockRecorder) Get(ctx, userId, contactId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, userId, contactId)
+}
+
+// GetAllByUserId mocks base method.
+func (m *MockRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserId", ctx, userId)
+	ret0, _ := ret[0].([]*model.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserId indicates an expected call of GetAllByUserId.
+func (mr *MockRepositoryMockRecorder) GetAllByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserId", reflect.TypeOf((*MockRepository)(nil).GetAllByUserId), ctx, userId)
+}
+
+// UpdateNickname mocks base method.
+func (m *MockRepository) UpdateNickname(ctx context.Context, userId, contactId, nickname string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNickname", ctx, userId, contactId, nickname)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname.
+func (mr *MockRepositoryMockRecorder) UpdateNickname(ctx, userId, contactId, nickname interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockRepository)(nil).UpdateNickname), ctx, userId, contactId, nickname)
+}
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockUsecase) Add(ctx context.Context, req *payload.AddContactRequest) (*payload.AddContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, req)
+	ret0, _ := ret[0].(*payload.AddContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockUsecaseMockRecorder) Add(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockUsecase)(nil).Add), ctx, req)
+}
+
+// GetAll mocks base method.
+func (m *MockUsecase) GetAll(ctx context.Context, req *payload.GetAllContactRequest) (*payload.GetAllContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, req)
+	ret0, _ := ret[0].(*payload.GetAllContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockUsecaseMockRecorder) GetAll(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsecase)(nil).GetAll), ctx, req)
+}
+
+// Import mocks base method.
+func (m *MockUsecase) Import(ctx context.Context, req *payload.ImportContactRequest) (*payload.ImportContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, req)
+	ret0, _ := ret[0].(*payload.ImportContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockUsecaseMockRecorder) Import(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockUsecase)(nil).Import), ctx, req)
+}
+
+// Remove mocks base method.
+func (m *MockUsecase) Remove(ctx context.Context, req *payload.RemoveContactRequest) (*payload.RemoveContactResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, req)
+	ret0, _ := ret[0].(*payload.RemoveContactResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockUsecaseMockRecorder) Remove(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockUsecase)(nil).Remove), ctx, req)
+}
+
+// UpdateNickname mocks base method.
+func (m *MockUsecase) UpdateNickname(ctx context.Context, req *payload.UpdateNicknameRequest) (*payload.UpdateNicknameResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNickname", ctx, req)
+	ret0, _ := ret[0].(*payload.UpdateNicknameResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname.
+func (mr *MockUsecaseMockRecorder) UpdateNickname(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockUsecase)(nil).UpdateNickname), ctx, req)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockHandler) Add(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockHandlerMockRecorder) Add(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockHandler)(nil).Add), ctx)
+}
+
+// GetAll mocks base method.
+func (m *MockHandler) GetAll(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockHandlerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockHandler)(nil).GetAll), ctx)
+}
+
+// Import mocks base method.
+func (m *MockHandler) Import(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockHandlerMockRecorder) Import(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockHandler)(nil).Import), ctx)
+}
+
+// Remove mocks base method.
+func (m *MockHandler) Remove(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockHandlerMockRecorder) Remove(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockHandler)(nil).Remove), ctx)
+}
+
+// UpdateNickname mocks base method.
+func (m *MockHandler) UpdateNickname(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNickname", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname.
+func (mr *MockHandlerMockRecorder) UpdateNickname(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockHandler)(nil).UpdateNickname), ctx)
+}
@@ -0,0 +1,524 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/conversation/conversation.go
+
+// Package mock_conversation is a generated GoMock package.
+package mock_conversation
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+	payload "gitlab.com/raihanlh/messenger-api/internal/domain/conversation/payload"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+	pagination "gitlab.com/raihanlh/messenger-api/pkg/pagination"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, conv *model.Conversation) (*model.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, conv)
+	ret0, _ := ret[0].(*model.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, conv interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, conv)
+}
+
+// GetAllByUserId mocks base method.
+func (m *MockRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserId", ctx, userId)
+	ret0, _ := ret[0].([]*model.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserId indicates an expected call of GetAllByUserId.
+func (mr *MockRepositoryMockRecorder) GetAllByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserId", reflect.TypeOf((*MockRepository)(nil).GetAllByUserId), ctx, userId)
+}
+
+// GetById mocks base method.
+func (m *MockRepository) GetById(ctx context.Context, id string) (*model.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
+}
+
+// GetBySenderReceiverIds mocks base method.
+func (m *MockRepository) GetBySenderReceiverIds(ctx context.Context, senderId, receiverId string) (*model.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySenderReceiverIds", ctx, senderId, receiverId)
+	ret0, _ := ret[0].(*model.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySenderReceiverIds indicates an expected call of GetBySenderReceiverIds.
+func (mr *MockRepositoryMockRecorder) GetBySenderReceiverIds(ctx, senderId, receiverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySenderReceiverIds", reflect.TypeOf((*MockRepository)(nil).GetBySenderReceiverIds), ctx, senderId, receiverId)
+}
+
+// GetListByUserId mocks base method.
+func (m *MockRepository) GetListByUserId(ctx context.Context, pgn *pagination.Pagination, req *payload.GetAllByUserIdConvRequest) ([]*payload.ConversationListRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListByUserId", ctx, pgn, req)
+	ret0, _ := ret[0].([]*payload.ConversationListRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListByUserId indicates an expected call of GetListByUserId.
+func (mr *MockRepositoryMockRecorder) GetListByUserId(ctx, pgn, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListByUserId", reflect.TypeOf((*MockRepository)(nil).GetListByUserId), ctx, pgn, req)
+}
+
+// GetParticipant mocks base method.
+func (m *MockRepository) GetParticipant(ctx context.Context, userId, conversationId string) (*model.UserParticipant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParticipant", ctx, userId, conversationId)
+	ret0, _ := ret[0].(*model.UserParticipant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParticipant indicates an expected call of GetParticipant.
+func (mr *MockRepositoryMockRecorder) GetParticipant(ctx, userId, conversationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParticipant", reflect.TypeOf((*MockRepository)(nil).GetParticipant), ctx, userId, conversationId)
+}
+
+// GetParticipantsByUserId mocks base method.
+func (m *MockRepository) GetParticipantsByUserId(ctx context.Context, userId string) ([]*model.UserParticipant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParticipantsByUserId", ctx, userId)
+	ret0, _ := ret[0].([]*model.UserParticipant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParticipantsByUserId indicates an expected call of GetParticipantsByUserId.
+func (mr *MockRepositoryMockRecorder) GetParticipantsByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParticipantsByUserId", reflect.TypeOf((*MockRepository)(nil).GetParticipantsByUserId), ctx, userId)
+}
+
+// GetRequestsByUserId mocks base method.
+func (m *MockRepository) GetRequestsByUserId(ctx context.Context, pgn *pagination.Pagination, userId string) ([]*payload.ConversationListRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequestsByUserId", ctx, pgn, userId)
+	ret0, _ := ret[0].([]*payload.ConversationListRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRequestsByUserId indicates an expected call of GetRequestsByUserId.
+func (mr *MockRepositoryMockRecorder) GetRequestsByUserId(ctx, pgn, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequestsByUserId", reflect.TypeOf((*MockRepository)(nil).GetRequestsByUserId), ctx, pgn, userId)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockRepositoryMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockRepository)(nil).UpdateStatus), ctx, id, status)
+}
+
+// UpsertParticipant mocks base method.
+func (m *MockRepository) UpsertParticipant(ctx context.Context, participant *model.UserParticipant) (*model.UserParticipant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertParticipant", ctx, participant)
+	ret0, _ := ret[0].(*model.UserParticipant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertParticipant indicates an expected call of UpsertParticipant.
+func (mr *MockRepositoryMockRecorder) UpsertParticipant(ctx, participant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertParticipant", reflect.TypeOf((*MockRepository)(nil).UpsertParticipant), ctx, participant)
+}
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// AcceptRequest mocks base method.
+func (m *MockUsecase) AcceptRequest(ctx context.Context, req *payload.RespondRequestRequest) (*payload.RespondRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptRequest", ctx, req)
+	ret0, _ := ret[0].(*payload.RespondRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptRequest indicates an expected call of AcceptRequest.
+func (mr *MockUsecaseMockRecorder) AcceptRequest(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptRequest", reflect.TypeOf((*MockUsecase)(nil).AcceptRequest), ctx, req)
+}
+
+// Archive mocks base method.
+func (m *MockUsecase) Archive(ctx context.Context, req *payload.ArchiveConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, req)
+	ret0, _ := ret[0].(*payload.ConversationSettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockUsecaseMockRecorder) Archive(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockUsecase)(nil).Archive), ctx, req)
+}
+
+// Clear mocks base method.
+func (m *MockUsecase) Clear(ctx context.Context, req *payload.ClearConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx, req)
+	ret0, _ := ret[0].(*payload.ConversationSettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockUsecaseMockRecorder) Clear(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockUsecase)(nil).Clear), ctx, req)
+}
+
+// Create mocks base method.
+func (m *MockUsecase) Create(ctx context.Context, req *payload.CreateConversationRequest) (*payload.CreateConversationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*payload.CreateConversationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUsecaseMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUsecase)(nil).Create), ctx, req)
+}
+
+// DeclineRequest mocks base method.
+func (m *MockUsecase) DeclineRequest(ctx context.Context, req *payload.RespondRequestRequest) (*payload.RespondRequestResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineRequest", ctx, req)
+	ret0, _ := ret[0].(*payload.RespondRequestResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclineRequest indicates an expected call of DeclineRequest.
+func (mr *MockUsecaseMockRecorder) DeclineRequest(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineRequest", reflect.TypeOf((*MockUsecase)(nil).DeclineRequest), ctx, req)
+}
+
+// GetAllByUserId mocks base method.
+func (m *MockUsecase) GetAllByUserId(ctx context.Context, req *payload.GetAllByUserIdConvRequest) (*payload.GetAllByUserIdConvResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserId", ctx, req)
+	ret0, _ := ret[0].(*payload.GetAllByUserIdConvResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserId indicates an expected call of GetAllByUserId.
+func (mr *MockUsecaseMockRecorder) GetAllByUserId(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserId", reflect.TypeOf((*MockUsecase)(nil).GetAllByUserId), ctx, req)
+}
+
+// GetById mocks base method.
+func (m *MockUsecase) GetById(ctx context.Context, req *payload.GetByIdConversationRequest) (*payload.GetByIdConversationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, req)
+	ret0, _ := ret[0].(*payload.GetByIdConversationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockUsecaseMockRecorder) GetById(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockUsecase)(nil).GetById), ctx, req)
+}
+
+// GetRequests mocks base method.
+func (m *MockUsecase) GetRequests(ctx context.Context, req *payload.GetRequestsRequest) (*payload.GetRequestsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequests", ctx, req)
+	ret0, _ := ret[0].(*payload.GetRequestsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRequests indicates an expected call of GetRequests.
+func (mr *MockUsecaseMockRecorder) GetRequests(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequests", reflect.TypeOf((*MockUsecase)(nil).GetRequests), ctx, req)
+}
+
+// Mute mocks base method.
+func (m *MockUsecase) Mute(ctx context.Context, req *payload.MuteConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mute", ctx, req)
+	ret0, _ := ret[0].(*payload.ConversationSettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Mute indicates an expected call of Mute.
+func (mr *MockUsecaseMockRecorder) Mute(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mute", reflect.TypeOf((*MockUsecase)(nil).Mute), ctx, req)
+}
+
+// Pin mocks base method.
+func (m *MockUsecase) Pin(ctx context.Context, req *payload.PinConversationRequest) (*payload.ConversationSettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pin", ctx, req)
+	ret0, _ := ret[0].(*payload.ConversationSettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pin indicates an expected call of Pin.
+func (mr *MockUsecaseMockRecorder) Pin(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pin", reflect.TypeOf((*MockUsecase)(nil).Pin), ctx, req)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// AcceptRequest mocks base method.
+func (m *MockHandler) AcceptRequest(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptRequest", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptRequest indicates an expected call of AcceptRequest.
+func (mr *MockHandlerMockRecorder) AcceptRequest(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptRequest", reflect.TypeOf((*MockHandler)(nil).AcceptRequest), ctx)
+}
+
+// Archive mocks base method.
+func (m *MockHandler) Archive(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockHandlerMockRecorder) Archive(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockHandler)(nil).Archive), ctx)
+}
+
+// Clear mocks base method.
+func (m *MockHandler) Clear(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockHandlerMockRecorder) Clear(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockHandler)(nil).Clear), ctx)
+}
+
+// DeclineAndBlockRequest mocks base method.
+func (m *MockHandler) DeclineAndBlockRequest(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineAndBlockRequest", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeclineAndBlockRequest indicates an expected call of DeclineAndBlockRequest.
+func (mr *MockHandlerMockRecorder) DeclineAndBlockRequest(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineAndBlockRequest", reflect.TypeOf((*MockHandler)(nil).DeclineAndBlockRequest), ctx)
+}
+
+// DeclineRequest mocks base method.
+func (m *MockHandler) DeclineRequest(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineRequest", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeclineRequest indicates an expected call of DeclineRequest.
+func (mr *MockHandlerMockRecorder) DeclineRequest(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineRequest", reflect.TypeOf((*MockHandler)(nil).DeclineRequest), ctx)
+}
+
+// GetAllByUserId mocks base method.
+func (m *MockHandler) GetAllByUserId(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserId", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAllByUserId indicates an expected call of GetAllByUserId.
+func (mr *MockHandlerMockRecorder) GetAllByUserId(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserId", reflect.TypeOf((*MockHandler)(nil).GetAllByUserId), ctx)
+}
+
+// GetById mocks base method.
+func (m *MockHandler) GetById(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockHandlerMockRecorder) GetById(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockHandler)(nil).GetById), ctx)
+}
+
+// GetRequests mocks base method.
+func (m *MockHandler) GetRequests(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRequests", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetRequests indicates an expected call of GetRequests.
+func (mr *MockHandlerMockRecorder) GetRequests(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRequests", reflect.TypeOf((*MockHandler)(nil).GetRequests), ctx)
+}
+
+// Mute mocks base method.
+func (m *MockHandler) Mute(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Mute", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Mute indicates an expected call of Mute.
+func (mr *MockHandlerMockRecorder) Mute(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mute", reflect.TypeOf((*MockHandler)(nil).Mute), ctx)
+}
+
+// Pin mocks base method.
+func (m *MockHandler) Pin(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pin", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Pin indicates an expected call of Pin.
+func (mr *MockHandlerMockRecorder) Pin(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pin", reflect.TypeOf((*MockHandler)(nil).Pin), ctx)
+}
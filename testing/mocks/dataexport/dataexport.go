@@ -0,0 +1,306 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/dataexport/dataexport.go
+
+// Package mock_dataexport is a generated GoMock package.
+package mock_dataexport
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+	payload "gitlab.com/raihanlh/messenger-api/internal/domain/dataexport/payload"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+	eventbus "gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, export *model.DataExport) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, export)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, export)
+}
+
+// FailStale mocks base method.
+func (m *MockRepository) FailStale(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStale", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStale indicates an expected call of FailStale.
+func (mr *MockRepositoryMockRecorder) FailStale(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStale", reflect.TypeOf((*MockRepository)(nil).FailStale), ctx, before)
+}
+
+// GetActiveByUserId mocks base method.
+func (m *MockRepository) GetActiveByUserId(ctx context.Context, userId string, since time.Time) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByUserId", ctx, userId, since)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByUserId indicates an expected call of GetActiveByUserId.
+func (mr *MockRepositoryMockRecorder) GetActiveByUserId(ctx, userId, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByUserId", reflect.TypeOf((*MockRepository)(nil).GetActiveByUserId), ctx, userId, since)
+}
+
+// GetArchivesByUserId mocks base method.
+func (m *MockRepository) GetArchivesByUserId(ctx context.Context, userId string) ([]*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivesByUserId", ctx, userId)
+	ret0, _ := ret[0].([]*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivesByUserId indicates an expected call of GetArchivesByUserId.
+func (mr *MockRepositoryMockRecorder) GetArchivesByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivesByUserId", reflect.TypeOf((*MockRepository)(nil).GetArchivesByUserId), ctx, userId)
+}
+
+// GetById mocks base method.
+func (m *MockRepository) GetById(ctx context.Context, id string) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
+}
+
+// GetExpiredArchives mocks base method.
+func (m *MockRepository) GetExpiredArchives(ctx context.Context, at time.Time) ([]*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredArchives", ctx, at)
+	ret0, _ := ret[0].([]*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredArchives indicates an expected call of GetExpiredArchives.
+func (mr *MockRepositoryMockRecorder) GetExpiredArchives(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredArchives", reflect.TypeOf((*MockRepository)(nil).GetExpiredArchives), ctx, at)
+}
+
+// Update mocks base method.
+func (m *MockRepository) Update(ctx context.Context, export *model.DataExport) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, export)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(ctx, export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, export)
+}
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// CleanUp mocks base method.
+func (m *MockUsecase) CleanUp(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanUp", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CleanUp indicates an expected call of CleanUp.
+func (mr *MockUsecaseMockRecorder) CleanUp(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanUp", reflect.TypeOf((*MockUsecase)(nil).CleanUp), ctx)
+}
+
+// DeleteUserArchives mocks base method.
+func (m *MockUsecase) DeleteUserArchives(ctx context.Context, e *eventbus.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserArchives", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserArchives indicates an expected call of DeleteUserArchives.
+func (mr *MockUsecaseMockRecorder) DeleteUserArchives(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserArchives", reflect.TypeOf((*MockUsecase)(nil).DeleteUserArchives), ctx, e)
+}
+
+// Download mocks base method.
+func (m *MockUsecase) Download(ctx context.Context, req *payload.DownloadDataExportRequest) (*payload.DownloadDataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", ctx, req)
+	ret0, _ := ret[0].(*payload.DownloadDataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockUsecaseMockRecorder) Download(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockUsecase)(nil).Download), ctx, req)
+}
+
+// GetById mocks base method.
+func (m *MockUsecase) GetById(ctx context.Context, req *payload.GetDataExportRequest) (*payload.DataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, req)
+	ret0, _ := ret[0].(*payload.DataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockUsecaseMockRecorder) GetById(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockUsecase)(nil).GetById), ctx, req)
+}
+
+// Request mocks base method.
+func (m *MockUsecase) Request(ctx context.Context, req *payload.RequestDataExportRequest) (*payload.DataExportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx, req)
+	ret0, _ := ret[0].(*payload.DataExportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockUsecaseMockRecorder) Request(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockUsecase)(nil).Request), ctx, req)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// Download mocks base method.
+func (m *MockHandler) Download(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Download indicates an expected call of Download.
+func (mr *MockHandlerMockRecorder) Download(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockHandler)(nil).Download), ctx)
+}
+
+// GetById mocks base method.
+func (m *MockHandler) GetById(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockHandlerMockRecorder) GetById(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockHandler)(nil).GetById), ctx)
+}
+
+// Request mocks base method.
+func (m *MockHandler) Request(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockHandlerMockRecorder) Request(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockHandler)(nil).Request), ctx)
+}
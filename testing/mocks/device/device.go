@@ -0,0 +1,360 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/device/device.go
+
+// Package mock_device is a generated GoMock package.
+package mock_device
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+	payload "gitlab.com/raihanlh/messenger-api/internal/domain/device/payload"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddPrekeys mocks base method.
+func (m *MockRepository) AddPrekeys(ctx context.Context, prekeys []*model.OneTimePrekey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrekeys", ctx, prekeys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPrekeys indicates an expected call of AddPrekeys.
+func (mr *MockRepositoryMockRecorder) AddPrekeys(ctx, prekeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrekeys", reflect.TypeOf((*MockRepository)(nil).AddPrekeys), ctx, prekeys)
+}
+
+// ClaimPrekey mocks base method.
+func (m *MockRepository) ClaimPrekey(ctx context.Context, deviceId string) (*model.OneTimePrekey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPrekey", ctx, deviceId)
+	ret0, _ := ret[0].(*model.OneTimePrekey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPrekey indicates an expected call of ClaimPrekey.
+func (mr *MockRepositoryMockRecorder) ClaimPrekey(ctx, deviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPrekey", reflect.TypeOf((*MockRepository)(nil).ClaimPrekey), ctx, deviceId)
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, device *model.Device, prekeys []*model.OneTimePrekey) (*model.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, device, prekeys)
+	ret0, _ := ret[0].(*model.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, device, prekeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, device, prekeys)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
+}
+
+// GetAllByUserId mocks base method.
+func (m *MockRepository) GetAllByUserId(ctx context.Context, userId string) ([]*model.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUserId", ctx, userId)
+	ret0, _ := ret[0].([]*model.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUserId indicates an expected call of GetAllByUserId.
+func (mr *MockRepositoryMockRecorder) GetAllByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserId", reflect.TypeOf((*MockRepository)(nil).GetAllByUserId), ctx, userId)
+}
+
+// GetById mocks base method.
+func (m *MockRepository) GetById(ctx context.Context, id string) (*model.Device, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.Device)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
+}
+
+// UpdateSignedPrekey mocks base method.
+func (m *MockRepository) UpdateSignedPrekey(ctx context.Context, device *model.Device) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSignedPrekey", ctx, device)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSignedPrekey indicates an expected call of UpdateSignedPrekey.
+func (mr *MockRepositoryMockRecorder) UpdateSignedPrekey(ctx, device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSignedPrekey", reflect.TypeOf((*MockRepository)(nil).UpdateSignedPrekey), ctx, device)
+}
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ClaimBundles mocks base method.
+func (m *MockUsecase) ClaimBundles(ctx context.Context, req *payload.ClaimBundlesRequest) (*payload.ClaimBundlesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimBundles", ctx, req)
+	ret0, _ := ret[0].(*payload.ClaimBundlesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimBundles indicates an expected call of ClaimBundles.
+func (mr *MockUsecaseMockRecorder) ClaimBundles(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimBundles", reflect.TypeOf((*MockUsecase)(nil).ClaimBundles), ctx, req)
+}
+
+// GetAll mocks base method.
+func (m *MockUsecase) GetAll(ctx context.Context, req *payload.GetAllDevicesRequest) (*payload.GetAllDevicesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, req)
+	ret0, _ := ret[0].(*payload.GetAllDevicesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockUsecaseMockRecorder) GetAll(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockUsecase)(nil).GetAll), ctx, req)
+}
+
+// Register mocks base method.
+func (m *MockUsecase) Register(ctx context.Context, req *payload.RegisterDeviceRequest) (*payload.RegisterDeviceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, req)
+	ret0, _ := ret[0].(*payload.RegisterDeviceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockUsecaseMockRecorder) Register(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUsecase)(nil).Register), ctx, req)
+}
+
+// Remove mocks base method.
+func (m *MockUsecase) Remove(ctx context.Context, req *payload.RemoveDeviceRequest) (*payload.RemoveDeviceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, req)
+	ret0, _ := ret[0].(*payload.RemoveDeviceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockUsecaseMockRecorder) Remove(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockUsecase)(nil).Remove), ctx, req)
+}
+
+// RotateSignedPrekey mocks base method.
+func (m *MockUsecase) RotateSignedPrekey(ctx context.Context, req *payload.RotateSignedPrekeyRequest) (*payload.RotateSignedPrekeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSignedPrekey", ctx, req)
+	ret0, _ := ret[0].(*payload.RotateSignedPrekeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateSignedPrekey indicates an expected call of RotateSignedPrekey.
+func (mr *MockUsecaseMockRecorder) RotateSignedPrekey(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSignedPrekey", reflect.TypeOf((*MockUsecase)(nil).RotateSignedPrekey), ctx, req)
+}
+
+// UploadPrekeys mocks base method.
+func (m *MockUsecase) UploadPrekeys(ctx context.Context, req *payload.UploadPrekeysRequest) (*payload.UploadPrekeysResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadPrekeys", ctx, req)
+	ret0, _ := ret[0].(*payload.UploadPrekeysResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadPrekeys indicates an expected call of UploadPrekeys.
+func (mr *MockUsecaseMockRecorder) UploadPrekeys(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPrekeys", reflect.TypeOf((*MockUsecase)(nil).UploadPrekeys), ctx, req)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// ClaimBundles mocks base method.
+func (m *MockHandler) ClaimBundles(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimBundles", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClaimBundles indicates an expected call of ClaimBundles.
+func (mr *MockHandlerMockRecorder) ClaimBundles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimBundles", reflect.TypeOf((*MockHandler)(nil).ClaimBundles), ctx)
+}
+
+// GetAll mocks base method.
+func (m *MockHandler) GetAll(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockHandlerMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockHandler)(nil).GetAll), ctx)
+}
+
+// Register mocks base method.
+func (m *MockHandler) Register(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockHandlerMockRecorder) Register(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockHandler)(nil).Register), ctx)
+}
+
+// Remove mocks base method.
+func (m *MockHandler) Remove(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockHandlerMockRecorder) Remove(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockHandler)(nil).Remove), ctx)
+}
+
+// RotateSignedPrekey mocks base method.
+func (m *MockHandler) RotateSignedPrekey(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSignedPrekey", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateSignedPrekey indicates an expected call of RotateSignedPrekey.
+func (mr *MockHandlerMockRecorder) RotateSignedPrekey(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSignedPrekey", reflect.TypeOf((*MockHandler)(nil).RotateSignedPrekey), ctx)
+}
+
+// UploadPrekeys mocks base method.
+func (m *MockHandler) UploadPrekeys(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadPrekeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UploadPrekeys indicates an expected call of UploadPrekeys.
+func (mr *MockHandlerMockRecorder) UploadPrekeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadPrekeys", reflect.TypeOf((*MockHandler)(nil).UploadPrekeys), ctx)
+}
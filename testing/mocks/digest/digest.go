@@ -0,0 +1,230 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/digest/digest.go
+
+// Package mock_digest is a generated GoMock package.
+package mock_digest
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+	payload "gitlab.com/raihanlh/messenger-api/internal/domain/digest/payload"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimUser mocks base method.
+func (m *MockRepository) ClaimUser(ctx context.Context, userId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimUser", ctx, userId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimUser indicates an expected call of ClaimUser.
+func (mr *MockRepositoryMockRecorder) ClaimUser(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimUser", reflect.TypeOf((*MockRepository)(nil).ClaimUser), ctx, userId)
+}
+
+// GetDueMessages mocks base method.
+func (m *MockRepository) GetDueMessages(ctx context.Context, userId string, from, to time.Time) ([]*payload.DueMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueMessages", ctx, userId, from, to)
+	ret0, _ := ret[0].([]*payload.DueMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueMessages indicates an expected call of GetDueMessages.
+func (mr *MockRepositoryMockRecorder) GetDueMessages(ctx, userId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueMessages", reflect.TypeOf((*MockRepository)(nil).GetDueMessages), ctx, userId, from, to)
+}
+
+// GetDueUserIds mocks base method.
+func (m *MockRepository) GetDueUserIds(ctx context.Context, from, to time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueUserIds", ctx, from, to, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueUserIds indicates an expected call of GetDueUserIds.
+func (mr *MockRepositoryMockRecorder) GetDueUserIds(ctx, from, to, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueUserIds", reflect.TypeOf((*MockRepository)(nil).GetDueUserIds), ctx, from, to, limit)
+}
+
+// MarkDigested mocks base method.
+func (m *MockRepository) MarkDigested(ctx context.Context, entries []*model.DigestedMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDigested", ctx, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDigested indicates an expected call of MarkDigested.
+func (mr *MockRepositoryMockRecorder) MarkDigested(ctx, entries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDigested", reflect.TypeOf((*MockRepository)(nil).MarkDigested), ctx, entries)
+}
+
+// SetOptOut mocks base method.
+func (m *MockRepository) SetOptOut(ctx context.Context, userId string, optOut bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOptOut", ctx, userId, optOut)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOptOut indicates an expected call of SetOptOut.
+func (mr *MockRepositoryMockRecorder) SetOptOut(ctx, userId, optOut interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOptOut", reflect.TypeOf((*MockRepository)(nil).SetOptOut), ctx, userId, optOut)
+}
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetSettings mocks base method.
+func (m *MockUsecase) GetSettings(ctx context.Context, req *payload.GetSettingsRequest) (*payload.SettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", ctx, req)
+	ret0, _ := ret[0].(*payload.SettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockUsecaseMockRecorder) GetSettings(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockUsecase)(nil).GetSettings), ctx, req)
+}
+
+// SendDigests mocks base method.
+func (m *MockUsecase) SendDigests(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendDigests", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendDigests indicates an expected call of SendDigests.
+func (mr *MockUsecaseMockRecorder) SendDigests(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDigests", reflect.TypeOf((*MockUsecase)(nil).SendDigests), ctx)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockUsecase) UpdateSettings(ctx context.Context, req *payload.UpdateSettingsRequest) (*payload.SettingsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", ctx, req)
+	ret0, _ := ret[0].(*payload.SettingsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockUsecaseMockRecorder) UpdateSettings(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockUsecase)(nil).UpdateSettings), ctx, req)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// GetSettings mocks base method.
+func (m *MockHandler) GetSettings(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSettings", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetSettings indicates an expected call of GetSettings.
+func (mr *MockHandlerMockRecorder) GetSettings(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettings", reflect.TypeOf((*MockHandler)(nil).GetSettings), ctx)
+}
+
+// UpdateSettings mocks base method.
+func (m *MockHandler) UpdateSettings(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSettings", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSettings indicates an expected call of UpdateSettings.
+func (mr *MockHandlerMockRecorder) UpdateSettings(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSettings", reflect.TypeOf((*MockHandler)(nil).UpdateSettings), ctx)
+}
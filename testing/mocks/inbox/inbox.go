@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/inbox/inbox.go
+
+// Package mock_inbox is a generated GoMock package.
+package mock_inbox
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// MarkRead mocks base method.
+func (m *MockRepository) MarkRead(ctx context.Context, userId, conversationId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userId, conversationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockRepositoryMockRecorder) MarkRead(ctx, userId, conversationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockRepository)(nil).MarkRead), ctx, userId, conversationId)
+}
+
+// Rebuild mocks base method.
+func (m *MockRepository) Rebuild(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rebuild", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rebuild indicates an expected call of Rebuild.
+func (mr *MockRepositoryMockRecorder) Rebuild(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rebuild", reflect.TypeOf((*MockRepository)(nil).Rebuild), ctx)
+}
+
+// RecordIncoming mocks base method.
+func (m *MockRepository) RecordIncoming(ctx context.Context, message *model.Message, userIds []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordIncoming", ctx, message, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordIncoming indicates an expected call of RecordIncoming.
+func (mr *MockRepositoryMockRecorder) RecordIncoming(ctx, message, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordIncoming", reflect.TypeOf((*MockRepository)(nil).RecordIncoming), ctx, message, userIds)
+}
+
+// RecordMessage mocks base method.
+func (m *MockRepository) RecordMessage(ctx context.Context, message *model.Message, receiverId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMessage", ctx, message, receiverId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMessage indicates an expected call of RecordMessage.
+func (mr *MockRepositoryMockRecorder) RecordMessage(ctx, message, receiverId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMessage", reflect.TypeOf((*MockRepository)(nil).RecordMessage), ctx, message, receiverId)
+}
+
+// Refresh mocks base method.
+func (m *MockRepository) Refresh(ctx context.Context, conversationId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, conversationId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockRepositoryMockRecorder) Refresh(ctx, conversationId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockRepository)(nil).Refresh), ctx, conversationId)
+}
+
+// RefreshByUserId mocks base method.
+func (m *MockRepository) RefreshByUserId(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshByUserId", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshByUserId indicates an expected call of RefreshByUserId.
+func (mr *MockRepositoryMockRecorder) RefreshByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshByUserId", reflect.TypeOf((*MockRepository)(nil).RefreshByUserId), ctx, userId)
+}
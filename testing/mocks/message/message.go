@@ -0,0 +1,467 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/message/message.go
+
+// Package mock_message is a generated GoMock package.
+package mock_message
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+	payload "gitlab.com/raihanlh/messenger-api/internal/domain/message/payload"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, message *model.Message) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, message)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, message)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
+}
+
+// DropSearchIndex mocks base method.
+func (m *MockRepository) DropSearchIndex(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropSearchIndex", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DropSearchIndex indicates an expected call of DropSearchIndex.
+func (mr *MockRepositoryMockRecorder) DropSearchIndex(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropSearchIndex", reflect.TypeOf((*MockRepository)(nil).DropSearchIndex), ctx)
+}
+
+// GetAllByConversationId mocks base method.
+func (m *MockRepository) GetAllByConversationId(ctx context.Context, conversationId string, since *time.Time) ([]*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByConversationId", ctx, conversationId, since)
+	ret0, _ := ret[0].([]*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByConversationId indicates an expected call of GetAllByConversationId.
+func (mr *MockRepositoryMockRecorder) GetAllByConversationId(ctx, conversationId, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByConversationId", reflect.TypeOf((*MockRepository)(nil).GetAllByConversationId), ctx, conversationId, since)
+}
+
+// GetById mocks base method.
+func (m *MockRepository) GetById(ctx context.Context, id string) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
+}
+
+// GetByIds mocks base method.
+func (m *MockRepository) GetByIds(ctx context.Context, ids []string) ([]*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIds", ctx, ids)
+	ret0, _ := ret[0].([]*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIds indicates an expected call of GetByIds.
+func (mr *MockRepositoryMockRecorder) GetByIds(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIds", reflect.TypeOf((*MockRepository)(nil).GetByIds), ctx, ids)
+}
+
+// GetDeviceContents mocks base method.
+func (m *MockRepository) GetDeviceContents(ctx context.Context, messageIds []string, userId string) ([]*model.MessageDeviceContent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeviceContents", ctx, messageIds, userId)
+	ret0, _ := ret[0].([]*model.MessageDeviceContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeviceContents indicates an expected call of GetDeviceContents.
+func (mr *MockRepositoryMockRecorder) GetDeviceContents(ctx, messageIds, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviceContents", reflect.TypeOf((*MockRepository)(nil).GetDeviceContents), ctx, messageIds, userId)
+}
+
+// GetUnreadSummary mocks base method.
+func (m *MockRepository) GetUnreadSummary(ctx context.Context, userId string, at time.Time) (*payload.UnreadSummaryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadSummary", ctx, userId, at)
+	ret0, _ := ret[0].(*payload.UnreadSummaryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadSummary indicates an expected call of GetUnreadSummary.
+func (mr *MockRepositoryMockRecorder) GetUnreadSummary(ctx, userId, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadSummary", reflect.TypeOf((*MockRepository)(nil).GetUnreadSummary), ctx, userId, at)
+}
+
+// MarkRead mocks base method.
+func (m *MockRepository) MarkRead(ctx context.Context, conversationId, userId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, conversationId, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockRepositoryMockRecorder) MarkRead(ctx, conversationId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockRepository)(nil).MarkRead), ctx, conversationId, userId)
+}
+
+// PurgeBySenderId mocks base method.
+func (m *MockRepository) PurgeBySenderId(ctx context.Context, senderId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBySenderId", ctx, senderId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeBySenderId indicates an expected call of PurgeBySenderId.
+func (mr *MockRepositoryMockRecorder) PurgeBySenderId(ctx, senderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBySenderId", reflect.TypeOf((*MockRepository)(nil).PurgeBySenderId), ctx, senderId)
+}
+
+// ReencryptBatch mocks base method.
+func (m *MockRepository) ReencryptBatch(ctx context.Context, afterId string, batchSize int) (string, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptBatch", ctx, afterId, batchSize)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReencryptBatch indicates an expected call of ReencryptBatch.
+func (mr *MockRepositoryMockRecorder) ReencryptBatch(ctx, afterId, batchSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptBatch", reflect.TypeOf((*MockRepository)(nil).ReencryptBatch), ctx, afterId, batchSize)
+}
+
+// Search mocks base method.
+func (m *MockRepository) Search(ctx context.Context, conversationId, term string, since *time.Time, limit int) ([]*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, conversationId, term, since, limit)
+	ret0, _ := ret[0].([]*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockRepositoryMockRecorder) Search(ctx, conversationId, term, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockRepository)(nil).Search), ctx, conversationId, term, since, limit)
+}
+
+// StreamByConversationId mocks base method.
+func (m *MockRepository) StreamByConversationId(ctx context.Context, conversationId string, since *time.Time, batchSize int, fn func([]*model.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamByConversationId", ctx, conversationId, since, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamByConversationId indicates an expected call of StreamByConversationId.
+func (mr *MockRepositoryMockRecorder) StreamByConversationId(ctx, conversationId, since, batchSize, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamByConversationId", reflect.TypeOf((*MockRepository)(nil).StreamByConversationId), ctx, conversationId, since, batchSize, fn)
+}
+
+// StreamBySenderId mocks base method.
+func (m *MockRepository) StreamBySenderId(ctx context.Context, senderId string, batchSize int, fn func([]*model.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamBySenderId", ctx, senderId, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamBySenderId indicates an expected call of StreamBySenderId.
+func (mr *MockRepositoryMockRecorder) StreamBySenderId(ctx, senderId, batchSize, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamBySenderId", reflect.TypeOf((*MockRepository)(nil).StreamBySenderId), ctx, senderId, batchSize, fn)
+}
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUsecase) Create(ctx context.Context, req *payload.CreateMessageRequest) (*payload.CreateMessageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(*payload.CreateMessageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUsecaseMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUsecase)(nil).Create), ctx, req)
+}
+
+// Delete mocks base method.
+func (m *MockUsecase) Delete(ctx context.Context, req *payload.DeleteMessageRequest) (*payload.DeleteMessageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, req)
+	ret0, _ := ret[0].(*payload.DeleteMessageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockUsecaseMockRecorder) Delete(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUsecase)(nil).Delete), ctx, req)
+}
+
+// Export mocks base method.
+func (m *MockUsecase) Export(ctx context.Context, req *payload.ExportRequest, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, req, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockUsecaseMockRecorder) Export(ctx, req, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockUsecase)(nil).Export), ctx, req, w)
+}
+
+// GetAllByConversationId mocks base method.
+func (m *MockUsecase) GetAllByConversationId(ctx context.Context, req *payload.GetMessagesByConvIdRequest) (*payload.GetMessagesByConvIdResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByConversationId", ctx, req)
+	ret0, _ := ret[0].(*payload.GetMessagesByConvIdResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByConversationId indicates an expected call of GetAllByConversationId.
+func (mr *MockUsecaseMockRecorder) GetAllByConversationId(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByConversationId", reflect.TypeOf((*MockUsecase)(nil).GetAllByConversationId), ctx, req)
+}
+
+// GetUnreadSummary mocks base method.
+func (m *MockUsecase) GetUnreadSummary(ctx context.Context, req *payload.GetUnreadSummaryRequest) (*payload.UnreadSummaryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadSummary", ctx, req)
+	ret0, _ := ret[0].(*payload.UnreadSummaryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadSummary indicates an expected call of GetUnreadSummary.
+func (mr *MockUsecaseMockRecorder) GetUnreadSummary(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadSummary", reflect.TypeOf((*MockUsecase)(nil).GetUnreadSummary), ctx, req)
+}
+
+// Search mocks base method.
+func (m *MockUsecase) Search(ctx context.Context, req *payload.SearchMessagesRequest) (*payload.SearchMessagesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, req)
+	ret0, _ := ret[0].(*payload.SearchMessagesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockUsecaseMockRecorder) Search(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUsecase)(nil).Search), ctx, req)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockHandler) Create(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockHandlerMockRecorder) Create(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHandler)(nil).Create), ctx)
+}
+
+// Delete mocks base method.
+func (m *MockHandler) Delete(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockHandlerMockRecorder) Delete(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockHandler)(nil).Delete), ctx)
+}
+
+// Export mocks base method.
+func (m *MockHandler) Export(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockHandlerMockRecorder) Export(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockHandler)(nil).Export), ctx)
+}
+
+// GetByConversationId mocks base method.
+func (m *MockHandler) GetByConversationId(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByConversationId", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetByConversationId indicates an expected call of GetByConversationId.
+func (mr *MockHandlerMockRecorder) GetByConversationId(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByConversationId", reflect.TypeOf((*MockHandler)(nil).GetByConversationId), ctx)
+}
+
+// GetUnreadSummary mocks base method.
+func (m *MockHandler) GetUnreadSummary(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadSummary", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetUnreadSummary indicates an expected call of GetUnreadSummary.
+func (mr *MockHandlerMockRecorder) GetUnreadSummary(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadSummary", reflect.TypeOf((*MockHandler)(nil).GetUnreadSummary), ctx)
+}
+
+// Search mocks base method.
+func (m *MockHandler) Search(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockHandlerMockRecorder) Search(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockHandler)(nil).Search), ctx)
+}
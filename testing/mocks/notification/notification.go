@@ -0,0 +1,272 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/notification/notification.go
+
+// Package mock_notification is a generated GoMock package.
+package mock_notification
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	echo "github.com/labstack/echo/v4"
+	payload "gitlab.com/raihanlh/messenger-api/internal/domain/notification/payload"
+	model "gitlab.com/raihanlh/messenger-api/internal/model"
+	eventbus "gitlab.com/raihanlh/messenger-api/pkg/eventbus"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimPending mocks base method.
+func (m *MockRepository) ClaimPending(ctx context.Context, limit int) ([]*model.PushJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPending", ctx, limit)
+	ret0, _ := ret[0].([]*model.PushJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPending indicates an expected call of ClaimPending.
+func (mr *MockRepositoryMockRecorder) ClaimPending(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPending", reflect.TypeOf((*MockRepository)(nil).ClaimPending), ctx, limit)
+}
+
+// DeleteToken mocks base method.
+func (m *MockRepository) DeleteToken(ctx context.Context, userId, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteToken", ctx, userId, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteToken indicates an expected call of DeleteToken.
+func (mr *MockRepositoryMockRecorder) DeleteToken(ctx, userId, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteToken", reflect.TypeOf((*MockRepository)(nil).DeleteToken), ctx, userId, token)
+}
+
+// DeleteTokens mocks base method.
+func (m *MockRepository) DeleteTokens(ctx context.Context, tokens []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTokens", ctx, tokens)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTokens indicates an expected call of DeleteTokens.
+func (mr *MockRepositoryMockRecorder) DeleteTokens(ctx, tokens interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTokens", reflect.TypeOf((*MockRepository)(nil).DeleteTokens), ctx, tokens)
+}
+
+// Enqueue mocks base method.
+func (m *MockRepository) Enqueue(ctx context.Context, job *model.PushJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockRepositoryMockRecorder) Enqueue(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockRepository)(nil).Enqueue), ctx, job)
+}
+
+// GetTokensByUserId mocks base method.
+func (m *MockRepository) GetTokensByUserId(ctx context.Context, userId string) ([]*model.PushToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTokensByUserId", ctx, userId)
+	ret0, _ := ret[0].([]*model.PushToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTokensByUserId indicates an expected call of GetTokensByUserId.
+func (mr *MockRepositoryMockRecorder) GetTokensByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokensByUserId", reflect.TypeOf((*MockRepository)(nil).GetTokensByUserId), ctx, userId)
+}
+
+// UpdateJob mocks base method.
+func (m *MockRepository) UpdateJob(ctx context.Context, job *model.PushJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockRepositoryMockRecorder) UpdateJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockRepository)(nil).UpdateJob), ctx, job)
+}
+
+// UpsertToken mocks base method.
+func (m *MockRepository) UpsertToken(ctx context.Context, token *model.PushToken) (*model.PushToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertToken", ctx, token)
+	ret0, _ := ret[0].(*model.PushToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertToken indicates an expected call of UpsertToken.
+func (mr *MockRepositoryMockRecorder) UpsertToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertToken", reflect.TypeOf((*MockRepository)(nil).UpsertToken), ctx, token)
+}
+
+// MockUsecase is a mock of Usecase interface.
+type MockUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsecaseMockRecorder
+}
+
+// MockUsecaseMockRecorder is the mock recorder for MockUsecase.
+type MockUsecaseMockRecorder struct {
+	mock *MockUsecase
+}
+
+// NewMockUsecase creates a new mock instance.
+func NewMockUsecase(ctrl *gomock.Controller) *MockUsecase {
+	mock := &MockUsecase{ctrl: ctrl}
+	mock.recorder = &MockUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
+	return m.recorder
+}
+
+// QueuePush mocks base method.
+func (m *MockUsecase) QueuePush(ctx context.Context, e *eventbus.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueuePush", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueuePush indicates an expected call of QueuePush.
+func (mr *MockUsecaseMockRecorder) QueuePush(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueuePush", reflect.TypeOf((*MockUsecase)(nil).QueuePush), ctx, e)
+}
+
+// RegisterToken mocks base method.
+func (m *MockUsecase) RegisterToken(ctx context.Context, req *payload.RegisterTokenRequest) (*payload.RegisterTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterToken", ctx, req)
+	ret0, _ := ret[0].(*payload.RegisterTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterToken indicates an expected call of RegisterToken.
+func (mr *MockUsecaseMockRecorder) RegisterToken(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterToken", reflect.TypeOf((*MockUsecase)(nil).RegisterToken), ctx, req)
+}
+
+// SendPending mocks base method.
+func (m *MockUsecase) SendPending(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPending", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPending indicates an expected call of SendPending.
+func (mr *MockUsecaseMockRecorder) SendPending(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPending", reflect.TypeOf((*MockUsecase)(nil).SendPending), ctx)
+}
+
+// UnregisterToken mocks base method.
+func (m *MockUsecase) UnregisterToken(ctx context.Context, req *payload.UnregisterTokenRequest) (*payload.UnregisterTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnregisterToken", ctx, req)
+	ret0, _ := ret[0].(*payload.UnregisterTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnregisterToken indicates an expected call of UnregisterToken.
+func (mr *MockUsecaseMockRecorder) UnregisterToken(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterToken", reflect.TypeOf((*MockUsecase)(nil).UnregisterToken), ctx, req)
+}
+
+// MockHandler is a mock of Handler interface.
+type MockHandler struct {
+	ctrl     *gomock.Controller
+	recorder *MockHandlerMockRecorder
+}
+
+// MockHandlerMockRecorder is the mock recorder for MockHandler.
+type MockHandlerMockRecorder struct {
+	mock *MockHandler
+}
+
+// NewMockHandler creates a new mock instance.
+func NewMockHandler(ctrl *gomock.Controller) *MockHandler {
+	mock := &MockHandler{ctrl: ctrl}
+	mock.recorder = &MockHandlerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
+	return m.recorder
+}
+
+// RegisterToken mocks base method.
+func (m *MockHandler) RegisterToken(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterToken", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterToken indicates an expected call of RegisterToken.
+func (mr *MockHandlerMockRecorder) RegisterToken(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterToken", reflect.TypeOf((*MockHandler)(nil).RegisterToken), ctx)
+}
+
+// UnregisterToken mocks base method.
+func (m *MockHandler) UnregisterToken(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnregisterToken", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnregisterToken indicates an expected call of UnregisterToken.
+func (mr *MockHandlerMockRecorder) UnregisterToken(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterToken", reflect.TypeOf((*MockHandler)(nil).UnregisterToken), ctx)
+}
@@ -7,6 +7,7 @@ package mock_user
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	echo "github.com/labstack/echo/v4"
@@ -38,6 +39,20 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Anonymize mocks base method.
+func (m *MockRepository) Anonymize(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockRepositoryMockRecorder) Anonymize(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockRepository)(nil).Anonymize), ctx, id)
+}
+
 // Create mocks base method.
 func (m *MockRepository) Create(ctx context.Context, user *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -53,6 +68,21 @@ func (mr *MockRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, user)
 }
 
+// CreateDeletion mocks base method.
+func (m *MockRepository) CreateDeletion(ctx context.Context, deletion *model.AccountDeletion) (*model.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeletion", ctx, deletion)
+	ret0, _ := ret[0].(*model.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDeletion indicates an expected call of CreateDeletion.
+func (mr *MockRepositoryMockRecorder) CreateDeletion(ctx, deletion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeletion", reflect.TypeOf((*MockRepository)(nil).CreateDeletion), ctx, deletion)
+}
+
 // Delete mocks base method.
 func (m *MockRepository) Delete(ctx context.Context, id string) error {
 	m.ctrl.T.Helper()
@@ -97,6 +127,21 @@ func (mr *MockRepositoryMockRecorder) GetByEmail(ctx, email interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockRepository)(nil).GetByEmail), ctx, email)
 }
 
+// GetByEmails mocks base method.
+func (m *MockRepository) GetByEmails(ctx context.Context, emails []string) ([]*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmails", ctx, emails)
+	ret0, _ := ret[0].([]*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmails indicates an expected call of GetByEmails.
+func (mr *MockRepositoryMockRecorder) GetByEmails(ctx, emails interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmails", reflect.TypeOf((*MockRepository)(nil).GetByEmails), ctx, emails)
+}
+
 // GetById mocks base method.
 func (m *MockRepository) GetById(ctx context.Context, id string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -112,6 +157,51 @@ func (mr *MockRepositoryMockRecorder) GetById(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
 }
 
+// GetByIdWithDeleted mocks base method.
+func (m *MockRepository) GetByIdWithDeleted(ctx context.Context, id string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIdWithDeleted", ctx, id)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIdWithDeleted indicates an expected call of GetByIdWithDeleted.
+func (mr *MockRepositoryMockRecorder) GetByIdWithDeleted(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIdWithDeleted", reflect.TypeOf((*MockRepository)(nil).GetByIdWithDeleted), ctx, id)
+}
+
+// GetDeletionById mocks base method.
+func (m *MockRepository) GetDeletionById(ctx context.Context, id string) (*model.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletionById", ctx, id)
+	ret0, _ := ret[0].(*model.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletionById indicates an expected call of GetDeletionById.
+func (mr *MockRepositoryMockRecorder) GetDeletionById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletionById", reflect.TypeOf((*MockRepository)(nil).GetDeletionById), ctx, id)
+}
+
+// GetDueDeletions mocks base method.
+func (m *MockRepository) GetDueDeletions(ctx context.Context, at time.Time) ([]*model.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeletions", ctx, at)
+	ret0, _ := ret[0].([]*model.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeletions indicates an expected call of GetDueDeletions.
+func (mr *MockRepositoryMockRecorder) GetDueDeletions(ctx, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeletions", reflect.TypeOf((*MockRepository)(nil).GetDueDeletions), ctx, at)
+}
+
 // Update mocks base method.
 func (m *MockRepository) Update(ctx context.Context, user *model.User) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +217,35 @@ func (mr *MockRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, user)
 }
 
+// UpdateDeletion mocks base method.
+func (m *MockRepository) UpdateDeletion(ctx context.Context, deletion *model.AccountDeletion) (*model.AccountDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDeletion", ctx, deletion)
+	ret0, _ := ret[0].(*model.AccountDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDeletion indicates an expected call of UpdateDeletion.
+func (mr *MockRepositoryMockRecorder) UpdateDeletion(ctx, deletion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDeletion", reflect.TypeOf((*MockRepository)(nil).UpdateDeletion), ctx, deletion)
+}
+
+// UpdateLastSeen mocks base method.
+func (m *MockRepository) UpdateLastSeen(ctx context.Context, id string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLastSeen", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastSeen indicates an expected call of UpdateLastSeen.
+func (mr *MockRepositoryMockRecorder) UpdateLastSeen(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastSeen", reflect.TypeOf((*MockRepository)(nil).UpdateLastSeen), ctx, id, at)
+}
+
 // MockUsecase is a mock of Usecase interface.
 type MockUsecase struct {
 	ctrl     *gomock.Controller
@@ -150,6 +269,21 @@ func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
 	return m.recorder
 }
 
+// CancelPurge mocks base method.
+func (m *MockUsecase) CancelPurge(ctx context.Context, req *payload.CancelPurgeRequest) (*payload.CancelPurgeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPurge", ctx, req)
+	ret0, _ := ret[0].(*payload.CancelPurgeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelPurge indicates an expected call of CancelPurge.
+func (mr *MockUsecaseMockRecorder) CancelPurge(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPurge", reflect.TypeOf((*MockUsecase)(nil).CancelPurge), ctx, req)
+}
+
 // Create mocks base method.
 func (m *MockUsecase) Create(ctx context.Context, req *payload.CreateRequest) (*payload.CreateResponse, error) {
 	m.ctrl.T.Helper()
@@ -240,6 +374,79 @@ func (mr *MockUsecaseMockRecorder) Login(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUsecase)(nil).Login), ctx, req)
 }
 
+// Logout mocks base method.
+func (m *MockUsecase) Logout(ctx context.Context, req *payload.LogoutRequest) (*payload.LogoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, req)
+	ret0, _ := ret[0].(*payload.LogoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUsecaseMockRecorder) Logout(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUsecase)(nil).Logout), ctx, req)
+}
+
+// MarkSeen mocks base method.
+func (m *MockUsecase) MarkSeen(ctx context.Context, user *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSeen", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSeen indicates an expected call of MarkSeen.
+func (mr *MockUsecaseMockRecorder) MarkSeen(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSeen", reflect.TypeOf((*MockUsecase)(nil).MarkSeen), ctx, user)
+}
+
+// PurgeDeletedAccounts mocks base method.
+func (m *MockUsecase) PurgeDeletedAccounts(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedAccounts", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeletedAccounts indicates an expected call of PurgeDeletedAccounts.
+func (mr *MockUsecaseMockRecorder) PurgeDeletedAccounts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedAccounts", reflect.TypeOf((*MockUsecase)(nil).PurgeDeletedAccounts), ctx)
+}
+
+// Refresh mocks base method.
+func (m *MockUsecase) Refresh(ctx context.Context, req *payload.RefreshRequest) (*payload.RefreshResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, req)
+	ret0, _ := ret[0].(*payload.RefreshResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUsecaseMockRecorder) Refresh(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUsecase)(nil).Refresh), ctx, req)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockUsecase) RevokeSessions(ctx context.Context, req *payload.RevokeSessionsRequest) (*payload.RevokeSessionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", ctx, req)
+	ret0, _ := ret[0].(*payload.RevokeSessionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockUsecaseMockRecorder) RevokeSessions(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockUsecase)(nil).RevokeSessions), ctx, req)
+}
+
 // Update mocks base method.
 func (m *MockUsecase) Update(ctx context.Context, req *payload.UpdateRequest) (*payload.UpdateResponse, error) {
 	m.ctrl.T.Helper()
@@ -255,6 +462,21 @@ func (mr *MockUsecaseMockRecorder) Update(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUsecase)(nil).Update), ctx, req)
 }
 
+// UpdateRole mocks base method.
+func (m *MockUsecase) UpdateRole(ctx context.Context, req *payload.UpdateRoleRequest) (*payload.UpdateRoleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, req)
+	ret0, _ := ret[0].(*payload.UpdateRoleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockUsecaseMockRecorder) UpdateRole(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockUsecase)(nil).UpdateRole), ctx, req)
+}
+
 // MockHandler is a mock of Handler interface.
 type MockHandler struct {
 	ctrl     *gomock.Controller
@@ -278,6 +500,20 @@ func (m *MockHandler) EXPECT() *MockHandlerMockRecorder {
 	return m.recorder
 }
 
+// CancelPurge mocks base method.
+func (m *MockHandler) CancelPurge(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelPurge", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelPurge indicates an expected call of CancelPurge.
+func (mr *MockHandlerMockRecorder) CancelPurge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPurge", reflect.TypeOf((*MockHandler)(nil).CancelPurge), ctx)
+}
+
 // Create mocks base method.
 func (m *MockHandler) Create(ctx echo.Context) error {
 	m.ctrl.T.Helper()
@@ -348,6 +584,62 @@ func (mr *MockHandlerMockRecorder) GetByToken(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByToken", reflect.TypeOf((*MockHandler)(nil).GetByToken), ctx)
 }
 
+// Login mocks base method.
+func (m *MockHandler) Login(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockHandlerMockRecorder) Login(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockHandler)(nil).Login), ctx)
+}
+
+// Logout mocks base method.
+func (m *MockHandler) Logout(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockHandlerMockRecorder) Logout(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockHandler)(nil).Logout), ctx)
+}
+
+// Refresh mocks base method.
+func (m *MockHandler) Refresh(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockHandlerMockRecorder) Refresh(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockHandler)(nil).Refresh), ctx)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockHandler) RevokeSessions(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockHandlerMockRecorder) RevokeSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockHandler)(nil).RevokeSessions), ctx)
+}
+
 // Update mocks base method.
 func (m *MockHandler) Update(ctx echo.Context) error {
 	m.ctrl.T.Helper()
@@ -361,3 +653,17 @@ func (mr *MockHandlerMockRecorder) Update(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockHandler)(nil).Update), ctx)
 }
+
+// UpdateRole mocks base method.
+func (m *MockHandler) UpdateRole(ctx echo.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockHandlerMockRecorder) UpdateRole(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockHandler)(nil).UpdateRole), ctx)
+}